@@ -1,6 +1,8 @@
 package command
 
 import (
+	"strings"
+
 	"github.com/b4nd/git-profile/pkg/application"
 	"github.com/b4nd/git-profile/pkg/domain"
 
@@ -8,77 +10,219 @@ import (
 )
 
 type AmendProfileCommitCommand struct {
-	currentProfileService *application.CurrentProfileService
-	amendProfileService   *application.AmendProfileService
+	currentProfileService        *application.CurrentProfileService
+	amendProfileService          *application.AmendProfileService
+	rewriteProfileCommitsService *application.RewriteProfileCommitsService
+	getProfileService            *application.GetProfileService
+	listProfileService           *application.ListProfileService
 }
 
 func NewAmendProfileCommitCommnad(
 	currentProfileService *application.CurrentProfileService,
 	amendProfileService *application.AmendProfileService,
+	rewriteProfileCommitsService *application.RewriteProfileCommitsService,
+	getProfileService *application.GetProfileService,
+	listProfileService *application.ListProfileService,
 ) *AmendProfileCommitCommand {
 	return &AmendProfileCommitCommand{
-		currentProfileService: currentProfileService,
-		amendProfileService:   amendProfileService,
+		currentProfileService:        currentProfileService,
+		amendProfileService:          amendProfileService,
+		rewriteProfileCommitsService: rewriteProfileCommitsService,
+		getProfileService:            getProfileService,
+		listProfileService:           listProfileService,
 	}
 }
 
 func (c *AmendProfileCommitCommand) Register(rootCmd *cobra.Command) {
 	var workspace string
+	var force bool
+	var keepDate bool
+	var resetDate bool
+	var committerOnly bool
 
 	cmd := &cobra.Command{
-		Use:   "amend [-w workspace]",
+		Use:   "amend [rev | base..tip] [-w workspace] [--force] [--keep-date | --reset-date] [--committer-only]",
 		Short: "Amend author of last commit",
-		Long:  `Amend author of last commit`,
+		Long: `Amend author of last commit.
+The author and the committer are set to the profile, the author date is kept and
+the committer date is set to now unless --keep-date or --reset-date is given.
+With --committer-only the author is kept and only the committer is set.
+With a revision, the author and committer of the commit or of every commit of the
+range are set to the profile, the commits up to HEAD are recreated on top of them.
+Commits that already match the profile are left as they are. Ranges with merges or
+reachable from a remote branch are refused unless --force is given.
+A single argument naming an existing profile is used as the workspace.
+`,
 		Example: `  git profile amend
   git profile amend work
-  git profile amend -w work`,
+  git profile amend -w work
+  git profile amend HEAD~2
+  git profile amend origin/main..HEAD -w work
+  git profile amend --keep-date
+  git profile amend --committer-only`,
 		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if workspace == "" && len(args) > 0 {
-				workspace = args[0]
+			revision := ""
+			if len(args) > 0 {
+				if workspace == "" && c.isWorkspace(args[0]) {
+					workspace = args[0]
+				} else {
+					revision = args[0]
+				}
+			}
+
+			if revision != "" {
+				return c.ExecuteRange(cmd, application.RewriteProfileCommitsServiceParams{
+					Workspace:     workspace,
+					Revision:      revision,
+					Force:         force,
+					KeepDate:      keepDate,
+					ResetDate:     resetDate,
+					CommitterOnly: committerOnly,
+				})
 			}
 
-			return c.Execute(cmd, workspace)
+			return c.Execute(cmd, application.AmendProfileServiceParams{
+				Workspace:     workspace,
+				KeepDate:      keepDate,
+				ResetDate:     resetDate,
+				CommitterOnly: committerOnly,
+			})
 		},
 	}
 
 	cmd.Flags().StringVarP(&workspace, "workspace", "w", "", "The workspace of the profile")
+	cmd.Flags().BoolVar(&force, "force", false, "Rewrite merges and commits already pushed")
+	cmd.Flags().BoolVar(&keepDate, "keep-date", false, "Keep the author and committer dates")
+	cmd.Flags().BoolVar(&resetDate, "reset-date", false, "Set the author and committer dates to now")
+	cmd.Flags().BoolVar(&committerOnly, "committer-only", false, "Keep the author and only set the committer")
+
+	// The argument is a revision, only the flag takes a workspace
+	registerWorkspaceCompletion(cmd, c.listProfileService, 0)
+	cmd.MarkFlagsMutuallyExclusive("keep-date", "reset-date")
 
 	rootCmd.AddCommand(cmd)
 }
 
-func (c *AmendProfileCommitCommand) Execute(cmd *cobra.Command, workspace string) error {
-	if workspace == "" {
-		// If no workspace is provided, use the current profile workspace
-		profileWorkspace, err := c.currentProfileService.Execute()
-		if err != nil {
-			cmd.Println("Profile not found")
-			return nil
-		}
+func (c *AmendProfileCommitCommand) Execute(cmd *cobra.Command, params application.AmendProfileServiceParams) error {
+	workspace, ok := c.resolveWorkspace(cmd, params.Workspace)
+	if !ok {
+		return nil
+	}
+
+	params.Workspace = workspace
+	commit, err := c.amendProfileService.Execute(params)
+
+	if err != nil {
+		printErrorMessage(cmd, err, workspace)
+		return nil
+	}
 
-		workspace = profileWorkspace.Workspace().String()
+	if params.CommitterOnly {
+		cmd.Printf("Amended commit committer to %s <%s>\n", commit.Committer.Name(), commit.Committer.Email())
 	} else {
-		profileWorkspace, err := domain.NewProfileWorkspace(workspace)
-		if err != nil {
-			cmd.Println("Profile not found")
+		cmd.Printf("Amended commit author to %s <%s>\n", commit.Author.Name(), commit.Author.Email())
+	}
+	cmd.Printf("\nSuggest to check the commit with the following command:\n")
+	cmd.Printf("  git log -1\n")
+
+	return nil
+}
+
+func (c *AmendProfileCommitCommand) ExecuteRange(cmd *cobra.Command, params application.RewriteProfileCommitsServiceParams) error {
+	workspace, ok := c.resolveWorkspace(cmd, params.Workspace)
+	if !ok {
+		return nil
+	}
+
+	params.Workspace = workspace
+	revision := params.Revision
+	rewrites, err := c.rewriteProfileCommitsService.Execute(params)
+
+	switch err {
+	case nil:
+	case application.ErrCommitRangeHasMerges:
+		cmd.Printf("The commits of \"%s\" contain merges, use --force to rewrite them anyway\n", revision)
+		return nil
+	case application.ErrCommitRangePushed:
+		cmd.Printf("The commits of \"%s\" are reachable from a remote branch, use --force to rewrite them anyway\n", revision)
+		return nil
+	case application.ErrCommitNotInHead:
+		cmd.Printf("The commits of \"%s\" are not reachable from HEAD, suggest to checkout the branch first\n", revision)
+		return nil
+	case domain.ErrScmCommitNotFound, domain.ErrInvalidHash:
+		cmd.Printf("Commit \"%s\" not found\n", revision)
+		return nil
+	default:
+		if _, ok := errorMessages[err]; ok {
+			printErrorMessage(cmd, err, workspace)
 			return nil
 		}
 
-		workspace = profileWorkspace.String()
+		cmd.Printf("Failed to rewrite the commits of \"%s\": %v\n", revision, err)
+		return nil
 	}
 
-	commit, err := c.amendProfileService.Execute(application.AmendProfileServiceParams{
-		Workspace: workspace,
-	})
-
-	if err != nil {
-		cmd.Printf(errorMessages[err], workspace)
+	if len(rewrites) == 0 {
+		cmd.Printf("The commits of \"%s\" already match the profile \"%s\"\n", revision, workspace)
 		return nil
 	}
 
-	cmd.Printf("Amended commit author to %s <%s>\n", commit.Author.Name(), commit.Author.Email())
-	cmd.Printf("\nSuggest to check the commit with the following command:\n")
-	cmd.Printf("  git log -1\n")
+	cmd.Printf("Rewrote %d commits with the profile \"%s\":\n", len(rewrites), workspace)
+	for _, rewrite := range rewrites {
+		cmd.Printf("  %s -> %s\n", rewrite.Old.String(), rewrite.New.String())
+	}
+
+	cmd.Printf("\nSuggest to check the commits with the following command:\n")
+	cmd.Printf("  git log -%d\n", len(rewrites))
 
 	return nil
 }
+
+// isWorkspace reports whether the argument names an existing profile rather than a revision
+func (c *AmendProfileCommitCommand) isWorkspace(arg string) bool {
+	if strings.Contains(arg, "..") {
+		return false
+	}
+
+	_, err := c.getProfileService.Execute(application.GetProfileServiceParams{Workspace: arg})
+	return err == nil
+}
+
+// resolveWorkspace validates the workspace, the current profile is used when it is empty
+func (c *AmendProfileCommitCommand) resolveWorkspace(cmd *cobra.Command, workspace string) (string, bool) {
+	current, err := c.currentProfileService.Execute()
+	if err == domain.ErrScmRepositoryNotFound {
+		printErrorMessage(cmd, err, "")
+		return "", false
+	}
+
+	if workspace == "" {
+		// If no workspace is provided, use the current profile workspace
+		if err != nil {
+			// Without a current profile, the profile is picked when the input is a terminal
+			picked, err := pickWorkspace(cmd, c.listProfileService, nil)
+			if err == ErrPickerNotTerminal {
+				cmd.Println("Profile not found")
+				return "", false
+			}
+
+			if err != nil {
+				printErrorMessage(cmd, err, "")
+				return "", false
+			}
+
+			return picked, true
+		}
+
+		return current.Workspace().String(), true
+	}
+
+	profileWorkspace, err := domain.NewProfileWorkspace(workspace)
+	if err != nil {
+		cmd.Println("Profile not found")
+		return "", false
+	}
+
+	return profileWorkspace.String(), true
+}
@@ -0,0 +1,216 @@
+package command
+
+import (
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/b4nd/git-profile/pkg/application"
+	"github.com/b4nd/git-profile/pkg/domain"
+
+	"github.com/spf13/cobra"
+)
+
+// auditShortHash is the length of the example hashes in the table
+const auditShortHash = 7
+
+type AuditProfileCommitsCommand struct {
+	auditProfileCommitsService *application.AuditProfileCommitsService
+	currentProfileService      *application.CurrentProfileService
+	listProfileService         *application.ListProfileService
+}
+
+// auditOutput is the documented schema of an audit in the structured outputs
+type auditOutput struct {
+	Revision   string                `json:"revision" yaml:"revision"`
+	Workspace  string                `json:"workspace,omitempty" yaml:"workspace,omitempty"`
+	Commits    int                   `json:"commits" yaml:"commits"`
+	Identities []auditIdentityOutput `json:"identities" yaml:"identities"`
+}
+
+type auditIdentityOutput struct {
+	Name      string   `json:"name" yaml:"name"`
+	Email     string   `json:"email" yaml:"email"`
+	Status    string   `json:"status" yaml:"status"`
+	Workspace string   `json:"workspace,omitempty" yaml:"workspace,omitempty"`
+	Authored  int      `json:"authored" yaml:"authored"`
+	Committed int      `json:"committed" yaml:"committed"`
+	First     string   `json:"first" yaml:"first"`
+	Last      string   `json:"last" yaml:"last"`
+	Examples  []string `json:"examples" yaml:"examples"`
+}
+
+func NewAuditProfileCommitsCommand(
+	auditProfileCommitsService *application.AuditProfileCommitsService,
+	currentProfileService *application.CurrentProfileService,
+	listProfileService *application.ListProfileService,
+) *AuditProfileCommitsCommand {
+	return &AuditProfileCommitsCommand{
+		auditProfileCommitsService: auditProfileCommitsService,
+		currentProfileService:      currentProfileService,
+		listProfileService:         listProfileService,
+	}
+}
+
+func (c *AuditProfileCommitsCommand) Register(rootCmd *cobra.Command) {
+	var workspace string
+
+	cmd := &cobra.Command{
+		Use:   "audit [rev | base..tip] [-w workspace]",
+		Short: "Lists the identities found in the history of the repository.",
+		Long: `List the identities found in the history of the repository.
+The commits are grouped by author and committer, every identity is mapped to the
+profile with its email and flagged as unknown when no profile has it, or as
+other-workspace when it belongs to a profile other than the audited one. The
+audited profile is the one given with -w, the current profile otherwise, and
+every profile is accepted when none is configured.
+The history is the one of HEAD unless a revision or a range is given, base..tip
+or base...tip as in git.
+`,
+		Example: `  git profile audit
+  git profile audit -w work
+  git profile audit origin/main..HEAD
+  git profile audit -o json`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			revision := ""
+			if len(args) > 0 {
+				revision = args[0]
+			}
+
+			return c.Execute(cmd, application.AuditProfileCommitsServiceParams{
+				Workspace: workspace,
+				Revision:  revision,
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&workspace, "workspace", "w", "", "The workspace of the audited profile")
+
+	// The argument is a revision, only the flag takes a workspace
+	registerWorkspaceCompletion(cmd, c.listProfileService, 0)
+
+	supportOutput(cmd)
+	rootCmd.AddCommand(cmd)
+}
+
+func (c *AuditProfileCommitsCommand) Execute(cmd *cobra.Command, params application.AuditProfileCommitsServiceParams) error {
+	output, err := newOutput(cmd)
+	if err != nil {
+		return err
+	}
+
+	current, err := c.currentProfileService.Execute()
+	if err == domain.ErrScmRepositoryNotFound {
+		printErrorMessage(cmd, err, "")
+		return nil
+	}
+
+	if params.Workspace == "" && err == nil && current.Workspace().String() != domain.NotConfiguredWorkspace {
+		params.Workspace = current.Workspace().String()
+	}
+
+	revision := params.Revision
+	if revision == "" {
+		revision = domain.NewScmCommitHashHead().String()
+	}
+
+	result, err := c.auditProfileCommitsService.Execute(params)
+
+	switch err {
+	case nil:
+	case domain.ErrScmCommitNotFound, domain.ErrInvalidHash:
+		cmd.Printf("Commit \"%s\" not found\n", revision)
+		return nil
+	default:
+		if _, ok := errorMessages[err]; ok {
+			printErrorMessage(cmd, err, params.Workspace)
+			return nil
+		}
+
+		cmd.Printf("Failed to audit the commits of \"%s\": %v\n", revision, err)
+		return nil
+	}
+
+	if !output.IsTable() {
+		value := auditOutput{
+			Revision:   revision,
+			Workspace:  result.Workspace,
+			Commits:    result.Commits,
+			Identities: make([]auditIdentityOutput, 0, len(result.Identities)),
+		}
+
+		for _, identity := range result.Identities {
+			examples := make([]string, 0, len(identity.Examples))
+			for _, hash := range identity.Examples {
+				examples = append(examples, hash.String())
+			}
+
+			value.Identities = append(value.Identities, auditIdentityOutput{
+				Name:      identity.Name,
+				Email:     identity.Email,
+				Status:    string(identity.Status),
+				Workspace: identity.Workspace,
+				Authored:  identity.Authored,
+				Committed: identity.Committed,
+				First:     identity.First.Format(time.RFC3339),
+				Last:      identity.Last.Format(time.RFC3339),
+				Examples:  examples,
+			})
+		}
+
+		return output.Print(cmd, value)
+	}
+
+	if result.Commits == 0 {
+		cmd.Printf("No commits found in \"%s\"\n", revision)
+		return nil
+	}
+
+	if result.Workspace == "" {
+		cmd.Printf("Audited %d commits of \"%s\" against every profile:\n\n", result.Commits, revision)
+	} else {
+		cmd.Printf("Audited %d commits of \"%s\" against the profile \"%s\":\n\n", result.Commits, revision, result.Workspace)
+	}
+
+	writer := tabwriter.NewWriter(cmd.OutOrStderr(), 0, 0, 2, ' ', 0)
+	c.printRow(writer, "STATUS", "IDENTITY", "PROFILE", "AUTHORED", "COMMITTED", "FIRST", "LAST", "EXAMPLES")
+
+	for _, identity := range result.Identities {
+		examples := make([]string, 0, len(identity.Examples))
+		for _, hash := range identity.Examples {
+			examples = append(examples, shortHash(hash.String()))
+		}
+
+		profile := identity.Workspace
+		if profile == "" {
+			profile = "-"
+		}
+
+		c.printRow(writer,
+			string(identity.Status),
+			identity.Name+" <"+identity.Email+">",
+			profile,
+			strconv.Itoa(identity.Authored),
+			strconv.Itoa(identity.Committed),
+			identity.First.Format(time.DateOnly),
+			identity.Last.Format(time.DateOnly),
+			strings.Join(examples, ", "),
+		)
+	}
+
+	return writer.Flush()
+}
+
+func (c *AuditProfileCommitsCommand) printRow(writer *tabwriter.Writer, columns ...string) {
+	_, _ = writer.Write([]byte(strings.Join(columns, "\t") + "\n"))
+}
+
+func shortHash(hash string) string {
+	if len(hash) > auditShortHash {
+		return hash[:auditShortHash]
+	}
+
+	return hash
+}
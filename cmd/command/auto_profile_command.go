@@ -0,0 +1,86 @@
+package command
+
+import (
+	"github.com/b4nd/git-profile/pkg/application"
+
+	"github.com/spf13/cobra"
+)
+
+type AutoProfileCommand struct {
+	autoProfileService *application.AutoProfileService
+}
+
+func NewAutoProfileCommand(autoProfileService *application.AutoProfileService) *AutoProfileCommand {
+	return &AutoProfileCommand{autoProfileService}
+}
+
+func (c *AutoProfileCommand) Register(rootCmd *cobra.Command) {
+	var quiet bool
+
+	cmd := &cobra.Command{
+		Use:   "auto [--quiet]",
+		Short: "Sets the profile matching the remote urls of the repository.",
+		Long: `Set the profile whose remote url patterns match a remote of the current repository.
+The patterns are added to a profile with "git profile add --remote", the ssh, scp-like
+and https forms of an url are equivalent and "*" matches any sequence of characters.
+
+Exit codes:
+  0  the matching profile is now in use
+  1  the remotes of the repository cannot be read
+  2  no profile matches the remotes of the repository
+  3  more than one profile matches the remotes of the repository`,
+		Example: `  git profile add work --remote "github.com:acme-corp/*"
+  git profile auto
+  git profile auto --quiet`,
+		Args:          cobra.NoArgs,
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return c.Execute(cmd, quiet)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&quiet, "quiet", "q", false, "Only print the errors")
+
+	rootCmd.AddCommand(cmd)
+}
+
+func (c *AutoProfileCommand) Execute(cmd *cobra.Command, quiet bool) error {
+	profile, err := c.autoProfileService.Execute()
+
+	if err == application.ErrProfileNoMatch {
+		if !quiet {
+			cmd.Println("No profile matches the remotes of the repository")
+			cmd.Printf("\nSuggest to add a remote url pattern to a profile with the following command:\n")
+			cmd.Printf("  git profile add <workspace> --remote \"github.com:<organization>/*\"\n")
+		}
+
+		return &ExitError{Code: ExitCodeNoMatch, Err: err}
+	}
+
+	if err == application.ErrProfileAmbiguous {
+		cmd.Println("More than one profile matches the remotes of the repository:")
+		if matches, err := c.autoProfileService.Match(); err == nil {
+			for _, match := range matches {
+				cmd.Printf("  %s\n", match.Workspace().String())
+			}
+		}
+
+		cmd.Printf("\nSuggest to set one of them with the following command:\n")
+		cmd.Printf("  git profile set <workspace>\n")
+
+		return &ExitError{Code: ExitCodeAmbiguous, Err: err}
+	}
+
+	if err != nil {
+		cmd.Printf("Failed to read the remotes of the repository: %v\n", err)
+		return &ExitError{Code: ExitCodeFailure, Err: err}
+	}
+
+	if !quiet {
+		cmd.Printf("Profile \"%s\" is now in use\n", profile.Workspace().String())
+		printProfile(cmd, profile)
+	}
+
+	return nil
+}
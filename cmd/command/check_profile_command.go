@@ -0,0 +1,240 @@
+package command
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+
+	"github.com/b4nd/git-profile/pkg/application"
+	"github.com/b4nd/git-profile/pkg/domain"
+
+	"github.com/spf13/cobra"
+)
+
+// checkPushRemote is the remote checked when --pre-push is run without arguments
+const checkPushRemote = "origin"
+
+type CheckProfileCommand struct {
+	checkProfileService     *application.CheckProfileService
+	checkPushProfileService *application.CheckPushProfileService
+}
+
+func NewCheckProfileCommand(
+	checkProfileService *application.CheckProfileService,
+	checkPushProfileService *application.CheckPushProfileService,
+) *CheckProfileCommand {
+	return &CheckProfileCommand{checkProfileService, checkPushProfileService}
+}
+
+func (c *CheckProfileCommand) Register(rootCmd *cobra.Command) {
+	var quiet bool
+	var prePush bool
+
+	cmd := &cobra.Command{
+		Use:   "check [--quiet] [--pre-push [remote [url]]]",
+		Short: "Checks that git commits with the identity of the configured profile.",
+		Long: `Check that a profile is configured, that it still exists and that the name and
+email git uses for a new commit are the ones stored in the profile.
+With --pre-push the refs are read from the standard input as git gives them to
+the pre-push hook, and the author and the committer of every commit the remote
+does not have yet are checked against the profile expected for the url of the
+remote: the only profile whose remote url pattern matches it, the configured one
+otherwise. The commits brought by cherry-picks and rebases are checked as well.
+The command exits with code 1 when the check fails, it is run by the hooks
+installed with "git profile hook install".
+`,
+		Example: `  git profile check
+  git profile check --quiet
+  git profile check --pre-push origin git@github.com:acme-corp/api.git < refs`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if prePush {
+				return cobra.MaximumNArgs(2)(cmd, args)
+			}
+
+			return cobra.NoArgs(cmd, args)
+		},
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if prePush {
+				params := application.CheckPushProfileServiceParams{Remote: checkPushRemote}
+				if len(args) > 0 {
+					params.Remote = args[0]
+				}
+
+				if len(args) > 1 {
+					params.Url = args[1]
+				}
+
+				return c.ExecutePrePush(cmd, params, quiet)
+			}
+
+			return c.Execute(cmd, quiet)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&quiet, "quiet", "q", false, "Only print the errors")
+	cmd.Flags().BoolVar(&prePush, "pre-push", false, "Check the commits pushed, read from the standard input")
+
+	rootCmd.AddCommand(cmd)
+}
+
+func (c *CheckProfileCommand) Execute(cmd *cobra.Command, quiet bool) error {
+	result, err := c.checkProfileService.Execute()
+
+	switch err {
+	case nil:
+		if !quiet {
+			cmd.Printf("Profile \"%s\" matches the git identity\n", result.Profile.Workspace().String())
+		}
+
+		return nil
+	case application.ErrProfileNotConfigured:
+		cmd.Printf("No profile is configured, suggest to set a profile with the following command:\n")
+		cmd.Printf("  git profile set\n")
+	case application.ErrProfileNotExists:
+		printErrorMessage(cmd, err, result.Identity.Workspace)
+		cmd.Printf("\nSuggest to set an existing profile with the following command:\n")
+		cmd.Printf("  git profile set\n")
+	case application.ErrProfileMismatch:
+		workspace := result.Profile.Workspace().String()
+		cmd.Printf("The git identity does not match the profile \"%s\":\n", workspace)
+		cmd.Printf("  Profile: %s <%s>\n", result.Profile.Name().String(), result.Profile.Email().String())
+		cmd.Printf("  Git:     %s <%s>\n", result.Identity.Name, result.Identity.Email)
+		cmd.Printf("\nSuggest to set the profile again with the following command:\n")
+		cmd.Printf("  git profile set %s\n", workspace)
+	default:
+		cmd.Printf("Failed to check the profile: %v\n", err)
+	}
+
+	return &ExitError{Code: ExitCodeFailure, Err: err}
+}
+
+func (c *CheckProfileCommand) ExecutePrePush(cmd *cobra.Command, params application.CheckPushProfileServiceParams, quiet bool) error {
+	updates, err := c.readPushUpdates(cmd)
+	if err != nil {
+		cmd.Printf("Failed to read the pushed refs: %v\n", err)
+		return &ExitError{Code: ExitCodeFailure, Err: err}
+	}
+
+	params.Updates = updates
+	target := params.Url
+	if target == "" {
+		target = params.Remote
+	}
+
+	result, err := c.checkPushProfileService.Execute(params)
+
+	switch err {
+	case nil:
+		if !quiet {
+			commits := 0
+			for _, ref := range result.Refs {
+				commits += ref.Commits
+			}
+
+			cmd.Printf("All %d pushed commits match the profile \"%s\"\n", commits, result.Profile.Workspace().String())
+		}
+
+		return nil
+	case application.ErrProfileNotConfigured:
+		cmd.Printf("No profile matches \"%s\" and no profile is configured, suggest to set a profile with the following command:\n", target)
+		cmd.Printf("  git profile set\n")
+	case application.ErrProfileNotExists:
+		cmd.Printf("No profile matches \"%s\" and the configured profile does not exist, suggest to set a profile with the following command:\n", target)
+		cmd.Printf("  git profile set\n")
+	case application.ErrProfileAmbiguous:
+		cmd.Printf("More than one profile matches \"%s\", suggest to set the expected one with the following command:\n", target)
+		cmd.Printf("  git profile set\n")
+	case application.ErrProfileMismatch:
+		c.printPushMismatch(cmd, result)
+	default:
+		cmd.Printf("Failed to check the pushed commits: %v\n", err)
+	}
+
+	return &ExitError{Code: ExitCodeFailure, Err: err}
+}
+
+// readPushUpdates reads the refs given by git to the pre-push hook, one per line
+func (c *CheckProfileCommand) readPushUpdates(cmd *cobra.Command) ([]*domain.ScmPushUpdate, error) {
+	updates := make([]*domain.ScmPushUpdate, 0)
+
+	scanner := bufio.NewScanner(cmd.InOrStdin())
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		update, err := domain.NewScmPushUpdate(line)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", err, line)
+		}
+
+		updates = append(updates, update)
+	}
+
+	return updates, scanner.Err()
+}
+
+func (c *CheckProfileCommand) printPushMismatch(cmd *cobra.Command, result *application.CheckPushProfileServiceResult) {
+	workspace := result.Profile.Workspace().String()
+	cmd.Printf("The pushed commits do not match the profile \"%s\" expected for \"%s\":\n", workspace, result.Url)
+	cmd.Printf("  Profile: %s <%s>\n", result.Profile.Name().String(), result.Profile.Email().String())
+
+	for _, ref := range result.Refs {
+		if len(ref.Violations) == 0 {
+			continue
+		}
+
+		cmd.Printf("\n  %s:\n", ref.Update.LocalRef)
+		for _, violation := range ref.Violations {
+			cmd.Printf("    %s %-9s %s <%s>\n", shortHash(violation.Hash.String()), violation.Role, violation.Name, violation.Email)
+		}
+	}
+
+	for _, ref := range result.Refs {
+		if len(ref.Violations) == 0 {
+			continue
+		}
+
+		// The commits pushed on top of a remote commit are amended at once
+		if ref.Base != nil {
+			cmd.Printf("\nSuggest to amend the commits of \"%s\" with the following command, once it is checked out:\n", ref.Update.LocalRef)
+			cmd.Printf("  git profile amend %s..HEAD -w %s%s\n", shortHash(ref.Base.String()), workspace, c.committerOnlyFlag(ref.Violations))
+			continue
+		}
+
+		// Otherwise the range could reach the root commit, every commit is amended
+		// by its hash, the children first so the hashes of their parents are kept
+		hashes := []string{}
+		violations := map[string][]*application.VerifyViolation{}
+		for _, violation := range ref.Violations {
+			hash := violation.Hash.String()
+			if _, ok := violations[hash]; !ok {
+				hashes = append(hashes, hash)
+			}
+
+			violations[hash] = append(violations[hash], violation)
+		}
+
+		cmd.Printf("\nSuggest to amend the commits of \"%s\" with the following commands in order, once it is checked out:\n", ref.Update.LocalRef)
+		for _, hash := range hashes {
+			cmd.Printf("  git profile amend %s -w %s%s\n", shortHash(hash), workspace, c.committerOnlyFlag(violations[hash]))
+		}
+	}
+
+	cmd.Printf("\nThe check can be skipped once with \"git push --no-verify\"\n")
+}
+
+// committerOnlyFlag returns the --committer-only flag when only the committers
+// of the commits are not the profile
+func (c *CheckProfileCommand) committerOnlyFlag(violations []*application.VerifyViolation) string {
+	for _, violation := range violations {
+		if violation.Role != application.VerifyRoleCommitter {
+			return ""
+		}
+	}
+
+	return " --committer-only"
+}
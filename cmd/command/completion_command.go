@@ -0,0 +1,203 @@
+package command
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/b4nd/git-profile/pkg/application"
+
+	"github.com/spf13/cobra"
+)
+
+// Shells of the completion scripts
+const (
+	CompletionShellBash       = "bash"
+	CompletionShellZsh        = "zsh"
+	CompletionShellFish       = "fish"
+	CompletionShellPowerShell = "powershell"
+)
+
+// completionProgramName is the executable git runs for "git profile", the
+// scripts ask it for the completions
+const completionProgramName = "git-profile"
+
+// completionReplacements make the scripts generated by cobra for git-profile
+// also complete "git profile", the first argument of the command line is git
+var completionReplacements = map[string][2]string{
+	CompletionShellZsh: {
+		`requestComp="${words[1]} __complete ${words[2,-1]}"`,
+		`requestComp="git-profile __complete ${words[2,-1]}"`,
+	},
+	CompletionShellFish: {
+		`    set -l args (commandline -opc)
+`,
+		`    set -l args (commandline -opc)
+    if test "$args[1]" = git
+        set -l index (contains -i -- profile $args)
+        and set -e args[1..$index]
+        and set -p args git-profile
+    end
+`,
+	},
+	CompletionShellPowerShell: {
+		`    $Program,$Arguments = $Command.Split(" ",2)
+`,
+		`    $Program,$Arguments = $Command.Split(" ",2)
+    if ($Program -eq "git") {
+        $Subcommand,$Arguments = "$Arguments".Split(" ",2)
+        $Program = "git-profile"
+    }
+`,
+	},
+}
+
+// completionGitScripts register the completions for "git profile" in the
+// completion of git, which completes the subcommands in the same way
+var completionGitScripts = map[string]string{
+	CompletionShellBash: `
+# git profile is completed by the git completion through _git_profile
+_git_profile()
+{
+    local index=1
+    while [[ $index -lt $cword && ${words[index]} != profile ]]; do
+        ((index++))
+    done
+
+    local -a gitprofile_words=(git-profile "${words[@]:index+1:cword-index}")
+    local gitprofile_cword=$((cword - index))
+    local words=("${gitprofile_words[@]}") cword=$gitprofile_cword
+
+    local out directive
+    __git-profile_get_completion_results
+    __git-profile_process_completion_results
+}
+`,
+	CompletionShellFish: `
+# git profile is completed as git-profile
+complete -c git -n '__fish_seen_subcommand_from profile; and __git_profile_clear_perform_completion_once_result'
+complete -c git -n '__fish_seen_subcommand_from profile; and __git_profile_prepare_completions' -f -a '$__git_profile_comp_results'
+`,
+	CompletionShellPowerShell: `
+# git profile is completed as git-profile, the other git commands are not completed
+Register-ArgumentCompleter -Native -CommandName 'git' -ScriptBlock {
+    param($WordToComplete, $CommandAst, $CursorPosition)
+
+    if ($CommandAst.CommandElements.Count -gt 1 -and "$($CommandAst.CommandElements[1])" -eq "profile") {
+        & ${__git_profileCompleterBlock} $WordToComplete $CommandAst $CursorPosition
+    }
+}
+`,
+}
+
+type CompletionCommand struct{}
+
+func NewCompletionCommand() *CompletionCommand {
+	return &CompletionCommand{}
+}
+
+func (c *CompletionCommand) Register(rootCmd *cobra.Command) {
+	// The default completion command generates scripts for the git program
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
+
+	cmd := &cobra.Command{
+		Use:   "completion <bash|zsh|fish|powershell>",
+		Short: "Generates the completion script for the shell.",
+		Long: `Generate the completion script for the shell, it completes both git-profile and
+"git profile" with the commands, the flags and the workspaces of the profiles.
+The completion of git must be loaded for "git profile" in bash, zsh and fish.
+`,
+		Example: `  source <(git profile completion bash)
+  git profile completion zsh > "${fpath[1]}/_git-profile"
+  git profile completion fish > ~/.config/fish/completions/git-profile.fish
+  git profile completion powershell | Out-String | Invoke-Expression`,
+		ValidArgs:             []string{CompletionShellBash, CompletionShellZsh, CompletionShellFish, CompletionShellPowerShell},
+		Args:                  cobra.ExactArgs(1),
+		DisableFlagsInUseLine: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return c.Execute(cmd, args[0])
+		},
+	}
+
+	rootCmd.AddCommand(cmd)
+}
+
+func (c *CompletionCommand) Execute(cmd *cobra.Command, shell string) error {
+	// The scripts are generated for the program git runs, named after the root command
+	rootCmd := cmd.Root()
+	use := rootCmd.Use
+	rootCmd.Use = completionProgramName
+	defer func() { rootCmd.Use = use }()
+
+	var script bytes.Buffer
+	var err error
+
+	switch shell {
+	case CompletionShellBash:
+		err = rootCmd.GenBashCompletionV2(&script, true)
+	case CompletionShellZsh:
+		err = rootCmd.GenZshCompletion(&script)
+	case CompletionShellFish:
+		err = rootCmd.GenFishCompletion(&script, true)
+	case CompletionShellPowerShell:
+		err = rootCmd.GenPowerShellCompletionWithDesc(&script)
+	default:
+		cmd.Printf("The shell must be bash, zsh, fish or powershell.\n")
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	content := script.String()
+	if replacement, ok := completionReplacements[shell]; ok {
+		content = strings.Replace(content, replacement[0], replacement[1], 1)
+	}
+
+	_, err = fmt.Fprint(cmd.OutOrStdout(), content+completionGitScripts[shell])
+	return err
+}
+
+// completeWorkspaces returns a completion of the workspaces of the profiles,
+// described by their name and email. The workspaces are completed for the
+// arguments up to maxArgs, for all of them when it is negative, and the
+// workspaces already given are not completed again.
+func completeWorkspaces(listProfileService *application.ListProfileService, maxArgs int) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if maxArgs >= 0 && len(args) >= maxArgs {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		profiles, err := listProfileService.Execute()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		completions := make([]string, 0, len(profiles))
+		for _, profile := range profiles {
+			workspace := profile.Workspace().String()
+			if !strings.HasPrefix(workspace, toComplete) || slices.Contains(args, workspace) {
+				continue
+			}
+
+			completions = append(completions, fmt.Sprintf("%s\t%s <%s>", workspace, profile.Name().String(), profile.Email().String()))
+		}
+
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// registerWorkspaceCompletion completes the workspaces for the --workspace
+// flag of the command and for its first maxArgs arguments
+func registerWorkspaceCompletion(cmd *cobra.Command, listProfileService *application.ListProfileService, maxArgs int) {
+	if maxArgs != 0 {
+		cmd.ValidArgsFunction = completeWorkspaces(listProfileService, maxArgs)
+	}
+
+	if cmd.Flags().Lookup("workspace") != nil {
+		// nolint
+		cmd.RegisterFlagCompletionFunc("workspace", completeWorkspaces(listProfileService, -1)) // #nosec G104
+	}
+}
@@ -2,9 +2,13 @@ package command
 
 import (
 	"bufio"
+	"os"
+	"path/filepath"
+	"runtime"
 	"strings"
 
 	"github.com/b4nd/git-profile/pkg/application"
+	"github.com/b4nd/git-profile/pkg/domain"
 
 	"github.com/spf13/cobra"
 )
@@ -28,19 +32,32 @@ func NewCreateProfileCommand(
 }
 
 type CreateProfileCommandParams struct {
-	Workspace string
-	Email     string
-	Name      string
+	Workspace     string
+	Email         string
+	Name          string
+	SigningKey    string
+	SigningFormat string
+	CommitSign    bool
+	TagSign       bool
+	NoSigning     bool
+	SshKey        string
+	NoSshKey      bool
+	Remotes       []string
+	NoRemotes     bool
+	Usernames     []string
+	NoUsernames   bool
+	Config        []string
+	NoConfig      bool
+	Extends       string
+	NoExtends     bool
 }
 
 func (c *CreateProfileCommand) Register(rootCmd *cobra.Command) {
-	var workspace string
-	var email string
-	var name string
+	var params CreateProfileCommandParams
 	var force bool
 
 	cmd := &cobra.Command{
-		Use: "add [-w workspace] [-e email] [-n name] [--force]",
+		Use: "add [-w workspace] [-e email] [-n name] [--signing-key key] [--force]",
 		Aliases: []string{
 			"create",
 		},
@@ -51,41 +68,67 @@ If no arguments are provided, the command will prompt for the missing values.
 		Example: `  git profile add
   git profile add work
   git profile add --workspace work --email email@example.com --name "Firstname Lastname"
-  git profile add -w work -e email@example.com -n "Firstname Lastname"`,
+  git profile add -w work -e email@example.com -n "Firstname Lastname"
+  git profile add -w work --signing-key ~/.ssh/id_ed25519.pub --signing-format ssh --sign-commits
+  git profile add -w work --ssh-key ~/.ssh/id_ed25519_work
+  git profile add -w work --remote "github.com:acme-corp/*" --remote "gitlab.internal/*"
+  git profile add -w work --username github.com=jane-acme
+  git profile add -w work --config pull.rebase=true --config init.defaultBranch=main
+  git profile add -w acme-client --extends acme -e jane@client.com
+  git profile add -w acme-client --no-extends --force`,
 		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if workspace == "" && len(args) > 0 {
-				workspace = args[0]
+			if params.Workspace == "" && len(args) > 0 {
+				params.Workspace = args[0]
 			}
 
-			return c.Execute(cmd, workspace, email, name, force)
+			return c.Execute(cmd, params, force)
 		},
 	}
 
-	cmd.Flags().StringVarP(&workspace, "workspace", "w", "", "The workspace of the profile")
-	cmd.Flags().StringVarP(&email, "email", "e", "", "The email of the profile")
-	cmd.Flags().StringVarP(&name, "name", "n", "", "The name of the profile")
+	cmd.Flags().StringVarP(&params.Workspace, "workspace", "w", "", "The workspace of the profile")
+	cmd.Flags().StringVarP(&params.Email, "email", "e", "", "The email of the profile")
+	cmd.Flags().StringVarP(&params.Name, "name", "n", "", "The name of the profile")
+	cmd.Flags().StringVar(&params.SigningKey, "signing-key", "", "The key used to sign commits and tags (user.signingkey)")
+	cmd.Flags().StringVar(&params.SigningFormat, "signing-format", "", "The format of the signing key: openpgp, ssh or x509 (gpg.format)")
+	cmd.Flags().BoolVar(&params.CommitSign, "sign-commits", false, "Sign all commits (commit.gpgsign)")
+	cmd.Flags().BoolVar(&params.TagSign, "sign-tags", false, "Sign all tags (tag.gpgsign)")
+	cmd.Flags().BoolVar(&params.NoSigning, "no-signing", false, "Remove the signing configuration of the profile")
+	cmd.Flags().StringVar(&params.SshKey, "ssh-key", "", "The ssh identity file used to push and pull (core.sshCommand)")
+	cmd.Flags().BoolVar(&params.NoSshKey, "no-ssh-key", false, "Remove the ssh identity file of the profile")
+	cmd.Flags().StringArrayVar(&params.Remotes, "remote", nil, "A remote url pattern that selects the profile with git profile auto")
+	cmd.Flags().BoolVar(&params.NoRemotes, "no-remotes", false, "Remove the remote url patterns of the profile")
+	cmd.Flags().StringArrayVar(&params.Usernames, "username", nil, "The host=username the profile authenticates with, used by git profile credential")
+	cmd.Flags().BoolVar(&params.NoUsernames, "no-usernames", false, "Remove the usernames of the profile")
+	cmd.Flags().StringArrayVar(&params.Config, "config", nil, "An extra git config key=value applied with the profile, an empty value removes the key")
+	cmd.Flags().BoolVar(&params.NoConfig, "no-config", false, "Remove the extra git config keys of the profile")
+	cmd.Flags().StringVar(&params.Extends, "extends", "", "The workspace of the profile whose fields are inherited")
+	cmd.Flags().BoolVar(&params.NoExtends, "no-extends", false, "Stop inheriting, the inherited fields are kept as the fields of the profile")
 	cmd.Flags().BoolVar(&force, "force", false, "Force the update of an existing profile")
 
 	rootCmd.AddCommand(cmd)
 }
 
-func (c *CreateProfileCommand) Execute(cmd *cobra.Command, workspace string, email string, name string, force bool) error {
+func (c *CreateProfileCommand) Execute(cmd *cobra.Command, params CreateProfileCommandParams, force bool) error {
 	reader := bufio.NewReader(cmd.InOrStdin())
 
-	params := CreateProfileCommandParams{
-		Workspace: workspace,
-		Email:     email,
-		Name:      name,
-	}
+	// The signing configuration is only prompted when the profile itself is prompted
+	interactive := params.Workspace == "" || params.Email == "" || params.Name == ""
+	promptSigning := interactive && params.SigningKey == "" && !params.NoSigning
+	promptSshKey := interactive && params.SshKey == "" && !params.NoSshKey
+
+	email, name := params.Email, params.Name
 
-	if workspace == "" {
+	if params.Workspace == "" {
 		cmd.Print("Enter workspace: ")
 		input, _ := reader.ReadString('\n')
 		params.Workspace = strings.TrimSpace(input)
 	}
 
 	updateProfile, params := c.checkAndUpdateProfile(cmd, reader, params, force)
+	if !updateProfile {
+		params = c.inheritProfile(params)
+	}
 
 	if email == "" {
 		cmd.Print("Enter email [" + params.Email + "]: ")
@@ -105,18 +148,78 @@ func (c *CreateProfileCommand) Execute(cmd *cobra.Command, workspace string, ema
 		}
 	}
 
+	if promptSigning {
+		params = c.promptSigning(cmd, reader, params)
+	}
+
+	if promptSshKey {
+		cmd.Print("Enter SSH key (optional) [" + params.SshKey + "]: ")
+		input, _ := reader.ReadString('\n')
+		input = strings.TrimSpace(input)
+		if input != "" {
+			params.SshKey = input
+		}
+	}
+
+	if params.NoSigning {
+		params.SigningKey = ""
+	}
+
+	if params.NoSshKey {
+		params.SshKey = ""
+	}
+
+	if params.NoRemotes {
+		params.Remotes = nil
+	}
+
+	if params.NoUsernames {
+		params.Usernames = nil
+	}
+
+	if params.NoConfig {
+		params.Config = nil
+	}
+
+	if params.NoExtends {
+		params.Extends = ""
+	}
+
+	usernames, err := newUsernameValues(params.Usernames)
+	if err != nil {
+		printErrorMessage(cmd, err, "")
+		return nil
+	}
+
+	config, err := newConfigValues(params.Config)
+	if err != nil {
+		printErrorMessage(cmd, err, "")
+		return nil
+	}
+
 	if updateProfile {
 		profile, err := c.updateProfileService.Execute(application.UpdateProfileServiceParams{
-			Workspace: params.Workspace,
-			Email:     params.Email,
-			Name:      params.Name,
+			Workspace:     params.Workspace,
+			Extends:       params.Extends,
+			Email:         params.Email,
+			Name:          params.Name,
+			SigningKey:    params.SigningKey,
+			SigningFormat: params.SigningFormat,
+			CommitSign:    params.CommitSign,
+			TagSign:       params.TagSign,
+			SshKey:        params.SshKey,
+			Remotes:       params.Remotes,
+			Usernames:     usernames,
+			Config:        config,
 		})
 
 		if err != nil {
-			cmd.Printf(errorMessages[err], params.Workspace)
+			printErrorMessage(cmd, err, params.Workspace)
 			return nil
 		}
 
+		warnSshKeyFile(cmd, params.SshKey)
+
 		cmd.Printf("Profile \"%s\" updated successfully", profile.Workspace().String())
 		cmd.Printf("\nSuggest to set the updated profile with the following command:\n")
 		cmd.Printf("  git profile set %s\n", params.Workspace)
@@ -125,16 +228,27 @@ func (c *CreateProfileCommand) Execute(cmd *cobra.Command, workspace string, ema
 	}
 
 	profile, err := c.createProfileService.Execute(application.CreateProfileServiceParams{
-		Workspace: params.Workspace,
-		Email:     params.Email,
-		Name:      params.Name,
+		Workspace:     params.Workspace,
+		Extends:       params.Extends,
+		Email:         params.Email,
+		Name:          params.Name,
+		SigningKey:    params.SigningKey,
+		SigningFormat: params.SigningFormat,
+		CommitSign:    params.CommitSign,
+		TagSign:       params.TagSign,
+		SshKey:        params.SshKey,
+		Remotes:       params.Remotes,
+		Usernames:     usernames,
+		Config:        config,
 	})
 
 	if err != nil {
-		cmd.Printf(errorMessages[err], params.Workspace)
+		printErrorMessage(cmd, err, params.Workspace)
 		return nil
 	}
 
+	warnSshKeyFile(cmd, params.SshKey)
+
 	cmd.Printf("Profile \"%s\" created successfully", profile.Workspace().String())
 	cmd.Printf("\nSuggest to set the new profile with the following command:\n")
 	cmd.Printf("  git profile set %s\n", params.Workspace)
@@ -168,5 +282,194 @@ func (c *CreateProfileCommand) checkAndUpdateProfile(cmd *cobra.Command, reader
 		params.Name = profile.Name().String()
 	}
 
+	// The profile keeps extending the stored one unless --extends or --no-extends is given
+	if params.Extends == "" {
+		params.Extends = profile.Extends().String()
+	}
+
+	// The stored signing configuration is kept, except for the flags given explicitly
+	if signing := profile.Signing(); params.SigningKey == "" && !signing.IsEmpty() {
+		params.SigningKey = signing.Key()
+		if !cmd.Flags().Changed("signing-format") {
+			params.SigningFormat = signing.Format()
+		}
+
+		if !cmd.Flags().Changed("sign-commits") {
+			params.CommitSign = signing.CommitSign()
+		}
+
+		if !cmd.Flags().Changed("sign-tags") {
+			params.TagSign = signing.TagSign()
+		}
+	}
+
+	if sshKey := profile.SshKey(); params.SshKey == "" && !sshKey.IsEmpty() {
+		params.SshKey = sshKey.String()
+	}
+
+	// New remote url patterns are added to the existing ones
+	remotes := make([]string, 0, len(profile.Remotes())+len(params.Remotes))
+	for _, remote := range profile.Remotes() {
+		remotes = append(remotes, remote.String())
+	}
+	params.Remotes = append(remotes, params.Remotes...)
+
+	// New usernames are added to the existing ones or replace the one of their host
+	usernames := make([]string, 0, len(profile.Usernames())+len(params.Usernames))
+	for _, username := range profile.Usernames() {
+		usernames = append(usernames, username.String())
+	}
+	params.Usernames = append(usernames, params.Usernames...)
+
+	// New git config keys are added to the existing ones or replace them
+	config := make([]string, 0, len(profile.Config().Entries())+len(params.Config))
+	for _, entry := range profile.Config().Entries() {
+		config = append(config, entry.Key()+"="+entry.Value())
+	}
+	params.Config = append(config, params.Config...)
+
 	return true, params
 }
+
+// inheritProfile proposes the email and the name of the extended profile to a
+// new profile, the fields equal to the extended ones are left inherited
+func (c *CreateProfileCommand) inheritProfile(params CreateProfileCommandParams) CreateProfileCommandParams {
+	if params.Extends == "" || params.NoExtends {
+		return params
+	}
+
+	parent, err := c.getProfileService.Execute(application.GetProfileServiceParams{Workspace: params.Extends})
+	if err != nil {
+		return params
+	}
+
+	if params.Email == "" {
+		params.Email = parent.Email().String()
+	}
+
+	if params.Name == "" {
+		params.Name = parent.Name().String()
+	}
+
+	return params
+}
+
+// newUsernameValues parses the host=username flags, the last username of a host wins
+func newUsernameValues(values []string) (map[string]string, error) {
+	usernames := make(map[string]string, len(values))
+	for _, value := range values {
+		host, username, found := strings.Cut(value, "=")
+		if !found {
+			return nil, domain.ErrInvalidUsername
+		}
+
+		usernames[strings.ToLower(strings.TrimSpace(host))] = username
+	}
+
+	return usernames, nil
+}
+
+// newConfigValues parses the key=value git config flags, the last value of a key
+// wins and an empty value removes the key
+func newConfigValues(values []string) (map[string]string, error) {
+	config := make(map[string]string, len(values))
+	for _, value := range values {
+		key, value, found := strings.Cut(value, "=")
+		if !found {
+			return nil, domain.ErrInvalidConfigKey
+		}
+
+		key, err := domain.NormalizeConfigKey(key)
+		if err != nil {
+			return nil, err
+		}
+
+		if value == "" {
+			delete(config, key)
+			continue
+		}
+
+		config[key] = value
+	}
+
+	return config, nil
+}
+
+// promptSigning asks for the optional signing configuration, the current values are used as defaults
+func (c *CreateProfileCommand) promptSigning(cmd *cobra.Command, reader *bufio.Reader, params CreateProfileCommandParams) CreateProfileCommandParams {
+	cmd.Print("Enter signing key (optional) [" + params.SigningKey + "]: ")
+	input, _ := reader.ReadString('\n')
+	input = strings.TrimSpace(input)
+	if input != "" {
+		params.SigningKey = input
+	}
+
+	if params.SigningKey == "" {
+		return params
+	}
+
+	format := params.SigningFormat
+	if format == "" {
+		format = domain.SigningFormatOpenPGP
+	}
+
+	cmd.Print("Enter signing format (openpgp, ssh, x509) [" + format + "]: ")
+	input, _ = reader.ReadString('\n')
+	input = strings.TrimSpace(input)
+	if input != "" {
+		format = input
+	}
+	params.SigningFormat = format
+
+	params.CommitSign = promptYesNo(cmd, reader, "Sign commits?", params.CommitSign)
+	params.TagSign = promptYesNo(cmd, reader, "Sign tags?", params.TagSign)
+
+	return params
+}
+
+// promptYesNo asks a yes/no question, an empty answer keeps the default value
+func promptYesNo(cmd *cobra.Command, reader *bufio.Reader, question string, defaultValue bool) bool {
+	options := "y/N"
+	if defaultValue {
+		options = "Y/n"
+	}
+
+	cmd.Printf("%s (%s): ", question, options)
+	answer, _ := reader.ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+
+	switch answer {
+	case "y", "yes":
+		return true
+	case "n", "no":
+		return false
+	default:
+		return defaultValue
+	}
+}
+
+// warnSshKeyFile warns when the ssh identity file does not exist or can be read by other users,
+// ssh refuses to use private keys with loose permissions
+func warnSshKeyFile(cmd *cobra.Command, sshKey string) {
+	if sshKey == "" {
+		return
+	}
+
+	path := sshKey
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, path[1:])
+		}
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		cmd.Printf("Warning: the SSH key \"%s\" does not exist\n", sshKey)
+		return
+	}
+
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0077 != 0 {
+		cmd.Printf("Warning: the SSH key \"%s\" permissions %#o are too open, suggest to run:\n", sshKey, info.Mode().Perm())
+		cmd.Printf("  chmod 600 %s\n", sshKey)
+	}
+}
@@ -0,0 +1,124 @@
+package command
+
+import (
+	"bufio"
+	"strings"
+
+	"github.com/b4nd/git-profile/pkg/application"
+	"github.com/b4nd/git-profile/pkg/domain"
+
+	"github.com/spf13/cobra"
+)
+
+// Operations of the git credential helper protocol
+const (
+	CredentialOperationGet   = "get"
+	CredentialOperationStore = "store"
+	CredentialOperationErase = "erase"
+)
+
+// defaultCredentialHelper stores the secrets in memory for a while, see git-credential-cache
+const defaultCredentialHelper = "cache"
+
+type CredentialCommand struct {
+	getCredentialService   *application.GetCredentialService
+	storeCredentialService *application.StoreCredentialService
+}
+
+func NewCredentialCommand(
+	getCredentialService *application.GetCredentialService,
+	storeCredentialService *application.StoreCredentialService,
+) *CredentialCommand {
+	return &CredentialCommand{getCredentialService, storeCredentialService}
+}
+
+func (c *CredentialCommand) Register(rootCmd *cobra.Command) {
+	var helper string
+
+	cmd := &cobra.Command{
+		Use:   "credential [--helper helper] <get|store|erase>",
+		Short: "Acts as a git credential helper using the usernames of the profile.",
+		Long: `Implement the git credential helper protocol: the attributes of the credential
+are read from the standard input and the answer is written to the standard output.
+"get" answers with the username the active profile uses on the host, the secrets
+are stored in the helper given with --helper, "cache" by default.
+The helper is registered in the repository with "git profile set <workspace> --credential-helper <helper>".
+`,
+		Example: `  git config credential.helper "!git profile credential --helper osxkeychain"
+  printf "protocol=https\nhost=github.com\n\n" | git profile credential get`,
+		Args:          cobra.ExactArgs(1),
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return c.Execute(cmd, args[0], helper)
+		},
+	}
+
+	cmd.Flags().StringVar(&helper, "helper", defaultCredentialHelper, "The git credential helper storing the secrets, none when empty")
+
+	rootCmd.AddCommand(cmd)
+}
+
+func (c *CredentialCommand) Execute(cmd *cobra.Command, operation string, helper string) error {
+	credential, err := readCredential(cmd)
+	if err != nil {
+		printErrorMessage(cmd, err, "")
+		return nil
+	}
+
+	switch operation {
+	case CredentialOperationGet:
+		credential, err = c.getCredentialService.Execute(application.GetCredentialServiceParams{
+			Credential: credential,
+			Helper:     helper,
+		})
+
+		if err == nil {
+			_, err = cmd.OutOrStdout().Write([]byte(credential.String()))
+		}
+	case CredentialOperationStore, CredentialOperationErase:
+		err = c.storeCredentialService.Execute(application.StoreCredentialServiceParams{
+			Credential: credential,
+			Helper:     helper,
+			Erase:      operation == CredentialOperationErase,
+		})
+	default:
+		// The operations a helper does not know are ignored, as git expects
+	}
+
+	if err != nil {
+		cmd.Printf("The credential helper \"%s\" failed: %v\n", helper, err)
+	}
+
+	return nil
+}
+
+// readCredential reads the attributes git writes up to the empty line that ends them
+func readCredential(cmd *cobra.Command) (*domain.ScmCredential, error) {
+	var input strings.Builder
+
+	scanner := bufio.NewScanner(cmd.InOrStdin())
+	for scanner.Scan() {
+		if scanner.Text() == "" {
+			break
+		}
+
+		input.WriteString(scanner.Text() + "\n")
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, domain.ErrInvalidScmCredential
+	}
+
+	return domain.NewScmCredential(input.String())
+}
+
+// credentialHelperCommand returns the credential.helper value running git
+// profile credential with the given helper storing the secrets
+func credentialHelperCommand(helper string) string {
+	if strings.ContainsAny(helper, " \t") {
+		helper = "'" + helper + "'"
+	}
+
+	return "!git profile credential --helper " + helper
+}
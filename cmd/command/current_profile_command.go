@@ -27,10 +27,12 @@ func (c *CurrentProfileCommand) Register(rootCmd *cobra.Command) {
 	var global bool
 
 	cmd := &cobra.Command{
-		Use:     "current [--verbose] [--global]",
-		Short:   "Displays the currently active profile",
-		Example: `  git profile current`,
-		Args:    cobra.NoArgs,
+		Use:   "current [--verbose] [--global]",
+		Short: "Displays the currently active profile",
+		Example: `  git profile current
+  git profile current --output json
+  git profile current --output template='{{.Email}}'`,
+		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return c.Execute(cmd, verbose, global)
 		},
@@ -39,25 +41,45 @@ func (c *CurrentProfileCommand) Register(rootCmd *cobra.Command) {
 	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Display the profile in verbose mode")
 	cmd.Flags().BoolVarP(&global, "global", "g", false, "Display the global profile (default: false)")
 
+	supportOutput(cmd)
 	rootCmd.AddCommand(cmd)
 }
 
 func (c *CurrentProfileCommand) Execute(cmd *cobra.Command, verbose bool, global bool) error {
+	output, err := newOutput(cmd)
+	if err != nil {
+		return err
+	}
+
 	service := c.currentProfileService
 	if global {
 		service = c.currentProfileGlobalService
 	}
 
 	profile, err := service.Execute()
+	if err == domain.ErrScmRepositoryNotFound {
+		printErrorMessage(cmd, err, "")
+		return nil
+	}
+
 	if err != nil {
 		cmd.Println("Profile not found")
 		return nil
 	}
 
+	if !output.IsTable() {
+		value := newProfileOutput(profile, true)
+
+		// The identity is not configured with a profile
+		if profile.Workspace().String() == domain.NotConfiguredWorkspace {
+			value.Workspace = ""
+		}
+
+		return output.Print(cmd, value)
+	}
+
 	if verbose {
-		cmd.Printf("Workspace: %s\n", profile.Workspace().String())
-		cmd.Printf("Email: %s\n", profile.Email().String())
-		cmd.Printf("Name: %s\n", profile.Name().String())
+		printProfile(cmd, profile)
 		return nil
 	}
 
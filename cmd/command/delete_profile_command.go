@@ -12,15 +12,22 @@ import (
 type DeleteProfileCommand struct {
 	getProfileService    *application.GetProfileService
 	deleteProfileService *application.DeleteProfileService
+	listProfileService   *application.ListProfileService
+	// The current profile is highlighted in the picker
+	currentProfileService *application.CurrentProfileService
 }
 
 func NewDeleteProfileCommand(
 	getProfileService *application.GetProfileService,
 	createProfileService *application.DeleteProfileService,
+	listProfileService *application.ListProfileService,
+	currentProfileService *application.CurrentProfileService,
 ) *DeleteProfileCommand {
 	return &DeleteProfileCommand{
 		getProfileService,
 		createProfileService,
+		listProfileService,
+		currentProfileService,
 	}
 }
 
@@ -56,6 +63,8 @@ If no arguments are provided, the command will prompt for the missing values.
 
 	cmd.Flags().StringVarP(&workspace, "workspace", "w", "", "The workspace of the profile")
 
+	registerWorkspaceCompletion(cmd, c.listProfileService, 1)
+
 	rootCmd.AddCommand(cmd)
 }
 
@@ -66,18 +75,44 @@ func (c *DeleteProfileCommand) Execute(cmd *cobra.Command, workspace string) err
 		Workspace: workspace,
 	}
 
+	if workspace == "" {
+		picked, err := pickWorkspace(cmd, c.listProfileService, c.currentProfileService)
+		if err != ErrPickerNotTerminal {
+			if err != nil {
+				printErrorMessage(cmd, err, "")
+				return nil
+			}
+
+			params.Workspace, workspace = picked, picked
+		}
+	}
+
 	if workspace == "" {
 		cmd.Print("Enter workspace: ")
 		input, _ := reader.ReadString('\n')
 		params.Workspace = strings.TrimSpace(input)
 	}
 
-	err := c.deleteProfileService.Execute(application.DeleteProfileServiceParams{
+	result, err := c.deleteProfileService.Execute(application.DeleteProfileServiceParams{
 		Workspace: params.Workspace,
 	})
 
+	if err == application.ErrProfileExtended {
+		children := make([]string, 0, len(result.Children))
+		for _, child := range result.Children {
+			children = append(children, child.Workspace().String())
+		}
+
+		cmd.Printf("Profile \"%s\" is extended by the profiles %s, suggest to delete them or remove their extends first:\n", params.Workspace, strings.Join(children, ", "))
+		for _, child := range children {
+			cmd.Printf("  git profile add %s --no-extends --force\n", child)
+		}
+
+		return nil
+	}
+
 	if err != nil {
-		cmd.Printf(errorMessages[err], params.Workspace)
+		printErrorMessage(cmd, err, params.Workspace)
 		return nil
 	}
 
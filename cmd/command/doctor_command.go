@@ -0,0 +1,180 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/b4nd/git-profile/pkg/application"
+
+	"github.com/spf13/cobra"
+)
+
+type DoctorCommand struct {
+	doctorProfileService *application.DoctorProfileService
+}
+
+// findingOutput is the documented schema of a finding in the structured outputs
+type findingOutput struct {
+	Check    string   `json:"check" yaml:"check"`
+	Severity string   `json:"severity" yaml:"severity"`
+	Message  string   `json:"message" yaml:"message"`
+	Subject  string   `json:"subject,omitempty" yaml:"subject,omitempty"`
+	Scope    string   `json:"scope,omitempty" yaml:"scope,omitempty"`
+	Paths    []string `json:"paths,omitempty" yaml:"paths,omitempty"`
+	Fixable  bool     `json:"fixable" yaml:"fixable"`
+	Fixed    bool     `json:"fixed" yaml:"fixed"`
+}
+
+func NewDoctorCommand(doctorProfileService *application.DoctorProfileService) *DoctorCommand {
+	return &DoctorCommand{doctorProfileService}
+}
+
+func (c *DoctorCommand) Register(rootCmd *cobra.Command) {
+	var fix bool
+
+	cmd := &cobra.Command{
+		Use:   "doctor [--fix]",
+		Short: "Diagnoses the profiles and the git configuration.",
+		Long: `Diagnose the profiles and the git configuration:
+  - git is installed and recent enough for every feature
+  - every profile file parses and its profiles resolve
+  - no profile is defined twice in a file or shadowed by another file
+  - the identity of the repository and of the global configuration matches its profile
+  - the workspace of the git configuration names an existing profile
+  - no GIT_AUTHOR_* or GIT_COMMITTER_* variable overrides the identity
+  - no includeIf condition includes different files
+Each finding has a severity, error or warning. With --fix the identities that do
+not match their profile are set again and the workspaces of deleted profiles are
+removed. The command exits with code 1 when an error is left.
+`,
+		Example: `  git profile doctor
+  git profile doctor --fix
+  git profile doctor -o json`,
+		Args:          cobra.NoArgs,
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return c.Execute(cmd, application.DoctorProfileServiceParams{Fix: fix})
+		},
+	}
+
+	cmd.Flags().BoolVar(&fix, "fix", false, "Fix the findings that can be fixed")
+
+	supportOutput(cmd)
+	rootCmd.AddCommand(cmd)
+}
+
+func (c *DoctorCommand) Execute(cmd *cobra.Command, params application.DoctorProfileServiceParams) error {
+	output, err := newOutput(cmd)
+	if err != nil {
+		return err
+	}
+
+	findings, err := c.doctorProfileService.Execute(params)
+	if err != nil {
+		cmd.Printf("Failed to diagnose the configuration: %v\n", err)
+		return &ExitError{Code: ExitCodeFailure, Err: err}
+	}
+
+	failed := false
+	for _, finding := range findings {
+		if finding.Severity == application.DoctorSeverityError && !finding.Fixed {
+			failed = true
+		}
+	}
+
+	if !output.IsTable() {
+		values := make([]findingOutput, 0, len(findings))
+		for _, finding := range findings {
+			values = append(values, findingOutput{
+				Check:    string(finding.Check),
+				Severity: string(finding.Severity),
+				Message:  findingMessage(finding),
+				Subject:  finding.Subject,
+				Scope:    string(finding.Scope),
+				Paths:    finding.Paths,
+				Fixable:  finding.Fixable,
+				Fixed:    finding.Fixed,
+			})
+		}
+
+		if err := output.Print(cmd, values); err != nil {
+			return err
+		}
+	} else {
+		c.printFindings(cmd, findings)
+	}
+
+	if failed {
+		return &ExitError{Code: ExitCodeFailure}
+	}
+
+	return nil
+}
+
+func (c *DoctorCommand) printFindings(cmd *cobra.Command, findings []*application.DoctorFinding) {
+	if len(findings) == 0 {
+		cmd.Println("No problems found")
+		return
+	}
+
+	fixable := 0
+	for _, finding := range findings {
+		severity := colorize(cmd, colorYellow, string(finding.Severity))
+		if finding.Severity == application.DoctorSeverityError {
+			severity = colorize(cmd, colorRed, string(finding.Severity))
+		}
+
+		suffix := ""
+		if finding.Fixed {
+			suffix = " " + colorize(cmd, colorGreen, "(fixed)")
+		} else if finding.Fixable {
+			suffix = " (fixable)"
+			fixable++
+		}
+
+		cmd.Printf("[%s] %s%s\n", severity, findingMessage(finding), suffix)
+	}
+
+	if fixable > 0 {
+		cmd.Printf("\nSuggest to fix the fixable findings with the following command:\n")
+		cmd.Printf("  git profile doctor --fix\n")
+	}
+}
+
+// findingMessage describes the finding in a sentence
+func findingMessage(finding *application.DoctorFinding) string {
+	switch finding.Check {
+	case application.DoctorCheckGitNotInstalled:
+		return "git is not installed or not found in the PATH"
+	case application.DoctorCheckGitOutdated:
+		return fmt.Sprintf("git %s is older than %s, the rules by remote url are ignored", finding.Subject, finding.Detail)
+	case application.DoctorCheckSourceInvalid:
+		return fmt.Sprintf("The profile file %s cannot be parsed: %s", finding.Subject, findingError(finding))
+	case application.DoctorCheckProfileInvalid:
+		return fmt.Sprintf("The profile \"%s\" of %s cannot be resolved: %s", finding.Subject, strings.Join(finding.Paths, ", "), findingError(finding))
+	case application.DoctorCheckProfileDuplicated:
+		return fmt.Sprintf("The profile \"%s\" is defined more than once in %s, its keys are merged", finding.Subject, strings.Join(finding.Paths, ", "))
+	case application.DoctorCheckProfileShadowed:
+		return fmt.Sprintf("The profile \"%s\" of %s is shadowed by the one of %s", finding.Subject, finding.Paths[0], finding.Paths[1])
+	case application.DoctorCheckIdentityMismatch:
+		return fmt.Sprintf("The %s git identity %s does not match the profile \"%s\"", finding.Scope, finding.Detail, finding.Subject)
+	case application.DoctorCheckWorkspaceNotExists:
+		return fmt.Sprintf("The %s git configuration uses the profile \"%s\" which does not exist", finding.Scope, finding.Subject)
+	case application.DoctorCheckEnvironmentOverride:
+		return fmt.Sprintf("%s=%s overrides the identity of the profile", finding.Subject, finding.Detail)
+	case application.DoctorCheckIncludeConflict:
+		return fmt.Sprintf("The includeIf sections of \"%s\" include different files: %s", finding.Subject, strings.Join(finding.Paths, ", "))
+	}
+
+	return string(finding.Check)
+}
+
+// findingError uses the message of the known errors, without the trailing newline
+func findingError(finding *application.DoctorFinding) string {
+	if message, ok := errorMessages[finding.Err]; ok && !strings.Contains(message, "%") {
+		return strings.TrimSuffix(message, "\n")
+	}
+
+	return strings.TrimSpace(finding.Err.Error())
+}
@@ -1,15 +1,56 @@
 package command
 
 import (
+	"strings"
+
 	"github.com/b4nd/git-profile/pkg/application"
 	"github.com/b4nd/git-profile/pkg/domain"
+
+	"github.com/spf13/cobra"
 )
 
 var errorMessages = map[error]string{
 	application.ErrProfileAlreadyExists:  "Profile \"%s\" already exists.\n",
 	application.ErrProfileNotExists:      "Profile \"%s\" does not exist.\n",
+	application.ErrProfileRuleNotExists:  "No rules found for the given profile or condition.\n",
+	application.ErrCommitDateConflict:    "The --keep-date and --reset-date options cannot be used together.\n",
+	application.ErrInvalidImportStrategy: "The import strategy must be skip, overwrite or rename.\n",
 	domain.ErrInvalidEmail:               "The email is invalid.\n",
 	domain.ErrInvalidName:                "The name is invalid.\n",
 	domain.ErrInvalidWorkspace:           "Profile \"%s\" does not exist.\n",
 	domain.ErrInvalidWorkspaceCharacters: "The workspace must contain only alphanumeric characters.\n",
+	domain.ErrInvalidSigningKey:          "The signing key is invalid.\n",
+	domain.ErrInvalidSigningFormat:       "The signing format must be openpgp, ssh or x509.\n",
+	domain.ErrInvalidSshKey:              "The SSH key is invalid.\n",
+	domain.ErrInvalidRuleCondition:       "The rule needs a valid --gitdir directory or --remote url.\n",
+	domain.ErrInvalidRemotePattern:       "The remote url pattern is invalid.\n",
+	domain.ErrProfileExtendsCycle:        "The profile extends itself through the profiles it extends.\n",
+	domain.ErrProfileParentNotExists:     "The profile extends a profile that does not exist.\n",
+	domain.ErrInvalidUsernameHost:        "The username host must be a host name such as github.com.\n",
+	domain.ErrInvalidUsername:            "The username must be given as host=username.\n",
+	domain.ErrInvalidScmCredential:       "The credential must be given as key=value lines.\n",
+	domain.ErrInvalidConfigKey:           "The git config key must be given as section.name=value.\n",
+	domain.ErrReservedConfigKey:          "The git config key is set from the other options of the profile.\n",
+	domain.ErrInvalidConfigValue:         "The git config value cannot contain quotes, backslashes, #, ; or new lines.\n",
+	domain.ErrScmRepositoryNotFound:      "Not a git repository (or any of the parent directories).\n",
+	ErrOutputNotSupported:                "The --output flag is only supported by list, get, current, rules list, audit, verify, doctor and version.\n",
+	ErrPickerCancelled:                   "No profile selected.\n",
+	ErrPickerNoProfiles:                  "No profiles found\n",
+}
+
+// printErrorMessage prints the message of the error, the workspace is only
+// given to the messages that name it and an error without message is printed as is
+func printErrorMessage(cmd *cobra.Command, err error, workspace string) {
+	message, ok := errorMessages[err]
+	if !ok {
+		cmd.Printf("Unexpected error: %v\n", err)
+		return
+	}
+
+	if strings.Contains(message, "%s") {
+		cmd.Printf(message, workspace)
+		return
+	}
+
+	cmd.Print(message)
 }
@@ -0,0 +1,27 @@
+package command
+
+import (
+	"fmt"
+)
+
+// Exit codes of the commands that report their result to scripts and hooks
+const (
+	ExitCodeFailure   = 1
+	ExitCodeNoMatch   = 2
+	ExitCodeAmbiguous = 3
+)
+
+// ExitError is returned by a command that already printed its message and
+// must terminate the process with a specific exit code
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("exit status %d: %v", e.Code, e.Err)
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,98 @@
+package command
+
+import (
+	"github.com/b4nd/git-profile/pkg/application"
+	"github.com/b4nd/git-profile/pkg/domain"
+
+	"github.com/spf13/cobra"
+)
+
+type ExportProfileCommand struct {
+	listProfileService *application.ListProfileService
+	getProfileService  *application.GetProfileService
+}
+
+func NewExportProfileCommand(
+	listProfileService *application.ListProfileService,
+	getProfileService *application.GetProfileService,
+) *ExportProfileCommand {
+	return &ExportProfileCommand{
+		listProfileService,
+		getProfileService,
+	}
+}
+
+func (c *ExportProfileCommand) Register(rootCmd *cobra.Command) {
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "export [workspaces...] [--format json|yaml|toml|csv]",
+		Short: "Exports profiles as JSON, YAML, TOML or CSV.",
+		Long: `Export the given profiles, or every profile when none is given, to the standard output.
+A profile that extends another one is exported with its extends key and the
+fields it defines itself, the inherited ones are left out.
+The exported file can be imported again with git profile import.
+`,
+		Example: `  git profile export > profiles.json
+  git profile export work personal --format yaml
+  git profile export --format csv > profiles.csv`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return c.Execute(cmd, args, format)
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", RecordFormatJson, "The format of the profiles: json, yaml, toml or csv")
+
+	registerWorkspaceCompletion(cmd, c.listProfileService, -1)
+
+	rootCmd.AddCommand(cmd)
+}
+
+func (c *ExportProfileCommand) Execute(cmd *cobra.Command, workspaces []string, format string) error {
+	profiles := make([]*domain.Profile, 0, len(workspaces))
+
+	if len(workspaces) == 0 {
+		list, err := c.listProfileService.Execute()
+		if err != nil {
+			printErrorMessage(cmd, err, "")
+			return nil
+		}
+
+		profiles = list
+	}
+
+	for _, workspace := range workspaces {
+		profile, err := c.getProfileService.Execute(application.GetProfileServiceParams{
+			Workspace: workspace,
+		})
+
+		if err != nil {
+			printErrorMessage(cmd, err, workspace)
+			return nil
+		}
+
+		profiles = append(profiles, profile)
+	}
+
+	// The profiles that extend another one are exported with their own fields only
+	parents := map[string]*domain.Profile{}
+	for _, profile := range profiles {
+		workspace := profile.Extends().String()
+		if _, ok := parents[workspace]; ok || workspace == "" {
+			continue
+		}
+
+		parent, err := c.getProfileService.Execute(application.GetProfileServiceParams{
+			Workspace: workspace,
+		})
+
+		if err != nil {
+			printErrorMessage(cmd, err, workspace)
+			return nil
+		}
+
+		parents[workspace] = parent
+	}
+
+	return encodeProfileRecords(cmd.OutOrStdout(), format, profiles, parents)
+}
@@ -2,27 +2,36 @@ package command
 
 import (
 	"bufio"
+	"errors"
 	"strings"
 
 	"github.com/b4nd/git-profile/pkg/application"
+	"github.com/b4nd/git-profile/pkg/domain"
 
 	"github.com/spf13/cobra"
 )
 
 type GetProfileCommand struct {
-	getProfileService *application.GetProfileService
+	getProfileService     *application.GetProfileService
+	currentProfileService *application.CurrentProfileService
+	listProfileService    *application.ListProfileService
 }
 
 func NewGetProfileCommand(
 	getProfileService *application.GetProfileService,
+	currentProfileService *application.CurrentProfileService,
+	listProfileService *application.ListProfileService,
 ) *GetProfileCommand {
 	return &GetProfileCommand{
 		getProfileService,
+		currentProfileService,
+		listProfileService,
 	}
 }
 
 func (c *GetProfileCommand) Register(rootCmd *cobra.Command) {
 	var workspace string
+	var resolved bool
 
 	cmd := &cobra.Command{
 		Use:   "get [-w workspace]",
@@ -31,29 +40,52 @@ func (c *GetProfileCommand) Register(rootCmd *cobra.Command) {
 		Example: `  git profile get
   git profile get work
   git profile get --workspace work 
-  git profile get -w work`,
+  git profile get -w work
+  git profile get work --resolved
+  git profile get work --output json`,
 		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if workspace == "" && len(args) > 0 {
 				workspace = args[0]
 			}
 
-			return c.Execute(cmd, workspace)
+			return c.Execute(cmd, workspace, resolved)
 		},
 	}
 
 	cmd.Flags().StringVarP(&workspace, "workspace", "w", "", "The workspace of the profile")
+	cmd.Flags().BoolVar(&resolved, "resolved", false, "Show the profile each field is inherited from")
 
+	registerWorkspaceCompletion(cmd, c.listProfileService, 1)
+
+	supportOutput(cmd)
 	rootCmd.AddCommand(cmd)
 }
 
-func (c *GetProfileCommand) Execute(cmd *cobra.Command, workspace string) error {
+func (c *GetProfileCommand) Execute(cmd *cobra.Command, workspace string, resolved bool) error {
+	output, err := newOutput(cmd)
+	if err != nil {
+		return err
+	}
+
 	reader := bufio.NewReader(cmd.InOrStdin())
 
 	params := application.GetProfileServiceParams{
 		Workspace: workspace,
 	}
 
+	if params.Workspace == "" {
+		picked, err := pickWorkspace(cmd, c.listProfileService, c.currentProfileService)
+		if err != ErrPickerNotTerminal {
+			if err != nil {
+				printErrorMessage(cmd, err, "")
+				return nil
+			}
+
+			params.Workspace = picked
+		}
+	}
+
 	if params.Workspace == "" {
 		cmd.Print("Enter workspace: ")
 		input, _ := reader.ReadString('\n')
@@ -62,16 +94,36 @@ func (c *GetProfileCommand) Execute(cmd *cobra.Command, workspace string) error
 
 	profile, err := c.getProfileService.Execute(params)
 
+	if errors.Is(err, domain.ErrProfileExtendsCycle) || errors.Is(err, domain.ErrProfileParentNotExists) {
+		printErrorMessage(cmd, err, params.Workspace)
+		return nil
+	}
+
 	if err != nil {
-		cmd.Printf(errorMessages[err], params.Workspace)
+		printErrorMessage(cmd, err, params.Workspace)
 		cmd.Printf("\nSuggest to create a new profile with the following command:\n")
 		cmd.Printf("  git profile set %s\n", params.Workspace)
 		return nil
 	}
 
-	cmd.Printf("Workspace: %s\n", profile.Workspace().String())
-	cmd.Printf("Email: %s\n", profile.Email().String())
-	cmd.Printf("Name: %s\n", profile.Name().String())
+	if !output.IsTable() {
+		currentProfile, _ := c.currentProfileService.Execute()
+		isCurrentProfile := currentProfile != nil && currentProfile.Workspace().Equals(profile.Workspace())
+
+		value := newProfileOutput(profile, isCurrentProfile)
+		if resolved {
+			value = value.withOrigins(profile)
+		}
+
+		return output.Print(cmd, value)
+	}
+
+	if resolved {
+		printResolvedProfile(cmd, profile)
+		return nil
+	}
+
+	printProfile(cmd, profile)
 
 	return nil
 }
@@ -0,0 +1,125 @@
+package command
+
+import (
+	"github.com/b4nd/git-profile/pkg/application"
+	"github.com/b4nd/git-profile/pkg/domain"
+
+	"github.com/spf13/cobra"
+)
+
+type HookCommand struct {
+	installHookService   *application.InstallHookService
+	uninstallHookService *application.UninstallHookService
+}
+
+func NewHookCommand(
+	installHookService *application.InstallHookService,
+	uninstallHookService *application.UninstallHookService,
+) *HookCommand {
+	return &HookCommand{
+		installHookService,
+		uninstallHookService,
+	}
+}
+
+func (c *HookCommand) Register(rootCmd *cobra.Command) {
+	cmd := &cobra.Command{
+		Use: "hook [command]",
+		Aliases: []string{
+			"hooks",
+		},
+		Short: "Manages the git hooks that check the profile.",
+		Long: `Manage the hooks that check the identity of the commits:
+  - pre-commit runs "git profile check" before every commit
+  - pre-push runs "git profile check --pre-push" before every push, checking the
+    commits sent against the profile expected for the remote url
+The hook is installed in the hooks directory of the repository, honoring core.hooksPath.
+An existing hook is kept and run after the check, it is restored on uninstall.
+`,
+		Example: `  git profile hook install
+  git profile hook install pre-push
+  git profile hook uninstall pre-push`,
+	}
+
+	installCmd := &cobra.Command{
+		Use:       "install [pre-commit|pre-push]",
+		Short:     "Installs the pre-commit hook, or the given hook, in the current repository.",
+		Args:      cobra.MatchAll(cobra.MaximumNArgs(1), cobra.OnlyValidArgs),
+		ValidArgs: hookNames,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return c.ExecuteInstall(cmd, hookName(args))
+		},
+	}
+
+	uninstallCmd := &cobra.Command{
+		Use:       "uninstall [pre-commit|pre-push]",
+		Short:     "Removes the pre-commit hook, or the given hook, from the current repository.",
+		Args:      cobra.MatchAll(cobra.MaximumNArgs(1), cobra.OnlyValidArgs),
+		ValidArgs: hookNames,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return c.ExecuteUninstall(cmd, hookName(args))
+		},
+	}
+
+	cmd.AddCommand(installCmd, uninstallCmd)
+	rootCmd.AddCommand(cmd)
+}
+
+func (c *HookCommand) ExecuteInstall(cmd *cobra.Command, hook string) error {
+	err := c.installHookService.Execute(application.InstallHookServiceParams{
+		Hook: hook,
+	})
+
+	if err == domain.ErrScmHookChainExists {
+		cmd.Printf("The existing %s hook cannot be chained, a chained hook already exists\n", hook)
+		return nil
+	}
+
+	if err != nil {
+		cmd.Printf("Failed to install the %s hook: %v\n", hook, err)
+		return nil
+	}
+
+	if hook == domain.ScmHookPrePush {
+		cmd.Printf("Hook \"%s\" installed, pushed commits are checked against the profile of the remote\n", hook)
+	} else {
+		cmd.Printf("Hook \"%s\" installed, commits are checked against the configured profile\n", hook)
+	}
+
+	cmd.Printf("\nSuggest to check the current repository with the following command:\n")
+	cmd.Printf("  git profile check\n")
+
+	return nil
+}
+
+func (c *HookCommand) ExecuteUninstall(cmd *cobra.Command, hook string) error {
+	err := c.uninstallHookService.Execute(application.UninstallHookServiceParams{
+		Hook: hook,
+	})
+
+	if err == domain.ErrScmHookNotInstalled {
+		cmd.Printf("Hook \"%s\" is not installed\n", hook)
+		return nil
+	}
+
+	if err != nil {
+		cmd.Printf("Failed to uninstall the %s hook: %v\n", hook, err)
+		return nil
+	}
+
+	cmd.Printf("Hook \"%s\" uninstalled\n", hook)
+
+	return nil
+}
+
+// hookNames are the hooks that can be installed
+var hookNames = []string{domain.ScmHookPreCommit, domain.ScmHookPrePush}
+
+// hookName returns the hook given as argument, the pre-commit hook by default
+func hookName(args []string) string {
+	if len(args) == 0 {
+		return domain.ScmHookPreCommit
+	}
+
+	return args[0]
+}
@@ -0,0 +1,210 @@
+package command
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/b4nd/git-profile/pkg/application"
+
+	"github.com/spf13/cobra"
+)
+
+type ImportProfileCommand struct {
+	importProfilesService *application.ImportProfilesService
+}
+
+func NewImportProfileCommand(importProfilesService *application.ImportProfilesService) *ImportProfileCommand {
+	return &ImportProfileCommand{importProfilesService}
+}
+
+func (c *ImportProfileCommand) Register(rootCmd *cobra.Command) {
+	var fromGitConfig bool
+	var yes bool
+	var strategy string
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "import <file|-> [--strategy skip|overwrite|rename] | import --from-gitconfig [--yes]",
+		Short: "Imports profiles from a file or from the git configuration.",
+		Long: `Import the profiles of a JSON, YAML, TOML or CSV file, as written by git profile export,
+or of the standard input when the file is -. The format is given by the extension of the
+file unless --format is given. Every record is validated before anything is saved, so
+nothing is imported when a record is invalid. The --strategy tells what is done with a
+record whose workspace is already a profile: skip it, overwrite the profile or import it
+under a new workspace with rename.
+
+With --from-gitconfig the identities of the git configuration are imported instead.
+The global .gitconfig, the files it includes through include and includeIf sections
+and the .git/config of the current repository are read. Every distinct user.name and
+user.email pair that is not already a profile is proposed with a workspace named after
+the email domain, with its signing key and the identity file of its core.sshCommand.
+Each identity is reviewed before it is imported unless --yes is given.
+`,
+		Example: `  git profile import profiles.json
+  git profile import profiles.yaml --strategy overwrite
+  cat profiles.csv | git profile import - --format csv --strategy rename
+  git profile import --from-gitconfig
+  git profile import --from-gitconfig --yes`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 0 {
+				return c.ExecuteFile(cmd, args[0], format, application.ImportStrategy(strategy))
+			}
+
+			if !fromGitConfig {
+				cmd.Printf("No file or --from-gitconfig given to import from.\n")
+				cmd.Printf("\nSuggest to import the profiles of a file with the following command:\n")
+				cmd.Printf("  git profile import profiles.json\n")
+				return nil
+			}
+
+			return c.Execute(cmd, yes)
+		},
+	}
+
+	cmd.Flags().BoolVar(&fromGitConfig, "from-gitconfig", false, "Import the identities of the git configuration files")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Import every identity with the proposed workspace without prompting")
+	cmd.Flags().StringVarP(&strategy, "strategy", "s", string(application.ImportStrategySkip), "What is done with an existing workspace: skip, overwrite or rename")
+	cmd.Flags().StringVar(&format, "format", "", "The format of the file: json, yaml, toml or csv")
+	cmd.MarkFlagsMutuallyExclusive("from-gitconfig", "strategy")
+	cmd.MarkFlagsMutuallyExclusive("from-gitconfig", "format")
+
+	rootCmd.AddCommand(cmd)
+}
+
+func (c *ImportProfileCommand) Execute(cmd *cobra.Command, yes bool) error {
+	reader := bufio.NewReader(cmd.InOrStdin())
+
+	candidates, err := c.importProfilesService.Candidates()
+	if err != nil {
+		cmd.Printf("Failed to read the git configuration: %v\n", err)
+		return nil
+	}
+
+	if len(candidates) == 0 {
+		cmd.Println("No identities to import found in the git configuration")
+		return nil
+	}
+
+	imported := 0
+	for _, candidate := range candidates {
+		params := candidate.Params
+
+		if !yes {
+			cmd.Printf("Found %s <%s> in %s\n", params.Name, params.Email, candidate.Source)
+			if params.SigningKey != "" {
+				cmd.Printf("  Signing Key: %s\n", params.SigningKey)
+			}
+
+			if params.SshKey != "" {
+				cmd.Printf("  SSH Key: %s\n", params.SshKey)
+			}
+
+			cmd.Print("Import this identity? (Y/n): ")
+			input, _ := reader.ReadString('\n')
+			if answer := strings.ToLower(strings.TrimSpace(input)); answer != "" && answer != "y" && answer != "yes" {
+				cmd.Println()
+				continue
+			}
+
+			cmd.Print("Enter workspace [" + params.Workspace + "]: ")
+			input, _ = reader.ReadString('\n')
+			if input = strings.TrimSpace(input); input != "" {
+				params.Workspace = input
+			}
+		}
+
+		profile, err := c.importProfilesService.Execute(application.ImportProfileCandidate{
+			Source: candidate.Source,
+			Params: params,
+		})
+
+		if err != nil {
+			printErrorMessage(cmd, err, params.Workspace)
+			if !yes {
+				cmd.Println()
+			}
+
+			continue
+		}
+
+		imported++
+		cmd.Printf("Profile \"%s\" imported from %s\n", profile.Workspace().String(), candidate.Source)
+		if !yes {
+			cmd.Println()
+		}
+	}
+
+	if imported == 0 {
+		return nil
+	}
+
+	cmd.Printf("\nSuggest to list the imported profiles with the following command:\n")
+	cmd.Printf("  git profile list --verbose\n")
+
+	return nil
+}
+
+// ExecuteFile imports the profiles of the file, or of the standard input when the file is -
+func (c *ImportProfileCommand) ExecuteFile(cmd *cobra.Command, file string, format string, strategy application.ImportStrategy) error {
+	if format == "" {
+		format = recordFormatFromPath(file)
+	}
+
+	var reader io.Reader = cmd.InOrStdin()
+	if file != "-" {
+		opened, err := os.Open(file) // #nosec G304
+		if err != nil {
+			cmd.Printf("Failed to read the file %s: %v\n", file, err)
+			return nil
+		}
+
+		defer opened.Close() // nolint
+		reader = opened
+	}
+
+	records, err := decodeProfileRecords(reader, format)
+	if err != nil {
+		cmd.Printf("Failed to read the profiles of %s: %v\n", file, err)
+		return nil
+	}
+
+	results, err := c.importProfilesService.Import(application.ImportProfilesServiceParams{
+		Records:  records,
+		Strategy: strategy,
+	})
+
+	if errors.Is(err, application.ErrInvalidProfileRecord) {
+		cmd.Printf("Nothing was imported, %v\n", err)
+		return nil
+	}
+
+	if err != nil {
+		if _, ok := errorMessages[err]; ok {
+			printErrorMessage(cmd, err, "")
+			return nil
+		}
+
+		cmd.Printf("Failed to import the profiles: %v\n", err)
+		return nil
+	}
+
+	if len(results) == 0 {
+		cmd.Println("No profiles found to import")
+		return nil
+	}
+
+	for _, result := range results {
+		if result.Action == application.ImportActionRenamed {
+			cmd.Printf("Profile \"%s\" renamed to \"%s\"\n", result.Workspace, result.Profile.Workspace().String())
+			continue
+		}
+
+		cmd.Printf("Profile \"%s\" %s\n", result.Workspace, result.Action)
+	}
+
+	return nil
+}
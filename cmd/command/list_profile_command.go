@@ -2,6 +2,7 @@ package command
 
 import (
 	"github.com/b4nd/git-profile/pkg/application"
+	"github.com/b4nd/git-profile/pkg/domain"
 
 	"github.com/spf13/cobra"
 )
@@ -41,36 +42,52 @@ func (c *ListProfileCommand) Register(rootCmd *cobra.Command) {
 
 	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Show full profile details")
 
+	supportOutput(cmd)
 	rootCmd.AddCommand(cmd)
 }
 
 func (c *ListProfileCommand) Execute(cmd *cobra.Command, verbose bool) error {
+	output, err := newOutput(cmd)
+	if err != nil {
+		return err
+	}
+
 	profiles, err := c.listProfileService.Execute()
 
 	if err != nil {
-		cmd.Print(errorMessages[err])
+		printErrorMessage(cmd, err, "")
 		return nil
 	}
 
+	currentProfile, _ := c.currentProfileService.Execute()
+	isCurrentProfile := func(profile *domain.Profile) bool {
+		return currentProfile != nil && currentProfile.Workspace().Equals(profile.Workspace())
+	}
+
+	if !output.IsTable() {
+		values := make([]profileOutput, 0, len(profiles))
+		for _, profile := range profiles {
+			values = append(values, newProfileOutput(profile, isCurrentProfile(profile)))
+		}
+
+		return output.Print(cmd, values)
+	}
+
 	if len(profiles) == 0 {
 		cmd.Println("No profiles found")
 		return nil
 	}
 
-	currentProfile, _ := c.currentProfileService.Execute()
 	for _, profile := range profiles {
-		isCurrentProfile := currentProfile != nil && currentProfile.Workspace().Equals(profile.Workspace())
 		if !verbose {
-			if isCurrentProfile {
-				cmd.Printf("\033[32m%s\033[0m\n", profile.Workspace().String())
+			if isCurrentProfile(profile) {
+				cmd.Printf("%s\n", colorize(cmd, colorGreen, profile.Workspace().String()))
 			} else {
 				cmd.Printf("%s\n", profile.Workspace().String())
 			}
 		} else {
-			cmd.Printf("Workspace: %s\n", profile.Workspace().String())
-			cmd.Printf("Email: %s\n", profile.Email().String())
-			cmd.Printf("Name: %s\n", profile.Name().String())
-			if isCurrentProfile {
+			printProfile(cmd, profile)
+			if isCurrentProfile(profile) {
 				cmd.Printf("Current: true\n")
 			}
 			cmd.Println()
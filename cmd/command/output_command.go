@@ -0,0 +1,266 @@
+package command
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"text/template"
+
+	"github.com/b4nd/git-profile/pkg/domain"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+	"gopkg.in/yaml.v3"
+)
+
+// Formats of the global --output flag
+const (
+	OutputTable    = "table"
+	OutputJson     = "json"
+	OutputYaml     = "yaml"
+	OutputTemplate = "template"
+)
+
+const outputFlagName = "output"
+
+// outputAnnotation marks the commands printing their result in the format of the --output flag
+const outputAnnotation = "git-profile/output"
+
+// ANSI color codes
+const (
+	colorRed    = "31"
+	colorGreen  = "32"
+	colorYellow = "33"
+)
+
+// noColorEnvName disables the colors when it is set, see https://no-color.org
+const noColorEnvName = "NO_COLOR"
+
+var ErrInvalidOutput = errors.New("invalid output format")
+var ErrOutputNotSupported = errors.New("output format not supported by the command")
+
+// profileOutput is the documented schema of a profile in the structured outputs
+type profileOutput struct {
+	Workspace string            `json:"workspace" yaml:"workspace"`
+	Email     string            `json:"email" yaml:"email"`
+	Name      string            `json:"name" yaml:"name"`
+	Signing   *signingOutput    `json:"signing,omitempty" yaml:"signing,omitempty"`
+	SshKey    string            `json:"sshKey,omitempty" yaml:"sshKey,omitempty"`
+	Remotes   []string          `json:"remotes,omitempty" yaml:"remotes,omitempty"`
+	Usernames map[string]string `json:"usernames,omitempty" yaml:"usernames,omitempty"`
+	Config    map[string]string `json:"config,omitempty" yaml:"config,omitempty"`
+	Extends   string            `json:"extends,omitempty" yaml:"extends,omitempty"`
+	Current   bool              `json:"current" yaml:"current"`
+	Source    string            `json:"source" yaml:"source"`
+	Scope     string            `json:"scope" yaml:"scope"`
+	// Origins maps the inherited fields to the profile defining them, only with --resolved
+	Origins map[string]string `json:"origins,omitempty" yaml:"origins,omitempty"`
+}
+
+type signingOutput struct {
+	Key         string `json:"key" yaml:"key"`
+	Format      string `json:"format" yaml:"format"`
+	SignCommits bool   `json:"signCommits" yaml:"signCommits"`
+	SignTags    bool   `json:"signTags" yaml:"signTags"`
+}
+
+// ruleOutput is the documented schema of a rule in the structured outputs
+type ruleOutput struct {
+	Workspace string `json:"workspace" yaml:"workspace"`
+	Condition string `json:"condition" yaml:"condition"`
+}
+
+// newProfileOutput returns the schema of the profile, the not configured
+// profile has no source
+func newProfileOutput(profile *domain.Profile, current bool) profileOutput {
+	output := profileOutput{
+		Workspace: profile.Workspace().String(),
+		Email:     profile.Email().String(),
+		Name:      profile.Name().String(),
+		SshKey:    profile.SshKey().String(),
+		Extends:   profile.Extends().String(),
+		Current:   current,
+		Source:    profile.Source().Path(),
+		Scope:     string(profile.Source().Scope()),
+	}
+
+	if signing := profile.Signing(); !signing.IsEmpty() {
+		output.Signing = &signingOutput{
+			Key:         signing.Key(),
+			Format:      signing.Format(),
+			SignCommits: signing.CommitSign(),
+			SignTags:    signing.TagSign(),
+		}
+	}
+
+	for _, remote := range profile.Remotes() {
+		output.Remotes = append(output.Remotes, remote.String())
+	}
+
+	for _, username := range profile.Usernames() {
+		if output.Usernames == nil {
+			output.Usernames = map[string]string{}
+		}
+
+		output.Usernames[username.Host()] = username.Username()
+	}
+
+	if config := profile.Config(); !config.IsEmpty() {
+		output.Config = config.Values()
+	}
+
+	return output
+}
+
+// withOrigins returns the schema with the profile each inherited field comes from
+func (o profileOutput) withOrigins(profile *domain.Profile) profileOutput {
+	o.Origins = map[string]string{}
+	for _, field := range domain.ProfileFields {
+		if origin := profile.Origin(field); !origin.Equals(profile.Workspace()) {
+			o.Origins[string(field)] = origin.String()
+		}
+	}
+
+	return o
+}
+
+// RegisterOutputFlag adds the global --output flag honoured by the commands,
+// the other commands reject a format other than table
+func RegisterOutputFlag(rootCmd *cobra.Command) {
+	rootCmd.PersistentFlags().StringP(outputFlagName, "o", OutputTable, "Output format: table, json, yaml or template='{{.Workspace}}'")
+	rootCmd.PersistentPreRunE = checkOutputFlag
+}
+
+// supportOutput marks the command as printing its result in the format of the --output flag
+func supportOutput(cmd *cobra.Command) {
+	if cmd.Annotations == nil {
+		cmd.Annotations = map[string]string{}
+	}
+
+	cmd.Annotations[outputAnnotation] = "true"
+}
+
+// checkOutputFlag rejects a structured output on a command that only prints messages
+func checkOutputFlag(cmd *cobra.Command, args []string) error {
+	flag := cmd.Flags().Lookup(outputFlagName)
+	if flag == nil || !flag.Changed || cmd.Annotations[outputAnnotation] != "" {
+		return nil
+	}
+
+	if format := strings.TrimSpace(flag.Value.String()); format == "" || format == OutputTable {
+		return nil
+	}
+
+	// The error is printed here as the diagnostic commands silence the errors
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	printErrorMessage(cmd, ErrOutputNotSupported, "")
+
+	return &ExitError{Code: ExitCodeFailure, Err: ErrOutputNotSupported}
+}
+
+// output writes the values in the format given by the --output flag
+type output struct {
+	format   string
+	template *template.Template
+}
+
+// newOutput parses the --output flag, the table format is used when the flag is not registered
+func newOutput(cmd *cobra.Command) (*output, error) {
+	format := OutputTable
+	if flag := cmd.Flags().Lookup(outputFlagName); flag != nil {
+		format = strings.TrimSpace(flag.Value.String())
+	}
+
+	switch format {
+	case "", OutputTable:
+		return &output{format: OutputTable}, nil
+	case OutputJson, OutputYaml:
+		return &output{format: format}, nil
+	}
+
+	text, ok := strings.CutPrefix(format, OutputTemplate+"=")
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidOutput, format)
+	}
+
+	tmpl, err := template.New(OutputTemplate).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidOutput, err)
+	}
+
+	return &output{format: OutputTemplate, template: tmpl}, nil
+}
+
+// IsTable reports whether the human readable output is used
+func (o *output) IsTable() bool {
+	return o.format == OutputTable
+}
+
+// Print writes the value to the standard output, the template is executed
+// once per element when the value is a list
+func (o *output) Print(cmd *cobra.Command, value any) error {
+	writer := cmd.OutOrStdout()
+
+	switch o.format {
+	case OutputJson:
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(value)
+	case OutputYaml:
+		encoder := yaml.NewEncoder(writer)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(value); err != nil {
+			return err
+		}
+
+		return encoder.Close()
+	case OutputTemplate:
+		return o.printTemplate(writer, value)
+	}
+
+	return fmt.Errorf("%w: %s", ErrInvalidOutput, o.format)
+}
+
+func (o *output) printTemplate(writer io.Writer, value any) error {
+	items := reflect.ValueOf(value)
+	if items.Kind() != reflect.Slice {
+		if err := o.template.Execute(writer, value); err != nil {
+			return err
+		}
+
+		_, err := fmt.Fprintln(writer)
+		return err
+	}
+
+	for i := 0; i < items.Len(); i++ {
+		if err := o.template.Execute(writer, items.Index(i).Interface()); err != nil {
+			return err
+		}
+
+		if _, err := fmt.Fprintln(writer); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// colorize wraps the text with the ANSI color code when the output of the
+// command is a terminal and NO_COLOR is not set
+func colorize(cmd *cobra.Command, code string, text string) string {
+	if os.Getenv(noColorEnvName) != "" {
+		return text
+	}
+
+	file, ok := cmd.OutOrStderr().(*os.File)
+	if !ok || !term.IsTerminal(int(file.Fd())) { // #nosec G115
+		return text
+	}
+
+	return "\033[" + code + "m" + text + "\033[0m"
+}
@@ -0,0 +1,84 @@
+package command
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/b4nd/git-profile/pkg/domain"
+
+	"github.com/spf13/cobra"
+)
+
+// printProfile prints the details of a profile, one field per line
+func printProfile(cmd *cobra.Command, profile *domain.Profile) {
+	printProfileFields(cmd, profile, false)
+}
+
+// printResolvedProfile prints the details of a profile with the profile
+// each inherited field comes from
+func printResolvedProfile(cmd *cobra.Command, profile *domain.Profile) {
+	printProfileFields(cmd, profile, true)
+}
+
+func printProfileFields(cmd *cobra.Command, profile *domain.Profile, resolved bool) {
+	writeProfileFields(cmd.OutOrStderr(), profile, resolved)
+}
+
+// writeProfileFields writes the details of a profile, one field per line
+func writeProfileFields(w io.Writer, profile *domain.Profile, resolved bool) {
+	origin := func(field domain.ProfileField) string {
+		if from := profile.Origin(field); resolved && !from.Equals(profile.Workspace()) {
+			return " (from " + from.String() + ")"
+		}
+
+		return ""
+	}
+
+	fmt.Fprintf(w, "Workspace: %s\n", profile.Workspace().String())
+	if extends := profile.Extends(); resolved && extends.String() != "" {
+		fmt.Fprintf(w, "Extends: %s\n", extends.String())
+	}
+
+	fmt.Fprintf(w, "Email: %s%s\n", profile.Email().String(), origin(domain.ProfileFieldEmail))
+	fmt.Fprintf(w, "Name: %s%s\n", profile.Name().String(), origin(domain.ProfileFieldName))
+
+	if signing := profile.Signing(); !signing.IsEmpty() {
+		from := origin(domain.ProfileFieldSigning)
+		fmt.Fprintf(w, "Signing Key: %s%s\n", signing.Key(), from)
+		fmt.Fprintf(w, "Signing Format: %s%s\n", signing.Format(), from)
+		fmt.Fprintf(w, "Sign Commits: %t%s\n", signing.CommitSign(), from)
+		fmt.Fprintf(w, "Sign Tags: %t%s\n", signing.TagSign(), from)
+	}
+
+	if sshKey := profile.SshKey(); !sshKey.IsEmpty() {
+		fmt.Fprintf(w, "SSH Key: %s%s\n", sshKey.String(), origin(domain.ProfileFieldSshKey))
+	}
+
+	if remotes := profile.Remotes(); len(remotes) > 0 {
+		values := make([]string, 0, len(remotes))
+		for _, remote := range remotes {
+			values = append(values, remote.String())
+		}
+
+		fmt.Fprintf(w, "Remotes: %s%s\n", strings.Join(values, ", "), origin(domain.ProfileFieldRemotes))
+	}
+
+	if usernames := profile.Usernames(); len(usernames) > 0 {
+		values := make([]string, 0, len(usernames))
+		for _, username := range usernames {
+			values = append(values, username.String())
+		}
+
+		fmt.Fprintf(w, "Usernames: %s%s\n", strings.Join(values, ", "), origin(domain.ProfileFieldUsernames))
+	}
+
+	if config := profile.Config(); !config.IsEmpty() {
+		values := make([]string, 0, len(config.Entries()))
+		for _, entry := range config.Entries() {
+			values = append(values, entry.Key()+"="+entry.Value())
+		}
+
+		fmt.Fprintf(w, "Config: %s%s\n", strings.Join(values, ", "), origin(domain.ProfileFieldConfig))
+	}
+}
@@ -0,0 +1,435 @@
+package command
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/b4nd/git-profile/pkg/application"
+	"github.com/b4nd/git-profile/pkg/domain"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var ErrPickerNotTerminal = errors.New("picker needs a terminal")
+var ErrPickerCancelled = errors.New("picker cancelled")
+var ErrPickerNoProfiles = errors.New("no profiles to pick")
+
+// pickerMaxItems is the number of profiles listed at once, the list scrolls
+const pickerMaxItems = 10
+
+// Keys of the picker, the printable characters are added to the query
+const (
+	pickerKeyNone = iota
+	pickerKeyUp
+	pickerKeyDown
+	pickerKeyEnter
+	pickerKeyCancel
+	pickerKeyBackspace
+	pickerKeyClear
+	pickerKeyRune
+)
+
+// ANSI sequences used to draw the picker
+const (
+	ansiClearDown  = "\033[J"
+	ansiHideCursor = "\033[?25l"
+	ansiShowCursor = "\033[?25h"
+	ansiReverse    = "7"
+)
+
+type pickerKey struct {
+	code int
+	r    rune
+}
+
+// profilePicker filters the profiles with the query and keeps the selected one
+type profilePicker struct {
+	profiles []*domain.Profile
+	current  string
+	query    []rune
+	matches  []*domain.Profile
+	selected int
+	offset   int
+}
+
+func newProfilePicker(profiles []*domain.Profile, current string) *profilePicker {
+	p := &profilePicker{profiles: profiles, current: current}
+	p.filter()
+
+	// The current profile is selected first
+	for i, profile := range p.matches {
+		if profile.Workspace().String() == current {
+			p.selected = i
+		}
+	}
+
+	p.scroll()
+	return p
+}
+
+// filter keeps the profiles matching the query, the best matches first
+func (p *profilePicker) filter() {
+	query := string(p.query)
+	scores := map[*domain.Profile]int{}
+
+	p.matches = p.matches[:0]
+	for _, profile := range p.profiles {
+		score, ok := fuzzyMatchProfile(query, profile)
+		if ok {
+			scores[profile] = score
+			p.matches = append(p.matches, profile)
+		}
+	}
+
+	sort.SliceStable(p.matches, func(i, j int) bool {
+		return scores[p.matches[i]] < scores[p.matches[j]]
+	})
+
+	p.selected = 0
+	p.offset = 0
+}
+
+// scroll moves the visible window of the list to the selected profile
+func (p *profilePicker) scroll() {
+	if p.selected < p.offset {
+		p.offset = p.selected
+	}
+
+	if p.selected >= p.offset+pickerMaxItems {
+		p.offset = p.selected - pickerMaxItems + 1
+	}
+}
+
+// handle applies the key, done is true when a profile is picked or the picker is cancelled
+func (p *profilePicker) handle(key pickerKey) (done bool, err error) {
+	switch key.code {
+	case pickerKeyUp:
+		if p.selected > 0 {
+			p.selected--
+		}
+	case pickerKeyDown:
+		if p.selected < len(p.matches)-1 {
+			p.selected++
+		}
+	case pickerKeyEnter:
+		if len(p.matches) > 0 {
+			return true, nil
+		}
+	case pickerKeyCancel:
+		return true, ErrPickerCancelled
+	case pickerKeyBackspace:
+		if len(p.query) > 0 {
+			p.query = p.query[:len(p.query)-1]
+			p.filter()
+		}
+	case pickerKeyClear:
+		p.query = nil
+		p.filter()
+	case pickerKeyRune:
+		p.query = append(p.query, key.r)
+		p.filter()
+	}
+
+	p.scroll()
+	return false, nil
+}
+
+func (p *profilePicker) selection() *domain.Profile {
+	if len(p.matches) == 0 {
+		return nil
+	}
+
+	return p.matches[p.selected]
+}
+
+// lines returns the picker as lines, the query, the visible profiles and the
+// preview of the selected profile, no longer than width
+func (p *profilePicker) lines(width int, color bool) []string {
+	style := func(code string, text string) string {
+		if !color {
+			return text
+		}
+
+		return "\033[" + code + "m" + text + "\033[0m"
+	}
+
+	lines := []string{fmt.Sprintf("Select a profile (%d/%d): %s", len(p.matches), len(p.profiles), string(p.query))}
+
+	size := 0
+	for _, profile := range p.profiles {
+		size = max(size, utf8.RuneCountInString(profile.Workspace().String()))
+	}
+
+	end := min(p.offset+pickerMaxItems, len(p.matches))
+	for i := p.offset; i < end; i++ {
+		profile := p.matches[i]
+		workspace := profile.Workspace().String()
+
+		marker := "  "
+		if i == p.selected {
+			marker = "> "
+		}
+
+		current := " "
+		if workspace == p.current {
+			current = "*"
+		}
+
+		text := fmt.Sprintf("%s%s %-*s  %s <%s>", marker, current, size, workspace, profile.Name().String(), profile.Email().String())
+		text = truncateText(text, width)
+
+		switch {
+		case i == p.selected:
+			text = style(ansiReverse, text)
+		case workspace == p.current:
+			text = style(colorGreen, text)
+		}
+
+		lines = append(lines, text)
+	}
+
+	if len(p.matches) == 0 {
+		lines = append(lines, "  No profile matches")
+	}
+
+	if profile := p.selection(); profile != nil {
+		var preview bytes.Buffer
+		writeProfileFields(&preview, profile, false)
+
+		lines = append(lines, "")
+		for _, line := range strings.Split(strings.TrimRight(preview.String(), "\n"), "\n") {
+			lines = append(lines, truncateText("  "+line, width))
+		}
+	}
+
+	lines[0] = truncateText(lines[0], width)
+	return lines
+}
+
+// fuzzyMatchProfile matches the query with the workspace, the email and the
+// name of the profile and returns the best score
+func fuzzyMatchProfile(query string, profile *domain.Profile) (int, bool) {
+	best, found := 0, false
+	for _, text := range []string{profile.Workspace().String(), profile.Email().String(), profile.Name().String()} {
+		if score, ok := fuzzyMatch(query, text); ok && (!found || score < best) {
+			best, found = score, true
+		}
+	}
+
+	return best, found
+}
+
+// fuzzyMatch reports whether the characters of the query appear in order in
+// the text, ignoring case and spaces. The score is lower for the matches that
+// start early and have fewer gaps.
+func fuzzyMatch(query string, text string) (int, bool) {
+	pattern := []rune(strings.ToLower(strings.ReplaceAll(query, " ", "")))
+	runes := []rune(strings.ToLower(text))
+
+	score, last, i := 0, -1, 0
+	for j := 0; j < len(runes) && i < len(pattern); j++ {
+		if runes[j] != pattern[i] {
+			continue
+		}
+
+		if last < 0 {
+			score += j
+		} else {
+			score += j - last - 1
+		}
+
+		last = j
+		i++
+	}
+
+	return score, i == len(pattern)
+}
+
+func truncateText(text string, width int) string {
+	if width <= 0 || utf8.RuneCountInString(text) <= width {
+		return text
+	}
+
+	return string([]rune(text)[:width])
+}
+
+// readPickerKeys reads the keys of the bytes the terminal sends in raw mode
+func readPickerKeys(input []byte) []pickerKey {
+	keys := []pickerKey{}
+
+	arrow := func(final byte) {
+		switch final {
+		case 'A':
+			keys = append(keys, pickerKey{code: pickerKeyUp})
+		case 'B':
+			keys = append(keys, pickerKey{code: pickerKeyDown})
+		}
+	}
+
+	for len(input) > 0 {
+		switch {
+		case bytes.HasPrefix(input, []byte("\033[")):
+			// A CSI sequence is made of parameter and intermediate bytes up to
+			// its final byte, only the arrows up and down are used
+			end := 2
+			for end < len(input) && input[end] >= 0x20 && input[end] < 0x40 {
+				end++
+			}
+
+			if end < len(input) && input[end] >= 0x40 && input[end] <= 0x7e {
+				arrow(input[end])
+				end++
+			}
+
+			input = input[end:]
+			continue
+		case bytes.HasPrefix(input, []byte("\033O")) && len(input) > 2:
+			// The arrows of the application cursor mode, SS3 and a single byte
+			arrow(input[2])
+			input = input[3:]
+			continue
+		case input[0] == '\033' && len(input) > 1:
+			// A key pressed with alt is ignored
+			_, size := utf8.DecodeRune(input[1:])
+			input = input[1+size:]
+			continue
+		}
+
+		r, size := utf8.DecodeRune(input)
+		input = input[size:]
+
+		switch r {
+		case '\033', 0x03, 0x04:
+			keys = append(keys, pickerKey{code: pickerKeyCancel})
+		case '\r', '\n':
+			keys = append(keys, pickerKey{code: pickerKeyEnter})
+		case 0x10:
+			keys = append(keys, pickerKey{code: pickerKeyUp})
+		case 0x0e:
+			keys = append(keys, pickerKey{code: pickerKeyDown})
+		case 0x7f, 0x08:
+			keys = append(keys, pickerKey{code: pickerKeyBackspace})
+		case 0x15:
+			keys = append(keys, pickerKey{code: pickerKeyClear})
+		default:
+			if r != utf8.RuneError && unicode.IsPrint(r) {
+				keys = append(keys, pickerKey{code: pickerKeyRune, r: r})
+			}
+		}
+	}
+
+	return keys
+}
+
+// terminalFiles returns the input and the output of the command when both are terminals
+func terminalFiles(cmd *cobra.Command) (*os.File, *os.File, bool) {
+	input, ok := cmd.InOrStdin().(*os.File)
+	if !ok || !term.IsTerminal(int(input.Fd())) { // #nosec G115
+		return nil, nil, false
+	}
+
+	output, ok := cmd.OutOrStderr().(*os.File)
+	if !ok || !term.IsTerminal(int(output.Fd())) { // #nosec G115
+		return nil, nil, false
+	}
+
+	return input, output, true
+}
+
+// pickWorkspace lets the user pick a profile with the arrow keys and a fuzzy
+// filter on the workspace, email and name, the current profile is highlighted.
+// ErrPickerNotTerminal is returned when the input or the output is not a
+// terminal, the command then asks for the workspace as before.
+func pickWorkspace(
+	cmd *cobra.Command,
+	listProfileService *application.ListProfileService,
+	currentProfileService *application.CurrentProfileService,
+) (string, error) {
+	input, output, ok := terminalFiles(cmd)
+	if !ok {
+		return "", ErrPickerNotTerminal
+	}
+
+	profiles, err := listProfileService.Execute()
+	if err != nil {
+		return "", err
+	}
+
+	if len(profiles) == 0 {
+		return "", ErrPickerNoProfiles
+	}
+
+	current := ""
+	if currentProfileService != nil {
+		if profile, err := currentProfileService.Execute(); err == nil {
+			current = profile.Workspace().String()
+		}
+	}
+
+	state, err := term.MakeRaw(int(input.Fd())) // #nosec G115
+	if err != nil {
+		return "", ErrPickerNotTerminal
+	}
+
+	// nolint
+	defer term.Restore(int(input.Fd()), state) // #nosec G104
+
+	picker := newProfilePicker(profiles, current)
+	color := os.Getenv(noColorEnvName) == ""
+
+	drawn := 0
+	draw := func(lines []string) {
+		var frame strings.Builder
+		if drawn > 0 {
+			fmt.Fprintf(&frame, "\033[%dA", drawn)
+		}
+
+		frame.WriteString("\r" + ansiClearDown + strings.Join(lines, "\r\n"))
+		drawn = len(lines) - 1
+
+		// nolint
+		io.WriteString(output, frame.String()) // #nosec G104
+	}
+
+	// nolint
+	io.WriteString(output, ansiHideCursor) // #nosec G104
+	defer func() {
+		draw([]string{""})
+		// nolint
+		io.WriteString(output, ansiShowCursor) // #nosec G104
+	}()
+
+	buffer := make([]byte, 64)
+	for {
+		width, _, err := term.GetSize(int(output.Fd())) // #nosec G115
+		if err != nil {
+			width = 0
+		}
+
+		draw(picker.lines(width-1, color))
+
+		n, err := input.Read(buffer)
+		if err != nil {
+			return "", ErrPickerCancelled
+		}
+
+		for _, key := range readPickerKeys(buffer[:n]) {
+			done, err := picker.handle(key)
+			if err != nil {
+				return "", err
+			}
+
+			if done {
+				return picker.selection().Workspace().String(), nil
+			}
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/b4nd/git-profile/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		query string
+		text  string
+		score int
+		ok    bool
+	}{
+		{"", "work", 0, true},
+		{"work", "work", 0, true},
+		{"WoRk", "work", 0, true},
+		{"wk", "work", 2, true},
+		{"ork", "work", 1, true},
+		{"jane doe", "Jane Doe", 1, true},
+		{"acme", "jane@acme.com", 5, true},
+		{"kw", "work", 0, false},
+		{"works", "work", 0, false},
+	}
+
+	for _, test := range tests {
+		score, ok := fuzzyMatch(test.query, test.text)
+		assert.Equal(t, test.ok, ok, test.query+" in "+test.text)
+		if test.ok {
+			assert.Equal(t, test.score, score, test.query+" in "+test.text)
+		}
+	}
+}
+
+func TestReadPickerKeys(t *testing.T) {
+	up := pickerKey{code: pickerKeyUp}
+	down := pickerKey{code: pickerKeyDown}
+	runeKey := func(r rune) pickerKey {
+		return pickerKey{code: pickerKeyRune, r: r}
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		keys  []pickerKey
+	}{
+		{"arrows", "\033[A\033[B\033OA\033OB", []pickerKey{up, down, up, down}},
+		{"control keys", "\x10\x0e\r\x7f\x15", []pickerKey{up, down, {code: pickerKeyEnter}, {code: pickerKeyBackspace}, {code: pickerKeyClear}}},
+		{"cancel", "\x03", []pickerKey{{code: pickerKeyCancel}}},
+		{"escape alone", "\033", []pickerKey{{code: pickerKeyCancel}}},
+		{"runes", "wé", []pickerKey{runeKey('w'), runeKey('é')}},
+		{"left and right arrows", "\033[Cw\033[Do", []pickerKey{runeKey('w'), runeKey('o')}},
+		{"sequence with parameters", "\033[1;5Cw\033[3~o\033[1;2A", []pickerKey{runeKey('w'), runeKey('o'), up}},
+		{"bracketed paste", "\033[200~work\033[201~", []pickerKey{runeKey('w'), runeKey('o'), runeKey('r'), runeKey('k')}},
+		{"application cursor right", "\033OCw", []pickerKey{runeKey('w')}},
+		{"alt with a key", "\033xw", []pickerKey{runeKey('w')}},
+		{"unfinished sequence", "w\033[1;", []pickerKey{runeKey('w')}},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.keys, readPickerKeys([]byte(test.input)), test.name)
+	}
+}
+
+func TestProfilePicker(t *testing.T) {
+	newProfile := func(workspace string, email string, name string) *domain.Profile {
+		profile, err := domain.NewProfile(workspace, email, name)
+		assert.NoError(t, err)
+
+		return profile
+	}
+
+	profiles := []*domain.Profile{
+		newProfile("work", "jane@acme.com", "Jane Doe"),
+		newProfile("personal", "jane@home.org", "Jane Doe"),
+		newProfile("client", "jdoe@client.io", "J. Doe"),
+	}
+
+	typeQuery := func(picker *profilePicker, query string) {
+		for _, r := range query {
+			done, err := picker.handle(pickerKey{code: pickerKeyRune, r: r})
+			assert.NoError(t, err)
+			assert.False(t, done)
+		}
+	}
+
+	t.Run("should select the current profile first", func(t *testing.T) {
+		picker := newProfilePicker(profiles, "personal")
+		assert.Equal(t, "personal", picker.selection().Workspace().String())
+	})
+
+	t.Run("should move the selection within the matches", func(t *testing.T) {
+		picker := newProfilePicker(profiles, "")
+
+		for _, key := range []int{pickerKeyUp, pickerKeyDown, pickerKeyDown, pickerKeyDown} {
+			done, err := picker.handle(pickerKey{code: key})
+			assert.NoError(t, err)
+			assert.False(t, done)
+		}
+
+		assert.Equal(t, "client", picker.selection().Workspace().String())
+
+		done, err := picker.handle(pickerKey{code: pickerKeyEnter})
+		assert.NoError(t, err)
+		assert.True(t, done)
+	})
+
+	t.Run("should filter the profiles with the query", func(t *testing.T) {
+		picker := newProfilePicker(profiles, "work")
+
+		typeQuery(picker, "home")
+		assert.Len(t, picker.matches, 1)
+		assert.Equal(t, "personal", picker.selection().Workspace().String())
+
+		typeQuery(picker, "x")
+		assert.Empty(t, picker.matches)
+		assert.Nil(t, picker.selection())
+
+		// Enter does nothing without a match
+		done, err := picker.handle(pickerKey{code: pickerKeyEnter})
+		assert.NoError(t, err)
+		assert.False(t, done)
+
+		_, err = picker.handle(pickerKey{code: pickerKeyBackspace})
+		assert.NoError(t, err)
+		assert.Equal(t, "home", string(picker.query))
+		assert.Len(t, picker.matches, 1)
+
+		_, err = picker.handle(pickerKey{code: pickerKeyClear})
+		assert.NoError(t, err)
+		assert.Empty(t, picker.query)
+		assert.Len(t, picker.matches, 3)
+	})
+
+	t.Run("should return an error when the picker is cancelled", func(t *testing.T) {
+		picker := newProfilePicker(profiles, "")
+
+		done, err := picker.handle(pickerKey{code: pickerKeyCancel})
+		assert.ErrorIs(t, err, ErrPickerCancelled)
+		assert.True(t, done)
+	})
+
+	t.Run("should draw the query, the profiles and the preview", func(t *testing.T) {
+		picker := newProfilePicker(profiles, "personal")
+
+		lines := picker.lines(0, false)
+		assert.Equal(t, "Select a profile (3/3): ", lines[0])
+		assert.Equal(t, "    work      Jane Doe <jane@acme.com>", lines[1])
+		assert.Equal(t, "> * personal  Jane Doe <jane@home.org>", lines[2])
+		assert.Equal(t, "    client    J. Doe <jdoe@client.io>", lines[3])
+		assert.Equal(t, "", lines[4])
+		assert.Contains(t, strings.Join(lines[5:], "\n"), "jane@home.org")
+
+		// The lines are truncated to the width and the selection is reversed with colors
+		lines = picker.lines(12, true)
+		assert.Equal(t, "Select a pro", lines[0])
+		assert.Equal(t, "\033[7m> * personal\033[0m", lines[2])
+
+		typeQuery(picker, "nomatch")
+		lines = picker.lines(0, false)
+		assert.Equal(t, []string{"Select a profile (0/3): nomatch", "  No profile matches"}, lines)
+	})
+
+	t.Run("should scroll the list to the selected profile", func(t *testing.T) {
+		many := make([]*domain.Profile, 0, pickerMaxItems+5)
+		for i := 0; i < pickerMaxItems+5; i++ {
+			many = append(many, newProfile("profile-"+string(rune('a'+i)), "jane@acme.com", "Jane Doe"))
+		}
+
+		picker := newProfilePicker(many, "")
+		for i := 0; i < pickerMaxItems+2; i++ {
+			_, err := picker.handle(pickerKey{code: pickerKeyDown})
+			assert.NoError(t, err)
+		}
+
+		assert.Equal(t, 3, picker.offset)
+		lines := picker.lines(0, false)
+		assert.True(t, strings.HasPrefix(lines[1], "    profile-d"))
+		assert.True(t, strings.HasPrefix(lines[pickerMaxItems], "> "))
+	})
+}
@@ -0,0 +1,368 @@
+package command
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/b4nd/git-profile/pkg/application"
+	"github.com/b4nd/git-profile/pkg/domain"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Formats of the exported and imported profiles
+const (
+	RecordFormatJson = "json"
+	RecordFormatYaml = "yaml"
+	RecordFormatToml = "toml"
+	RecordFormatCsv  = "csv"
+)
+
+var ErrInvalidRecordFormat = errors.New("invalid record format")
+
+// recordCsvHeader are the columns of the csv format, the remotes and the
+// host=username pairs are separated by spaces and the git config key=value
+// pairs by new lines, which their values cannot contain
+var recordCsvHeader = []string{
+	"workspace", "extends", "email", "name", "signingKey", "signingFormat", "signCommits", "signTags", "sshKey", "remotes", "usernames", "config",
+}
+
+// profileRecord is the schema of an exported profile, it holds what is
+// needed to create the profile again. A profile that extends another one
+// only holds the fields it defines itself, the other ones are inherited
+type profileRecord struct {
+	Workspace string            `json:"workspace" yaml:"workspace" toml:"workspace"`
+	Extends   string            `json:"extends,omitempty" yaml:"extends,omitempty" toml:"extends,omitempty"`
+	Email     string            `json:"email,omitempty" yaml:"email,omitempty" toml:"email,omitempty"`
+	Name      string            `json:"name,omitempty" yaml:"name,omitempty" toml:"name,omitempty"`
+	Signing   *signingRecord    `json:"signing,omitempty" yaml:"signing,omitempty" toml:"signing,omitempty"`
+	SshKey    string            `json:"sshKey,omitempty" yaml:"sshKey,omitempty" toml:"sshKey,omitempty"`
+	Remotes   []string          `json:"remotes,omitempty" yaml:"remotes,omitempty" toml:"remotes,omitempty"`
+	Usernames map[string]string `json:"usernames,omitempty" yaml:"usernames,omitempty" toml:"usernames,omitempty"`
+	Config    map[string]string `json:"config,omitempty" yaml:"config,omitempty" toml:"config,omitempty"`
+}
+
+type signingRecord struct {
+	Key         string `json:"key" yaml:"key" toml:"key"`
+	Format      string `json:"format" yaml:"format" toml:"format"`
+	SignCommits bool   `json:"signCommits" yaml:"signCommits" toml:"signCommits"`
+	SignTags    bool   `json:"signTags" yaml:"signTags" toml:"signTags"`
+}
+
+// tomlProfileRecords is the toml document, a [[profile]] table per profile
+type tomlProfileRecords struct {
+	Profiles []profileRecord `toml:"profile"`
+}
+
+// newProfileRecord returns the record of the fields the profile defines
+// itself, the parent is the resolved profile it extends, nil when it extends none
+func newProfileRecord(profile *domain.Profile, parent *domain.Profile) profileRecord {
+	owns := func(field domain.ProfileField) bool {
+		return profile.Origin(field).Equals(profile.Workspace())
+	}
+
+	record := profileRecord{
+		Workspace: profile.Workspace().String(),
+		Extends:   profile.Extends().String(),
+	}
+
+	if owns(domain.ProfileFieldEmail) {
+		record.Email = profile.Email().String()
+	}
+
+	if owns(domain.ProfileFieldName) {
+		record.Name = profile.Name().String()
+	}
+
+	if owns(domain.ProfileFieldSshKey) {
+		record.SshKey = profile.SshKey().String()
+	}
+
+	if signing := profile.Signing(); !signing.IsEmpty() && owns(domain.ProfileFieldSigning) {
+		record.Signing = &signingRecord{
+			Key:         signing.Key(),
+			Format:      signing.Format(),
+			SignCommits: signing.CommitSign(),
+			SignTags:    signing.TagSign(),
+		}
+	}
+
+	if owns(domain.ProfileFieldRemotes) {
+		for _, remote := range profile.Remotes() {
+			record.Remotes = append(record.Remotes, remote.String())
+		}
+	}
+
+	if owns(domain.ProfileFieldUsernames) {
+		for _, username := range profile.Usernames() {
+			if record.Usernames == nil {
+				record.Usernames = map[string]string{}
+			}
+
+			record.Usernames[username.Host()] = username.Username()
+		}
+	}
+
+	if config := profile.Config(); !config.IsEmpty() && owns(domain.ProfileFieldConfig) {
+		record.Config = config.Values()
+	}
+
+	// The git configuration is merged with the parent one, only the keys the
+	// profile sets to another value are its own
+	if parent != nil {
+		inherited := parent.Config().Values()
+		for key, value := range record.Config {
+			if parentValue, ok := inherited[key]; ok && parentValue == value {
+				delete(record.Config, key)
+			}
+		}
+
+		if len(record.Config) == 0 {
+			record.Config = nil
+		}
+	}
+
+	return record
+}
+
+func (r profileRecord) params() application.CreateProfileServiceParams {
+	params := application.CreateProfileServiceParams{
+		Workspace: r.Workspace,
+		Extends:   r.Extends,
+		Email:     r.Email,
+		Name:      r.Name,
+		SshKey:    r.SshKey,
+		Remotes:   r.Remotes,
+		Usernames: r.Usernames,
+		Config:    r.Config,
+	}
+
+	if r.Signing != nil {
+		params.SigningKey = r.Signing.Key
+		params.SigningFormat = r.Signing.Format
+		params.CommitSign = r.Signing.SignCommits
+		params.TagSign = r.Signing.SignTags
+	}
+
+	return params
+}
+
+// recordFormatFromPath returns the format given by the extension of the file,
+// json is used for the standard input and unknown extensions
+func recordFormatFromPath(path string) string {
+	switch strings.ToLower(strings.TrimPrefix(filepath.Ext(path), ".")) {
+	case "yaml", "yml":
+		return RecordFormatYaml
+	case RecordFormatToml:
+		return RecordFormatToml
+	case RecordFormatCsv:
+		return RecordFormatCsv
+	}
+
+	return RecordFormatJson
+}
+
+// encodeProfileRecords writes the profiles in the given format, the parents
+// are the resolved profiles they extend by workspace
+func encodeProfileRecords(writer io.Writer, format string, profiles []*domain.Profile, parents map[string]*domain.Profile) error {
+	records := make([]profileRecord, 0, len(profiles))
+	for _, profile := range profiles {
+		records = append(records, newProfileRecord(profile, parents[profile.Extends().String()]))
+	}
+
+	switch format {
+	case RecordFormatJson:
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(records)
+	case RecordFormatYaml:
+		encoder := yaml.NewEncoder(writer)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(records); err != nil {
+			return err
+		}
+
+		return encoder.Close()
+	case RecordFormatToml:
+		return toml.NewEncoder(writer).Encode(tomlProfileRecords{records})
+	case RecordFormatCsv:
+		return encodeCsvProfileRecords(writer, records)
+	}
+
+	return fmt.Errorf("%w: %s", ErrInvalidRecordFormat, format)
+}
+
+// decodeProfileRecords reads the profiles written in the given format
+func decodeProfileRecords(reader io.Reader, format string) ([]application.CreateProfileServiceParams, error) {
+	records := make([]profileRecord, 0)
+
+	switch format {
+	case RecordFormatJson:
+		if err := json.NewDecoder(reader).Decode(&records); err != nil {
+			return nil, err
+		}
+	case RecordFormatYaml:
+		if err := yaml.NewDecoder(reader).Decode(&records); err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+	case RecordFormatToml:
+		var document tomlProfileRecords
+		if _, err := toml.NewDecoder(reader).Decode(&document); err != nil {
+			return nil, err
+		}
+
+		records = document.Profiles
+	case RecordFormatCsv:
+		decoded, err := decodeCsvProfileRecords(reader)
+		if err != nil {
+			return nil, err
+		}
+
+		records = decoded
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrInvalidRecordFormat, format)
+	}
+
+	params := make([]application.CreateProfileServiceParams, 0, len(records))
+	for _, record := range records {
+		params = append(params, record.params())
+	}
+
+	return params, nil
+}
+
+func encodeCsvProfileRecords(writer io.Writer, records []profileRecord) error {
+	csvWriter := csv.NewWriter(writer)
+	if err := csvWriter.Write(recordCsvHeader); err != nil {
+		return err
+	}
+
+	for _, record := range records {
+		signing := signingRecord{}
+		if record.Signing != nil {
+			signing = *record.Signing
+		}
+
+		row := []string{
+			record.Workspace,
+			record.Extends,
+			record.Email,
+			record.Name,
+			signing.Key,
+			signing.Format,
+			strconv.FormatBool(signing.SignCommits),
+			strconv.FormatBool(signing.SignTags),
+			record.SshKey,
+			strings.Join(record.Remotes, " "),
+			strings.Join(keyValuePairs(record.Usernames), " "),
+			strings.Join(keyValuePairs(record.Config), "\n"),
+		}
+
+		if err := csvWriter.Write(row); err != nil {
+			return err
+		}
+	}
+
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+// decodeCsvProfileRecords reads the rows by the names of the header, so the
+// columns can be in any order and the optional ones can be left out
+func decodeCsvProfileRecords(reader io.Reader) ([]profileRecord, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+
+	rows, err := csvReader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]profileRecord, 0)
+	if len(rows) == 0 {
+		return records, nil
+	}
+
+	columns := map[string]int{}
+	for i, column := range rows[0] {
+		columns[strings.TrimSpace(column)] = i
+	}
+
+	for _, row := range rows[1:] {
+		value := func(column string) string {
+			i, ok := columns[column]
+			if !ok || i >= len(row) {
+				return ""
+			}
+
+			return strings.TrimSpace(row[i])
+		}
+
+		record := profileRecord{
+			Workspace: value("workspace"),
+			Extends:   value("extends"),
+			Email:     value("email"),
+			Name:      value("name"),
+			SshKey:    value("sshKey"),
+			Remotes:   strings.Fields(value("remotes")),
+		}
+
+		for _, pair := range strings.Fields(value("usernames")) {
+			host, username, _ := strings.Cut(pair, "=")
+			if record.Usernames == nil {
+				record.Usernames = map[string]string{}
+			}
+
+			record.Usernames[host] = username
+		}
+
+		for _, line := range strings.Split(value("config"), "\n") {
+			key, value, found := strings.Cut(strings.TrimSpace(line), "=")
+			if !found {
+				continue
+			}
+
+			if record.Config == nil {
+				record.Config = map[string]string{}
+			}
+
+			record.Config[key] = value
+		}
+
+		if key := value("signingKey"); key != "" {
+			signCommits, _ := strconv.ParseBool(value("signCommits"))
+			signTags, _ := strconv.ParseBool(value("signTags"))
+
+			record.Signing = &signingRecord{
+				Key:         key,
+				Format:      value("signingFormat"),
+				SignCommits: signCommits,
+				SignTags:    signTags,
+			}
+		}
+
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// keyValuePairs returns the key=value pairs of the usernames or the git config
+// keys sorted by key
+func keyValuePairs(pairs map[string]string) []string {
+	values := make([]string, 0, len(pairs))
+	for key, value := range pairs {
+		values = append(values, key+"="+value)
+	}
+
+	sort.Strings(values)
+	return values
+}
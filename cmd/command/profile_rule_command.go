@@ -0,0 +1,221 @@
+package command
+
+import (
+	"github.com/b4nd/git-profile/pkg/application"
+
+	"github.com/spf13/cobra"
+)
+
+type ProfileRuleCommand struct {
+	createProfileRuleService *application.CreateProfileRuleService
+	listProfileRuleService   *application.ListProfileRuleService
+	deleteProfileRuleService *application.DeleteProfileRuleService
+	applyProfileRulesService *application.ApplyProfileRulesService
+}
+
+func NewProfileRuleCommand(
+	createProfileRuleService *application.CreateProfileRuleService,
+	listProfileRuleService *application.ListProfileRuleService,
+	deleteProfileRuleService *application.DeleteProfileRuleService,
+	applyProfileRulesService *application.ApplyProfileRulesService,
+) *ProfileRuleCommand {
+	return &ProfileRuleCommand{
+		createProfileRuleService,
+		listProfileRuleService,
+		deleteProfileRuleService,
+		applyProfileRulesService,
+	}
+}
+
+type ProfileRuleCommandParams struct {
+	Workspace string
+	GitDirs   []string
+	Remotes   []string
+}
+
+func (c *ProfileRuleCommand) Register(rootCmd *cobra.Command) {
+	cmd := &cobra.Command{
+		Use: "rules [command]",
+		Aliases: []string{
+			"rule",
+		},
+		Short: "Manages the rules that select a profile by directory or remote.",
+		Long: `Manage the rules that select a profile by directory or remote url.
+Once applied, the rules are written as includeIf sections into the global git
+configuration, so git selects the identity by itself without running git profile.
+`,
+		Example: `  git profile rule add work --gitdir ~/work/
+  git profile rule add work --remote "https://github.com/acme-corp/**"
+  git profile rules list
+  git profile rules remove work --gitdir ~/work/
+  git profile rules apply`,
+	}
+
+	var add ProfileRuleCommandParams
+	addCmd := &cobra.Command{
+		Use:   "add [-w workspace] [--gitdir dir] [--remote url]",
+		Short: "Adds a rule that selects the profile.",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if add.Workspace == "" && len(args) > 0 {
+				add.Workspace = args[0]
+			}
+
+			return c.ExecuteAdd(cmd, add)
+		},
+	}
+
+	addCmd.Flags().StringVarP(&add.Workspace, "workspace", "w", "", "The workspace of the profile")
+	addCmd.Flags().StringArrayVar(&add.GitDirs, "gitdir", nil, "Select the profile for the repositories inside the directory")
+	addCmd.Flags().StringArrayVar(&add.Remotes, "remote", nil, "Select the profile for the repositories with a remote url matching the pattern")
+
+	var remove ProfileRuleCommandParams
+	removeCmd := &cobra.Command{
+		Use: "remove [-w workspace] [--gitdir dir] [--remote url]",
+		Aliases: []string{
+			"rm",
+		},
+		Short: "Removes the rules of a profile or a condition.",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if remove.Workspace == "" && len(args) > 0 {
+				remove.Workspace = args[0]
+			}
+
+			return c.ExecuteRemove(cmd, remove)
+		},
+	}
+
+	removeCmd.Flags().StringVarP(&remove.Workspace, "workspace", "w", "", "The workspace of the profile")
+	removeCmd.Flags().StringArrayVar(&remove.GitDirs, "gitdir", nil, "Remove the rule of the directory")
+	removeCmd.Flags().StringArrayVar(&remove.Remotes, "remote", nil, "Remove the rule of the remote url pattern")
+
+	listCmd := &cobra.Command{
+		Use: "list",
+		Aliases: []string{
+			"ls",
+		},
+		Short: "Lists all rules.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return c.ExecuteList(cmd)
+		},
+	}
+
+	applyCmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Writes the rules into the global git configuration.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return c.ExecuteApply(cmd)
+		},
+	}
+
+	supportOutput(listCmd)
+	cmd.AddCommand(addCmd, removeCmd, listCmd, applyCmd)
+	rootCmd.AddCommand(cmd)
+}
+
+func (c *ProfileRuleCommand) ExecuteAdd(cmd *cobra.Command, params ProfileRuleCommandParams) error {
+	rules, err := c.createProfileRuleService.Execute(application.CreateProfileRuleServiceParams{
+		Workspace: params.Workspace,
+		GitDirs:   params.GitDirs,
+		Remotes:   params.Remotes,
+	})
+
+	if err != nil {
+		printErrorMessage(cmd, err, params.Workspace)
+		return nil
+	}
+
+	for _, rule := range rules {
+		cmd.Printf("Rule \"%s\" added to profile \"%s\"\n", rule.Condition(), rule.Workspace())
+	}
+
+	cmd.Printf("\nSuggest to apply the rules with the following command:\n")
+	cmd.Printf("  git profile rules apply\n")
+
+	return nil
+}
+
+func (c *ProfileRuleCommand) ExecuteRemove(cmd *cobra.Command, params ProfileRuleCommandParams) error {
+	rules, err := c.deleteProfileRuleService.Execute(application.DeleteProfileRuleServiceParams{
+		Workspace: params.Workspace,
+		GitDirs:   params.GitDirs,
+		Remotes:   params.Remotes,
+	})
+
+	if err != nil {
+		printErrorMessage(cmd, err, params.Workspace)
+		return nil
+	}
+
+	for _, rule := range rules {
+		cmd.Printf("Rule \"%s\" removed from profile \"%s\"\n", rule.Condition(), rule.Workspace())
+	}
+
+	cmd.Printf("\nSuggest to apply the rules with the following command:\n")
+	cmd.Printf("  git profile rules apply\n")
+
+	return nil
+}
+
+func (c *ProfileRuleCommand) ExecuteList(cmd *cobra.Command) error {
+	output, err := newOutput(cmd)
+	if err != nil {
+		return err
+	}
+
+	rules, err := c.listProfileRuleService.Execute()
+	if err != nil {
+		printErrorMessage(cmd, err, "")
+		return nil
+	}
+
+	if !output.IsTable() {
+		values := make([]ruleOutput, 0, len(rules))
+		for _, rule := range rules {
+			values = append(values, ruleOutput{
+				Workspace: rule.Workspace().String(),
+				Condition: rule.Condition().String(),
+			})
+		}
+
+		return output.Print(cmd, values)
+	}
+
+	if len(rules) == 0 {
+		cmd.Println("No rules found")
+		return nil
+	}
+
+	for _, rule := range rules {
+		cmd.Printf("%s -> %s\n", rule.Condition(), rule.Workspace())
+	}
+
+	return nil
+}
+
+func (c *ProfileRuleCommand) ExecuteApply(cmd *cobra.Command) error {
+	includes, err := c.applyProfileRulesService.Execute()
+	if err == application.ErrProfileNotExists {
+		cmd.Printf("A rule uses a profile that does not exist, suggest to check the rules with the following command:\n")
+		cmd.Printf("  git profile rules list\n")
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if len(includes) == 0 {
+		cmd.Println("No rules found, the rules managed by git profile were removed")
+		return nil
+	}
+
+	for _, include := range includes {
+		cmd.Printf("Applied \"%s\" -> %s\n", include.Condition, include.User.Workespace)
+	}
+
+	return nil
+}
@@ -0,0 +1,164 @@
+package command
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/b4nd/git-profile/pkg/application"
+
+	"github.com/spf13/cobra"
+)
+
+// Shells of the prompt segment and its snippets
+const (
+	PromptShellBash     = "bash"
+	PromptShellZsh      = "zsh"
+	PromptShellFish     = "fish"
+	PromptShellStarship = "starship"
+)
+
+// Markers added to the workspace when the repository does not match its profile
+const (
+	promptMismatchMarker = "!"
+	promptMissingMarker  = "?"
+)
+
+// promptSnippets add the segment in front of the prompt of each shell
+var promptSnippets = map[string]string{
+	PromptShellBash: `# git profile: add to ~/.bashrc
+__git_profile_ps1() {
+  local segment
+  segment="$(git profile prompt --shell bash 2>/dev/null)"
+  [ -n "$segment" ] && printf '[%s] ' "$segment"
+}
+PS1='$(__git_profile_ps1)'"$PS1"
+`,
+	PromptShellZsh: `# git profile: add to ~/.zshrc
+__git_profile_ps1() {
+  local segment
+  segment="$(git profile prompt --shell zsh 2>/dev/null)"
+  [[ -n "$segment" ]] && print -n "[$segment] "
+}
+setopt prompt_subst
+PROMPT='$(__git_profile_ps1)'"$PROMPT"
+`,
+	PromptShellFish: `# git profile: add to ~/.config/fish/config.fish
+function __git_profile_prompt
+    set -l segment (git profile prompt --shell fish 2>/dev/null)
+    test -n "$segment"; and printf '[%s] ' $segment
+end
+
+functions -q fish_prompt; and functions -c fish_prompt __git_profile_fish_prompt
+function fish_prompt
+    __git_profile_prompt
+    functions -q __git_profile_fish_prompt; and __git_profile_fish_prompt
+end
+`,
+	PromptShellStarship: `# git profile: add to ~/.config/starship.toml
+[custom.git_profile]
+description = "The git profile of the repository"
+command = "git profile prompt"
+when = true
+require_repo = true
+format = '[\[$output\]]($style) '
+style = "bold green"
+`,
+}
+
+type PromptCommand struct {
+	promptProfileService *application.PromptProfileService
+}
+
+func NewPromptCommand(promptProfileService *application.PromptProfileService) *PromptCommand {
+	return &PromptCommand{promptProfileService}
+}
+
+func (c *PromptCommand) Register(rootCmd *cobra.Command) {
+	var shell string
+	var init string
+
+	cmd := &cobra.Command{
+		Use:   "prompt [--shell bash|zsh|fish] [--init bash|zsh|fish|starship]",
+		Short: "Prints the workspace of the repository for the shell prompt.",
+		Long: `Print a compact segment with the workspace of the repository for the shell prompt,
+followed by "!" when the name or email of the repository differs from the profile
+and by "?" when the profile does not exist. Nothing is printed outside a repository
+with a profile. Only the local git configuration is read and the profiles are kept
+in a cache that is refreshed when a profile file changes.
+The segment is colored for the shell given with --shell unless NO_COLOR is set,
+--init prints a snippet adding the segment to the prompt of the shell.
+`,
+		Example: `  git profile prompt
+  git profile prompt --shell zsh
+  git profile prompt --init bash >> ~/.bashrc`,
+		Args:          cobra.NoArgs,
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if init != "" {
+				return c.Init(cmd, init)
+			}
+
+			return c.Execute(cmd, shell)
+		},
+	}
+
+	cmd.Flags().StringVar(&shell, "shell", "", "Color the segment for the prompt of bash, zsh or fish")
+	cmd.Flags().StringVar(&init, "init", "", "Print the snippet for bash, zsh, fish or starship")
+
+	rootCmd.AddCommand(cmd)
+}
+
+func (c *PromptCommand) Execute(cmd *cobra.Command, shell string) error {
+	if shell != "" && shell != PromptShellBash && shell != PromptShellZsh && shell != PromptShellFish {
+		cmd.Printf("The shell must be bash, zsh or fish.\n")
+		return nil
+	}
+
+	result, err := c.promptProfileService.Execute()
+
+	var segment, color string
+	switch err {
+	case nil:
+		segment, color = result.Workspace, colorGreen
+	case application.ErrProfileMismatch:
+		segment, color = result.Workspace+promptMismatchMarker, colorRed
+	case application.ErrProfileNotExists:
+		segment, color = result.Workspace+promptMissingMarker, colorYellow
+	default:
+		// Nothing is printed when the repository has no profile
+		return nil
+	}
+
+	_, err = fmt.Fprint(cmd.OutOrStdout(), colorizePrompt(shell, color, segment))
+	return err
+}
+
+func (c *PromptCommand) Init(cmd *cobra.Command, shell string) error {
+	snippet, ok := promptSnippets[shell]
+	if !ok {
+		cmd.Printf("The shell must be bash, zsh, fish or starship.\n")
+		return nil
+	}
+
+	_, err := fmt.Fprint(cmd.OutOrStdout(), snippet)
+	return err
+}
+
+// colorizePrompt wraps the text with the ANSI color code, the codes are
+// marked as non printing so that the shell computes the prompt width
+func colorizePrompt(shell string, code string, text string) string {
+	if shell == "" || os.Getenv(noColorEnvName) != "" {
+		return text
+	}
+
+	start, end := "\033["+code+"m", "\033[0m"
+	switch shell {
+	case PromptShellBash:
+		return "\001" + start + "\002" + text + "\001" + end + "\002"
+	case PromptShellZsh:
+		return "%{" + start + "%}" + text + "%{" + end + "%}"
+	default:
+		return start + text + end
+	}
+}
@@ -0,0 +1,88 @@
+package command
+
+import (
+	"github.com/b4nd/git-profile/pkg/application"
+
+	"github.com/spf13/cobra"
+)
+
+type RenameProfileCommand struct {
+	renameProfileService *application.RenameProfileService
+	listProfileService   *application.ListProfileService
+}
+
+func NewRenameProfileCommand(
+	renameProfileService *application.RenameProfileService,
+	listProfileService *application.ListProfileService,
+) *RenameProfileCommand {
+	return &RenameProfileCommand{renameProfileService, listProfileService}
+}
+
+func (c *RenameProfileCommand) Register(rootCmd *cobra.Command) {
+	var dirs []string
+
+	cmd := &cobra.Command{
+		Use:   "rename <workspace> <new-workspace> [--walk dir]",
+		Short: "Renames a profile and the repositories that use it.",
+		Long: `Rename a profile in the file that holds it, together with its rules and the
+local and global git configurations that reference the old workspace.
+The git repositories found under the directories given with --walk are updated too.
+`,
+		Example: `  git profile rename acme acme-corp
+  git profile rename acme acme-corp --walk ~/work`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return c.Execute(cmd, application.RenameProfileServiceParams{
+				Workspace:    args[0],
+				NewWorkspace: args[1],
+				Dirs:         dirs,
+			})
+		},
+	}
+
+	cmd.Flags().StringArrayVar(&dirs, "walk", nil, "A directory whose git repositories are updated")
+
+	// Only the current workspace is completed, the new one is free
+	registerWorkspaceCompletion(cmd, c.listProfileService, 1)
+
+	rootCmd.AddCommand(cmd)
+}
+
+func (c *RenameProfileCommand) Execute(cmd *cobra.Command, params application.RenameProfileServiceParams) error {
+	result, err := c.renameProfileService.Execute(params)
+
+	if err == application.ErrProfileAlreadyExists {
+		printErrorMessage(cmd, err, params.NewWorkspace)
+		return nil
+	}
+
+	if err != nil {
+		printErrorMessage(cmd, err, params.Workspace)
+		return nil
+	}
+
+	cmd.Printf("Profile \"%s\" renamed to \"%s\"\n", params.Workspace, result.Profile.Workspace())
+
+	for _, rule := range result.Rules {
+		cmd.Printf("Rule \"%s\" moved to profile \"%s\"\n", rule.Condition(), rule.Workspace())
+	}
+
+	if result.Local {
+		cmd.Printf("Updated the local git configuration\n")
+	}
+
+	if result.Global {
+		cmd.Printf("Updated the global git configuration\n")
+	}
+
+	for _, repository := range result.Repositories {
+		cmd.Printf("Updated the repository %s\n", repository)
+	}
+
+	if len(result.Rules) > 0 {
+		cmd.Printf("\nSuggest to apply the renamed rules with the following command:\n")
+		cmd.Printf("  git profile rules apply\n")
+	}
+
+	return nil
+}
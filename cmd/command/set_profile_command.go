@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/b4nd/git-profile/pkg/application"
+	"github.com/b4nd/git-profile/pkg/domain"
 
 	"github.com/spf13/cobra"
 )
@@ -14,6 +15,9 @@ type SetProfileCommand struct {
 	setGlobalProfileService *application.SetProfileService
 	getProfileService       *application.GetProfileService
 	listProfileService      *application.ListProfileService
+	// The current profiles are highlighted in the picker
+	currentProfileService       *application.CurrentProfileService
+	currentGlobalProfileService *application.CurrentProfileService
 }
 
 func NewSetProfileCommand(
@@ -21,25 +25,31 @@ func NewSetProfileCommand(
 	setGlobalProfileService *application.SetProfileService,
 	getProfileService *application.GetProfileService,
 	listProfileService *application.ListProfileService,
+	currentProfileService *application.CurrentProfileService,
+	currentGlobalProfileService *application.CurrentProfileService,
 ) *SetProfileCommand {
 	return &SetProfileCommand{
 		setProfileService,
 		setGlobalProfileService,
 		getProfileService,
 		listProfileService,
+		currentProfileService,
+		currentGlobalProfileService,
 	}
 }
 
 type SetProfileCommandParams struct {
 	Workspace string
+	// CredentialHelper is the helper storing the secrets behind git profile credential
+	CredentialHelper string
 }
 
 func (c *SetProfileCommand) Register(rootCmd *cobra.Command) {
-	var workspace string
+	var params SetProfileCommandParams
 	var global bool
 
 	cmd := &cobra.Command{
-		Use: "set [-w workspace] [--global]",
+		Use: "set [-w workspace] [--global] [--credential-helper helper]",
 		Aliases: []string{
 			"use",
 			"switch",
@@ -51,35 +61,59 @@ If no arguments are provided, the command will prompt for the missing values.
 		Example: `  git profile set
   git profile set work
   git profile set --workspace work
-  git profile set -w work`,
+  git profile set -w work
+  git profile set work --credential-helper cache`,
 		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if workspace == "" && len(args) > 0 {
-				workspace = args[0]
+			if params.Workspace == "" && len(args) > 0 {
+				params.Workspace = args[0]
 			}
 
-			return c.Execute(cmd, workspace, global)
+			return c.Execute(cmd, params, global)
 		},
 	}
 
-	cmd.Flags().StringVarP(&workspace, "workspace", "w", "", "The workspace of the profile")
+	cmd.Flags().StringVarP(&params.Workspace, "workspace", "w", "", "The workspace of the profile")
 	cmd.Flags().BoolVarP(&global, "global", "g", false, "Use the profile globally for all repositories (default: false)")
+	cmd.Flags().StringVar(&params.CredentialHelper, "credential-helper", "", "Register git profile credential as the credential helper, storing the secrets in the given helper")
+
+	registerWorkspaceCompletion(cmd, c.listProfileService, 1)
 
 	rootCmd.AddCommand(cmd)
 }
 
-func (c *SetProfileCommand) Execute(cmd *cobra.Command, workspace string, global bool) error {
+func (c *SetProfileCommand) Execute(cmd *cobra.Command, params SetProfileCommandParams, global bool) error {
 	reader := bufio.NewReader(cmd.InOrStdin())
+	workspace := params.Workspace
 
-	params := SetProfileCommandParams{
-		Workspace: workspace,
+	currentProfileService := c.currentProfileService
+	if global {
+		currentProfileService = c.currentGlobalProfileService
+	}
+
+	// The local profile is refused before asking for the workspace outside of a repository
+	if _, err := currentProfileService.Execute(); err == domain.ErrScmRepositoryNotFound {
+		printErrorMessage(cmd, err, "")
+		return nil
+	}
+
+	if workspace == "" {
+		picked, err := pickWorkspace(cmd, c.listProfileService, currentProfileService)
+		if err != ErrPickerNotTerminal {
+			if err != nil {
+				printErrorMessage(cmd, err, "")
+				return nil
+			}
+
+			params.Workspace, workspace = picked, picked
+		}
 	}
 
 	if workspace == "" {
 		profiles, err := c.listProfileService.Execute()
 
 		if err != nil {
-			cmd.Print(errorMessages[err])
+			printErrorMessage(cmd, err, "")
 			return nil
 		}
 
@@ -102,19 +136,23 @@ func (c *SetProfileCommand) Execute(cmd *cobra.Command, workspace string, global
 		service = c.setGlobalProfileService
 	}
 
+	credentialHelper := ""
+	if params.CredentialHelper != "" {
+		credentialHelper = credentialHelperCommand(params.CredentialHelper)
+	}
+
 	profile, err := service.Execute(application.SetProfileServiceParams{
-		Workspace: params.Workspace,
+		Workspace:        params.Workspace,
+		CredentialHelper: credentialHelper,
 	})
 
 	if err != nil {
-		cmd.Printf(errorMessages[err], workspace)
+		printErrorMessage(cmd, err, workspace)
 		return nil
 	}
 
 	cmd.Printf("Profile \"%s\" is now in use\n", profile.Workspace().String())
-	cmd.Printf("Workspace: %s\n", profile.Workspace().String())
-	cmd.Printf("Email: %s\n", profile.Email().String())
-	cmd.Printf("Name: %s\n", profile.Name().String())
+	printProfile(cmd, profile)
 
 	return nil
 }
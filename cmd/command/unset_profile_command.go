@@ -2,6 +2,7 @@ package command
 
 import (
 	"github.com/b4nd/git-profile/pkg/application"
+	"github.com/b4nd/git-profile/pkg/domain"
 
 	"github.com/spf13/cobra"
 )
@@ -60,6 +61,11 @@ func (c *UnsetProfileCommand) Execute(
 	profile, _ := currentProfileService.Execute()
 
 	err := unsetProfileService.Execute()
+	if err == domain.ErrScmRepositoryNotFound {
+		printErrorMessage(cmd, err, "")
+		return nil
+	}
+
 	if err != nil {
 		return err
 	}
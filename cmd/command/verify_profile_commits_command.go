@@ -0,0 +1,143 @@
+package command
+
+import (
+	"errors"
+
+	"github.com/b4nd/git-profile/pkg/application"
+	"github.com/b4nd/git-profile/pkg/domain"
+
+	"github.com/spf13/cobra"
+)
+
+type VerifyProfileCommitsCommand struct {
+	verifyProfileCommitsService *application.VerifyProfileCommitsService
+}
+
+// verifyOutput is the documented schema of a verification in the structured outputs
+type verifyOutput struct {
+	Range      string                  `json:"range" yaml:"range"`
+	Commits    int                     `json:"commits" yaml:"commits"`
+	Violations []verifyViolationOutput `json:"violations" yaml:"violations"`
+}
+
+type verifyViolationOutput struct {
+	Commit string `json:"commit" yaml:"commit"`
+	Role   string `json:"role" yaml:"role"`
+	Name   string `json:"name" yaml:"name"`
+	Email  string `json:"email" yaml:"email"`
+}
+
+func NewVerifyProfileCommitsCommand(verifyProfileCommitsService *application.VerifyProfileCommitsService) *VerifyProfileCommitsCommand {
+	return &VerifyProfileCommitsCommand{verifyProfileCommitsService}
+}
+
+func (c *VerifyProfileCommitsCommand) Register(rootCmd *cobra.Command) {
+	var params application.VerifyProfileCommitsServiceParams
+
+	cmd := &cobra.Command{
+		Use:   "verify [--range base..tip] [--allow workspace|email]... [--allow-domain domain]... [--policy file]",
+		Short: "Verifies that every commit of a range has an allowed author and committer.",
+		Long: `Verify that the author and the committer of every commit of a range are allowed.
+An identity is allowed when its email is the one of a profile given with --allow,
+an email given with --allow, or an email of a domain given with --allow-domain,
+subdomains included. The emails are compared regardless of the case.
+The allowed identities of the flags are added to the ones of the policy file of
+the repository, .gitprofile-policy at the root of the worktree, or of the file
+given with --policy:
+
+  [verify]
+  allow = work, ci-bot@acme.com
+  allow-domain = acme.com
+
+No profile needs to be configured, so the command runs in a CI checkout with a
+detached HEAD. The range is the history of HEAD unless --range is given.
+The command lists the offending commits and exits with code 1 when an identity
+is not allowed, when nothing is allowed or when the range cannot be read.
+`,
+		Example: `  git profile verify --range origin/main..HEAD --allow work --allow-domain acme.com
+  git profile verify --range origin/main..HEAD --policy .github/gitprofile-policy
+  git profile verify -o json`,
+		Args:          cobra.NoArgs,
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return c.Execute(cmd, params)
+		},
+	}
+
+	cmd.Flags().StringVar(&params.Range, "range", "", "The history up to a commit or a range in the form base..tip or base...tip")
+	cmd.Flags().StringArrayVar(&params.Allow, "allow", []string{}, "A workspace of a profile or an email allowed, can be repeated")
+	cmd.Flags().StringArrayVar(&params.AllowDomains, "allow-domain", []string{}, "A domain whose emails are allowed, can be repeated")
+	cmd.Flags().StringVar(&params.Policy, "policy", "", "The policy file, the policy of the repository by default")
+
+	supportOutput(cmd)
+	rootCmd.AddCommand(cmd)
+}
+
+func (c *VerifyProfileCommitsCommand) Execute(cmd *cobra.Command, params application.VerifyProfileCommitsServiceParams) error {
+	output, err := newOutput(cmd)
+	if err != nil {
+		return err
+	}
+
+	revision := params.Range
+	if revision == "" {
+		revision = domain.NewScmCommitHashHead().String()
+	}
+
+	result, err := c.verifyProfileCommitsService.Execute(params)
+
+	switch {
+	case err == nil:
+	case errors.Is(err, domain.ErrScmRepositoryNotFound):
+		cmd.Print(errorMessages[domain.ErrScmRepositoryNotFound])
+		return &ExitError{Code: ExitCodeFailure, Err: err}
+	case errors.Is(err, domain.ErrScmCommitNotFound), errors.Is(err, domain.ErrInvalidHash):
+		cmd.Printf("Commit \"%s\" not found\n", revision)
+		return &ExitError{Code: ExitCodeFailure, Err: err}
+	case errors.Is(err, application.ErrPolicyEmpty):
+		cmd.Printf("No identity is allowed, suggest to allow a profile, an email or a domain:\n")
+		cmd.Printf("  git profile verify --allow work --allow-domain example.com\n")
+		return &ExitError{Code: ExitCodeFailure, Err: err}
+	case errors.Is(err, domain.ErrProfilePolicyNotFound):
+		cmd.Printf("Policy file \"%s\" not found\n", params.Policy)
+		return &ExitError{Code: ExitCodeFailure, Err: err}
+	default:
+		cmd.Printf("Failed to verify the commits of \"%s\": %v\n", revision, err)
+		return &ExitError{Code: ExitCodeFailure, Err: err}
+	}
+
+	if !output.IsTable() {
+		value := verifyOutput{
+			Range:      revision,
+			Commits:    result.Commits,
+			Violations: make([]verifyViolationOutput, 0, len(result.Violations)),
+		}
+
+		for _, violation := range result.Violations {
+			value.Violations = append(value.Violations, verifyViolationOutput{
+				Commit: violation.Hash.String(),
+				Role:   violation.Role,
+				Name:   violation.Name,
+				Email:  violation.Email,
+			})
+		}
+
+		if err := output.Print(cmd, value); err != nil {
+			return err
+		}
+	} else if len(result.Violations) == 0 {
+		cmd.Printf("All %d commits of \"%s\" are allowed\n", result.Commits, revision)
+	} else {
+		cmd.Printf("Found %d violations of the policy in the %d commits of \"%s\":\n", len(result.Violations), result.Commits, revision)
+		for _, violation := range result.Violations {
+			cmd.Printf("  %s %-9s %s <%s>\n", shortHash(violation.Hash.String()), violation.Role, violation.Name, violation.Email)
+		}
+	}
+
+	if len(result.Violations) > 0 {
+		return &ExitError{Code: ExitCodeFailure}
+	}
+
+	return nil
+}
@@ -8,10 +8,12 @@ import (
 )
 
 type VersionCommand struct {
-	Version     string
-	GitCommit   string
-	BuildDate   string
-	ProfilePath string
+	Version         string
+	GitCommit       string
+	BuildDate       string
+	ProfilePath     string
+	GitGlobalConfig string
+	GitSystemConfig string
 }
 
 func NewVersionCommand(
@@ -19,15 +21,33 @@ func NewVersionCommand(
 	gitCommit string,
 	buildDate string,
 	profilePath string,
+	gitGlobalConfig string,
+	gitSystemConfig string,
 ) *VersionCommand {
 	return &VersionCommand{
-		Version:     version,
-		GitCommit:   gitCommit,
-		BuildDate:   buildDate,
-		ProfilePath: profilePath,
+		Version:         version,
+		GitCommit:       gitCommit,
+		BuildDate:       buildDate,
+		ProfilePath:     profilePath,
+		GitGlobalConfig: gitGlobalConfig,
+		GitSystemConfig: gitSystemConfig,
 	}
 }
 
+// versionOutput is the documented schema of the version in the structured outputs
+type versionOutput struct {
+	Version         string `json:"version" yaml:"version"`
+	GitCommit       string `json:"gitCommit" yaml:"gitCommit"`
+	BuildDate       string `json:"buildDate" yaml:"buildDate"`
+	GoVersion       string `json:"goVersion" yaml:"goVersion"`
+	Compiler        string `json:"compiler" yaml:"compiler"`
+	Platform        string `json:"platform" yaml:"platform"`
+	ProfilePath     string `json:"profilePath" yaml:"profilePath"`
+	GitGlobalConfig string `json:"gitGlobalConfig" yaml:"gitGlobalConfig"`
+	// GitSystemConfig is empty when GIT_CONFIG_NOSYSTEM disables it
+	GitSystemConfig string `json:"gitSystemConfig" yaml:"gitSystemConfig"`
+}
+
 func (c *VersionCommand) Register(rootCmd *cobra.Command) {
 	cmd := &cobra.Command{
 		Use:     "version",
@@ -38,17 +58,46 @@ func (c *VersionCommand) Register(rootCmd *cobra.Command) {
 			return c.Execute(cmd)
 		},
 	}
+	supportOutput(cmd)
 	rootCmd.AddCommand(cmd)
 }
 
 func (c *VersionCommand) Execute(cmd *cobra.Command) error {
-	cmd.Printf("Version: %s\n", c.Version)
-	cmd.Printf("Git Commit: %s\n", c.GitCommit)
-	cmd.Printf("Build Date: %s\n", c.BuildDate)
-	cmd.Printf("Go Version: %s\n", runtime.Version())
-	cmd.Printf("Compiler: %s\n", runtime.Compiler)
-	cmd.Printf("Platform: %s\n", fmt.Sprintf("%s/%s", runtime.GOOS, runtime.GOARCH))
-	cmd.Printf("Profile Path: %s\n", c.ProfilePath)
+	output, err := newOutput(cmd)
+	if err != nil {
+		return err
+	}
+
+	value := versionOutput{
+		Version:         c.Version,
+		GitCommit:       c.GitCommit,
+		BuildDate:       c.BuildDate,
+		GoVersion:       runtime.Version(),
+		Compiler:        runtime.Compiler,
+		Platform:        fmt.Sprintf("%s/%s", runtime.GOOS, runtime.GOARCH),
+		ProfilePath:     c.ProfilePath,
+		GitGlobalConfig: c.GitGlobalConfig,
+		GitSystemConfig: c.GitSystemConfig,
+	}
+
+	if !output.IsTable() {
+		return output.Print(cmd, value)
+	}
+
+	cmd.Printf("Version: %s\n", value.Version)
+	cmd.Printf("Git Commit: %s\n", value.GitCommit)
+	cmd.Printf("Build Date: %s\n", value.BuildDate)
+	cmd.Printf("Go Version: %s\n", value.GoVersion)
+	cmd.Printf("Compiler: %s\n", value.Compiler)
+	cmd.Printf("Platform: %s\n", value.Platform)
+	cmd.Printf("Profile Path: %s\n", value.ProfilePath)
+	cmd.Printf("Git Global Config: %s\n", value.GitGlobalConfig)
+
+	if value.GitSystemConfig == "" {
+		cmd.Printf("Git System Config: disabled\n")
+	} else {
+		cmd.Printf("Git System Config: %s\n", value.GitSystemConfig)
+	}
 
 	return nil
 }
@@ -1,8 +1,11 @@
 package main
 
 import (
+	"errors"
 	"os"
 
+	"github.com/b4nd/git-profile/cmd/command"
+
 	"github.com/spf13/cobra"
 )
 
@@ -19,6 +22,9 @@ const (
 	profileEnvName = "GIT_PROFILE_PATH"
 )
 
+// promptCommandName is the command run by the shell prompt
+const promptCommandName = "prompt"
+
 // Global Flags
 var (
 	profileFlag string = ""
@@ -35,17 +41,40 @@ func main() {
 	}
 
 	// Global Flags
-	rootCmd.PersistentFlags().BoolVarP(&localFlag, "local", "l", false, "Set the local profile (default is .gitprofile in the current directory)")
-	rootCmd.PersistentFlags().StringVarP(&profileFlag, "file", "f", os.Getenv(profileEnvName), "Set the profile file path (default is $HOME/.gitprofile)")
+	rootCmd.PersistentFlags().BoolVarP(&localFlag, "local", "l", false, "Set the local profile (default is .gitprofile at the root of the repository)")
+	rootCmd.PersistentFlags().StringVarP(&profileFlag, "file", "f", os.Getenv(profileEnvName), "Set the profile file path (default is $XDG_CONFIG_HOME/git-profile/.gitprofile)")
+
+	command.RegisterOutputFlag(rootCmd)
 
 	// nolint
 	rootCmd.ParseFlags(os.Args[1:]) // #nosec G104
 
-	rootComponent, err := NewRootComponent(&RootComponentOption{
+	option := &RootComponentOption{
 		profile: profileFlag,
 		local:   localFlag,
-	})
+	}
+
+	promptComponent, err := NewPromptComponent(option)
+	if err != nil {
+		panic(err)
+	}
+
+	promptComponent.PromptCommand.Register(rootCmd)
 
+	// The shell prompt runs the command often, the other commands are not built
+	args := rootCmd.Flags().Args()
+	if len(args) > 0 && args[0] == promptCommandName {
+		execute(rootCmd)
+		return
+	}
+
+	// The profiles of the home directory are moved to the XDG location, but not
+	// while the shell completes a command
+	if len(args) == 0 || (args[0] != cobra.ShellCompRequestCmd && args[0] != cobra.ShellCompNoDescRequestCmd) {
+		migrateProfile(option, os.Stderr)
+	}
+
+	rootComponent, err := NewRootComponent(option)
 	if err != nil {
 		panic(err)
 	}
@@ -59,9 +88,31 @@ func main() {
 	rootComponent.SetProfileCommand.Register(rootCmd)
 	rootComponent.CurrentProfileCommand.Register(rootCmd)
 	rootComponent.AmendProfileCommand.Register(rootCmd)
+	rootComponent.ProfileRuleCommand.Register(rootCmd)
+	rootComponent.AutoProfileCommand.Register(rootCmd)
+	rootComponent.CheckProfileCommand.Register(rootCmd)
+	rootComponent.HookCommand.Register(rootCmd)
+	rootComponent.ImportProfileCommand.Register(rootCmd)
+	rootComponent.ExportProfileCommand.Register(rootCmd)
+	rootComponent.RenameProfileCommand.Register(rootCmd)
+	rootComponent.CredentialCommand.Register(rootCmd)
+	rootComponent.CompletionCommand.Register(rootCmd)
+	rootComponent.DoctorCommand.Register(rootCmd)
+	rootComponent.AuditProfileCommand.Register(rootCmd)
+	rootComponent.VerifyProfileCommand.Register(rootCmd)
 	rootComponent.UnsetProfileCommand.Register(rootCmd)
 
-	err = rootCmd.Execute()
+	execute(rootCmd)
+}
+
+func execute(rootCmd *cobra.Command) {
+	err := rootCmd.Execute()
+
+	var exitErr *command.ExitError
+	if errors.As(err, &exitErr) {
+		os.Exit(exitErr.Code)
+	}
+
 	if err != nil {
 		os.Exit(1)
 	}
@@ -2,10 +2,17 @@ package main
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
 	"os/exec"
+	"path"
+	"strings"
 	"testing"
 
+	"github.com/b4nd/git-profile/cmd/command"
+	"github.com/b4nd/git-profile/pkg/application"
 	"github.com/b4nd/git-profile/pkg/domain"
 	"github.com/jaswdr/faker"
 	"github.com/spf13/cobra"
@@ -64,6 +71,14 @@ func lastCommit(t *testing.T, path string) string {
 	return string(output)
 }
 
+func gitConfig(t *testing.T, path string, key string) string {
+	cmd := exec.Command("git", "config", "--local", "--get", key)
+	cmd.Dir = path
+	output, _ := cmd.Output()
+
+	return strings.TrimSpace(string(output))
+}
+
 func initializateRootContainer(t *testing.T, option *RootComponentOption) *cobra.Command {
 	rootCmd := &cobra.Command{
 		Use:   "git profile [command]",
@@ -73,6 +88,17 @@ func initializateRootContainer(t *testing.T, option *RootComponentOption) *cobra
 		},
 	}
 
+	// The global flags are registered as in main, the options are given directly
+	rootCmd.PersistentFlags().BoolP("local", "l", false, "Set the local profile (default is .gitprofile at the root of the repository)")
+	rootCmd.PersistentFlags().StringP("file", "f", "", "Set the profile file path (default is $XDG_CONFIG_HOME/git-profile/.gitprofile)")
+
+	command.RegisterOutputFlag(rootCmd)
+
+	// The XDG directory is the one of the home directory of the test, not the one of the environment
+	if option.userHomeDir != "" && option.xdgConfigHome == "" {
+		option.xdgConfigHome = path.Join(option.userHomeDir, ".config")
+	}
+
 	rootComponent, err := NewRootComponent(option)
 	rootComponent.VersionCommand.Register(rootCmd)
 	rootComponent.UpsertProfileCommand.Register(rootCmd)
@@ -83,6 +109,23 @@ func initializateRootContainer(t *testing.T, option *RootComponentOption) *cobra
 	rootComponent.UnsetProfileCommand.Register(rootCmd)
 	rootComponent.CurrentProfileCommand.Register(rootCmd)
 	rootComponent.AmendProfileCommand.Register(rootCmd)
+	rootComponent.ProfileRuleCommand.Register(rootCmd)
+	rootComponent.AutoProfileCommand.Register(rootCmd)
+	rootComponent.CheckProfileCommand.Register(rootCmd)
+	rootComponent.HookCommand.Register(rootCmd)
+	rootComponent.ImportProfileCommand.Register(rootCmd)
+	rootComponent.ExportProfileCommand.Register(rootCmd)
+	rootComponent.RenameProfileCommand.Register(rootCmd)
+	rootComponent.CredentialCommand.Register(rootCmd)
+	rootComponent.CompletionCommand.Register(rootCmd)
+	rootComponent.DoctorCommand.Register(rootCmd)
+	rootComponent.AuditProfileCommand.Register(rootCmd)
+	rootComponent.VerifyProfileCommand.Register(rootCmd)
+
+	assert.Nil(t, err)
+
+	promptComponent, err := NewPromptComponent(option)
+	promptComponent.PromptCommand.Register(rootCmd)
 
 	assert.Nil(t, err)
 
@@ -729,7 +772,7 @@ func TestMainCommand(t *testing.T) {
 		emptyCommit(t, workingDir, "Initial commit", name, email)
 		commit := lastCommit(t, workingDir)
 
-		assert.Equal(t, name+","+email+"\n", commit)
+		assert.Equal(t, domain.NormalizeScmIdentityValue(name)+","+email+"\n", commit)
 
 		newEmail := faker.Internet().Email()
 		newName := faker.Person().Name()
@@ -748,11 +791,11 @@ func TestMainCommand(t *testing.T) {
 		err = rootCmd.Execute()
 
 		assert.Nil(t, err)
-		assert.Contains(t, stdout.String(), "Amended commit author to "+newName+" <"+newEmail+">")
+		assert.Contains(t, stdout.String(), "Amended commit author to "+domain.NormalizeScmIdentityValue(newName)+" <"+newEmail+">")
 		stdout.Reset()
 
 		commit = lastCommit(t, workingDir)
-		assert.Equal(t, newName+","+newEmail+"\n", commit)
+		assert.Equal(t, domain.NormalizeScmIdentityValue(newName)+","+newEmail+"\n", commit)
 		stdout.Reset()
 	})
 
@@ -776,7 +819,7 @@ func TestMainCommand(t *testing.T) {
 		emptyCommit(t, workingDir, "Initial commit", name, email)
 		commit := lastCommit(t, workingDir)
 
-		assert.Equal(t, name+","+email+"\n", commit)
+		assert.Equal(t, domain.NormalizeScmIdentityValue(name)+","+email+"\n", commit)
 
 		// Create a new profile
 		rootCmd.SetArgs([]string{"add", "-w", workspace, "-n", name, "-e", email})
@@ -800,11 +843,11 @@ func TestMainCommand(t *testing.T) {
 		err = rootCmd.Execute()
 
 		assert.Nil(t, err)
-		assert.Contains(t, stdout.String(), "Amended commit author to "+name+" <"+email+">")
+		assert.Contains(t, stdout.String(), "Amended commit author to "+domain.NormalizeScmIdentityValue(name)+" <"+email+">")
 		stdout.Reset()
 
 		commit = lastCommit(t, workingDir)
-		assert.Equal(t, name+","+email+"\n", commit)
+		assert.Equal(t, domain.NormalizeScmIdentityValue(name)+","+email+"\n", commit)
 		stdout.Reset()
 	})
 
@@ -933,33 +976,106 @@ func TestMainCommand(t *testing.T) {
 		stdout.Reset()
 	})
 
-	// Test Interactive Mode
-
-	t.Run("should create a new profile in interactive mode", func(t *testing.T) {
+	t.Run("should apply and clear the signing configuration of the profile", func(t *testing.T) {
 		workingDir := initializateGitRepository(t)
 		userHomeDir := t.TempDir()
+
+		profileDir := t.TempDir()
+
 		rootCmd := initializateRootContainer(t, &RootComponentOption{
-			profile:     t.TempDir(),
+			profile:     profileDir,
 			local:       false,
 			workingDir:  workingDir,
 			userHomeDir: userHomeDir,
 		})
 
+		rootCmd.SetOutput(stdout)
+
 		workspace := faker.Internet().User()
+		signingKey := faker.Hash().MD5()
 
-		rootCmd.SetOutput(stdout)
-		rootCmd.SetArgs([]string{"add"})
-		rootCmd.SetIn(bytes.NewBufferString(workspace + "\n" + faker.Internet().Email() + "\n" + faker.Person().Name() + "\n"))
+		// Create a new profile with a signing key
+		rootCmd.SetArgs([]string{"add", "-w", workspace, "-n", faker.Person().Name(), "-e", faker.Internet().Email(), "--signing-key", signingKey, "--signing-format", "ssh", "--sign-commits"})
 		err := rootCmd.Execute()
 
 		assert.Nil(t, err)
 		assert.Contains(t, stdout.String(), fmt.Sprintf(MsgProfileCreatedSuccessfully, workspace))
 		stdout.Reset()
+
+		rootCmd.SetArgs([]string{"get", workspace})
+		err = rootCmd.Execute()
+
+		assert.Nil(t, err)
+		assert.Contains(t, stdout.String(), "Signing Key: "+signingKey)
+		assert.Contains(t, stdout.String(), "Signing Format: ssh")
+		assert.Contains(t, stdout.String(), "Sign Commits: true")
+		stdout.Reset()
+
+		// The signing flags given on an update win over the stored ones, a new
+		// command is used as the flags of a command keep their values
+		updateCmd := initializateRootContainer(t, &RootComponentOption{
+			profile:     profileDir,
+			local:       false,
+			workingDir:  workingDir,
+			userHomeDir: userHomeDir,
+		})
+
+		updateCmd.SetOutput(stdout)
+		updateCmd.SetArgs([]string{"add", "-w", workspace, "--force", "-n", "Jane Doe", "-e", "jane@acme.com", "--sign-tags"})
+		assert.Nil(t, updateCmd.Execute())
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{"get", workspace})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Contains(t, stdout.String(), "Signing Key: "+signingKey)
+		assert.Contains(t, stdout.String(), "Sign Commits: true")
+		assert.Contains(t, stdout.String(), "Sign Tags: true")
+		stdout.Reset()
+
+		updateCmd = initializateRootContainer(t, &RootComponentOption{
+			profile:     profileDir,
+			local:       false,
+			workingDir:  workingDir,
+			userHomeDir: userHomeDir,
+		})
+
+		updateCmd.SetOutput(stdout)
+		updateCmd.SetArgs([]string{"add", "-w", workspace, "--force", "-n", "Jane Doe", "-e", "jane@acme.com", "--signing-key", signingKey, "--signing-format", "foo", "--sign-commits"})
+		assert.Nil(t, updateCmd.Execute())
+		assert.Equal(t, "The signing format must be openpgp, ssh or x509.\n", stdout.String())
+		stdout.Reset()
+
+		// Set the profile and check the git configuration
+		rootCmd.SetArgs([]string{"set", workspace})
+		err = rootCmd.Execute()
+
+		assert.Nil(t, err)
+		assert.Equal(t, signingKey, gitConfig(t, workingDir, "user.signingkey"))
+		assert.Equal(t, "ssh", gitConfig(t, workingDir, "gpg.format"))
+		assert.Equal(t, "true", gitConfig(t, workingDir, "commit.gpgsign"))
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{"current", "-v"})
+		err = rootCmd.Execute()
+
+		assert.Nil(t, err)
+		assert.Contains(t, stdout.String(), "Signing Key: "+signingKey)
+		stdout.Reset()
+
+		// Unset the profile clears the signing configuration
+		rootCmd.SetArgs([]string{"unset"})
+		err = rootCmd.Execute()
+
+		assert.Nil(t, err)
+		assert.Equal(t, "", gitConfig(t, workingDir, "user.signingkey"))
+		assert.Equal(t, "", gitConfig(t, workingDir, "gpg.format"))
+		stdout.Reset()
 	})
 
-	t.Run("should update a profile in interactive mode", func(t *testing.T) {
+	t.Run("should apply the ssh key of the profile and warn when it is missing", func(t *testing.T) {
 		workingDir := initializateGitRepository(t)
 		userHomeDir := t.TempDir()
+
 		rootCmd := initializateRootContainer(t, &RootComponentOption{
 			profile:     t.TempDir(),
 			local:       false,
@@ -967,29 +1083,53 @@ func TestMainCommand(t *testing.T) {
 			userHomeDir: userHomeDir,
 		})
 
+		rootCmd.SetOutput(stdout)
+
 		workspace := faker.Internet().User()
+		sshKey := path.Join(t.TempDir(), "id_ed25519")
 
-		rootCmd.SetOutput(stdout)
-		rootCmd.SetArgs([]string{"add"})
-		rootCmd.SetIn(bytes.NewBufferString(workspace + "\n" + faker.Internet().Email() + "\n" + faker.Person().Name() + "\n"))
+		// Create a new profile with a missing ssh key
+		rootCmd.SetArgs([]string{"add", "-w", workspace, "-n", faker.Person().Name(), "-e", faker.Internet().Email(), "--ssh-key", sshKey})
 		err := rootCmd.Execute()
 
 		assert.Nil(t, err)
+		assert.Contains(t, stdout.String(), "does not exist")
 		assert.Contains(t, stdout.String(), fmt.Sprintf(MsgProfileCreatedSuccessfully, workspace))
 		stdout.Reset()
 
-		rootCmd.SetArgs([]string{"add"})
-		rootCmd.SetIn(bytes.NewBufferString(workspace + "\ny\n" + faker.Internet().Email() + "\n" + faker.Person().Name() + "\n"))
+		// Update the profile once the ssh key exists with loose permissions
+		err = os.WriteFile(sshKey, []byte("key"), 0644)
+		assert.NoError(t, err)
+
+		rootCmd.SetArgs([]string{"add", "-w", workspace, "-n", faker.Person().Name(), "-e", faker.Internet().Email(), "--ssh-key", sshKey, "--force"})
 		err = rootCmd.Execute()
 
 		assert.Nil(t, err)
-		assert.Contains(t, stdout.String(), fmt.Sprintf(MsgProfileUpdatedSuccessfully, workspace))
+		assert.Contains(t, stdout.String(), "are too open")
+		stdout.Reset()
+
+		// Set the profile and check the git configuration
+		rootCmd.SetArgs([]string{"set", workspace})
+		err = rootCmd.Execute()
+
+		assert.Nil(t, err)
+		assert.Contains(t, stdout.String(), "SSH Key: "+sshKey)
+		assert.Equal(t, "ssh -i "+sshKey+" -o IdentitiesOnly=yes", gitConfig(t, workingDir, "core.sshCommand"))
+		stdout.Reset()
+
+		// Unset the profile removes the ssh command
+		rootCmd.SetArgs([]string{"unset"})
+		err = rootCmd.Execute()
+
+		assert.Nil(t, err)
+		assert.Equal(t, "", gitConfig(t, workingDir, "core.sshCommand"))
 		stdout.Reset()
 	})
 
-	t.Run("should show the error when the profile already exists in interactive mode", func(t *testing.T) {
+	t.Run("should add, apply and remove rules of a profile", func(t *testing.T) {
 		workingDir := initializateGitRepository(t)
 		userHomeDir := t.TempDir()
+
 		rootCmd := initializateRootContainer(t, &RootComponentOption{
 			profile:     t.TempDir(),
 			local:       false,
@@ -997,29 +1137,83 @@ func TestMainCommand(t *testing.T) {
 			userHomeDir: userHomeDir,
 		})
 
+		rootCmd.SetOutput(stdout)
+
 		workspace := faker.Internet().User()
+		email := faker.Internet().Email()
 
-		rootCmd.SetOutput(stdout)
-		rootCmd.SetArgs([]string{"add"})
-		rootCmd.SetIn(bytes.NewBufferString(workspace + "\n" + faker.Internet().Email() + "\n" + faker.Person().Name() + "\n"))
+		rootCmd.SetArgs([]string{"add", "-w", workspace, "-n", faker.Person().Name(), "-e", email})
 		err := rootCmd.Execute()
 
 		assert.Nil(t, err)
-		assert.Contains(t, stdout.String(), fmt.Sprintf(MsgProfileCreatedSuccessfully, workspace))
 		stdout.Reset()
 
-		rootCmd.SetArgs([]string{"add"})
-		rootCmd.SetIn(bytes.NewBufferString(workspace + "\nN\n"))
+		// Add a rule for a profile
+		rootCmd.SetArgs([]string{"rule", "add", workspace, "--gitdir", "~/work", "--remote", "git@github.com:acme/**"})
+		err = rootCmd.Execute()
+
+		assert.Nil(t, err)
+		assert.Contains(t, stdout.String(), "Rule \"gitdir:~/work/\" added to profile \""+workspace+"\"")
+		stdout.Reset()
+
+		// The profiles list does not contain the rules
+		rootCmd.SetArgs([]string{"list"})
+		err = rootCmd.Execute()
+
+		assert.Nil(t, err)
+		assert.Equal(t, workspace+"\n", stdout.String())
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{"rules", "list"})
+		err = rootCmd.Execute()
+
+		assert.Nil(t, err)
+		assert.Contains(t, stdout.String(), "gitdir:~/work/ -> "+workspace)
+		assert.Contains(t, stdout.String(), "hasconfig:remote.*.url:git@github.com:acme/** -> "+workspace)
+		stdout.Reset()
+
+		// Apply the rules to the global git configuration
+		rootCmd.SetArgs([]string{"rules", "apply"})
+		err = rootCmd.Execute()
+
+		assert.Nil(t, err)
+		assert.Contains(t, stdout.String(), "Applied \"gitdir:~/work/\" -> "+workspace)
+		stdout.Reset()
+
+		content, err := os.ReadFile(path.Join(userHomeDir, ".gitconfig"))
+		assert.NoError(t, err)
+		assert.Contains(t, string(content), `[includeIf "gitdir:~/work/"]`)
+		assert.Contains(t, string(content), `[includeIf "hasconfig:remote.*.url:git@github.com:acme/**"]`)
+
+		include, err := os.ReadFile(path.Join(userHomeDir, ".gitprofile.d", workspace+".gitconfig"))
+		assert.NoError(t, err)
+		assert.Contains(t, string(include), email)
+
+		// Remove the rules of the profile
+		rootCmd.SetArgs([]string{"rules", "remove", workspace})
+		err = rootCmd.Execute()
+
+		assert.Nil(t, err)
+		assert.Contains(t, stdout.String(), "removed from profile")
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{"rules", "apply"})
 		err = rootCmd.Execute()
 
 		assert.Nil(t, err)
-		assert.Contains(t, stdout.String(), fmt.Sprintf(ErrProfileAlreadyExists, workspace))
 		stdout.Reset()
+
+		content, err = os.ReadFile(path.Join(userHomeDir, ".gitconfig"))
+		assert.NoError(t, err)
+		assert.NotContains(t, string(content), "includeIf")
 	})
 
-	t.Run("should use a profile in interactive mode", func(t *testing.T) {
+	t.Run("should show the current profile applied by a rule", func(t *testing.T) {
 		workingDir := initializateGitRepository(t)
 		userHomeDir := t.TempDir()
+		t.Setenv("GIT_CONFIG_GLOBAL", path.Join(userHomeDir, ".gitconfig"))
+		t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+
 		rootCmd := initializateRootContainer(t, &RootComponentOption{
 			profile:     t.TempDir(),
 			local:       false,
@@ -1027,123 +1221,2296 @@ func TestMainCommand(t *testing.T) {
 			userHomeDir: userHomeDir,
 		})
 
-		workspace := faker.Internet().User()
-
 		rootCmd.SetOutput(stdout)
-		rootCmd.SetArgs([]string{"add"})
-		rootCmd.SetIn(bytes.NewBufferString(workspace + "\n" + faker.Internet().Email() + "\n" + faker.Person().Name() + "\n"))
-		err := rootCmd.Execute()
 
-		assert.Nil(t, err)
-		assert.Contains(t, stdout.String(), fmt.Sprintf(MsgProfileCreatedSuccessfully, workspace))
+		for _, args := range [][]string{
+			{"add", "-w", "work", "-n", "Jane Doe", "-e", "jane@acme.com"},
+			{"rule", "add", "work", "--gitdir", workingDir},
+			{"rules", "apply"},
+		} {
+			rootCmd.SetArgs(args)
+			assert.Nil(t, rootCmd.Execute())
+		}
 		stdout.Reset()
 
-		rootCmd.SetArgs([]string{"set"})
-		rootCmd.SetIn(bytes.NewBufferString(workspace + "\n"))
-		err = rootCmd.Execute()
+		// The repository has no workspace of its own, git reads it from the include
+		assert.Empty(t, gitConfig(t, workingDir, "user.workspace"))
 
-		assert.Nil(t, err)
-		assert.Contains(t, stdout.String(), fmt.Sprintf(ErrProfileInUse, workspace))
+		rootCmd.SetArgs([]string{"current"})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Equal(t, "work\n", stdout.String())
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{"prompt"})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Equal(t, "work", stdout.String())
 		stdout.Reset()
 	})
 
-	t.Run("should show the error when the profile does not exist in interactive mode", func(t *testing.T) {
+	t.Run("should show the error when the rule profile does not exist", func(t *testing.T) {
 		workingDir := initializateGitRepository(t)
 		userHomeDir := t.TempDir()
+
 		rootCmd := initializateRootContainer(t, &RootComponentOption{
 			profile:     t.TempDir(),
 			local:       false,
 			workingDir:  workingDir,
 			userHomeDir: userHomeDir,
 		})
+
 		rootCmd.SetOutput(stdout)
-		rootCmd.SetArgs([]string{"set"})
-		rootCmd.SetIn(bytes.NewBufferString("test\n"))
+
+		workspace := faker.Internet().User()
+
+		rootCmd.SetArgs([]string{"rule", "add", workspace, "--gitdir", "~/work"})
 		err := rootCmd.Execute()
 
 		assert.Nil(t, err)
-		assert.Contains(t, stdout.String(), ErrProfilesNotFound)
+		assert.Contains(t, stdout.String(), fmt.Sprintf(ErrProfileNotExist, workspace))
 		stdout.Reset()
 	})
 
-	t.Run("should delete a profile in interactive mode", func(t *testing.T) {
+	t.Run("should set the profile matching the remote of the repository", func(t *testing.T) {
 		workingDir := initializateGitRepository(t)
 		userHomeDir := t.TempDir()
+		profileDir := t.TempDir()
+
+		cmd := exec.Command("git", "remote", "add", "origin", "https://github.com/acme-corp/api.git")
+		cmd.Dir = workingDir
+		assert.NoError(t, cmd.Run())
+
+		workspace := faker.Internet().User()
+		email := faker.Internet().Email()
+
 		rootCmd := initializateRootContainer(t, &RootComponentOption{
-			profile:     t.TempDir(),
+			profile:     profileDir,
 			local:       false,
 			workingDir:  workingDir,
 			userHomeDir: userHomeDir,
 		})
 
-		workspace := faker.Internet().User()
-
 		rootCmd.SetOutput(stdout)
-		rootCmd.SetArgs([]string{"add"})
-		rootCmd.SetIn(bytes.NewBufferString(workspace + "\n" + faker.Internet().Email() + "\n" + faker.Person().Name() + "\n"))
+		rootCmd.SetArgs([]string{"add", "-w", workspace, "-n", faker.Person().Name(), "-e", email, "--remote", "github.com:acme-corp/*"})
 		err := rootCmd.Execute()
 
 		assert.Nil(t, err)
-		assert.Contains(t, stdout.String(), fmt.Sprintf(MsgProfileCreatedSuccessfully, workspace))
 		stdout.Reset()
 
-		rootCmd.SetArgs([]string{"delete"})
-		rootCmd.SetIn(bytes.NewBufferString(workspace + "\n"))
+		// The ssh and https forms of the remote url are equivalent
+		rootCmd.SetArgs([]string{"auto"})
+		err = rootCmd.Execute()
+
+		assert.Nil(t, err)
+		assert.Contains(t, stdout.String(), fmt.Sprintf(ErrProfileInUse, workspace))
+		assert.Contains(t, stdout.String(), "Remotes: github.com:acme-corp/*")
+		assert.Equal(t, email, gitConfig(t, workingDir, "user.email"))
+		stdout.Reset()
+
+		// A second profile matching the remote makes the selection ambiguous
+		rootCmd = initializateRootContainer(t, &RootComponentOption{
+			profile:     profileDir,
+			local:       false,
+			workingDir:  workingDir,
+			userHomeDir: userHomeDir,
+		})
+
+		other := faker.Internet().User()
+
+		rootCmd.SetOutput(stdout)
+		rootCmd.SetArgs([]string{"add", "-w", other, "-n", faker.Person().Name(), "-e", faker.Internet().Email(), "--remote", "github.com/*"})
 		err = rootCmd.Execute()
 
 		assert.Nil(t, err)
-		assert.Contains(t, stdout.String(), fmt.Sprintf(MsgProfileDeleted, workspace))
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{"auto"})
+		err = rootCmd.Execute()
+
+		var exitErr *command.ExitError
+		assert.True(t, errors.As(err, &exitErr))
+		assert.Equal(t, command.ExitCodeAmbiguous, exitErr.Code)
+		assert.Contains(t, stdout.String(), "More than one profile matches")
+		assert.Contains(t, stdout.String(), "  "+workspace+"\n")
+		assert.Contains(t, stdout.String(), "  "+other+"\n")
+		assert.NotContains(t, stdout.String(), "Usage:")
 		stdout.Reset()
 	})
 
-	t.Run("should show the error when the profile does not exist in interactive mode", func(t *testing.T) {
+	t.Run("should return the no match exit code when no profile matches the remotes", func(t *testing.T) {
 		workingDir := initializateGitRepository(t)
-		userHomeDir := t.TempDir()
+
+		cmd := exec.Command("git", "remote", "add", "origin", "git@bitbucket.org:someone/api.git")
+		cmd.Dir = workingDir
+		assert.NoError(t, cmd.Run())
+
 		rootCmd := initializateRootContainer(t, &RootComponentOption{
 			profile:     t.TempDir(),
 			local:       false,
 			workingDir:  workingDir,
-			userHomeDir: userHomeDir,
+			userHomeDir: t.TempDir(),
 		})
 
-		workspace := faker.Internet().User()
-
 		rootCmd.SetOutput(stdout)
-		rootCmd.SetArgs([]string{"delete"})
-		rootCmd.SetIn(bytes.NewBufferString(workspace + "\n"))
+		rootCmd.SetArgs([]string{"add", "-w", faker.Internet().User(), "-n", faker.Person().Name(), "-e", faker.Internet().Email(), "--remote", "gitlab.internal/*"})
 		err := rootCmd.Execute()
 
 		assert.Nil(t, err)
-		assert.Contains(t, stdout.String(), fmt.Sprintf(ErrProfileNotExist, workspace))
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{"auto", "--quiet"})
+		err = rootCmd.Execute()
+
+		var exitErr *command.ExitError
+		assert.True(t, errors.As(err, &exitErr))
+		assert.Equal(t, command.ExitCodeNoMatch, exitErr.Code)
+		assert.Equal(t, "", stdout.String())
+		assert.Equal(t, "", gitConfig(t, workingDir, "user.email"))
 		stdout.Reset()
 	})
 
-	t.Run("should show name profile in interactive mode", func(t *testing.T) {
+	t.Run("should check the git identity against the configured profile", func(t *testing.T) {
 		workingDir := initializateGitRepository(t)
-		userHomeDir := t.TempDir()
+
 		rootCmd := initializateRootContainer(t, &RootComponentOption{
 			profile:     t.TempDir(),
 			local:       false,
 			workingDir:  workingDir,
-			userHomeDir: userHomeDir,
+			userHomeDir: t.TempDir(),
 		})
-		workspace := faker.Internet().User()
 
 		rootCmd.SetOutput(stdout)
-		rootCmd.SetArgs([]string{"add"})
-		rootCmd.SetIn(bytes.NewBufferString(workspace + "\n" + faker.Internet().Email() + "\n" + faker.Person().Name() + "\n"))
+
+		workspace := faker.Internet().User()
+		name := faker.Person().FirstName() + " " + faker.Person().LastName()
+		email := faker.Internet().Email()
+
+		rootCmd.SetArgs([]string{"add", "-w", workspace, "-n", name, "-e", email})
 		err := rootCmd.Execute()
 
 		assert.Nil(t, err)
-		assert.Contains(t, stdout.String(), fmt.Sprintf(MsgProfileCreatedSuccessfully, workspace))
 		stdout.Reset()
 
-		rootCmd.SetArgs([]string{"get"})
-		rootCmd.SetIn(bytes.NewBufferString(workspace + "\n"))
+		rootCmd.SetArgs([]string{"set", workspace})
 		err = rootCmd.Execute()
 
 		assert.Nil(t, err)
-		assert.Contains(t, stdout.String(), "Workspace: "+workspace)
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{"check"})
+		err = rootCmd.Execute()
+
+		assert.Nil(t, err)
+		assert.Contains(t, stdout.String(), "Profile \""+workspace+"\" matches the git identity")
+		stdout.Reset()
+
+		// Change the email behind the back of git profile
+		configureGit(t, workingDir, name, faker.Internet().Email(), "local")
+
+		rootCmd.SetArgs([]string{"check", "--quiet"})
+		err = rootCmd.Execute()
+
+		var exitErr *command.ExitError
+		assert.True(t, errors.As(err, &exitErr))
+		assert.Equal(t, command.ExitCodeFailure, exitErr.Code)
+		assert.Contains(t, stdout.String(), "The git identity does not match the profile \""+workspace+"\"")
+		assert.Contains(t, stdout.String(), "git profile set "+workspace)
+		stdout.Reset()
+	})
+
+	t.Run("should match a name that git writes without its trailing dot", func(t *testing.T) {
+		workingDir := initializateGitRepository(t)
+
+		rootCmd := initializateRootContainer(t, &RootComponentOption{
+			profile:     t.TempDir(),
+			local:       false,
+			workingDir:  workingDir,
+			userHomeDir: t.TempDir(),
+		})
+
+		rootCmd.SetOutput(stdout)
+
+		for _, args := range [][]string{
+			{"add", "-w", "work", "-n", "Jane Doe Jr.", "-e", "jane@acme.com"},
+			{"set", "work"},
+		} {
+			rootCmd.SetArgs(args)
+			assert.Nil(t, rootCmd.Execute())
+		}
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{"check"})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Contains(t, stdout.String(), "Profile \"work\" matches the git identity")
+		stdout.Reset()
+	})
+
+	t.Run("should fail the check when no profile is configured", func(t *testing.T) {
+		workingDir := initializateGitRepository(t)
+
+		rootCmd := initializateRootContainer(t, &RootComponentOption{
+			profile:     t.TempDir(),
+			local:       false,
+			workingDir:  workingDir,
+			userHomeDir: t.TempDir(),
+		})
+
+		rootCmd.SetOutput(stdout)
+		rootCmd.SetArgs([]string{"check"})
+		err := rootCmd.Execute()
+
+		var exitErr *command.ExitError
+		assert.True(t, errors.As(err, &exitErr))
+		assert.ErrorIs(t, err, application.ErrProfileNotConfigured)
+		assert.Contains(t, stdout.String(), "No profile is configured")
+		stdout.Reset()
+	})
+
+	t.Run("should install and uninstall the pre-commit hook", func(t *testing.T) {
+		workingDir := initializateGitRepository(t)
+
+		rootCmd := initializateRootContainer(t, &RootComponentOption{
+			profile:     t.TempDir(),
+			local:       false,
+			workingDir:  workingDir,
+			userHomeDir: t.TempDir(),
+		})
+
+		hook := path.Join(workingDir, ".git", "hooks", "pre-commit")
+		existing := "#!/bin/sh\nexit 0\n"
+		assert.NoError(t, os.WriteFile(hook, []byte(existing), 0755)) // #nosec G306
+
+		rootCmd.SetOutput(stdout)
+		rootCmd.SetArgs([]string{"hook", "install"})
+		err := rootCmd.Execute()
+
+		assert.Nil(t, err)
+		assert.Contains(t, stdout.String(), "Hook \"pre-commit\" installed")
+		stdout.Reset()
+
+		content, err := os.ReadFile(hook)
+		assert.NoError(t, err)
+		assert.Contains(t, string(content), "git profile check --quiet")
+
+		chained, err := os.ReadFile(hook + ".chained")
+		assert.NoError(t, err)
+		assert.Equal(t, existing, string(chained))
+
+		rootCmd.SetArgs([]string{"hook", "uninstall"})
+		err = rootCmd.Execute()
+
+		assert.Nil(t, err)
+		assert.Contains(t, stdout.String(), "Hook \"pre-commit\" uninstalled")
+		stdout.Reset()
+
+		content, err = os.ReadFile(hook)
+		assert.NoError(t, err)
+		assert.Equal(t, existing, string(content))
+
+		rootCmd.SetArgs([]string{"hook", "uninstall"})
+		err = rootCmd.Execute()
+
+		assert.Nil(t, err)
+		assert.Contains(t, stdout.String(), "Hook \"pre-commit\" is not installed")
+		stdout.Reset()
+	})
+
+	t.Run("should rewrite the author and committer of a range of commits", func(t *testing.T) {
+		workingDir := initializateGitRepository(t)
+
+		rootCmd := initializateRootContainer(t, &RootComponentOption{
+			profile:     t.TempDir(),
+			local:       false,
+			workingDir:  workingDir,
+			userHomeDir: t.TempDir(),
+		})
+
+		rootCmd.SetOutput(stdout)
+
+		workspace := faker.Internet().User()
+		name := faker.Person().FirstName() + " " + faker.Person().LastName()
+		email := faker.Internet().Email()
+		wrongName := faker.Person().FirstName() + " " + faker.Person().LastName()
+		wrongEmail := faker.Internet().Email()
+
+		emptyCommit(t, workingDir, "Initial commit", wrongName, wrongEmail)
+		emptyCommit(t, workingDir, "Second commit", wrongName, wrongEmail)
+		emptyCommit(t, workingDir, "Third commit", wrongName, wrongEmail)
+
+		rootCmd.SetArgs([]string{"add", "-w", workspace, "-n", name, "-e", email})
+		err := rootCmd.Execute()
+
+		assert.Nil(t, err)
+		stdout.Reset()
+
+		// Pushed commits are refused
+		cmd := exec.Command("git", "update-ref", "refs/remotes/origin/master", "HEAD~1")
+		cmd.Dir = workingDir
+		assert.NoError(t, cmd.Run())
+
+		rootCmd.SetArgs([]string{"amend", "HEAD~2..HEAD", "-w", workspace})
+		err = rootCmd.Execute()
+
+		assert.Nil(t, err)
+		assert.Contains(t, stdout.String(), "are reachable from a remote branch, use --force")
+		stdout.Reset()
+
+		cmd = exec.Command("git", "update-ref", "-d", "refs/remotes/origin/master")
+		cmd.Dir = workingDir
+		assert.NoError(t, cmd.Run())
+
+		rootCmd.SetArgs([]string{"amend", "HEAD~2..HEAD", "-w", workspace})
+		err = rootCmd.Execute()
+
+		assert.Nil(t, err)
+		assert.Contains(t, stdout.String(), "Rewrote 2 commits with the profile \""+workspace+"\"")
+		assert.Contains(t, stdout.String(), " -> ")
+		stdout.Reset()
+
+		cmd = exec.Command("git", "log", "--format=%an <%ae>|%cn <%ce>")
+		cmd.Dir = workingDir
+		output, err := cmd.Output()
+		assert.NoError(t, err)
+
+		identity := name + " <" + strings.ToLower(email) + ">"
+		lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+		assert.Len(t, lines, 3)
+		assert.Equal(t, identity+"|"+identity, lines[0])
+		assert.Equal(t, identity+"|"+identity, lines[1])
+		assert.True(t, strings.HasPrefix(lines[2], wrongName+" <"+wrongEmail+">|"))
+
+		// Running it again leaves the commits as they are
+		rootCmd.SetArgs([]string{"amend", "HEAD~2..HEAD", "-w", workspace})
+		err = rootCmd.Execute()
+
+		assert.Nil(t, err)
+		assert.Contains(t, stdout.String(), "already match the profile")
+		stdout.Reset()
+	})
+
+	t.Run("should rewrite a commit whose email is not a valid address", func(t *testing.T) {
+		workingDir := initializateGitRepository(t)
+
+		rootCmd := initializateRootContainer(t, &RootComponentOption{
+			profile:     t.TempDir(),
+			local:       false,
+			workingDir:  workingDir,
+			userHomeDir: t.TempDir(),
+		})
+
+		rootCmd.SetOutput(stdout)
+
+		emptyCommit(t, workingDir, "Initial commit", "Jane Doe", "jane@acme.com")
+		emptyCommit(t, workingDir, "Odd commit", "Jane Doe", "jane")
+		emptyCommit(t, workingDir, "Last commit", "Jane Doe", "jane@acme.com")
+
+		rootCmd.SetArgs([]string{"add", "-w", "work", "-n", "Jane Doe", "-e", "jane@acme.com"})
+		assert.Nil(t, rootCmd.Execute())
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{"amend", "HEAD~1", "-w", "work"})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Contains(t, stdout.String(), "Rewrote 2 commits with the profile \"work\"")
+		stdout.Reset()
+
+		cmd := exec.Command("git", "log", "--format=%ae")
+		cmd.Dir = workingDir
+		output, err := cmd.Output()
+		assert.NoError(t, err)
+		assert.Equal(t, "jane@acme.com\njane@acme.com\njane@acme.com", strings.TrimSpace(string(output)))
+	})
+
+	t.Run("should amend the committer only of the last commit keeping the dates", func(t *testing.T) {
+		workingDir := initializateGitRepository(t)
+
+		rootCmd := initializateRootContainer(t, &RootComponentOption{
+			profile:     t.TempDir(),
+			local:       false,
+			workingDir:  workingDir,
+			userHomeDir: t.TempDir(),
+		})
+
+		rootCmd.SetOutput(stdout)
+
+		workspace := faker.Internet().User()
+		name := faker.Person().Name()
+		email := faker.Internet().Email()
+		authorName := faker.Person().Name()
+		authorEmail := faker.Internet().Email()
+
+		cmd := exec.Command("git", "commit", "--allow-empty", "-m", "Initial commit", "--author", authorName+" <"+authorEmail+">")
+		cmd.Dir = workingDir
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_DATE=1738436196 +0100", "GIT_COMMITTER_DATE=1738436196 +0100")
+		assert.NoError(t, cmd.Run())
+
+		rootCmd.SetArgs([]string{"add", "-w", workspace, "-n", name, "-e", email})
+		err := rootCmd.Execute()
+
+		assert.Nil(t, err)
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{"amend", "-w", workspace, "--committer-only", "--keep-date"})
+		err = rootCmd.Execute()
+
+		assert.Nil(t, err)
+		assert.Contains(t, stdout.String(), "Amended commit committer to "+domain.NormalizeScmIdentityValue(name)+" <"+strings.ToLower(email)+">")
+		stdout.Reset()
+
+		cmd = exec.Command("git", "log", "-1", "--format=%an <%ae> %at|%cn <%ce> %ct")
+		cmd.Dir = workingDir
+		output, err := cmd.Output()
+		assert.NoError(t, err)
+
+		assert.Equal(t, domain.NormalizeScmIdentityValue(authorName)+" <"+authorEmail+"> 1738436196|"+domain.NormalizeScmIdentityValue(name)+" <"+strings.ToLower(email)+"> 1738436196", strings.TrimSpace(string(output)))
+	})
+
+	t.Run("should not amend again a commit whose name git wrote without its trailing dot", func(t *testing.T) {
+		workingDir := initializateGitRepository(t)
+
+		rootCmd := initializateRootContainer(t, &RootComponentOption{
+			profile:     t.TempDir(),
+			local:       false,
+			workingDir:  workingDir,
+			userHomeDir: t.TempDir(),
+		})
+
+		rootCmd.SetOutput(stdout)
+
+		emptyCommit(t, workingDir, "Initial commit", faker.Person().Name(), faker.Internet().Email())
+
+		rootCmd.SetArgs([]string{"add", "-w", "work", "-n", "Jane Doe Jr.", "-e", "jane@acme.com"})
+		assert.Nil(t, rootCmd.Execute())
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{"amend", "-w", "work"})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Contains(t, stdout.String(), "Amended commit author to Jane Doe Jr <jane@acme.com>")
+		stdout.Reset()
+
+		head := func() string {
+			cmd := exec.Command("git", "rev-parse", "HEAD")
+			cmd.Dir = workingDir
+			output, err := cmd.Output()
+			assert.NoError(t, err)
+
+			return strings.TrimSpace(string(output))
+		}
+		amended := head()
+
+		rootCmd.SetArgs([]string{"amend", "-w", "work"})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Equal(t, amended, head())
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{"amend", "HEAD", "-w", "work"})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Contains(t, stdout.String(), "The commits of \"HEAD\" already match the profile \"work\"")
+		assert.Equal(t, amended, head())
+		stdout.Reset()
+	})
+
+	t.Run("should show the error when keep date and reset date are given to amend", func(t *testing.T) {
+		workingDir := initializateGitRepository(t)
+
+		rootCmd := initializateRootContainer(t, &RootComponentOption{
+			profile:     t.TempDir(),
+			local:       false,
+			workingDir:  workingDir,
+			userHomeDir: t.TempDir(),
+		})
+
+		rootCmd.SetOutput(stdout)
+
+		rootCmd.SetArgs([]string{"amend", "--keep-date", "--reset-date"})
+		err := rootCmd.Execute()
+
+		assert.Error(t, err)
+		stdout.Reset()
+	})
+
+	t.Run("should print the profiles in the structured output formats", func(t *testing.T) {
+		workingDir := initializateGitRepository(t)
+		profileDir := t.TempDir()
+
+		rootCmd := initializateRootContainer(t, &RootComponentOption{
+			profile:     profileDir,
+			local:       false,
+			workingDir:  workingDir,
+			userHomeDir: t.TempDir(),
+		})
+
+		rootCmd.SetOutput(stdout)
+
+		workspace := faker.Internet().User()
+		otherWorkspace := faker.Internet().User() + "-other"
+		email := strings.ToLower(faker.Internet().Email())
+		name := faker.Person().FirstName() + " " + faker.Person().LastName()
+
+		rootCmd.SetArgs([]string{"add", "-w", workspace, "-n", name, "-e", email})
+		assert.Nil(t, rootCmd.Execute())
+
+		rootCmd.SetArgs([]string{"add", "-w", otherWorkspace, "-n", name, "-e", email})
+		assert.Nil(t, rootCmd.Execute())
+
+		rootCmd.SetArgs([]string{"set", "-w", workspace})
+		assert.Nil(t, rootCmd.Execute())
+		stdout.Reset()
+
+		// The current profile is not colored when the output is not a terminal
+		rootCmd.SetArgs([]string{"list", "--output", "table"})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Contains(t, stdout.String(), workspace+"\n")
+		assert.NotContains(t, stdout.String(), "\033[")
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{"list", "--output", "json"})
+		assert.Nil(t, rootCmd.Execute())
+
+		var profiles []map[string]any
+		assert.NoError(t, json.Unmarshal(stdout.Bytes(), &profiles))
+		assert.Len(t, profiles, 2)
+		assert.Equal(t, map[string]any{
+			"workspace": workspace,
+			"email":     email,
+			"name":      name,
+			"current":   true,
+			"source":    path.Join(profileDir, PROFILE_NAME),
+			"scope":     "global",
+		}, profiles[0])
+		assert.Equal(t, otherWorkspace, profiles[1]["workspace"])
+		assert.Equal(t, false, profiles[1]["current"])
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{"get", workspace, "--output", "yaml"})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Contains(t, stdout.String(), "workspace: "+workspace+"\n")
+		assert.Contains(t, stdout.String(), "current: true\n")
+		assert.Contains(t, stdout.String(), "scope: global\n")
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{"list", "--output", "template={{.Workspace}} {{.Current}}"})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Equal(t, workspace+" true\n"+otherWorkspace+" false\n", stdout.String())
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{"current", "--output", "template={{.Email}}"})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Equal(t, email+"\n", stdout.String())
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{"version", "--output", "json"})
+		assert.Nil(t, rootCmd.Execute())
+
+		var version map[string]any
+		assert.NoError(t, json.Unmarshal(stdout.Bytes(), &version))
+		assert.Contains(t, version, "version")
+		assert.Contains(t, version, "goVersion")
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{"list", "--output", "xml"})
+		err := rootCmd.Execute()
+		assert.ErrorIs(t, err, command.ErrInvalidOutput)
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{"unset", "--output", "json"})
+		err = rootCmd.Execute()
+		assert.ErrorIs(t, err, command.ErrOutputNotSupported)
+		assert.Equal(t, "The --output flag is only supported by list, get, current, rules list, audit, verify, doctor and version.\n", stdout.String())
+		assert.Equal(t, email, gitConfig(t, workingDir, "user.email"))
+		stdout.Reset()
+	})
+
+	t.Run("should import the identities of the git configuration", func(t *testing.T) {
+		workingDir := initializateGitRepository(t)
+		userHomeDir := t.TempDir()
+
+		rootCmd := initializateRootContainer(t, &RootComponentOption{
+			profile:     t.TempDir(),
+			local:       false,
+			workingDir:  workingDir,
+			userHomeDir: userHomeDir,
+		})
+
+		rootCmd.SetOutput(stdout)
+
+		err := os.WriteFile(path.Join(userHomeDir, ".gitconfig"), []byte(`[user]
+	name = Jane Doe
+	email = jane@gmail.com
+[includeIf "gitdir:~/work/"]
+	path = ~/work.gitconfig
+`), 0600)
+		assert.NoError(t, err)
+
+		err = os.WriteFile(path.Join(userHomeDir, "work.gitconfig"), []byte(`[user]
+	name = Jane Doe
+	email = jane@acme.com
+`), 0600)
+		assert.NoError(t, err)
+
+		rootCmd.SetArgs([]string{"import", "--from-gitconfig", "--yes"})
+		err = rootCmd.Execute()
+
+		assert.Nil(t, err)
+		assert.Contains(t, stdout.String(), "Profile \"personal\" imported from "+path.Join(userHomeDir, ".gitconfig"))
+		assert.Contains(t, stdout.String(), "Profile \"acme\" imported from "+path.Join(userHomeDir, "work.gitconfig"))
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{"get", "acme"})
+		err = rootCmd.Execute()
+
+		assert.Nil(t, err)
+		assert.Contains(t, stdout.String(), "Email: jane@acme.com")
+		stdout.Reset()
+
+		// The identities already imported are not proposed again
+		rootCmd.SetArgs([]string{"import", "--from-gitconfig", "--yes"})
+		err = rootCmd.Execute()
+
+		assert.Nil(t, err)
+		assert.Contains(t, stdout.String(), "No identities to import found in the git configuration")
+		stdout.Reset()
+	})
+
+	t.Run("should export and import the profiles in every format", func(t *testing.T) {
+		workingDir := initializateGitRepository(t)
+		sourceDir := t.TempDir()
+
+		rootCmd := initializateRootContainer(t, &RootComponentOption{
+			profile:     t.TempDir(),
+			local:       false,
+			workingDir:  workingDir,
+			userHomeDir: t.TempDir(),
+		})
+
+		rootCmd.SetOutput(stdout)
+
+		source := path.Join(sourceDir, "profiles.json")
+		err := os.WriteFile(source, []byte(`[
+  {
+    "workspace": "work",
+    "email": "jane@acme.com",
+    "name": "Jane Doe",
+    "signing": {"key": "ABCDEF0123456789", "format": "openpgp", "signCommits": true, "signTags": false},
+    "sshKey": "~/.ssh/id_work",
+    "remotes": ["github.com/acme/*", "gitlab.com/acme/*"],
+    "config": {"pull.rebase": "true", "commit.template": "~/acme template, v2.txt"}
+  },
+  {"workspace": "personal", "email": "jane@gmail.com", "name": "Jane Doe"}
+]`), 0600)
+		assert.NoError(t, err)
+
+		rootCmd.SetArgs([]string{"import", source})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Contains(t, stdout.String(), "Profile \"work\" created")
+		assert.Contains(t, stdout.String(), "Profile \"personal\" created")
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{"export", "--format", "json"})
+		assert.Nil(t, rootCmd.Execute())
+		expected := stdout.String()
+		stdout.Reset()
+
+		assert.Contains(t, expected, "\"signCommits\": true")
+		assert.Contains(t, expected, "\"sshKey\": \"~/.ssh/id_work\"")
+		assert.Contains(t, expected, "\"pull.rebase\": \"true\"")
+
+		for _, format := range []string{"json", "yaml", "toml", "csv"} {
+			file := path.Join(sourceDir, "exported."+format)
+
+			rootCmd.SetArgs([]string{"export", "work", "personal", "--format", format})
+			assert.Nil(t, rootCmd.Execute())
+			assert.NoError(t, os.WriteFile(file, stdout.Bytes(), 0600))
+			stdout.Reset()
+
+			importCmd := initializateRootContainer(t, &RootComponentOption{
+				profile:     t.TempDir(),
+				local:       false,
+				workingDir:  workingDir,
+				userHomeDir: t.TempDir(),
+			})
+
+			importCmd.SetOutput(stdout)
+
+			importCmd.SetArgs([]string{"import", file})
+			assert.Nil(t, importCmd.Execute())
+			stdout.Reset()
+
+			importCmd.SetArgs([]string{"export", "--format", "json"})
+			assert.Nil(t, importCmd.Execute())
+			assert.Equal(t, expected, stdout.String(), format)
+			stdout.Reset()
+		}
+
+		// An unknown workspace is not exported
+		rootCmd.SetArgs([]string{"export", "unknown", "--format", "json"})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Equal(t, "Profile \"unknown\" does not exist.\n", stdout.String())
+		stdout.Reset()
+	})
+
+	t.Run("should export and import the profiles that extend another one", func(t *testing.T) {
+		workingDir := initializateGitRepository(t)
+		profileDir := t.TempDir()
+		sourceDir := t.TempDir()
+
+		rootCmd := initializateRootContainer(t, &RootComponentOption{
+			profile:     profileDir,
+			local:       false,
+			workingDir:  workingDir,
+			userHomeDir: t.TempDir(),
+		})
+
+		rootCmd.SetOutput(stdout)
+
+		err := os.WriteFile(path.Join(profileDir, PROFILE_NAME), []byte(`[acme]
+name = Jane Doe
+email = jane@acme.com
+sshkey = ~/.ssh/id_acme
+
+[config "acme"]
+pull.rebase = true
+core.autocrlf = input
+
+[client]
+extends = acme
+email = jane@client.com
+
+[config "client"]
+pull.rebase = false
+`), 0600)
+		assert.NoError(t, err)
+
+		rootCmd.SetArgs([]string{"export", "--format", "json"})
+		assert.Nil(t, rootCmd.Execute())
+		expected := stdout.String()
+		stdout.Reset()
+
+		// Only the keys the profile defines itself are exported
+		var records []map[string]any
+		assert.NoError(t, json.Unmarshal([]byte(expected), &records))
+		assert.Equal(t, map[string]any{
+			"workspace": "client",
+			"extends":   "acme",
+			"email":     "jane@client.com",
+			"config":    map[string]any{"pull.rebase": "false"},
+		}, records[1])
+
+		for _, format := range []string{"json", "yaml", "toml"} {
+			file := path.Join(sourceDir, "exported."+format)
+			importDir := t.TempDir()
+
+			rootCmd.SetArgs([]string{"export", "--format", format})
+			assert.Nil(t, rootCmd.Execute())
+			assert.NoError(t, os.WriteFile(file, stdout.Bytes(), 0600))
+			stdout.Reset()
+
+			importCmd := initializateRootContainer(t, &RootComponentOption{
+				profile:     importDir,
+				local:       false,
+				workingDir:  workingDir,
+				userHomeDir: t.TempDir(),
+			})
+
+			importCmd.SetOutput(stdout)
+
+			importCmd.SetArgs([]string{"import", file})
+			assert.Nil(t, importCmd.Execute())
+			stdout.Reset()
+
+			importCmd.SetArgs([]string{"export", "--format", "json"})
+			assert.Nil(t, importCmd.Execute())
+			assert.Equal(t, expected, stdout.String(), format)
+			stdout.Reset()
+
+			// The inherited fields are not written into the imported profile
+			imported, err := os.ReadFile(path.Join(importDir, PROFILE_NAME))
+			assert.NoError(t, err)
+			_, client, _ := strings.Cut(string(imported), "[client]")
+			client, _, _ = strings.Cut(client, "[")
+			assert.Contains(t, client, "extends")
+			assert.NotContains(t, client, "name")
+			assert.NotContains(t, client, "sshkey")
+
+			importCmd.SetArgs([]string{"get", "client", "--resolved", "--output", "json"})
+			assert.Nil(t, importCmd.Execute())
+			assert.Contains(t, stdout.String(), `"sshKey": "~/.ssh/id_acme"`)
+			assert.Contains(t, stdout.String(), `"core.autocrlf": "input"`)
+			assert.Contains(t, stdout.String(), `"pull.rebase": "false"`)
+			stdout.Reset()
+		}
+	})
+
+	t.Run("should import the profiles with the merge strategies", func(t *testing.T) {
+		workingDir := initializateGitRepository(t)
+
+		rootCmd := initializateRootContainer(t, &RootComponentOption{
+			profile:     t.TempDir(),
+			local:       false,
+			workingDir:  workingDir,
+			userHomeDir: t.TempDir(),
+		})
+
+		rootCmd.SetOutput(stdout)
+
+		rootCmd.SetArgs([]string{"add", "-w", "work", "-n", "Jane Doe", "-e", "jane@acme.com"})
+		assert.Nil(t, rootCmd.Execute())
+		stdout.Reset()
+
+		records := "workspace,email,name\nwork,jane@acme.io,Jane Doe\n"
+
+		rootCmd.SetIn(bytes.NewBufferString(records))
+		rootCmd.SetArgs([]string{"import", "-", "--format", "csv", "--strategy", "skip"})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Contains(t, stdout.String(), "Profile \"work\" skipped")
+		stdout.Reset()
+
+		rootCmd.SetIn(bytes.NewBufferString(records))
+		rootCmd.SetArgs([]string{"import", "-", "--format", "csv", "--strategy", "rename"})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Contains(t, stdout.String(), "Profile \"work\" renamed to \"work-2\"")
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{"get", "work-2"})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Contains(t, stdout.String(), "Email: jane@acme.io")
+		stdout.Reset()
+
+		rootCmd.SetIn(bytes.NewBufferString(records))
+		rootCmd.SetArgs([]string{"import", "-", "--format", "csv", "--strategy", "overwrite"})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Contains(t, stdout.String(), "Profile \"work\" overwritten")
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{"get", "work"})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Contains(t, stdout.String(), "Email: jane@acme.io")
+		stdout.Reset()
+
+		rootCmd.SetIn(bytes.NewBufferString(records))
+		rootCmd.SetArgs([]string{"import", "-", "--format", "csv", "--strategy", "merge"})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Equal(t, "The import strategy must be skip, overwrite or rename.\n", stdout.String())
+		stdout.Reset()
+	})
+
+	t.Run("should not import anything when a record is invalid", func(t *testing.T) {
+		workingDir := initializateGitRepository(t)
+
+		rootCmd := initializateRootContainer(t, &RootComponentOption{
+			profile:     t.TempDir(),
+			local:       false,
+			workingDir:  workingDir,
+			userHomeDir: t.TempDir(),
+		})
+
+		rootCmd.SetOutput(stdout)
+
+		rootCmd.SetIn(bytes.NewBufferString(`- workspace: work
+  email: jane@acme.com
+  name: Jane Doe
+- workspace: personal
+  email: not an email
+  name: Jane Doe
+`))
+		rootCmd.SetArgs([]string{"import", "-", "--format", "yaml"})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Contains(t, stdout.String(), "Nothing was imported, invalid profile record 2: invalid email")
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{"list"})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Contains(t, stdout.String(), "No profiles found")
+		stdout.Reset()
+	})
+
+	t.Run("should rename a profile and the repositories that use it", func(t *testing.T) {
+		workingDir := initializateGitRepository(t)
+		walkDir := t.TempDir()
+
+		rootCmd := initializateRootContainer(t, &RootComponentOption{
+			profile:     t.TempDir(),
+			local:       false,
+			workingDir:  workingDir,
+			userHomeDir: t.TempDir(),
+		})
+
+		rootCmd.SetOutput(stdout)
+
+		rootCmd.SetArgs([]string{"add", "-w", "acme", "-n", "Jane Doe", "-e", "jane@acme.com"})
+		assert.Nil(t, rootCmd.Execute())
+
+		rootCmd.SetArgs([]string{"set", "-w", "acme"})
+		assert.Nil(t, rootCmd.Execute())
+
+		rootCmd.SetArgs([]string{"rule", "add", "acme", "--gitdir", "~/acme"})
+		assert.Nil(t, rootCmd.Execute())
+		stdout.Reset()
+
+		// A repository of the walked directory that uses the profile
+		otherRepository := path.Join(walkDir, "api")
+		assert.NoError(t, os.MkdirAll(otherRepository, 0750))
+		cmd := exec.Command("git", "init")
+		cmd.Dir = otherRepository
+		assert.NoError(t, cmd.Run())
+		cmd = exec.Command("git", "config", "--local", "user.workspace", "acme")
+		cmd.Dir = otherRepository
+		assert.NoError(t, cmd.Run())
+
+		// The signing key of the user has no format, the rename must not write one
+		cmd = exec.Command("git", "config", "--local", "user.signingkey", "MYKEY")
+		cmd.Dir = otherRepository
+		assert.NoError(t, cmd.Run())
+
+		otherConfig, err := os.ReadFile(path.Join(otherRepository, ".git", "config"))
+		assert.NoError(t, err)
+
+		rootCmd.SetArgs([]string{"rename", "acme", "acme-corp", "--walk", walkDir})
+		err = rootCmd.Execute()
+
+		assert.Nil(t, err)
+		assert.Contains(t, stdout.String(), "Profile \"acme\" renamed to \"acme-corp\"")
+		assert.Contains(t, stdout.String(), "Rule \"gitdir:~/acme/\" moved to profile \"acme-corp\"")
+		assert.Contains(t, stdout.String(), "Updated the local git configuration")
+		assert.NotContains(t, stdout.String(), "Updated the global git configuration")
+		assert.Contains(t, stdout.String(), "Updated the repository "+otherRepository)
+		assert.Contains(t, stdout.String(), "git profile rules apply")
+		stdout.Reset()
+
+		assert.Equal(t, "acme-corp", gitConfig(t, workingDir, "user.workspace"))
+		assert.Equal(t, "acme-corp", gitConfig(t, otherRepository, "user.workspace"))
+
+		// Only the workspace of the repository is rewritten, git still reads its configuration
+		content, err := os.ReadFile(path.Join(otherRepository, ".git", "config"))
+		assert.NoError(t, err)
+		assert.Equal(t, strings.Replace(string(otherConfig), "workspace = acme\n", "workspace = acme-corp\n", 1), string(content))
+
+		cmd = exec.Command("git", "status")
+		cmd.Dir = otherRepository
+		assert.NoError(t, cmd.Run())
+
+		rootCmd.SetArgs([]string{"current"})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Equal(t, "acme-corp\n", stdout.String())
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{"rules", "list"})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Contains(t, stdout.String(), "gitdir:~/acme/ -> acme-corp")
+		stdout.Reset()
+
+		// The old workspace does not exist anymore
+		rootCmd.SetArgs([]string{"rename", "acme", "acme-2"})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Equal(t, "Profile \"acme\" does not exist.\n", stdout.String())
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{"add", "-w", "personal", "-n", "Jane Doe", "-e", "jane@gmail.com"})
+		assert.Nil(t, rootCmd.Execute())
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{"rename", "acme-corp", "personal"})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Equal(t, "Profile \"personal\" already exists.\n", stdout.String())
+		stdout.Reset()
+	})
+
+	t.Run("should resolve and apply the profiles that extend another one", func(t *testing.T) {
+		workingDir := initializateGitRepository(t)
+		profileDir := t.TempDir()
+
+		rootCmd := initializateRootContainer(t, &RootComponentOption{
+			profile:     profileDir,
+			local:       false,
+			workingDir:  workingDir,
+			userHomeDir: t.TempDir(),
+		})
+
+		rootCmd.SetOutput(stdout)
+
+		err := os.WriteFile(path.Join(profileDir, PROFILE_NAME), []byte(`[acme]
+name = Jane Doe
+email = jane@acme.com
+signingkey = ABCDEF0123456789
+signingformat = openpgp
+commitgpgsign = true
+
+[loop]
+extends = loop
+`), 0600)
+		assert.NoError(t, err)
+
+		// The local profile extends a profile of the global file
+		err = os.WriteFile(path.Join(workingDir, PROFILE_NAME), []byte(`[client]
+extends = acme
+email = jane@client.com
+`), 0600)
+		assert.NoError(t, err)
+
+		rootCmd.SetArgs([]string{"get", "client", "--resolved"})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Contains(t, stdout.String(), "Extends: acme\n")
+		assert.Contains(t, stdout.String(), "Email: jane@client.com\n")
+		assert.Contains(t, stdout.String(), "Name: Jane Doe (from acme)\n")
+		assert.Contains(t, stdout.String(), "Signing Key: ABCDEF0123456789 (from acme)\n")
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{"get", "client", "--resolved", "--output", "json"})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Contains(t, stdout.String(), `"extends": "acme"`)
+		assert.Contains(t, stdout.String(), `"name": "acme"`)
+		assert.Contains(t, stdout.String(), `"signing": "acme"`)
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{"set", "client", "--output", "table"})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Contains(t, stdout.String(), "Profile \"client\" is now in use")
+		stdout.Reset()
+
+		assert.Equal(t, "jane@client.com", gitConfig(t, workingDir, "user.email"))
+		assert.Equal(t, "Jane Doe", gitConfig(t, workingDir, "user.name"))
+		assert.Equal(t, "ABCDEF0123456789", gitConfig(t, workingDir, "user.signingkey"))
+		assert.Equal(t, "true", gitConfig(t, workingDir, "commit.gpgsign"))
+
+		rootCmd.SetArgs([]string{"get", "-w", "loop", "--resolved", "--output", "table"})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Equal(t, "The profile extends itself through the profiles it extends.\n", stdout.String())
+		stdout.Reset()
+	})
+
+	t.Run("should refuse to delete an extended profile and remove the extends key", func(t *testing.T) {
+		workingDir := initializateGitRepository(t)
+		profileDir := t.TempDir()
+
+		// run runs a new command for each case, the flags of a command keep their values
+		run := func(args ...string) {
+			rootCmd := initializateRootContainer(t, &RootComponentOption{
+				profile:     profileDir,
+				local:       false,
+				workingDir:  workingDir,
+				userHomeDir: t.TempDir(),
+			})
+
+			rootCmd.SetOutput(stdout)
+			rootCmd.SetIn(strings.NewReader(""))
+			rootCmd.SetArgs(args)
+			assert.Nil(t, rootCmd.Execute())
+		}
+
+		run("add", "-w", "acme", "-n", "Jane Doe", "-e", "jane@acme.com")
+		run("add", "-w", "client", "--extends", "acme", "-e", "jane@client.com")
+		stdout.Reset()
+
+		run("get", "client", "--resolved")
+		assert.Contains(t, stdout.String(), "Extends: acme\n")
+		assert.Contains(t, stdout.String(), "Name: Jane Doe (from acme)\n")
+		stdout.Reset()
+
+		// A profile extending a missing one does not hide the others
+		file, err := os.OpenFile(path.Join(profileDir, PROFILE_NAME), os.O_APPEND|os.O_WRONLY, 0600)
+		assert.NoError(t, err)
+		_, err = file.WriteString("\n[orphan]\nextends = missing\n")
+		assert.NoError(t, err)
+		assert.NoError(t, file.Close())
+
+		run("list")
+		assert.Contains(t, stdout.String(), "acme")
+		assert.Contains(t, stdout.String(), "client")
+		assert.NotContains(t, stdout.String(), "orphan")
+		stdout.Reset()
+
+		run("delete", "acme")
+		assert.Equal(t, "Profile \"acme\" is extended by the profiles client, suggest to delete them or remove their extends first:\n"+
+			"  git profile add client --no-extends --force\n", stdout.String())
+		stdout.Reset()
+
+		run("add", "client", "--no-extends", "--force")
+		assert.Contains(t, stdout.String(), "Profile \"client\" updated successfully")
+		stdout.Reset()
+
+		content, err := os.ReadFile(path.Join(profileDir, PROFILE_NAME))
+		assert.NoError(t, err)
+		assert.Equal(t, 1, strings.Count(string(content), "extends"))
+
+		run("get", "client", "--resolved")
+		assert.NotContains(t, stdout.String(), "Extends:")
+		assert.Contains(t, stdout.String(), "Name: Jane Doe\n")
+		stdout.Reset()
+
+		run("delete", "acme")
+		assert.Contains(t, stdout.String(), "Profile \"acme\" deleted")
+		stdout.Reset()
+
+		run("delete", "orphan")
+		assert.Contains(t, stdout.String(), "Profile \"orphan\" deleted")
+		stdout.Reset()
+	})
+
+	t.Run("should apply and remove the extra git config keys of the profiles", func(t *testing.T) {
+		workingDir := initializateGitRepository(t)
+
+		rootCmd := initializateRootContainer(t, &RootComponentOption{
+			profile:     t.TempDir(),
+			local:       false,
+			workingDir:  workingDir,
+			userHomeDir: t.TempDir(),
+		})
+
+		rootCmd.SetOutput(stdout)
+
+		rootCmd.SetArgs([]string{"add", "-w", "personal", "-n", "Jane Doe", "-e", "jane@gmail.com"})
+		assert.Nil(t, rootCmd.Execute())
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{"add", "-w", "work", "-n", "Jane Doe", "-e", "jane@acme.com", "--config", "pull.rebase=true", "--config", "init.defaultBranch=main"})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Contains(t, stdout.String(), fmt.Sprintf(MsgProfileCreatedSuccessfully, "work"))
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{"add", "-w", "work", "-n", "Jane Doe", "-e", "jane@acme.com", "--config", "user.email=jane@gmail.com", "--force"})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Equal(t, "The git config key is set from the other options of the profile.\n", stdout.String())
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{"set", "-w", "work", "--output", "table"})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Contains(t, stdout.String(), "Config: init.defaultbranch=main, pull.rebase=true\n")
+		assert.Equal(t, "true", gitConfig(t, workingDir, "pull.rebase"))
+		assert.Equal(t, "main", gitConfig(t, workingDir, "init.defaultBranch"))
+		stdout.Reset()
+
+		// Switching to another profile removes the keys added by the previous one
+		rootCmd.SetArgs([]string{"set", "-w", "personal"})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Equal(t, "", gitConfig(t, workingDir, "pull.rebase"))
+		assert.Equal(t, "", gitConfig(t, workingDir, "init.defaultBranch"))
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{"set", "-w", "work"})
+		assert.Nil(t, rootCmd.Execute())
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{"unset"})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Equal(t, "", gitConfig(t, workingDir, "pull.rebase"))
+		stdout.Reset()
+	})
+
+	t.Run("should answer the git credentials with the usernames of the profile", func(t *testing.T) {
+		workingDir := initializateGitRepository(t)
+
+		rootCmd := initializateRootContainer(t, &RootComponentOption{
+			profile:     t.TempDir(),
+			local:       false,
+			workingDir:  workingDir,
+			userHomeDir: t.TempDir(),
+		})
+
+		rootCmd.SetOutput(stdout)
+
+		// The backing helper answers a password for the username it is given
+		helper := path.Join(t.TempDir(), "git-credential-test")
+		err := os.WriteFile(helper, []byte("#!/bin/sh\ngrep -q '^username=jane-acme$' && echo password=secret\nexit 0\n"), 0700) // #nosec G306
+		assert.NoError(t, err)
+
+		rootCmd.SetArgs([]string{"add", "-w", "work", "-n", "Jane Doe", "-e", "jane@acme.com", "--username", "github.com=jane-acme"})
+		assert.Nil(t, rootCmd.Execute())
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{"add", "-w", "work", "-n", "Jane Doe", "-e", "jane@acme.com", "--username", "github.com", "--force"})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Equal(t, "The username must be given as host=username.\n", stdout.String())
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{"set", "-w", "work", "--credential-helper", helper})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Contains(t, stdout.String(), "Usernames: github.com=jane-acme\n")
+		assert.Equal(t, "jane-acme", gitConfig(t, workingDir, "credential.https://github.com.username"))
+		assert.Equal(t, "!git profile credential --helper "+helper, gitConfig(t, workingDir, "credential.helper"))
+		stdout.Reset()
+
+		rootCmd.SetIn(strings.NewReader("protocol=https\nhost=github.com\n\n"))
+		rootCmd.SetArgs([]string{"credential", "--helper", helper, "get"})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Equal(t, "username=jane-acme\npassword=secret\n", stdout.String())
+		stdout.Reset()
+
+		// Other hosts are left to the backing helper
+		rootCmd.SetIn(strings.NewReader("protocol=https\nhost=gitlab.com\n\n"))
+		rootCmd.SetArgs([]string{"credential", "--helper", helper, "get"})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Equal(t, "", stdout.String())
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{"unset"})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Equal(t, "", gitConfig(t, workingDir, "credential.helper"))
+		assert.Equal(t, "", gitConfig(t, workingDir, "credential.https://github.com.username"))
+		stdout.Reset()
+	})
+
+	t.Run("should print the workspace of the repository for the prompt", func(t *testing.T) {
+		workingDir := initializateGitRepository(t)
+		t.Setenv("NO_COLOR", "")
+
+		rootCmd := initializateRootContainer(t, &RootComponentOption{
+			profile:     t.TempDir(),
+			local:       false,
+			workingDir:  workingDir,
+			userHomeDir: t.TempDir(),
+		})
+
+		rootCmd.SetOutput(stdout)
+
+		// Nothing is printed before a profile is set
+		rootCmd.SetArgs([]string{"prompt"})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Equal(t, "", stdout.String())
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{"add", "-w", "work", "-n", "Jane Doe", "-e", "jane@acme.com"})
+		assert.Nil(t, rootCmd.Execute())
+		rootCmd.SetArgs([]string{"set", "-w", "work"})
+		assert.Nil(t, rootCmd.Execute())
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{"prompt"})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Equal(t, "work", stdout.String())
+		stdout.Reset()
+
+		// The cached profile is refreshed when the profile file changes
+		rootCmd.SetArgs([]string{"add", "-w", "work", "-n", "Jane Doe", "-e", "jane.doe@acme.com", "--force"})
+		assert.Nil(t, rootCmd.Execute())
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{"prompt"})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Equal(t, "work!", stdout.String())
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{"prompt", "--shell", "zsh"})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Equal(t, "%{\033[31m%}work!%{\033[0m%}", stdout.String())
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{"delete", "-w", "work"})
+		assert.Nil(t, rootCmd.Execute())
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{"prompt", "--shell", "bash"})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Equal(t, "\001\033[33m\002work?\001\033[0m\002", stdout.String())
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{"prompt", "--init", "starship"})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Contains(t, stdout.String(), "[custom.git_profile]\n")
+		assert.Contains(t, stdout.String(), "command = \"git profile prompt\"\n")
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{"prompt", "--init", "tcsh"})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Equal(t, "The shell must be bash, zsh, fish or starship.\n", stdout.String())
+		stdout.Reset()
+	})
+
+	t.Run("should complete the workspaces and generate the completion scripts", func(t *testing.T) {
+		workingDir := initializateGitRepository(t)
+
+		rootCmd := initializateRootContainer(t, &RootComponentOption{
+			profile:     t.TempDir(),
+			local:       false,
+			workingDir:  workingDir,
+			userHomeDir: t.TempDir(),
+		})
+
+		rootCmd.SetOutput(stdout)
+
+		rootCmd.SetArgs([]string{"add", "-w", "work", "-n", "Jane Doe", "-e", "jane@acme.com"})
+		assert.Nil(t, rootCmd.Execute())
+		rootCmd.SetArgs([]string{"add", "-w", "personal", "-n", "Jane", "-e", "jane@home.com"})
+		assert.Nil(t, rootCmd.Execute())
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{cobra.ShellCompRequestCmd, "set", ""})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Contains(t, stdout.String(), "work\tJane Doe <jane@acme.com>\n")
+		assert.Contains(t, stdout.String(), "personal\tJane <jane@home.com>\n")
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{cobra.ShellCompRequestCmd, "amend", "-w", "w"})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Contains(t, stdout.String(), "work\tJane Doe <jane@acme.com>\n")
+		assert.NotContains(t, stdout.String(), "personal")
+		stdout.Reset()
+
+		// The workspaces already given are not completed again
+		rootCmd.SetArgs([]string{cobra.ShellCompRequestCmd, "export", "work", ""})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Contains(t, stdout.String(), "personal\tJane <jane@home.com>\n")
+		assert.NotContains(t, stdout.String(), "work\t")
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{cobra.ShellCompRequestCmd, "get", "work", ""})
+		assert.Nil(t, rootCmd.Execute())
+		assert.NotContains(t, stdout.String(), "personal")
+		stdout.Reset()
+
+		scripts := map[string]string{
+			"bash":       "_git_profile()\n",
+			"zsh":        `requestComp="git-profile __complete ${words[2,-1]}"`,
+			"fish":       "and set -p args git-profile\n",
+			"powershell": "Register-ArgumentCompleter -Native -CommandName 'git'",
+		}
+
+		for shell, expected := range scripts {
+			rootCmd.SetArgs([]string{"completion", shell})
+			assert.Nil(t, rootCmd.Execute())
+			assert.Contains(t, stdout.String(), expected)
+			stdout.Reset()
+		}
+
+		// The scripts are generated for git-profile, the root command keeps its name
+		assert.Equal(t, "git profile [command]", rootCmd.Use)
+
+		rootCmd.SetArgs([]string{"completion", "tcsh"})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Equal(t, "The shell must be bash, zsh, fish or powershell.\n", stdout.String())
+		stdout.Reset()
+	})
+
+	t.Run("should set, show, amend and unset the profile from anywhere inside a repository", func(t *testing.T) {
+		repository := initializateGitRepository(t)
+		workingDir := path.Join(repository, "src", "pkg")
+		assert.NoError(t, os.MkdirAll(workingDir, 0750))
+
+		rootCmd := initializateRootContainer(t, &RootComponentOption{
+			profile:     t.TempDir(),
+			local:       false,
+			workingDir:  workingDir,
+			userHomeDir: t.TempDir(),
+		})
+
+		rootCmd.SetOutput(stdout)
+
+		rootCmd.SetArgs([]string{"add", "-w", "work", "-n", "Jane Doe", "-e", "jane@acme.com"})
+		assert.Nil(t, rootCmd.Execute())
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{"set", "-w", "work"})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Contains(t, stdout.String(), "Profile \"work\" is now in use\n")
+		assert.Equal(t, "jane@acme.com", gitConfig(t, repository, "user.email"))
+		stdout.Reset()
+
+		// No stray configuration is created in the subdirectory
+		_, err := os.Stat(path.Join(workingDir, ".git"))
+		assert.True(t, os.IsNotExist(err))
+
+		rootCmd.SetArgs([]string{"current"})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Equal(t, "work\n", stdout.String())
+		stdout.Reset()
+
+		emptyCommit(t, repository, "initial", "John Smith", "john@home.com")
+
+		rootCmd.SetArgs([]string{"amend"})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Equal(t, "Jane Doe,jane@acme.com\n", lastCommit(t, repository))
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{"unset"})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Equal(t, "Unset profile \"work\"\n", stdout.String())
+		stdout.Reset()
+	})
+
+	t.Run("should keep the local profile at the root of the repository", func(t *testing.T) {
+		repository := initializateGitRepository(t)
+		workingDir := path.Join(repository, "src")
+		assert.NoError(t, os.MkdirAll(workingDir, 0750))
+
+		rootCmd := initializateRootContainer(t, &RootComponentOption{
+			local:       true,
+			workingDir:  workingDir,
+			userHomeDir: t.TempDir(),
+		})
+
+		rootCmd.SetOutput(stdout)
+
+		rootCmd.SetArgs([]string{"add", "-w", "work", "-n", "Jane Doe", "-e", "jane@acme.com"})
+		assert.Nil(t, rootCmd.Execute())
+		stdout.Reset()
+
+		assert.FileExists(t, path.Join(repository, ".gitprofile"))
+		assert.NoFileExists(t, path.Join(workingDir, ".gitprofile"))
+	})
+
+	t.Run("should refuse the local profile outside of a repository", func(t *testing.T) {
+		workingDir := t.TempDir()
+		t.Setenv("GIT_CEILING_DIRECTORIES", path.Dir(workingDir))
+
+		rootCmd := initializateRootContainer(t, &RootComponentOption{
+			profile:     t.TempDir(),
+			local:       false,
+			workingDir:  workingDir,
+			userHomeDir: t.TempDir(),
+		})
+
+		rootCmd.SetOutput(stdout)
+
+		rootCmd.SetArgs([]string{"add", "-w", "work", "-n", "Jane Doe", "-e", "jane@acme.com"})
+		assert.Nil(t, rootCmd.Execute())
+		stdout.Reset()
+
+		for _, args := range [][]string{
+			{"set", "-w", "work"},
+			{"current"},
+			{"unset"},
+			{"amend", "-w", "work"},
+		} {
+			rootCmd.SetArgs(args)
+			assert.Nil(t, rootCmd.Execute())
+			assert.Equal(t, "Not a git repository (or any of the parent directories).\n", stdout.String(), args[0])
+			stdout.Reset()
+		}
+
+		_, err := os.Stat(path.Join(workingDir, ".git"))
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("should move the profiles to the XDG location and resolve the global git config as git does", func(t *testing.T) {
+		workingDir := initializateGitRepository(t)
+		userHomeDir := t.TempDir()
+		t.Setenv("GIT_CONFIG_GLOBAL", "")
+		t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+
+		// The profiles of an older version are in the home directory
+		rootCmd := initializateRootContainer(t, &RootComponentOption{
+			profile:     userHomeDir,
+			local:       false,
+			workingDir:  workingDir,
+			userHomeDir: userHomeDir,
+		})
+
+		rootCmd.SetOutput(stdout)
+		rootCmd.SetArgs([]string{"add", "-w", "work", "-n", "Jane Doe", "-e", "jane@acme.com"})
+		assert.Nil(t, rootCmd.Execute())
+		stdout.Reset()
+
+		// The profiles are read from the home directory until they are moved
+		option := &RootComponentOption{
+			local:       false,
+			workingDir:  workingDir,
+			userHomeDir: userHomeDir,
+		}
+
+		rootCmd = initializateRootContainer(t, option)
+		rootCmd.SetOutput(stdout)
+		rootCmd.SetArgs([]string{"list"})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Contains(t, stdout.String(), "work")
+		stdout.Reset()
+
+		profiles := path.Join(userHomeDir, ".config", "git-profile", ".gitprofile")
+		assert.NoFileExists(t, profiles)
+		assert.FileExists(t, path.Join(userHomeDir, ".gitprofile"))
+
+		// The profiles are moved once with a notice
+		notice := new(bytes.Buffer)
+		migrateProfile(option, notice)
+		assert.Equal(t, "Moved the profiles from "+path.Join(userHomeDir, ".gitprofile")+" to "+profiles+"\n", notice.String())
+		notice.Reset()
+
+		migrateProfile(option, notice)
+		assert.Empty(t, notice.String())
+		assert.FileExists(t, profiles)
+		assert.NoFileExists(t, path.Join(userHomeDir, ".gitprofile"))
+
+		rootCmd = initializateRootContainer(t, option)
+		rootCmd.SetOutput(stdout)
+
+		rootCmd.SetArgs([]string{"version"})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Contains(t, stdout.String(), "Profile Path: "+profiles+"\n")
+		assert.Contains(t, stdout.String(), "Git Global Config: "+path.Join(userHomeDir, ".gitconfig")+"\n")
+		assert.Contains(t, stdout.String(), "Git System Config: disabled\n")
+		stdout.Reset()
+
+		// GIT_CONFIG_GLOBAL replaces the global git configuration
+		global := path.Join(t.TempDir(), "gitconfig")
+		t.Setenv("GIT_CONFIG_GLOBAL", global)
+
+		rootCmd = initializateRootContainer(t, &RootComponentOption{
+			local:       false,
+			workingDir:  workingDir,
+			userHomeDir: userHomeDir,
+		})
+
+		rootCmd.SetOutput(stdout)
+		rootCmd.SetArgs([]string{"set", "-w", "work", "--global"})
+		assert.Nil(t, rootCmd.Execute())
+		stdout.Reset()
+
+		content, err := os.ReadFile(global)
+		assert.NoError(t, err)
+		assert.Contains(t, string(content), "\tworkspace = work\n")
+		_, err = os.Stat(path.Join(userHomeDir, ".gitconfig"))
+		assert.True(t, os.IsNotExist(err))
+
+		rootCmd.SetArgs([]string{"current", "--global"})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Equal(t, "work\n", stdout.String())
+		stdout.Reset()
+	})
+
+	t.Run("should keep the profiles in the XDG directory given in the options", func(t *testing.T) {
+		xdgConfigHome := t.TempDir()
+		t.Setenv("GIT_CONFIG_GLOBAL", "")
+
+		rootCmd := initializateRootContainer(t, &RootComponentOption{
+			local:         false,
+			workingDir:    initializateGitRepository(t),
+			userHomeDir:   t.TempDir(),
+			xdgConfigHome: xdgConfigHome,
+		})
+
+		rootCmd.SetOutput(stdout)
+		rootCmd.SetArgs([]string{"add", "-w", "work", "-n", "Jane Doe", "-e", "jane@acme.com"})
+		assert.Nil(t, rootCmd.Execute())
+		stdout.Reset()
+
+		assert.FileExists(t, path.Join(xdgConfigHome, "git-profile", ".gitprofile"))
+	})
+
+	t.Run("should diagnose and fix the identity of the repository", func(t *testing.T) {
+		workingDir := initializateGitRepository(t)
+		userHomeDir := t.TempDir()
+		t.Setenv("GIT_CONFIG_GLOBAL", path.Join(userHomeDir, ".gitconfig"))
+		t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+		for _, name := range []string{"GIT_AUTHOR_NAME", "GIT_AUTHOR_EMAIL", "GIT_COMMITTER_NAME", "GIT_COMMITTER_EMAIL"} {
+			t.Setenv(name, "")
+			assert.NoError(t, os.Unsetenv(name))
+		}
+
+		rootCmd := initializateRootContainer(t, &RootComponentOption{
+			profile:     t.TempDir(),
+			local:       false,
+			workingDir:  workingDir,
+			userHomeDir: userHomeDir,
+		})
+
+		rootCmd.SetOutput(stdout)
+
+		workspace := faker.Internet().User()
+		name := faker.Person().FirstName() + " " + faker.Person().LastName()
+		email := faker.Internet().Email()
+
+		rootCmd.SetArgs([]string{"add", "-w", workspace, "-n", name, "-e", email})
+		assert.Nil(t, rootCmd.Execute())
+		rootCmd.SetArgs([]string{"set", workspace})
+		assert.Nil(t, rootCmd.Execute())
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{"doctor"})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Equal(t, "No problems found\n", stdout.String())
+		stdout.Reset()
+
+		// A mismatch is a warning, the command succeeds and suggests the fix
+		configureGit(t, workingDir, name, faker.Internet().Email(), "local")
+		t.Setenv("GIT_COMMITTER_NAME", "Bot")
+
+		rootCmd.SetArgs([]string{"doctor"})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Contains(t, stdout.String(), "[warning] The local git identity "+name+" <")
+		assert.Contains(t, stdout.String(), "does not match the profile \""+workspace+"\" (fixable)\n")
+		assert.Contains(t, stdout.String(), "[warning] GIT_COMMITTER_NAME=Bot overrides the identity of the profile\n")
+		assert.Contains(t, stdout.String(), "git profile doctor --fix")
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{"doctor", "--fix"})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Contains(t, stdout.String(), "(fixed)")
+		stdout.Reset()
+
+		assert.NoError(t, os.Unsetenv("GIT_COMMITTER_NAME"))
+		rootCmd.SetArgs([]string{"doctor", "--fix=false"})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Equal(t, "No problems found\n", stdout.String())
+		stdout.Reset()
+
+		// A workspace of a deleted profile is an error, the fix removes it and keeps the identity
+		cmd := exec.Command("git", "config", "--local", "user.workspace", "deleted")
+		cmd.Dir = workingDir
+		assert.NoError(t, cmd.Run())
+
+		rootCmd.SetArgs([]string{"doctor", "-o", "json"})
+		err := rootCmd.Execute()
+
+		var exitErr *command.ExitError
+		assert.True(t, errors.As(err, &exitErr))
+		assert.Equal(t, command.ExitCodeFailure, exitErr.Code)
+
+		var findings []map[string]any
+		assert.NoError(t, json.Unmarshal(stdout.Bytes(), &findings))
+		assert.Len(t, findings, 1)
+		assert.Equal(t, "workspace-not-exists", findings[0]["check"])
+		assert.Equal(t, "error", findings[0]["severity"])
+		assert.Equal(t, "local", findings[0]["scope"])
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{"doctor", "--fix", "-o", "table"})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Contains(t, stdout.String(), "[error] The local git configuration uses the profile \"deleted\" which does not exist (fixed)\n")
+		stdout.Reset()
+
+		cmd = exec.Command("git", "config", "--local", "--get", "user.workspace")
+		cmd.Dir = workingDir
+		assert.Error(t, cmd.Run())
+
+		cmd = exec.Command("git", "config", "--local", "--get", "user.email")
+		cmd.Dir = workingDir
+		output, err := cmd.Output()
+		assert.NoError(t, err)
+		assert.Equal(t, email+"\n", string(output))
+	})
+
+	t.Run("should audit the identities of the history", func(t *testing.T) {
+		workingDir := initializateGitRepository(t)
+
+		rootCmd := initializateRootContainer(t, &RootComponentOption{
+			profile:     t.TempDir(),
+			local:       false,
+			workingDir:  workingDir,
+			userHomeDir: t.TempDir(),
+		})
+
+		rootCmd.SetOutput(stdout)
+
+		for _, args := range [][]string{
+			{"add", "-w", "work", "-n", "Jane Doe", "-e", "jane@acme.com"},
+			{"add", "-w", "personal", "-n", "Jane", "-e", "jane@home.org"},
+			{"set", "work"},
+		} {
+			rootCmd.SetArgs(args)
+			assert.Nil(t, rootCmd.Execute())
+		}
+		stdout.Reset()
+
+		emptyCommit(t, workingDir, "First", "Jane Doe", "jane@acme.com")
+		emptyCommit(t, workingDir, "Second", "Jane", "jane@home.org")
+		emptyCommit(t, workingDir, "Third", "Build", "build@ci.internal")
+
+		rootCmd.SetArgs([]string{"audit"})
+		assert.Nil(t, rootCmd.Execute())
+
+		lines := strings.Split(stdout.String(), "\n")
+		assert.Equal(t, "Audited 3 commits of \"HEAD\" against the profile \"work\":", lines[0])
+		assert.Regexp(t, `^STATUS +IDENTITY +PROFILE +AUTHORED +COMMITTED +FIRST +LAST +EXAMPLES$`, lines[2])
+		assert.Regexp(t, `^unknown +Build <build@ci.internal> +- +1 +0 `, lines[3])
+		assert.Regexp(t, `^other-workspace +Jane <jane@home.org> +personal +1 +0 `, lines[4])
+		assert.Regexp(t, `^profile +Jane Doe <jane@acme.com> +work +1 +3 `, lines[5])
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{"audit", "HEAD~1", "-w", "personal", "-o", "json"})
+		assert.Nil(t, rootCmd.Execute())
+
+		var audit map[string]any
+		assert.NoError(t, json.Unmarshal(stdout.Bytes(), &audit))
+		assert.Equal(t, "HEAD~1", audit["revision"])
+		assert.Equal(t, "personal", audit["workspace"])
+		assert.Equal(t, float64(2), audit["commits"])
+
+		identities := audit["identities"].([]any)
+		assert.Len(t, identities, 2)
+		assert.Equal(t, "other-workspace", identities[0].(map[string]any)["status"])
+		assert.Equal(t, "jane@acme.com", identities[0].(map[string]any)["email"])
+		assert.Equal(t, "profile", identities[1].(map[string]any)["status"])
+		assert.Len(t, identities[1].(map[string]any)["examples"], 1)
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{"audit", "unknown", "-w", "", "-o", "table"})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Equal(t, "Commit \"unknown\" not found\n", stdout.String())
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{"audit", "-w", "not valid!", "-o", "table"})
+		assert.Nil(t, rootCmd.Execute())
+		assert.Equal(t, "The workspace must contain only alphanumeric characters.\n", stdout.String())
+		stdout.Reset()
+	})
+
+	t.Run("should verify the identities of a range without a configured profile", func(t *testing.T) {
+		workingDir := initializateGitRepository(t)
+		configureGit(t, workingDir, "Jane Doe", "jane@acme.com", "local")
+
+		emptyCommit(t, workingDir, "First", "Jane Doe", "jane@acme.com")
+		emptyCommit(t, workingDir, "Second", "Jane", "jane@gmail.com")
+		emptyCommit(t, workingDir, "Third", "Build", "build@ci.acme.com")
+
+		// A CI checkout has a detached HEAD and no profiles
+		cmd := exec.Command("git", "checkout", "--detach")
+		cmd.Dir = workingDir
+		_, err := cmd.CombinedOutput()
+		assert.NoError(t, err)
+
+		// verify runs a new command for each case, the flags of a command keep their values
+		verify := func(args ...string) error {
+			rootCmd := initializateRootContainer(t, &RootComponentOption{
+				profile:     t.TempDir(),
+				local:       false,
+				workingDir:  workingDir,
+				userHomeDir: t.TempDir(),
+			})
+
+			rootCmd.SetOutput(stdout)
+			rootCmd.SetArgs(append([]string{"verify"}, args...))
+			return rootCmd.Execute()
+		}
+
+		var exitErr *command.ExitError
+
+		err = verify("--allow-domain", "acme.com")
+		assert.ErrorAs(t, err, &exitErr)
+		assert.Equal(t, command.ExitCodeFailure, exitErr.Code)
+
+		lines := strings.Split(stdout.String(), "\n")
+		assert.Equal(t, "Found 1 violations of the policy in the 3 commits of \"HEAD\":", lines[0])
+		assert.Regexp(t, `^  [0-9a-f]{7} author +Jane <jane@gmail.com>$`, lines[1])
+		stdout.Reset()
+
+		// An identity that authored and committed a commit is two violations
+		err = verify("--range", "HEAD~2", "--allow", "jane@gmail.com")
+		assert.ErrorAs(t, err, &exitErr)
+
+		lines = strings.Split(stdout.String(), "\n")
+		assert.Equal(t, "Found 2 violations of the policy in the 1 commits of \"HEAD~2\":", lines[0])
+		assert.Regexp(t, `^  [0-9a-f]{7} author +Jane Doe <jane@acme.com>$`, lines[1])
+		assert.Regexp(t, `^  [0-9a-f]{7} committer +Jane Doe <jane@acme.com>$`, lines[2])
+		stdout.Reset()
+
+		assert.Nil(t, verify("--allow-domain", "acme.com", "--allow", "JANE@gmail.com"))
+		assert.Equal(t, "All 3 commits of \"HEAD\" are allowed\n", stdout.String())
+		stdout.Reset()
+
+		assert.Nil(t, verify("--range", "HEAD~2..HEAD~1", "--allow", "jane@acme.com", "--allow", "jane@gmail.com"))
+		assert.Equal(t, "All 1 commits of \"HEAD~2..HEAD~1\" are allowed\n", stdout.String())
+		stdout.Reset()
+
+		// The symmetric difference and the empty sides of a range are read as git does
+		assert.Nil(t, verify("--range", "HEAD~2...HEAD~1", "--allow", "jane@acme.com", "--allow", "jane@gmail.com"))
+		assert.Equal(t, "All 1 commits of \"HEAD~2...HEAD~1\" are allowed\n", stdout.String())
+		stdout.Reset()
+
+		assert.Nil(t, verify("--range", "HEAD~1..", "--allow-domain", "acme.com"))
+		assert.Equal(t, "All 1 commits of \"HEAD~1..\" are allowed\n", stdout.String())
+		stdout.Reset()
+
+		assert.Nil(t, verify("--range", "..HEAD", "--allow", "nobody@acme.com"))
+		assert.Equal(t, "All 0 commits of \"..HEAD\" are allowed\n", stdout.String())
+		stdout.Reset()
+
+		// The policy file of the repository is read without flags
+		err = os.WriteFile(path.Join(workingDir, ".gitprofile-policy"), []byte("[verify]\nallow-domain = acme.com\n"), 0600)
+		assert.NoError(t, err)
+
+		err = verify("-o", "json")
+		assert.ErrorAs(t, err, &exitErr)
+
+		var result map[string]any
+		assert.NoError(t, json.Unmarshal(stdout.Bytes(), &result))
+		assert.Equal(t, "HEAD", result["range"])
+		assert.Equal(t, float64(3), result["commits"])
+
+		violations := result["violations"].([]any)
+		assert.Len(t, violations, 1)
+		assert.Equal(t, "author", violations[0].(map[string]any)["role"])
+		assert.Equal(t, "jane@gmail.com", violations[0].(map[string]any)["email"])
+		stdout.Reset()
+
+		err = verify("--allow", "personal")
+		assert.ErrorAs(t, err, &exitErr)
+		assert.Contains(t, stdout.String(), "allowed profile does not exist: personal")
+		stdout.Reset()
+
+		err = verify("--range", "unknown..HEAD", "--allow", "jane@acme.com")
+		assert.ErrorAs(t, err, &exitErr)
+		assert.Equal(t, "Commit \"unknown..HEAD\" not found\n", stdout.String())
+		stdout.Reset()
+
+		assert.NoError(t, os.Remove(path.Join(workingDir, ".gitprofile-policy")))
+
+		err = verify()
+		assert.ErrorAs(t, err, &exitErr)
+		assert.Contains(t, stdout.String(), "No identity is allowed")
+		stdout.Reset()
+	})
+
+	t.Run("should check the pushed commits against the profile of the remote", func(t *testing.T) {
+		workingDir := initializateGitRepository(t)
+		profileDir := t.TempDir()
+		userHomeDir := t.TempDir()
+
+		// run runs a new command for each case, the flags of a command keep their values
+		run := func(input string, args ...string) error {
+			rootCmd := initializateRootContainer(t, &RootComponentOption{
+				profile:     profileDir,
+				local:       false,
+				workingDir:  workingDir,
+				userHomeDir: userHomeDir,
+			})
+
+			rootCmd.SetOutput(stdout)
+			rootCmd.SetIn(strings.NewReader(input))
+			rootCmd.SetArgs(args)
+			return rootCmd.Execute()
+		}
+
+		git := func(args ...string) string {
+			cmd := exec.Command("git", args...)
+			cmd.Dir = workingDir
+			output, err := cmd.Output()
+			assert.NoError(t, err)
+
+			return strings.TrimSpace(string(output))
+		}
+
+		assert.Nil(t, run("", "add", "-w", "work", "-n", "Jane Doe", "-e", "jane@acme.com", "--remote", "github.com:acme-corp/*"))
+		assert.Nil(t, run("", "add", "-w", "personal", "-n", "Jane Doe", "-e", "jane@home.org"))
+		assert.Nil(t, run("", "set", "work"))
+		emptyCommit(t, workingDir, "First", "Jane Doe", "jane@acme.com")
+
+		base := git("rev-parse", "HEAD")
+		git("remote", "add", "origin", "git@github.com:acme-corp/api.git")
+		git("update-ref", "refs/remotes/origin/main", base)
+
+		// A commit brought with the personal profile, the configured one
+		assert.Nil(t, run("", "set", "personal"))
+		emptyCommit(t, workingDir, "Second", "Jane Doe", "jane@home.org")
+		head := git("rev-parse", "HEAD")
+		stdout.Reset()
+
+		var exitErr *command.ExitError
+
+		err := run("refs/heads/main "+head+" refs/heads/main "+base+"\n", "check", "--pre-push", "origin", "git@github.com:acme-corp/api.git")
+		assert.ErrorAs(t, err, &exitErr)
+		assert.Equal(t, command.ExitCodeFailure, exitErr.Code)
+
+		lines := strings.Split(stdout.String(), "\n")
+		assert.Equal(t, "The pushed commits do not match the profile \"work\" expected for \"git@github.com:acme-corp/api.git\":", lines[0])
+		assert.Equal(t, "  refs/heads/main:", lines[3])
+		assert.Equal(t, "    "+head[:7]+" author    Jane Doe <jane@home.org>", lines[4])
+		assert.Equal(t, "    "+head[:7]+" committer Jane Doe <jane@home.org>", lines[5])
+		assert.Contains(t, stdout.String(), "  git profile amend "+base[:7]+"..HEAD -w work\n")
+		stdout.Reset()
+
+		assert.Nil(t, run("", "amend", base[:7]+"..HEAD", "-w", "work"))
+		stdout.Reset()
+
+		// A new ref is checked against the remote-tracking branches, the url is the one of the remote
+		push := "refs/heads/main " + git("rev-parse", "HEAD") + " refs/heads/feature 0000000000000000000000000000000000000000\n"
+		assert.Nil(t, run(push, "check", "--pre-push", "origin"))
+		assert.Equal(t, "All 1 pushed commits match the profile \"work\"\n", stdout.String())
+		stdout.Reset()
+
+		assert.Nil(t, run(push, "check", "--pre-push", "--quiet", "origin"))
+		assert.Empty(t, stdout.String())
+
+		// A new branch pushed to a fork url skips the commits of the remote-tracking branches of origin
+		git("update-ref", "refs/remotes/origin/main", git("rev-parse", "HEAD"))
+		emptyCommit(t, workingDir, "Fork", "Jane Doe", "jane@home.org")
+		fork := "git@github.com:jane/api.git"
+		push = "refs/heads/fork " + git("rev-parse", "HEAD") + " refs/heads/fork 0000000000000000000000000000000000000000\n"
+		assert.Nil(t, run(push, "check", "--pre-push", fork, fork))
+		assert.Equal(t, "All 1 pushed commits match the profile \"personal\"\n", stdout.String())
+		stdout.Reset()
+
+		err = run("invalid\n", "check", "--pre-push", "origin")
+		assert.ErrorAs(t, err, &exitErr)
+		assert.Contains(t, stdout.String(), "Failed to read the pushed refs")
+		stdout.Reset()
+
+		assert.Nil(t, run("", "hook", "install", "pre-push"))
+		assert.Contains(t, stdout.String(), "Hook \"pre-push\" installed")
+		stdout.Reset()
+
+		content, err := os.ReadFile(path.Join(workingDir, ".git", "hooks", "pre-push"))
+		assert.NoError(t, err)
+		assert.Contains(t, string(content), "git profile check --pre-push --quiet \"$@\"")
+
+		assert.Nil(t, run("", "hook", "uninstall", "pre-push"))
+		assert.Contains(t, stdout.String(), "Hook \"pre-push\" uninstalled")
+		stdout.Reset()
+
+		assert.Error(t, run("", "hook", "install", "post-merge"))
+		stdout.Reset()
+	})
+
+	t.Run("should suggest to amend the commits of a new ref one at a time", func(t *testing.T) {
+		workingDir := initializateGitRepository(t)
+		profileDir := t.TempDir()
+
+		run := func(input string, args ...string) error {
+			rootCmd := initializateRootContainer(t, &RootComponentOption{
+				profile:     profileDir,
+				local:       false,
+				workingDir:  workingDir,
+				userHomeDir: t.TempDir(),
+			})
+
+			rootCmd.SetOutput(stdout)
+			rootCmd.SetIn(strings.NewReader(input))
+			rootCmd.SetArgs(args)
+			return rootCmd.Execute()
+		}
+
+		git := func(args ...string) string {
+			cmd := exec.Command("git", args...)
+			cmd.Dir = workingDir
+			output, err := cmd.Output()
+			assert.NoError(t, err)
+
+			return strings.TrimSpace(string(output))
+		}
+
+		assert.Nil(t, run("", "add", "-w", "work", "-n", "Jane Doe", "-e", "jane@acme.com", "--remote", "github.com:acme-corp/*"))
+		assert.Nil(t, run("", "set", "work"))
+		git("remote", "add", "origin", "git@github.com:acme-corp/api.git")
+
+		// The root commit and the last one do not have the profile, the remote has no branch
+		emptyCommit(t, workingDir, "First", "Jane Doe", "jane@home.org")
+		root := git("rev-parse", "HEAD")
+		emptyCommit(t, workingDir, "Second", "Jane Doe", "jane@acme.com")
+		emptyCommit(t, workingDir, "Third", "Jane", "jane@acme.com")
+		head := git("rev-parse", "HEAD")
+		stdout.Reset()
+
+		push := "refs/heads/main " + head + " refs/heads/main 0000000000000000000000000000000000000000\n"
+		err := run(push, "check", "--pre-push", "origin")
+		assert.Error(t, err)
+		assert.Contains(t, stdout.String(), "with the following commands in order, once it is checked out:\n"+
+			"  git profile amend "+head[:7]+" -w work\n"+
+			"  git profile amend "+root[:7]+" -w work\n")
+		stdout.Reset()
+
+		// The commands are run as suggested, the root commit included
+		assert.Nil(t, run("", "amend", head[:7], "-w", "work"))
+		assert.Nil(t, run("", "amend", root[:7], "-w", "work"))
+		stdout.Reset()
+
+		push = "refs/heads/main " + git("rev-parse", "HEAD") + " refs/heads/main 0000000000000000000000000000000000000000\n"
+		assert.Nil(t, run(push, "check", "--pre-push", "origin"))
+		assert.Equal(t, "All 3 pushed commits match the profile \"work\"\n", stdout.String())
+		stdout.Reset()
+	})
+
+	// Test Interactive Mode
+
+	t.Run("should review the identities to import in interactive mode", func(t *testing.T) {
+		workingDir := initializateGitRepository(t)
+		userHomeDir := t.TempDir()
+
+		rootCmd := initializateRootContainer(t, &RootComponentOption{
+			profile:     t.TempDir(),
+			local:       false,
+			workingDir:  workingDir,
+			userHomeDir: userHomeDir,
+		})
+
+		rootCmd.SetOutput(stdout)
+
+		err := os.WriteFile(path.Join(userHomeDir, ".gitconfig"), []byte(`[user]
+	name = Jane Doe
+	email = jane@gmail.com
+[includeIf "gitdir:~/work/"]
+	path = work.gitconfig
+`), 0600)
+		assert.NoError(t, err)
+
+		err = os.WriteFile(path.Join(userHomeDir, "work.gitconfig"), []byte(`[user]
+	name = Jane Doe
+	email = jane@acme.com
+`), 0600)
+		assert.NoError(t, err)
+
+		// Skip the personal identity and import the work one as "job"
+		rootCmd.SetIn(bytes.NewBufferString("n\ny\njob\n"))
+		rootCmd.SetArgs([]string{"import", "--from-gitconfig"})
+		err = rootCmd.Execute()
+
+		assert.Nil(t, err)
+		assert.Contains(t, stdout.String(), "Found Jane Doe <jane@gmail.com> in "+path.Join(userHomeDir, ".gitconfig"))
+		assert.Contains(t, stdout.String(), "Enter workspace [acme]: ")
+		assert.Contains(t, stdout.String(), "Profile \"job\" imported from "+path.Join(userHomeDir, "work.gitconfig"))
+		assert.NotContains(t, stdout.String(), "Profile \"personal\" imported")
+		stdout.Reset()
+	})
+
+	t.Run("should create a new profile in interactive mode", func(t *testing.T) {
+		workingDir := initializateGitRepository(t)
+		userHomeDir := t.TempDir()
+		rootCmd := initializateRootContainer(t, &RootComponentOption{
+			profile:     t.TempDir(),
+			local:       false,
+			workingDir:  workingDir,
+			userHomeDir: userHomeDir,
+		})
+
+		workspace := faker.Internet().User()
+
+		rootCmd.SetOutput(stdout)
+		rootCmd.SetArgs([]string{"add"})
+		rootCmd.SetIn(bytes.NewBufferString(workspace + "\n" + faker.Internet().Email() + "\n" + faker.Person().Name() + "\n"))
+		err := rootCmd.Execute()
+
+		assert.Nil(t, err)
+		assert.Contains(t, stdout.String(), fmt.Sprintf(MsgProfileCreatedSuccessfully, workspace))
+		stdout.Reset()
+	})
+
+	t.Run("should update a profile in interactive mode", func(t *testing.T) {
+		workingDir := initializateGitRepository(t)
+		userHomeDir := t.TempDir()
+		rootCmd := initializateRootContainer(t, &RootComponentOption{
+			profile:     t.TempDir(),
+			local:       false,
+			workingDir:  workingDir,
+			userHomeDir: userHomeDir,
+		})
+
+		workspace := faker.Internet().User()
+
+		rootCmd.SetOutput(stdout)
+		rootCmd.SetArgs([]string{"add"})
+		rootCmd.SetIn(bytes.NewBufferString(workspace + "\n" + faker.Internet().Email() + "\n" + faker.Person().Name() + "\n"))
+		err := rootCmd.Execute()
+
+		assert.Nil(t, err)
+		assert.Contains(t, stdout.String(), fmt.Sprintf(MsgProfileCreatedSuccessfully, workspace))
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{"add"})
+		rootCmd.SetIn(bytes.NewBufferString(workspace + "\ny\n" + faker.Internet().Email() + "\n" + faker.Person().Name() + "\n"))
+		err = rootCmd.Execute()
+
+		assert.Nil(t, err)
+		assert.Contains(t, stdout.String(), fmt.Sprintf(MsgProfileUpdatedSuccessfully, workspace))
+		stdout.Reset()
+	})
+
+	t.Run("should show the error when the profile already exists in interactive mode", func(t *testing.T) {
+		workingDir := initializateGitRepository(t)
+		userHomeDir := t.TempDir()
+		rootCmd := initializateRootContainer(t, &RootComponentOption{
+			profile:     t.TempDir(),
+			local:       false,
+			workingDir:  workingDir,
+			userHomeDir: userHomeDir,
+		})
+
+		workspace := faker.Internet().User()
+
+		rootCmd.SetOutput(stdout)
+		rootCmd.SetArgs([]string{"add"})
+		rootCmd.SetIn(bytes.NewBufferString(workspace + "\n" + faker.Internet().Email() + "\n" + faker.Person().Name() + "\n"))
+		err := rootCmd.Execute()
+
+		assert.Nil(t, err)
+		assert.Contains(t, stdout.String(), fmt.Sprintf(MsgProfileCreatedSuccessfully, workspace))
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{"add"})
+		rootCmd.SetIn(bytes.NewBufferString(workspace + "\nN\n"))
+		err = rootCmd.Execute()
+
+		assert.Nil(t, err)
+		assert.Contains(t, stdout.String(), fmt.Sprintf(ErrProfileAlreadyExists, workspace))
+		stdout.Reset()
+	})
+
+	t.Run("should use a profile in interactive mode", func(t *testing.T) {
+		workingDir := initializateGitRepository(t)
+		userHomeDir := t.TempDir()
+		rootCmd := initializateRootContainer(t, &RootComponentOption{
+			profile:     t.TempDir(),
+			local:       false,
+			workingDir:  workingDir,
+			userHomeDir: userHomeDir,
+		})
+
+		workspace := faker.Internet().User()
+
+		rootCmd.SetOutput(stdout)
+		rootCmd.SetArgs([]string{"add"})
+		rootCmd.SetIn(bytes.NewBufferString(workspace + "\n" + faker.Internet().Email() + "\n" + faker.Person().Name() + "\n"))
+		err := rootCmd.Execute()
+
+		assert.Nil(t, err)
+		assert.Contains(t, stdout.String(), fmt.Sprintf(MsgProfileCreatedSuccessfully, workspace))
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{"set"})
+		rootCmd.SetIn(bytes.NewBufferString(workspace + "\n"))
+		err = rootCmd.Execute()
+
+		assert.Nil(t, err)
+		assert.Contains(t, stdout.String(), fmt.Sprintf(ErrProfileInUse, workspace))
+		stdout.Reset()
+	})
+
+	t.Run("should show the error when the profile does not exist in interactive mode", func(t *testing.T) {
+		workingDir := initializateGitRepository(t)
+		userHomeDir := t.TempDir()
+		rootCmd := initializateRootContainer(t, &RootComponentOption{
+			profile:     t.TempDir(),
+			local:       false,
+			workingDir:  workingDir,
+			userHomeDir: userHomeDir,
+		})
+		rootCmd.SetOutput(stdout)
+		rootCmd.SetArgs([]string{"set"})
+		rootCmd.SetIn(bytes.NewBufferString("test\n"))
+		err := rootCmd.Execute()
+
+		assert.Nil(t, err)
+		assert.Contains(t, stdout.String(), ErrProfilesNotFound)
+		stdout.Reset()
+	})
+
+	t.Run("should delete a profile in interactive mode", func(t *testing.T) {
+		workingDir := initializateGitRepository(t)
+		userHomeDir := t.TempDir()
+		rootCmd := initializateRootContainer(t, &RootComponentOption{
+			profile:     t.TempDir(),
+			local:       false,
+			workingDir:  workingDir,
+			userHomeDir: userHomeDir,
+		})
+
+		workspace := faker.Internet().User()
+
+		rootCmd.SetOutput(stdout)
+		rootCmd.SetArgs([]string{"add"})
+		rootCmd.SetIn(bytes.NewBufferString(workspace + "\n" + faker.Internet().Email() + "\n" + faker.Person().Name() + "\n"))
+		err := rootCmd.Execute()
+
+		assert.Nil(t, err)
+		assert.Contains(t, stdout.String(), fmt.Sprintf(MsgProfileCreatedSuccessfully, workspace))
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{"delete"})
+		rootCmd.SetIn(bytes.NewBufferString(workspace + "\n"))
+		err = rootCmd.Execute()
+
+		assert.Nil(t, err)
+		assert.Contains(t, stdout.String(), fmt.Sprintf(MsgProfileDeleted, workspace))
+		stdout.Reset()
+	})
+
+	t.Run("should show the error when the profile does not exist in interactive mode", func(t *testing.T) {
+		workingDir := initializateGitRepository(t)
+		userHomeDir := t.TempDir()
+		rootCmd := initializateRootContainer(t, &RootComponentOption{
+			profile:     t.TempDir(),
+			local:       false,
+			workingDir:  workingDir,
+			userHomeDir: userHomeDir,
+		})
+
+		workspace := faker.Internet().User()
+
+		rootCmd.SetOutput(stdout)
+		rootCmd.SetArgs([]string{"delete"})
+		rootCmd.SetIn(bytes.NewBufferString(workspace + "\n"))
+		err := rootCmd.Execute()
+
+		assert.Nil(t, err)
+		assert.Contains(t, stdout.String(), fmt.Sprintf(ErrProfileNotExist, workspace))
+		stdout.Reset()
+	})
+
+	t.Run("should show name profile in interactive mode", func(t *testing.T) {
+		workingDir := initializateGitRepository(t)
+		userHomeDir := t.TempDir()
+		rootCmd := initializateRootContainer(t, &RootComponentOption{
+			profile:     t.TempDir(),
+			local:       false,
+			workingDir:  workingDir,
+			userHomeDir: userHomeDir,
+		})
+		workspace := faker.Internet().User()
+
+		rootCmd.SetOutput(stdout)
+		rootCmd.SetArgs([]string{"add"})
+		rootCmd.SetIn(bytes.NewBufferString(workspace + "\n" + faker.Internet().Email() + "\n" + faker.Person().Name() + "\n"))
+		err := rootCmd.Execute()
+
+		assert.Nil(t, err)
+		assert.Contains(t, stdout.String(), fmt.Sprintf(MsgProfileCreatedSuccessfully, workspace))
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{"get"})
+		rootCmd.SetIn(bytes.NewBufferString(workspace + "\n"))
+		err = rootCmd.Execute()
+
+		assert.Nil(t, err)
+		assert.Contains(t, stdout.String(), "Workspace: "+workspace)
+		stdout.Reset()
+	})
+
+	t.Run("should create a new profile with signing key in interactive mode", func(t *testing.T) {
+		workingDir := initializateGitRepository(t)
+		userHomeDir := t.TempDir()
+		rootCmd := initializateRootContainer(t, &RootComponentOption{
+			profile:     t.TempDir(),
+			local:       false,
+			workingDir:  workingDir,
+			userHomeDir: userHomeDir,
+		})
+
+		workspace := faker.Internet().User()
+		signingKey := faker.Hash().MD5()
+
+		rootCmd.SetOutput(stdout)
+		rootCmd.SetArgs([]string{"add"})
+		rootCmd.SetIn(bytes.NewBufferString(workspace + "\n" + faker.Internet().Email() + "\n" + faker.Person().Name() + "\n" + signingKey + "\n\ny\nn\n"))
+		err := rootCmd.Execute()
+
+		assert.Nil(t, err)
+		assert.Contains(t, stdout.String(), fmt.Sprintf(MsgProfileCreatedSuccessfully, workspace))
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{"get", workspace})
+		err = rootCmd.Execute()
+
+		assert.Nil(t, err)
+		assert.Contains(t, stdout.String(), "Signing Key: "+signingKey)
+		assert.Contains(t, stdout.String(), "Signing Format: openpgp")
+		assert.Contains(t, stdout.String(), "Sign Commits: true")
+		assert.Contains(t, stdout.String(), "Sign Tags: false")
+		stdout.Reset()
+	})
+
+	t.Run("should ask for the workspace when the input is not a terminal", func(t *testing.T) {
+		workingDir := initializateGitRepository(t)
+		rootCmd := initializateRootContainer(t, &RootComponentOption{
+			profile:     t.TempDir(),
+			local:       false,
+			workingDir:  workingDir,
+			userHomeDir: t.TempDir(),
+		})
+
+		rootCmd.SetOutput(stdout)
+		rootCmd.SetArgs([]string{"add", "-w", "work", "-n", "Jane Doe", "-e", "jane@acme.com"})
+		assert.Nil(t, rootCmd.Execute())
+		stdout.Reset()
+
+		rootCmd.SetArgs([]string{"get"})
+		rootCmd.SetIn(bytes.NewBufferString("work\n"))
+		assert.Nil(t, rootCmd.Execute())
+		assert.Contains(t, stdout.String(), "Enter workspace: ")
+		assert.Contains(t, stdout.String(), "Email: jane@acme.com\n")
+		assert.NotContains(t, stdout.String(), "Select a profile")
 		stdout.Reset()
 	})
 }
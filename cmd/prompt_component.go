@@ -0,0 +1,80 @@
+package main
+
+import (
+	"os"
+	"path"
+
+	"github.com/b4nd/git-profile/cmd/command"
+	"github.com/b4nd/git-profile/pkg/application"
+	"github.com/b4nd/git-profile/pkg/domain"
+	"github.com/b4nd/git-profile/pkg/infrastructure"
+)
+
+// PromptComponent holds only what the prompt command needs, it is built
+// instead of the RootComponent when the shell prompt runs the command
+type PromptComponent struct {
+	ProfileRepository domain.ProfileRepository
+	ScmUserRepository domain.ScmUserRepository
+
+	PromptProfileService *application.PromptProfileService
+
+	PromptCommand *command.PromptCommand
+}
+
+func NewPromptComponent(option *RootComponentOption) (*PromptComponent, error) {
+	workingDir, userHomeDir, err := resolveDirs(option)
+	if err != nil {
+		return nil, err
+	}
+
+	profiles, err := resolveProfileLocations(workingDir, userHomeDir, option)
+	if err != nil {
+		return nil, err
+	}
+
+	// The cache is kept in the user cache directory, in the home directory when it is set in the options
+	cacheDir, err := os.UserCacheDir()
+	if err != nil || (option != nil && option.userHomeDir != "") {
+		cacheDir = path.Join(userHomeDir, ".cache")
+	}
+
+	// Repositories
+	iniFileProfileRepository, err := infrastructure.NewIniFileProfileRepository(profiles)
+	if err != nil {
+		return nil, err
+	}
+
+	profileRepository, err := infrastructure.NewCachedProfileRepository(
+		iniFileProfileRepository.WithLocalPath(resolveLocalProfile(workingDir)),
+		cacheDir,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	scmUserRepository, err := infrastructure.NewGitLocalUserRepository(workingDir)
+	if err != nil {
+		return nil, err
+	}
+
+	scmIdentityRepository, err := infrastructure.NewGitIdentityRepository(workingDir)
+	if err != nil {
+		return nil, err
+	}
+
+	// Services
+	promptProfileService := application.NewPromptProfileService(profileRepository, scmUserRepository, scmIdentityRepository)
+
+	// Command
+	promptCommand := command.NewPromptCommand(promptProfileService)
+
+	return &PromptComponent{
+		// Repositories
+		ProfileRepository: profileRepository,
+		ScmUserRepository: scmUserRepository,
+		// Services
+		PromptProfileService: promptProfileService,
+		// Command
+		PromptCommand: promptCommand,
+	}, nil
+}
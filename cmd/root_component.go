@@ -1,6 +1,8 @@
 package main
 
 import (
+	"fmt"
+	"io"
 	"os"
 	"path"
 
@@ -12,26 +14,57 @@ import (
 
 const (
 	PROFILE_NAME string = ".gitprofile"
+	// PROFILE_XDG_DIR is the directory of the profile file under $XDG_CONFIG_HOME
+	PROFILE_XDG_DIR string = "git-profile"
+	// PROFILE_POLICY_NAME is the policy file of the verify command at the root of the worktree
+	PROFILE_POLICY_NAME string = ".gitprofile-policy"
 )
 
 type RootComponent struct {
-	ProfileRepository       domain.ProfileRepository
-	ScmUserRepository       domain.ScmUserRepository
-	ScmGlobalUserRepository domain.ScmUserRepository
-	ScmCommitRepository     domain.ScmCommitRepository
-
-	CreateProfileService        *application.CreateProfileService
-	UpdateProfileService        *application.UpdateProfileService
-	GetProfileService           *application.GetProfileService
-	ListProfileService          *application.ListProfileService
-	DeleteProfileService        *application.DeleteProfileService
-	SetProfileService           *application.SetProfileService
-	SetProfileGlobalService     *application.SetProfileService
-	UnsetProfileService         *application.UnsetProfileService
-	UnsetProfileGlobalService   *application.UnsetProfileService
-	CurrentProfileService       *application.CurrentProfileService
-	CurrentProfileGlobalService *application.CurrentProfileService
-	AmendProfileService         *application.AmendProfileService
+	ProfileRepository           domain.ProfileRepository
+	ScmUserRepository           domain.ScmUserRepository
+	ScmGlobalUserRepository     domain.ScmUserRepository
+	ScmCommitRepository         domain.ScmCommitRepository
+	ProfileRuleRepository       domain.ProfileRuleRepository
+	ScmIncludeRepository        domain.ScmIncludeRepository
+	ScmRemoteRepository         domain.ScmRemoteRepository
+	ScmIdentityRepository       domain.ScmIdentityRepository
+	ScmHookRepository           domain.ScmHookRepository
+	ScmConfigRepository         domain.ScmConfigRepository
+	ScmRepositoryUserRepository domain.ScmRepositoryUserRepository
+	ScmCredentialRepository     domain.ScmCredentialRepository
+	ScmVersionRepository        domain.ScmVersionRepository
+	ProfilePolicyRepository     domain.ProfilePolicyRepository
+
+	CreateProfileService         *application.CreateProfileService
+	UpdateProfileService         *application.UpdateProfileService
+	GetProfileService            *application.GetProfileService
+	ListProfileService           *application.ListProfileService
+	DeleteProfileService         *application.DeleteProfileService
+	SetProfileService            *application.SetProfileService
+	SetProfileGlobalService      *application.SetProfileService
+	UnsetProfileService          *application.UnsetProfileService
+	UnsetProfileGlobalService    *application.UnsetProfileService
+	CurrentProfileService        *application.CurrentProfileService
+	CurrentProfileGlobalService  *application.CurrentProfileService
+	AmendProfileService          *application.AmendProfileService
+	RewriteProfileCommitsService *application.RewriteProfileCommitsService
+	AuditProfileCommitsService   *application.AuditProfileCommitsService
+	VerifyProfileCommitsService  *application.VerifyProfileCommitsService
+	CreateProfileRuleService     *application.CreateProfileRuleService
+	ListProfileRuleService       *application.ListProfileRuleService
+	DeleteProfileRuleService     *application.DeleteProfileRuleService
+	ApplyProfileRulesService     *application.ApplyProfileRulesService
+	AutoProfileService           *application.AutoProfileService
+	CheckProfileService          *application.CheckProfileService
+	CheckPushProfileService      *application.CheckPushProfileService
+	InstallHookService           *application.InstallHookService
+	UninstallHookService         *application.UninstallHookService
+	ImportProfilesService        *application.ImportProfilesService
+	RenameProfileService         *application.RenameProfileService
+	GetCredentialService         *application.GetCredentialService
+	StoreCredentialService       *application.StoreCredentialService
+	DoctorProfileService         *application.DoctorProfileService
 
 	VersionCommand        *command.VersionCommand
 	UpsertProfileCommand  *command.CreateProfileCommand
@@ -42,61 +75,126 @@ type RootComponent struct {
 	UnsetProfileCommand   *command.UnsetProfileCommand
 	CurrentProfileCommand *command.CurrentProfileCommand
 	AmendProfileCommand   *command.AmendProfileCommitCommand
+	ProfileRuleCommand    *command.ProfileRuleCommand
+	AutoProfileCommand    *command.AutoProfileCommand
+	CheckProfileCommand   *command.CheckProfileCommand
+	HookCommand           *command.HookCommand
+	ImportProfileCommand  *command.ImportProfileCommand
+	ExportProfileCommand  *command.ExportProfileCommand
+	RenameProfileCommand  *command.RenameProfileCommand
+	CredentialCommand     *command.CredentialCommand
+	CompletionCommand     *command.CompletionCommand
+	DoctorCommand         *command.DoctorCommand
+	AuditProfileCommand   *command.AuditProfileCommitsCommand
+	VerifyProfileCommand  *command.VerifyProfileCommitsCommand
 }
 
 type RootComponentOption struct {
-	// profile flag is used to set the profile file path (default is $HOME/.gitprofile)
+	// profile flag is used to set the profile file path (default is $XDG_CONFIG_HOME/git-profile/.gitprofile)
 	profile string
-	// local flag is used to set the local profile (default is .gitprofile in the current directory)
+	// local flag is used to set the local profile (default is .gitprofile at the root of the repository)
 	local bool
 	// workingDir flag is used to set the current working directory (default is the current directory)
 	workingDir string
 	// userHomeDir is used to set the user home directory (default is the user home directory)
 	userHomeDir string
+	// xdgConfigHome is used to set the XDG config directory (default is $XDG_CONFIG_HOME or ~/.config)
+	xdgConfigHome string
 }
 
 func NewRootComponent(option *RootComponentOption) (*RootComponent, error) {
-	userHomeDir, err := os.UserHomeDir()
+	workingDir, userHomeDir, err := resolveDirs(option)
 	if err != nil {
 		return nil, err
 	}
 
-	if option != nil && option.userHomeDir != "" {
-		userHomeDir = option.userHomeDir
+	// Set default profile file path to $XDG_CONFIG_HOME/git-profile/.gitprofile if not provided by user
+	profiles, err := resolveProfileLocations(workingDir, userHomeDir, option)
+	if err != nil {
+		return nil, err
 	}
 
-	workingDir, err := os.Getwd()
+	gitConfigLocations := infrastructure.NewGitConfigLocations(userHomeDir, resolveXdgConfigHome(userHomeDir, option))
+
+	// Repositories
+	profileRepository, err := infrastructure.NewIniFileProfileRepository(profiles)
 	if err != nil {
 		return nil, err
 	}
 
-	if option != nil && option.workingDir != "" {
-		workingDir = option.workingDir
+	profileRepository = profileRepository.WithLocalPath(resolveLocalProfile(workingDir))
+
+	scmUserRepository, err := infrastructure.NewGitLocalUserRepository(workingDir)
+	if err != nil {
+		return nil, err
 	}
 
-	// Set default profile file path to $HOME/.gitprofile if not provided by user
-	profiles, err := resolveProfileLocations(workingDir, userHomeDir, option)
+	scmGlobalUserRepository, err := infrastructure.NewGitUserRepository(gitConfigLocations.GlobalWrite)
 	if err != nil {
 		return nil, err
 	}
 
-	// Repositories
-	profileRepository, err := infrastructure.NewIniFileProfileRepository(profiles)
+	scmGlobalUserRepository = scmGlobalUserRepository.WithReadPaths(gitConfigLocations.Global)
+
+	scmCommitRepository, err := infrastructure.NewGitCommitRepository(workingDir)
 	if err != nil {
 		return nil, err
 	}
 
-	scmUserRepository, err := infrastructure.NewGitUserRepository(path.Join(workingDir, infrastructure.GIT_LOCAL_CONFIG_FILE))
+	profileRuleRepository, err := infrastructure.NewIniFileProfileRuleRepository(profiles)
 	if err != nil {
 		return nil, err
 	}
 
-	scmGlobalUserRepository, err := infrastructure.NewGitUserRepository(path.Join(userHomeDir, infrastructure.GIT_GLOBAL_CONFIG_FILE))
+	scmIncludeRepository, err := infrastructure.NewGitIncludeRepository(
+		gitConfigLocations.GlobalWrite,
+		path.Join(userHomeDir, infrastructure.GIT_INCLUDE_DIR),
+	)
 	if err != nil {
 		return nil, err
 	}
 
-	scmCommitRepository, err := infrastructure.NewGitCommitRepository(workingDir)
+	scmRemoteRepository, err := infrastructure.NewGitRemoteRepository(workingDir)
+	if err != nil {
+		return nil, err
+	}
+
+	scmIdentityRepository, err := infrastructure.NewGitIdentityRepository(workingDir)
+	if err != nil {
+		return nil, err
+	}
+
+	scmHookRepository, err := infrastructure.NewGitHookRepository(workingDir)
+	if err != nil {
+		return nil, err
+	}
+
+	// The local configuration is the one of the repository of the working directory, if any
+	localConfigFile := path.Join(workingDir, infrastructure.GIT_LOCAL_CONFIG_FILE)
+	if gitDir, err := infrastructure.FindGitDir(workingDir); err == nil {
+		localConfigFile = gitDir.ConfigFile()
+	}
+
+	policyFile := path.Join(resolveWorkTree(workingDir), PROFILE_POLICY_NAME)
+
+	scmConfigRepository, err := infrastructure.NewGitConfigRepository(
+		append(append([]string{}, gitConfigLocations.Global...), localConfigFile),
+		userHomeDir,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	scmRepositoryUserRepository := infrastructure.NewGitRepositoryUserRepository()
+
+	scmCredentialRepository, err := infrastructure.NewGitCredentialRepository(workingDir)
+	if err != nil {
+		return nil, err
+	}
+
+	scmVersionRepository := infrastructure.NewGitVersionRepository()
+
+	profilePolicyRepository, err := infrastructure.NewIniFileProfilePolicyRepository(policyFile)
 	if err != nil {
 		return nil, err
 	}
@@ -111,39 +209,103 @@ func NewRootComponent(option *RootComponentOption) (*RootComponent, error) {
 	setProfileGlobalService := application.NewSetProfileService(profileRepository, scmGlobalUserRepository)
 	usetProfileService := application.NewUnsetProfileService(scmUserRepository)
 	unsetProfileGlobalService := application.NewUnsetProfileService(scmGlobalUserRepository)
-	currentProfileService := application.NewCurrentProfileService(profileRepository, scmUserRepository)
-	currentProfileGlobalService := application.NewCurrentProfileService(profileRepository, scmGlobalUserRepository)
+	currentProfileService := application.NewCurrentProfileService(profileRepository, scmUserRepository, scmIdentityRepository)
+	currentProfileGlobalService := application.NewCurrentProfileService(profileRepository, scmGlobalUserRepository, nil)
 	amendProfileService := application.NewAmendProfileService(profileRepository, scmCommitRepository)
+	rewriteProfileCommitsService := application.NewRewriteProfileCommitsService(profileRepository, scmCommitRepository)
+	auditProfileCommitsService := application.NewAuditProfileCommitsService(profileRepository, scmCommitRepository)
+	verifyProfileCommitsService := application.NewVerifyProfileCommitsService(profileRepository, profilePolicyRepository, scmCommitRepository)
+	createProfileRuleService := application.NewCreateProfileRuleService(profileRepository, profileRuleRepository)
+	listProfileRuleService := application.NewListProfileRuleService(profileRuleRepository)
+	deleteProfileRuleService := application.NewDeleteProfileRuleService(profileRuleRepository)
+	applyProfileRulesService := application.NewApplyProfileRulesService(profileRepository, profileRuleRepository, scmIncludeRepository)
+	autoProfileService := application.NewAutoProfileService(profileRepository, scmRemoteRepository, setProfileService)
+	checkProfileService := application.NewCheckProfileService(profileRepository, scmIdentityRepository)
+	checkPushProfileService := application.NewCheckPushProfileService(profileRepository, scmIdentityRepository, scmRemoteRepository, scmCommitRepository)
+	installHookService := application.NewInstallHookService(scmHookRepository)
+	uninstallHookService := application.NewUninstallHookService(scmHookRepository)
+	importProfilesService := application.NewImportProfilesService(profileRepository, scmConfigRepository, createProfileService)
+	renameProfileService := application.NewRenameProfileService(profileRepository, profileRuleRepository, scmUserRepository, scmGlobalUserRepository, scmRepositoryUserRepository)
+	getCredentialService := application.NewGetCredentialService(profileRepository, scmIdentityRepository, scmCredentialRepository)
+	storeCredentialService := application.NewStoreCredentialService(scmCredentialRepository)
+	doctorProfileService := application.NewDoctorProfileService(
+		profileRepository,
+		profileRepository,
+		scmVersionRepository,
+		scmUserRepository,
+		scmGlobalUserRepository,
+		scmIdentityRepository,
+		scmIncludeRepository,
+	)
 
 	// Command
-	versionCommand := command.NewVersionCommand(version, gitCommit, buildDate, profiles[0])
+	versionCommand := command.NewVersionCommand(version, gitCommit, buildDate, profiles[0], gitConfigLocations.GlobalWrite, gitConfigLocations.System)
 	createProfileCommand := command.NewCreateProfileCommand(createProfileService, updateProfileService, getProfileService)
-	getProfileCommand := command.NewGetProfileCommand(getProfileService)
+	getProfileCommand := command.NewGetProfileCommand(getProfileService, currentProfileService, listProfilesService)
 	listProfileCommand := command.NewListProfileCommand(listProfilesService, currentProfileService)
-	deleteProfileCommand := command.NewDeleteProfileCommand(getProfileService, deleteProfileService)
-	SetProfileCommand := command.NewSetProfileCommand(setProfileService, setProfileGlobalService, getProfileService, listProfilesService)
+	deleteProfileCommand := command.NewDeleteProfileCommand(getProfileService, deleteProfileService, listProfilesService, currentProfileService)
+	SetProfileCommand := command.NewSetProfileCommand(setProfileService, setProfileGlobalService, getProfileService, listProfilesService, currentProfileService, currentProfileGlobalService)
 	unsetProfileCommand := command.NewUnsetProfileCommand(usetProfileService, unsetProfileGlobalService, currentProfileService, currentProfileGlobalService)
 	currentProfileCommand := command.NewCurrentProfileCommand(currentProfileService, currentProfileGlobalService)
-	amendProfileCommitCommand := command.NewAmendProfileCommitCommnad(currentProfileService, amendProfileService)
+	amendProfileCommitCommand := command.NewAmendProfileCommitCommnad(currentProfileService, amendProfileService, rewriteProfileCommitsService, getProfileService, listProfilesService)
+	profileRuleCommand := command.NewProfileRuleCommand(createProfileRuleService, listProfileRuleService, deleteProfileRuleService, applyProfileRulesService)
+	autoProfileCommand := command.NewAutoProfileCommand(autoProfileService)
+	checkProfileCommand := command.NewCheckProfileCommand(checkProfileService, checkPushProfileService)
+	hookCommand := command.NewHookCommand(installHookService, uninstallHookService)
+	importProfileCommand := command.NewImportProfileCommand(importProfilesService)
+	exportProfileCommand := command.NewExportProfileCommand(listProfilesService, getProfileService)
+	renameProfileCommand := command.NewRenameProfileCommand(renameProfileService, listProfilesService)
+	credentialCommand := command.NewCredentialCommand(getCredentialService, storeCredentialService)
+	completionCommand := command.NewCompletionCommand()
+	doctorCommand := command.NewDoctorCommand(doctorProfileService)
+	auditProfileCommand := command.NewAuditProfileCommitsCommand(auditProfileCommitsService, currentProfileService, listProfilesService)
+	verifyProfileCommand := command.NewVerifyProfileCommitsCommand(verifyProfileCommitsService)
 
 	return &RootComponent{
 		// Repositories
-		ProfileRepository:       profileRepository,
-		ScmUserRepository:       scmUserRepository,
-		ScmGlobalUserRepository: scmGlobalUserRepository,
-		ScmCommitRepository:     scmCommitRepository,
+		ProfileRepository:           profileRepository,
+		ScmUserRepository:           scmUserRepository,
+		ScmGlobalUserRepository:     scmGlobalUserRepository,
+		ScmCommitRepository:         scmCommitRepository,
+		ProfileRuleRepository:       profileRuleRepository,
+		ScmIncludeRepository:        scmIncludeRepository,
+		ScmRemoteRepository:         scmRemoteRepository,
+		ScmIdentityRepository:       scmIdentityRepository,
+		ScmHookRepository:           scmHookRepository,
+		ScmConfigRepository:         scmConfigRepository,
+		ScmRepositoryUserRepository: scmRepositoryUserRepository,
+		ScmCredentialRepository:     scmCredentialRepository,
+		ScmVersionRepository:        scmVersionRepository,
+		ProfilePolicyRepository:     profilePolicyRepository,
 		// Services
-		CreateProfileService:        createProfileService,
-		GetProfileService:           getProfileService,
-		ListProfileService:          listProfilesService,
-		DeleteProfileService:        deleteProfileService,
-		SetProfileService:           setProfileService,
-		SetProfileGlobalService:     setProfileGlobalService,
-		UnsetProfileService:         usetProfileService,
-		UnsetProfileGlobalService:   unsetProfileGlobalService,
-		CurrentProfileService:       currentProfileService,
-		CurrentProfileGlobalService: currentProfileGlobalService,
-		AmendProfileService:         amendProfileService,
+		CreateProfileService:         createProfileService,
+		GetProfileService:            getProfileService,
+		ListProfileService:           listProfilesService,
+		DeleteProfileService:         deleteProfileService,
+		SetProfileService:            setProfileService,
+		SetProfileGlobalService:      setProfileGlobalService,
+		UnsetProfileService:          usetProfileService,
+		UnsetProfileGlobalService:    unsetProfileGlobalService,
+		CurrentProfileService:        currentProfileService,
+		CurrentProfileGlobalService:  currentProfileGlobalService,
+		AmendProfileService:          amendProfileService,
+		RewriteProfileCommitsService: rewriteProfileCommitsService,
+		AuditProfileCommitsService:   auditProfileCommitsService,
+		VerifyProfileCommitsService:  verifyProfileCommitsService,
+		CreateProfileRuleService:     createProfileRuleService,
+		ListProfileRuleService:       listProfileRuleService,
+		DeleteProfileRuleService:     deleteProfileRuleService,
+		ApplyProfileRulesService:     applyProfileRulesService,
+		AutoProfileService:           autoProfileService,
+		CheckProfileService:          checkProfileService,
+		CheckPushProfileService:      checkPushProfileService,
+		InstallHookService:           installHookService,
+		UninstallHookService:         uninstallHookService,
+		ImportProfilesService:        importProfilesService,
+		RenameProfileService:         renameProfileService,
+		GetCredentialService:         getCredentialService,
+		StoreCredentialService:       storeCredentialService,
+		DoctorProfileService:         doctorProfileService,
 		// Command
 		VersionCommand:        versionCommand,
 		UpsertProfileCommand:  createProfileCommand,
@@ -154,14 +316,133 @@ func NewRootComponent(option *RootComponentOption) (*RootComponent, error) {
 		UnsetProfileCommand:   unsetProfileCommand,
 		CurrentProfileCommand: currentProfileCommand,
 		AmendProfileCommand:   amendProfileCommitCommand,
+		ProfileRuleCommand:    profileRuleCommand,
+		AutoProfileCommand:    autoProfileCommand,
+		CheckProfileCommand:   checkProfileCommand,
+		HookCommand:           hookCommand,
+		ImportProfileCommand:  importProfileCommand,
+		ExportProfileCommand:  exportProfileCommand,
+		RenameProfileCommand:  renameProfileCommand,
+		CredentialCommand:     credentialCommand,
+		CompletionCommand:     completionCommand,
+		DoctorCommand:         doctorCommand,
+		AuditProfileCommand:   auditProfileCommand,
+		VerifyProfileCommand:  verifyProfileCommand,
 	}, nil
 }
 
+// resolveDirs returns the working and the user home directories, unless they are set in the options
+func resolveDirs(option *RootComponentOption) (string, string, error) {
+	userHomeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", "", err
+	}
+
+	if option != nil && option.userHomeDir != "" {
+		userHomeDir = option.userHomeDir
+	}
+
+	workingDir, err := os.Getwd()
+	if err != nil {
+		return "", "", err
+	}
+
+	if option != nil && option.workingDir != "" {
+		workingDir = option.workingDir
+	}
+
+	return workingDir, userHomeDir, nil
+}
+
+// resolveXdgConfigHome returns the XDG config directory set in the options,
+// or $XDG_CONFIG_HOME, or ~/.config when it is not set
+func resolveXdgConfigHome(userHomeDir string, option *RootComponentOption) string {
+	if option != nil && option.xdgConfigHome != "" {
+		return option.xdgConfigHome
+	}
+
+	dir := os.Getenv(infrastructure.XDG_CONFIG_HOME_ENV)
+	if dir == "" || !path.IsAbs(dir) {
+		return path.Join(userHomeDir, ".config")
+	}
+
+	return dir
+}
+
+// resolveDefaultProfile returns the profile file of the XDG location, or the
+// one of the home directory while it has not been moved
+func resolveDefaultProfile(userHomeDir string, option *RootComponentOption) (string, string) {
+	oldPath := path.Join(userHomeDir, PROFILE_NAME)
+	newPath := path.Join(resolveXdgConfigHome(userHomeDir, option), PROFILE_XDG_DIR, PROFILE_NAME)
+
+	if _, err := os.Stat(newPath); err == nil {
+		return newPath, ""
+	}
+
+	if _, err := os.Lstat(oldPath); err == nil {
+		return oldPath, newPath
+	}
+
+	return newPath, ""
+}
+
+// migrateProfile moves the profile file of the home directory to its XDG
+// location and prints a notice, it does nothing once the file is moved. The
+// old path is kept when it is a symbolic link or when it cannot be moved.
+func migrateProfile(option *RootComponentOption, output io.Writer) {
+	if option != nil && (option.profile != "" || option.local) {
+		return
+	}
+
+	_, userHomeDir, err := resolveDirs(option)
+	if err != nil {
+		return
+	}
+
+	oldPath, newPath := resolveDefaultProfile(userHomeDir, option)
+	if newPath == "" {
+		return
+	}
+
+	info, err := os.Lstat(oldPath)
+	if err != nil || info.Mode()&os.ModeSymlink != 0 {
+		return
+	}
+
+	if err := os.MkdirAll(path.Dir(newPath), 0750); err != nil {
+		return
+	}
+
+	if err := os.Rename(oldPath, newPath); err != nil {
+		return
+	}
+
+	fmt.Fprintf(output, "Moved the profiles from %s to %s\n", oldPath, newPath)
+}
+
+// resolveWorkTree returns the root of the worktree of the repository of the
+// working directory, the working directory itself outside of a repository
+func resolveWorkTree(workingDir string) string {
+	gitDir, err := infrastructure.FindGitDir(workingDir)
+	if err != nil || gitDir.WorkTree == "" {
+		return workingDir
+	}
+
+	return gitDir.WorkTree
+}
+
+// resolveLocalProfile returns the local profile file, at the root of the
+// worktree so that it is the same file from every subdirectory
+func resolveLocalProfile(workingDir string) string {
+	return path.Join(resolveWorkTree(workingDir), PROFILE_NAME)
+}
+
 func resolveProfileLocations(workingDir string, userHomeDir string, option *RootComponentOption) ([]string, error) {
-	defaultProfile := path.Join(userHomeDir, PROFILE_NAME)
-	localProfile := path.Join(workingDir, PROFILE_NAME)
+	defaultProfile, _ := resolveDefaultProfile(userHomeDir, option)
+	localProfile := resolveLocalProfile(workingDir)
 
 	profiles := []string{}
+
 	if option != nil && option.profile != "" {
 		defaultProfile = option.profile
 
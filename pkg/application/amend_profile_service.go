@@ -1,9 +1,13 @@
 package application
 
 import (
+	"errors"
+
 	"github.com/b4nd/git-profile/pkg/domain"
 )
 
+var ErrCommitDateConflict = errors.New("keep date and reset date cannot be used together")
+
 type AmendProfileService struct {
 	profileRepository   domain.ProfileRepository
 	scmCommitRepository domain.ScmCommitRepository
@@ -11,6 +15,12 @@ type AmendProfileService struct {
 
 type AmendProfileServiceParams struct {
 	Workspace string
+	// KeepDate keeps the author and committer dates of the commit
+	KeepDate bool
+	// ResetDate sets the author and committer dates to now
+	ResetDate bool
+	// CommitterOnly keeps the author and sets the profile as committer
+	CommitterOnly bool
 }
 
 func NewAmendProfileService(
@@ -29,29 +39,36 @@ func (cp *AmendProfileService) Execute(params AmendProfileServiceParams) (*domai
 		return nil, err
 	}
 
+	date, err := newScmCommitDate(params.KeepDate, params.ResetDate)
+	if err != nil {
+		return nil, err
+	}
+
 	profile, err := cp.profileRepository.Get(workspace)
 	if err != nil {
 		return nil, ErrProfileNotExists
 	}
 
-	scmHash := domain.NewScmCommitHashHead()
-	scmCommit, err := cp.scmCommitRepository.Get(&scmHash)
+	identity, err := domain.NewScmCommitAuthor(profile.Name().String(), profile.Email().String())
 	if err != nil {
 		return nil, err
 	}
 
-	// If the author of the commit is the same as the profile, return the commit as the profile
-	if scmCommit.Author.Name() == profile.Name().String() && scmCommit.Author.Email() == profile.Email().String() {
-		return scmCommit, nil
-	}
+	amend := domain.NewScmCommitAmend(identity, params.CommitterOnly, date)
 
-	// If the author of the commit is different from the profile, amend the commit
-	newScmAuthor, err := domain.NewScmCommitAuthor(profile.Name().String(), profile.Email().String())
+	scmHash := domain.NewScmCommitHashHead()
+	scmCommit, err := cp.scmCommitRepository.Get(&scmHash)
 	if err != nil {
 		return nil, err
 	}
 
-	err = cp.scmCommitRepository.Save(&newScmAuthor)
+	// If the author and the committer of the commit are already the profile, return the commit
+	if amend.IsAppliedTo(scmCommit) {
+		return scmCommit, nil
+	}
+
+	// Otherwise amend the author and the committer of the commit
+	err = cp.scmCommitRepository.Save(amend)
 	if err != nil {
 		return nil, err
 	}
@@ -64,3 +81,17 @@ func (cp *AmendProfileService) Execute(params AmendProfileServiceParams) (*domai
 
 	return scmCommit, nil
 }
+
+// newScmCommitDate returns which dates are kept by the amend
+func newScmCommitDate(keepDate bool, resetDate bool) (domain.ScmCommitDate, error) {
+	switch {
+	case keepDate && resetDate:
+		return domain.ScmCommitDateDefault, ErrCommitDateConflict
+	case keepDate:
+		return domain.ScmCommitDateKeep, nil
+	case resetDate:
+		return domain.ScmCommitDateReset, nil
+	}
+
+	return domain.ScmCommitDateDefault, nil
+}
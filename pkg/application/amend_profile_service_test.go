@@ -71,7 +71,7 @@ func TestAmendProfileServiceExecute(t *testing.T) {
 
 		mockProfileRepository.On("Get", workspace).Return(profile, nil)
 		mockScmCommitRepository.On("Get", &headHash).Return(commit, nil).Once()
-		mockScmCommitRepository.On("Save", &newAuthor).Return(nil)
+		mockScmCommitRepository.On("Save", domain.NewScmCommitAmend(newAuthor, false, domain.ScmCommitDateDefault)).Return(nil)
 		mockScmCommitRepository.On("Get", &headHash).Return(newCommit, nil).Once()
 
 		amendProfileService := application.NewAmendProfileService(mockProfileRepository, mockScmCommitRepository)
@@ -127,6 +127,86 @@ func TestAmendProfileServiceExecute(t *testing.T) {
 		mockScmCommitRepository.AssertExpectations(t)
 	})
 
+	t.Run("should amend the commit when only the committer is different from the profile", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+		mockScmCommitRepository := &MockCommitRepository{}
+
+		profile, err := domain.NewProfile(
+			workspace.String(),
+			faker.Internet().Email(),
+			faker.Person().Name(),
+		)
+		assert.NoError(t, err)
+
+		hash, err := domain.NewScmCommitHash(faker.Hash().SHA256())
+		assert.NoError(t, err)
+
+		author, err := domain.NewScmCommitAuthor(
+			profile.Name().String(),
+			profile.Email().String(),
+		)
+		assert.NoError(t, err)
+
+		committer, err := domain.NewScmCommitAuthor(
+			faker.Person().Name(),
+			faker.Internet().Email(),
+		)
+		assert.NoError(t, err)
+
+		commit := domain.NewScmCommit(
+			hash,
+			author,
+			time.Now(),
+			faker.Lorem().Sentence(3),
+		)
+		commit.Committer = committer
+
+		newCommit := domain.NewScmCommit(
+			hash,
+			author,
+			commit.Date,
+			commit.Message,
+		)
+
+		headHash := domain.NewScmCommitHashHead()
+
+		mockProfileRepository.On("Get", workspace).Return(profile, nil)
+		mockScmCommitRepository.On("Get", &headHash).Return(commit, nil).Once()
+		mockScmCommitRepository.On("Save", domain.NewScmCommitAmend(author, true, domain.ScmCommitDateKeep)).Return(nil)
+		mockScmCommitRepository.On("Get", &headHash).Return(newCommit, nil).Once()
+
+		amendProfileService := application.NewAmendProfileService(mockProfileRepository, mockScmCommitRepository)
+		ammedCommit, err := amendProfileService.Execute(application.AmendProfileServiceParams{
+			Workspace:     params.Workspace,
+			KeepDate:      true,
+			CommitterOnly: true,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, newCommit, ammedCommit)
+
+		mockProfileRepository.AssertExpectations(t)
+		mockScmCommitRepository.AssertExpectations(t)
+	})
+
+	t.Run("should return error when keep date and reset date are set", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+		mockScmCommitRepository := &MockCommitRepository{}
+
+		amendProfileService := application.NewAmendProfileService(mockProfileRepository, mockScmCommitRepository)
+		ammedCommit, err := amendProfileService.Execute(application.AmendProfileServiceParams{
+			Workspace: params.Workspace,
+			KeepDate:  true,
+			ResetDate: true,
+		})
+
+		assert.ErrorIs(t, err, application.ErrCommitDateConflict)
+		assert.Nil(t, ammedCommit)
+
+		mockProfileRepository.AssertExpectations(t)
+		mockScmCommitRepository.AssertExpectations(t)
+	})
+
 	t.Run("should return error when scm commit does not exist", func(t *testing.T) {
 		mockProfileRepository := &MockProfileRepository{}
 		mockScmCommitRepository := &MockCommitRepository{}
@@ -191,7 +271,7 @@ func TestAmendProfileServiceExecute(t *testing.T) {
 
 		mockProfileRepository.On("Get", workspace).Return(profile, nil)
 		mockScmCommitRepository.On("Get", &headHash).Return(commit, nil).Once()
-		mockScmCommitRepository.On("Save", &newAuthor).Return(assert.AnError)
+		mockScmCommitRepository.On("Save", domain.NewScmCommitAmend(newAuthor, false, domain.ScmCommitDateDefault)).Return(assert.AnError)
 
 		amendProfileService := application.NewAmendProfileService(mockProfileRepository, mockScmCommitRepository)
 		ammedCommit, err := amendProfileService.Execute(params)
@@ -241,7 +321,7 @@ func TestAmendProfileServiceExecute(t *testing.T) {
 
 		mockProfileRepository.On("Get", workspace).Return(profile, nil)
 		mockScmCommitRepository.On("Get", &headHash).Return(commit, nil).Once()
-		mockScmCommitRepository.On("Save", &newAuthor).Return(nil)
+		mockScmCommitRepository.On("Save", domain.NewScmCommitAmend(newAuthor, false, domain.ScmCommitDateDefault)).Return(nil)
 		mockScmCommitRepository.On("Get", &headHash).Return(&domain.ScmCommit{}, assert.AnError).Once()
 
 		amendProfileService := application.NewAmendProfileService(mockProfileRepository, mockScmCommitRepository)
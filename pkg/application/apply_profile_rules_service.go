@@ -0,0 +1,47 @@
+package application
+
+import (
+	"github.com/b4nd/git-profile/pkg/domain"
+)
+
+type ApplyProfileRulesService struct {
+	profileRepository     domain.ProfileRepository
+	profileRuleRepository domain.ProfileRuleRepository
+	scmIncludeRepository  domain.ScmIncludeRepository
+}
+
+func NewApplyProfileRulesService(
+	profileRepository domain.ProfileRepository,
+	profileRuleRepository domain.ProfileRuleRepository,
+	scmIncludeRepository domain.ScmIncludeRepository,
+) *ApplyProfileRulesService {
+	return &ApplyProfileRulesService{
+		profileRepository,
+		profileRuleRepository,
+		scmIncludeRepository,
+	}
+}
+
+func (ar *ApplyProfileRulesService) Execute() ([]*domain.ScmInclude, error) {
+	rules, err := ar.profileRuleRepository.List()
+	if err != nil {
+		return nil, err
+	}
+
+	// Every profile is resolved before writing so that a missing profile leaves the configuration untouched
+	includes := make([]*domain.ScmInclude, 0, len(rules))
+	for _, rule := range rules {
+		profile, err := ar.profileRepository.Get(rule.Workspace())
+		if err != nil {
+			return nil, ErrProfileNotExists
+		}
+
+		includes = append(includes, domain.NewScmInclude(rule.Condition().String(), newScmUserFromProfile(profile)))
+	}
+
+	if err := ar.scmIncludeRepository.Save(includes); err != nil {
+		return nil, err
+	}
+
+	return includes, nil
+}
@@ -0,0 +1,72 @@
+package application_test
+
+import (
+	"testing"
+
+	"github.com/b4nd/git-profile/pkg/application"
+	"github.com/b4nd/git-profile/pkg/domain"
+
+	"github.com/jaswdr/faker"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyProfileRulesServiceExecute(t *testing.T) {
+	faker := faker.New()
+
+	profile, err := domain.NewProfile(
+		faker.Internet().User(),
+		faker.Internet().Email(),
+		faker.Person().Name(),
+	)
+	assert.NoError(t, err)
+
+	rule, err := domain.NewProfileRule(profile.Workspace().String(), "gitdir:~/work/")
+	assert.NoError(t, err)
+
+	t.Run("should save an include for each rule", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+		mockProfileRuleRepository := &MockProfileRuleRepository{}
+		mockIncludeRepository := &MockIncludeRepository{}
+
+		includes := []*domain.ScmInclude{
+			domain.NewScmInclude("gitdir:~/work/", domain.NewScmUser(
+				profile.Workspace().String(),
+				profile.Email().String(),
+				profile.Name().String(),
+			)),
+		}
+
+		mockProfileRuleRepository.On("List").Return([]*domain.ProfileRule{rule}, nil)
+		mockProfileRepository.On("Get", profile.Workspace()).Return(profile, nil)
+		mockIncludeRepository.On("Save", includes).Return(nil)
+
+		applyProfileRulesService := application.NewApplyProfileRulesService(mockProfileRepository, mockProfileRuleRepository, mockIncludeRepository)
+		applied, err := applyProfileRulesService.Execute()
+
+		assert.NoError(t, err)
+		assert.Equal(t, includes, applied)
+
+		mockProfileRepository.AssertExpectations(t)
+		mockProfileRuleRepository.AssertExpectations(t)
+		mockIncludeRepository.AssertExpectations(t)
+	})
+
+	t.Run("should not save anything when a profile does not exist", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+		mockProfileRuleRepository := &MockProfileRuleRepository{}
+		mockIncludeRepository := &MockIncludeRepository{}
+
+		mockProfileRuleRepository.On("List").Return([]*domain.ProfileRule{rule}, nil)
+		mockProfileRepository.On("Get", profile.Workspace()).Return(&domain.Profile{}, assert.AnError)
+
+		applyProfileRulesService := application.NewApplyProfileRulesService(mockProfileRepository, mockProfileRuleRepository, mockIncludeRepository)
+		applied, err := applyProfileRulesService.Execute()
+
+		assert.ErrorIs(t, err, application.ErrProfileNotExists)
+		assert.Nil(t, applied)
+
+		mockProfileRepository.AssertExpectations(t)
+		mockProfileRuleRepository.AssertExpectations(t)
+		mockIncludeRepository.AssertExpectations(t)
+	})
+}
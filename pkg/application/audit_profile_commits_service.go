@@ -0,0 +1,223 @@
+package application
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/b4nd/git-profile/pkg/domain"
+)
+
+// AuditIdentityStatus tells how an identity of the history relates to the profiles
+type AuditIdentityStatus string
+
+const (
+	// AuditIdentityUnknown is an identity that matches no profile
+	AuditIdentityUnknown AuditIdentityStatus = "unknown"
+	// AuditIdentityOtherWorkspace is the identity of a profile other than the audited one
+	AuditIdentityOtherWorkspace AuditIdentityStatus = "other-workspace"
+	// AuditIdentityProfile is the identity of the audited profile, or of any
+	// profile when no profile is audited
+	AuditIdentityProfile AuditIdentityStatus = "profile"
+)
+
+// auditIdentityOrder lists the most worrying identities first
+var auditIdentityOrder = map[AuditIdentityStatus]int{
+	AuditIdentityUnknown:        0,
+	AuditIdentityOtherWorkspace: 1,
+	AuditIdentityProfile:        2,
+}
+
+// auditExamples is the number of commits kept as examples of an identity
+const auditExamples = 3
+
+// AuditIdentity is a name and email found in the history, with the profile
+// it belongs to and the commits it authored or committed
+type AuditIdentity struct {
+	Name      string
+	Email     string
+	Status    AuditIdentityStatus
+	Workspace string
+	Authored  int
+	Committed int
+	First     time.Time
+	Last      time.Time
+	Examples  []domain.ScmCommitHash
+}
+
+type AuditProfileCommitsResult struct {
+	Workspace  string
+	Commits    int
+	Identities []*AuditIdentity
+}
+
+type AuditProfileCommitsService struct {
+	profileRepository   domain.ProfileRepository
+	scmCommitRepository domain.ScmCommitRepository
+}
+
+type AuditProfileCommitsServiceParams struct {
+	// Workspace is the profile expected in the history, every profile is expected when it is empty
+	Workspace string
+	// Revision is the history up to a commit or a range in the form base..tip or base...tip, HEAD when it is empty
+	Revision string
+}
+
+func NewAuditProfileCommitsService(
+	profileRepository domain.ProfileRepository,
+	scmCommitRepository domain.ScmCommitRepository,
+) *AuditProfileCommitsService {
+	return &AuditProfileCommitsService{
+		profileRepository,
+		scmCommitRepository,
+	}
+}
+
+// Execute groups the commits of the revision by author and committer and maps
+// every identity to the profile with its email
+func (ap *AuditProfileCommitsService) Execute(params AuditProfileCommitsServiceParams) (*AuditProfileCommitsResult, error) {
+	if params.Workspace != "" {
+		workspace, err := domain.NewProfileWorkspace(params.Workspace)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := ap.profileRepository.Get(workspace); err != nil {
+			return nil, ErrProfileNotExists
+		}
+	}
+
+	profiles, err := ap.profileRepository.List()
+	if err != nil {
+		return nil, err
+	}
+
+	emails := map[string]*domain.Profile{}
+	for _, profile := range profiles {
+		email := strings.ToLower(profile.Email().String())
+		if _, ok := emails[email]; !ok {
+			emails[email] = profile
+		}
+	}
+
+	tip, exclude, err := resolveCommitRange(params.Revision)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &AuditProfileCommitsResult{Workspace: params.Workspace}
+	identities := map[string]*AuditIdentity{}
+
+	identity := func(name string, email string) *AuditIdentity {
+		key := strings.ToLower(email) + "\x00" + name
+		if found, ok := identities[key]; ok {
+			return found
+		}
+
+		found := &AuditIdentity{Name: name, Email: email, Status: AuditIdentityUnknown}
+		if profile, ok := emails[strings.ToLower(email)]; ok {
+			found.Workspace = profile.Workspace().String()
+			found.Status = AuditIdentityProfile
+			if params.Workspace != "" && found.Workspace != params.Workspace {
+				found.Status = AuditIdentityOtherWorkspace
+			}
+		}
+
+		identities[key] = found
+		result.Identities = append(result.Identities, found)
+		return found
+	}
+
+	err = ap.scmCommitRepository.Walk(tip, exclude, func(record *domain.ScmCommitRecord) error {
+		result.Commits++
+
+		author := identity(record.AuthorName, record.AuthorEmail)
+		author.Authored++
+		author.visit(record.Hash, record.Date)
+
+		committer := identity(record.CommitterName, record.CommitterEmail)
+		committer.Committed++
+		committer.visit(record.Hash, record.CommitterDate)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(result.Identities, func(i, j int) bool {
+		a, b := result.Identities[i], result.Identities[j]
+		if auditIdentityOrder[a.Status] != auditIdentityOrder[b.Status] {
+			return auditIdentityOrder[a.Status] < auditIdentityOrder[b.Status]
+		}
+
+		if a.Authored+a.Committed != b.Authored+b.Committed {
+			return a.Authored+a.Committed > b.Authored+b.Committed
+		}
+
+		return strings.ToLower(a.Email) < strings.ToLower(b.Email)
+	})
+
+	return result, nil
+}
+
+// resolveCommitRange returns the tip and the excluded commits of the history
+// up to a commit or of a range in the form base..tip, HEAD when it is empty.
+// A side of the range left empty is HEAD as in git, and the symmetric
+// difference base...tip is given to git as the tip.
+func resolveCommitRange(revision string) (*domain.ScmCommitHash, []domain.ScmCommitHash, error) {
+	side := func(value string) string {
+		if strings.TrimSpace(value) == "" {
+			return domain.NewScmCommitHashHead().String()
+		}
+
+		return value
+	}
+
+	if base, tip, isSymmetric := strings.Cut(revision, "..."); isSymmetric {
+		hash, err := domain.NewScmCommitHash(side(base) + "..." + side(tip))
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return &hash, nil, nil
+	}
+
+	base, tip, isRange := strings.Cut(revision, "..")
+	if !isRange {
+		tip = revision
+	}
+
+	tipHash, err := domain.NewScmCommitHash(side(tip))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !isRange {
+		return &tipHash, nil, nil
+	}
+
+	baseHash, err := domain.NewScmCommitHash(side(base))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &tipHash, []domain.ScmCommitHash{baseHash}, nil
+}
+
+// visit records a commit of the identity, the commits are visited newest first
+// so the examples are the most recent ones
+func (i *AuditIdentity) visit(hash domain.ScmCommitHash, date time.Time) {
+	if i.First.IsZero() || date.Before(i.First) {
+		i.First = date
+	}
+
+	if date.After(i.Last) {
+		i.Last = date
+	}
+
+	count := len(i.Examples)
+	if count < auditExamples && (count == 0 || i.Examples[count-1] != hash) {
+		i.Examples = append(i.Examples, hash)
+	}
+}
@@ -0,0 +1,153 @@
+package application_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/b4nd/git-profile/pkg/application"
+	"github.com/b4nd/git-profile/pkg/domain"
+
+	"github.com/jaswdr/faker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAuditProfileCommitsServiceExecute(t *testing.T) {
+	faker := faker.New()
+
+	work, err := domain.NewProfile("work", "jane@acme.com", "Jane Doe")
+	assert.NoError(t, err)
+
+	personal, err := domain.NewProfile("personal", "jane@home.org", "Jane")
+	assert.NoError(t, err)
+
+	newHash := func() domain.ScmCommitHash {
+		hash, err := domain.NewScmCommitHash(faker.Hash().SHA256())
+		assert.NoError(t, err)
+
+		return hash
+	}
+
+	date := time.Date(2025, 2, 1, 18, 56, 36, 0, time.UTC)
+	newRecord := func(author string, email string, committer string, committerEmail string, days int) *domain.ScmCommitRecord {
+		return &domain.ScmCommitRecord{
+			Hash:           newHash(),
+			AuthorName:     author,
+			AuthorEmail:    email,
+			Date:           date.AddDate(0, 0, days),
+			CommitterName:  committer,
+			CommitterEmail: committerEmail,
+			CommitterDate:  date.AddDate(0, 0, days+1),
+		}
+	}
+
+	// Newest first, as the history is walked
+	records := []*domain.ScmCommitRecord{
+		newRecord("Jane Doe", "Jane@Acme.com", "Jane Doe", "jane@acme.com", 3),
+		newRecord("Jane", "jane@home.org", "Jane Doe", "jane@acme.com", 2),
+		newRecord("build", "build@ci.internal", "build", "build@ci.internal", 1),
+		newRecord("Jane Doe", "jane@acme.com", "Jane Doe", "jane@acme.com", 0),
+	}
+
+	head := domain.NewScmCommitHashHead()
+
+	t.Run("should group the identities and map them to the profiles", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+		mockCommitRepository := &MockCommitRepository{}
+
+		mockProfileRepository.On("Get", work.Workspace()).Return(work, nil)
+		mockProfileRepository.On("List").Return([]*domain.Profile{work, personal}, nil)
+		mockCommitRepository.On("Walk", &head, []domain.ScmCommitHash(nil), mock.Anything).Return(records, nil)
+
+		service := application.NewAuditProfileCommitsService(mockProfileRepository, mockCommitRepository)
+		result, err := service.Execute(application.AuditProfileCommitsServiceParams{Workspace: "work"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "work", result.Workspace)
+		assert.Equal(t, 4, result.Commits)
+		assert.Len(t, result.Identities, 3)
+
+		unknown := result.Identities[0]
+		assert.Equal(t, application.AuditIdentityUnknown, unknown.Status)
+		assert.Equal(t, "build@ci.internal", unknown.Email)
+		assert.Equal(t, "", unknown.Workspace)
+		assert.Equal(t, 1, unknown.Authored)
+		assert.Equal(t, 1, unknown.Committed)
+		assert.Equal(t, []domain.ScmCommitHash{records[2].Hash}, unknown.Examples)
+
+		other := result.Identities[1]
+		assert.Equal(t, application.AuditIdentityOtherWorkspace, other.Status)
+		assert.Equal(t, "personal", other.Workspace)
+		assert.Equal(t, 1, other.Authored)
+		assert.Equal(t, 0, other.Committed)
+
+		// The emails match regardless of the case, the first one seen is kept
+		profile := result.Identities[2]
+		assert.Equal(t, application.AuditIdentityProfile, profile.Status)
+		assert.Equal(t, "work", profile.Workspace)
+		assert.Equal(t, "Jane Doe", profile.Name)
+		assert.Equal(t, "Jane@Acme.com", profile.Email)
+		assert.Equal(t, 2, profile.Authored)
+		assert.Equal(t, 3, profile.Committed)
+		assert.Equal(t, date, profile.First)
+		assert.Equal(t, date.AddDate(0, 0, 4), profile.Last)
+		assert.Equal(t, []domain.ScmCommitHash{records[0].Hash, records[1].Hash, records[3].Hash}, profile.Examples)
+
+		mockProfileRepository.AssertExpectations(t)
+		mockCommitRepository.AssertExpectations(t)
+	})
+
+	t.Run("should accept every profile when no workspace is audited", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+		mockCommitRepository := &MockCommitRepository{}
+
+		base, err := domain.NewScmCommitHash("main")
+		assert.NoError(t, err)
+
+		mockProfileRepository.On("List").Return([]*domain.Profile{work, personal}, nil)
+		mockCommitRepository.On("Walk", &head, []domain.ScmCommitHash{base}, mock.Anything).Return(records[:2], nil)
+
+		service := application.NewAuditProfileCommitsService(mockProfileRepository, mockCommitRepository)
+		result, err := service.Execute(application.AuditProfileCommitsServiceParams{Revision: "main.."})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, result.Commits)
+		for _, identity := range result.Identities {
+			assert.Equal(t, application.AuditIdentityProfile, identity.Status)
+		}
+
+		mockProfileRepository.AssertNotCalled(t, "Get", mock.Anything)
+		mockCommitRepository.AssertExpectations(t)
+	})
+
+	t.Run("should return an error when the profile does not exist", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+		mockCommitRepository := &MockCommitRepository{}
+
+		mockProfileRepository.On("Get", work.Workspace()).Return(&domain.Profile{}, domain.ErrInvalidWorkspace)
+
+		service := application.NewAuditProfileCommitsService(mockProfileRepository, mockCommitRepository)
+		result, err := service.Execute(application.AuditProfileCommitsServiceParams{Workspace: "work"})
+
+		assert.ErrorIs(t, err, application.ErrProfileNotExists)
+		assert.Nil(t, result)
+		mockCommitRepository.AssertNotCalled(t, "Walk", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return an error when the revision does not exist", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+		mockCommitRepository := &MockCommitRepository{}
+
+		tip, err := domain.NewScmCommitHash("unknown")
+		assert.NoError(t, err)
+
+		mockProfileRepository.On("List").Return([]*domain.Profile{}, nil)
+		mockCommitRepository.On("Walk", &tip, []domain.ScmCommitHash(nil), mock.Anything).Return(nil, domain.ErrScmCommitNotFound)
+
+		service := application.NewAuditProfileCommitsService(mockProfileRepository, mockCommitRepository)
+		result, err := service.Execute(application.AuditProfileCommitsServiceParams{Revision: "unknown"})
+
+		assert.ErrorIs(t, err, domain.ErrScmCommitNotFound)
+		assert.Nil(t, result)
+	})
+}
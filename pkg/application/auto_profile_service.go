@@ -0,0 +1,73 @@
+package application
+
+import (
+	"errors"
+
+	"github.com/b4nd/git-profile/pkg/domain"
+)
+
+var ErrProfileNoMatch = errors.New("no profile matches the remotes")
+var ErrProfileAmbiguous = errors.New("more than one profile matches the remotes")
+
+type AutoProfileService struct {
+	profileRepository   domain.ProfileRepository
+	scmRemoteRepository domain.ScmRemoteRepository
+	setProfileService   *SetProfileService
+}
+
+func NewAutoProfileService(
+	profileRepository domain.ProfileRepository,
+	scmRemoteRepository domain.ScmRemoteRepository,
+	setProfileService *SetProfileService,
+) *AutoProfileService {
+	return &AutoProfileService{
+		profileRepository,
+		scmRemoteRepository,
+		setProfileService,
+	}
+}
+
+// Match returns the profiles with a remote url pattern matching any remote of the repository
+func (ap *AutoProfileService) Match() ([]*domain.Profile, error) {
+	remotes, err := ap.scmRemoteRepository.List()
+	if err != nil {
+		return nil, err
+	}
+
+	profiles, err := ap.profileRepository.List()
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make([]*domain.Profile, 0)
+	for _, profile := range profiles {
+		for _, remote := range remotes {
+			if profile.MatchRemote(remote.Url) {
+				matches = append(matches, profile)
+				break
+			}
+		}
+	}
+
+	return matches, nil
+}
+
+// Execute sets the only profile matching the remotes of the repository
+func (ap *AutoProfileService) Execute() (*domain.Profile, error) {
+	matches, err := ap.Match()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(matches) == 0 {
+		return nil, ErrProfileNoMatch
+	}
+
+	if len(matches) > 1 {
+		return nil, ErrProfileAmbiguous
+	}
+
+	return ap.setProfileService.Execute(SetProfileServiceParams{
+		Workspace: matches[0].Workspace().String(),
+	})
+}
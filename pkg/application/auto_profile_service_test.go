@@ -0,0 +1,129 @@
+package application_test
+
+import (
+	"testing"
+
+	"github.com/b4nd/git-profile/pkg/application"
+	"github.com/b4nd/git-profile/pkg/domain"
+
+	"github.com/jaswdr/faker"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAutoProfileServiceExecute(t *testing.T) {
+	faker := faker.New()
+
+	newProfile := func(pattern string) *domain.Profile {
+		profile, err := domain.NewProfile(
+			faker.Internet().User(),
+			faker.Internet().Email(),
+			faker.Person().Name(),
+		)
+		assert.NoError(t, err)
+
+		remote, err := domain.NewProfileRemotePattern(pattern)
+		assert.NoError(t, err)
+
+		return profile.WithRemotes([]domain.ProfileRemotePattern{remote})
+	}
+
+	work := newProfile("github.com:acme-corp/*")
+	internal := newProfile("gitlab.internal/*")
+
+	remotes := []*domain.ScmRemote{
+		domain.NewScmRemote("origin", "https://github.com/acme-corp/api.git"),
+	}
+
+	t.Run("should set the profile matching the remote", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+		mockRemoteRepository := &MockRemoteRepository{}
+		mockGitUserRepository := &MockUserRepository{}
+
+		mockRemoteRepository.On("List").Return(remotes, nil)
+		mockProfileRepository.On("List").Return([]*domain.Profile{work, internal}, nil)
+		mockProfileRepository.On("Get", work.Workspace()).Return(work, nil)
+		mockGitUserRepository.On("Save", domain.NewScmUser(
+			work.Workspace().String(),
+			work.Email().String(),
+			work.Name().String(),
+		)).Return(nil)
+
+		setProfileService := application.NewSetProfileService(mockProfileRepository, mockGitUserRepository)
+		autoProfileService := application.NewAutoProfileService(mockProfileRepository, mockRemoteRepository, setProfileService)
+		profile, err := autoProfileService.Execute()
+
+		assert.NoError(t, err)
+		assert.Equal(t, work, profile)
+
+		mockProfileRepository.AssertExpectations(t)
+		mockRemoteRepository.AssertExpectations(t)
+		mockGitUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("should return an error when no profile matches the remotes", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+		mockRemoteRepository := &MockRemoteRepository{}
+		mockGitUserRepository := &MockUserRepository{}
+
+		mockRemoteRepository.On("List").Return([]*domain.ScmRemote{
+			domain.NewScmRemote("origin", "git@bitbucket.org:someone/api.git"),
+		}, nil)
+		mockProfileRepository.On("List").Return([]*domain.Profile{work, internal}, nil)
+
+		setProfileService := application.NewSetProfileService(mockProfileRepository, mockGitUserRepository)
+		autoProfileService := application.NewAutoProfileService(mockProfileRepository, mockRemoteRepository, setProfileService)
+		profile, err := autoProfileService.Execute()
+
+		assert.ErrorIs(t, err, application.ErrProfileNoMatch)
+		assert.Nil(t, profile)
+
+		mockProfileRepository.AssertExpectations(t)
+		mockRemoteRepository.AssertExpectations(t)
+		mockGitUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("should return an error when more than one profile matches the remotes", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+		mockRemoteRepository := &MockRemoteRepository{}
+		mockGitUserRepository := &MockUserRepository{}
+
+		mockRemoteRepository.On("List").Return([]*domain.ScmRemote{
+			remotes[0],
+			domain.NewScmRemote("mirror", "ssh://git@gitlab.internal:2222/acme/api.git"),
+		}, nil)
+		mockProfileRepository.On("List").Return([]*domain.Profile{work, internal}, nil)
+
+		setProfileService := application.NewSetProfileService(mockProfileRepository, mockGitUserRepository)
+		autoProfileService := application.NewAutoProfileService(mockProfileRepository, mockRemoteRepository, setProfileService)
+		profile, err := autoProfileService.Execute()
+
+		assert.ErrorIs(t, err, application.ErrProfileAmbiguous)
+		assert.Nil(t, profile)
+
+		matches, err := autoProfileService.Match()
+		assert.NoError(t, err)
+		assert.Equal(t, []*domain.Profile{work, internal}, matches)
+
+		mockProfileRepository.AssertExpectations(t)
+		mockRemoteRepository.AssertExpectations(t)
+		mockGitUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("should return an error when the remotes cannot be read", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+		mockRemoteRepository := &MockRemoteRepository{}
+		mockGitUserRepository := &MockUserRepository{}
+
+		mockRemoteRepository.On("List").Return([]*domain.ScmRemote{}, assert.AnError)
+
+		setProfileService := application.NewSetProfileService(mockProfileRepository, mockGitUserRepository)
+		autoProfileService := application.NewAutoProfileService(mockProfileRepository, mockRemoteRepository, setProfileService)
+		profile, err := autoProfileService.Execute()
+
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Nil(t, profile)
+
+		mockProfileRepository.AssertExpectations(t)
+		mockRemoteRepository.AssertExpectations(t)
+	})
+}
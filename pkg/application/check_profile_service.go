@@ -0,0 +1,54 @@
+package application
+
+import (
+	"errors"
+
+	"github.com/b4nd/git-profile/pkg/domain"
+)
+
+var ErrProfileMismatch = errors.New("profile does not match the git identity")
+
+type CheckProfileService struct {
+	profileRepository     domain.ProfileRepository
+	scmIdentityRepository domain.ScmIdentityRepository
+}
+
+// CheckProfileServiceResult holds the configured profile and the identity git
+// uses, it is also returned along with ErrProfileNotExists and ErrProfileMismatch
+type CheckProfileServiceResult struct {
+	Profile  *domain.Profile
+	Identity *domain.ScmIdentity
+}
+
+func NewCheckProfileService(
+	profileRepository domain.ProfileRepository,
+	scmIdentityRepository domain.ScmIdentityRepository,
+) *CheckProfileService {
+	return &CheckProfileService{profileRepository, scmIdentityRepository}
+}
+
+func (cp *CheckProfileService) Execute() (*CheckProfileServiceResult, error) {
+	identity, err := cp.scmIdentityRepository.Get()
+	if err != nil || identity.Workspace == "" {
+		return nil, ErrProfileNotConfigured
+	}
+
+	result := &CheckProfileServiceResult{Identity: identity}
+
+	workspace, err := domain.NewProfileWorkspace(identity.Workspace)
+	if err != nil {
+		return result, ErrProfileNotExists
+	}
+
+	profile, err := cp.profileRepository.Get(workspace)
+	if err != nil {
+		return result, ErrProfileNotExists
+	}
+
+	result.Profile = profile
+	if !domain.IsSameScmIdentity(identity.Name, identity.Email, profile.Name().String(), profile.Email().String()) {
+		return result, ErrProfileMismatch
+	}
+
+	return result, nil
+}
@@ -0,0 +1,130 @@
+package application_test
+
+import (
+	"testing"
+
+	"github.com/b4nd/git-profile/pkg/application"
+	"github.com/b4nd/git-profile/pkg/domain"
+
+	"github.com/jaswdr/faker"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckProfileServiceExecute(t *testing.T) {
+	faker := faker.New()
+
+	profile, err := domain.NewProfile(
+		faker.Internet().User(),
+		faker.Internet().Email(),
+		faker.Person().Name(),
+	)
+	assert.NoError(t, err)
+
+	identity := domain.NewScmIdentity(
+		profile.Workspace().String(),
+		profile.Email().String(),
+		profile.Name().String(),
+	)
+
+	t.Run("should succeed when the identity matches the profile", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+		mockIdentityRepository := &MockIdentityRepository{}
+
+		mockIdentityRepository.On("Get").Return(identity, nil)
+		mockProfileRepository.On("Get", profile.Workspace()).Return(profile, nil)
+
+		checkProfileService := application.NewCheckProfileService(mockProfileRepository, mockIdentityRepository)
+		result, err := checkProfileService.Execute()
+
+		assert.NoError(t, err)
+		assert.Equal(t, profile, result.Profile)
+		assert.Equal(t, identity, result.Identity)
+
+		mockProfileRepository.AssertExpectations(t)
+		mockIdentityRepository.AssertExpectations(t)
+	})
+
+	t.Run("should succeed when the name only differs by the characters git strips", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+		mockIdentityRepository := &MockIdentityRepository{}
+
+		junior, err := domain.NewProfile(profile.Workspace().String(), "Jane@Acme.com", "Jane Doe Jr.")
+		assert.NoError(t, err)
+
+		mockIdentityRepository.On("Get").Return(domain.NewScmIdentity(profile.Workspace().String(), "jane@acme.com", "Jane Doe Jr"), nil)
+		mockProfileRepository.On("Get", profile.Workspace()).Return(junior, nil)
+
+		checkProfileService := application.NewCheckProfileService(mockProfileRepository, mockIdentityRepository)
+		result, err := checkProfileService.Execute()
+
+		assert.NoError(t, err)
+		assert.Equal(t, junior, result.Profile)
+	})
+
+	t.Run("should return an error when no profile is configured", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+		mockIdentityRepository := &MockIdentityRepository{}
+
+		mockIdentityRepository.On("Get").Return(domain.NewScmIdentity("", identity.Email, identity.Name), nil)
+
+		checkProfileService := application.NewCheckProfileService(mockProfileRepository, mockIdentityRepository)
+		result, err := checkProfileService.Execute()
+
+		assert.ErrorIs(t, err, application.ErrProfileNotConfigured)
+		assert.Nil(t, result)
+
+		mockProfileRepository.AssertExpectations(t)
+		mockIdentityRepository.AssertExpectations(t)
+	})
+
+	t.Run("should return an error when git has no identity", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+		mockIdentityRepository := &MockIdentityRepository{}
+
+		mockIdentityRepository.On("Get").Return(&domain.ScmIdentity{}, domain.ErrScmIdentityNotFound)
+
+		checkProfileService := application.NewCheckProfileService(mockProfileRepository, mockIdentityRepository)
+		result, err := checkProfileService.Execute()
+
+		assert.ErrorIs(t, err, application.ErrProfileNotConfigured)
+		assert.Nil(t, result)
+	})
+
+	t.Run("should return an error when the configured profile does not exist", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+		mockIdentityRepository := &MockIdentityRepository{}
+
+		mockIdentityRepository.On("Get").Return(identity, nil)
+		mockProfileRepository.On("Get", profile.Workspace()).Return(&domain.Profile{}, assert.AnError)
+
+		checkProfileService := application.NewCheckProfileService(mockProfileRepository, mockIdentityRepository)
+		result, err := checkProfileService.Execute()
+
+		assert.ErrorIs(t, err, application.ErrProfileNotExists)
+		assert.Equal(t, identity, result.Identity)
+		assert.Nil(t, result.Profile)
+
+		mockProfileRepository.AssertExpectations(t)
+		mockIdentityRepository.AssertExpectations(t)
+	})
+
+	t.Run("should return an error when the identity differs from the profile", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+		mockIdentityRepository := &MockIdentityRepository{}
+
+		other := domain.NewScmIdentity(identity.Workspace, faker.Internet().Email(), identity.Name)
+
+		mockIdentityRepository.On("Get").Return(other, nil)
+		mockProfileRepository.On("Get", profile.Workspace()).Return(profile, nil)
+
+		checkProfileService := application.NewCheckProfileService(mockProfileRepository, mockIdentityRepository)
+		result, err := checkProfileService.Execute()
+
+		assert.ErrorIs(t, err, application.ErrProfileMismatch)
+		assert.Equal(t, profile, result.Profile)
+		assert.Equal(t, other, result.Identity)
+
+		mockProfileRepository.AssertExpectations(t)
+		mockIdentityRepository.AssertExpectations(t)
+	})
+}
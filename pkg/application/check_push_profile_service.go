@@ -0,0 +1,233 @@
+package application
+
+import (
+	"slices"
+
+	"github.com/b4nd/git-profile/pkg/domain"
+)
+
+type CheckPushProfileService struct {
+	profileRepository     domain.ProfileRepository
+	scmIdentityRepository domain.ScmIdentityRepository
+	scmRemoteRepository   domain.ScmRemoteRepository
+	scmCommitRepository   domain.ScmCommitRepository
+}
+
+type CheckPushProfileServiceParams struct {
+	// Remote is the name of the remote pushed to, or its url when the push names no remote
+	Remote string
+	// Url is the url pushed to, the one of the remote when it is empty
+	Url string
+	// Updates are the refs sent by the push
+	Updates []*domain.ScmPushUpdate
+}
+
+// CheckPushRef holds the commits a ref sends to the remote that do not have
+// the identity of the expected profile
+type CheckPushRef struct {
+	Update *domain.ScmPushUpdate
+	// Base is the commit of the remote the commits are pushed on top of, nil
+	// when the ref is new or the remote hash is not known locally
+	Base *domain.ScmCommitHash
+	// Commits is the number of commits the remote does not have yet
+	Commits int
+	// Violations are listed the children before their parents
+	Violations []*VerifyViolation
+}
+
+// CheckPushProfileServiceResult holds the profile expected by the remote and the
+// refs pushed, it is also returned along with ErrProfileMismatch
+type CheckPushProfileServiceResult struct {
+	Profile *domain.Profile
+	Url     string
+	Refs    []*CheckPushRef
+}
+
+func NewCheckPushProfileService(
+	profileRepository domain.ProfileRepository,
+	scmIdentityRepository domain.ScmIdentityRepository,
+	scmRemoteRepository domain.ScmRemoteRepository,
+	scmCommitRepository domain.ScmCommitRepository,
+) *CheckPushProfileService {
+	return &CheckPushProfileService{
+		profileRepository,
+		scmIdentityRepository,
+		scmRemoteRepository,
+		scmCommitRepository,
+	}
+}
+
+// Execute checks that the author and the committer of every commit the push
+// sends have the name and email of the profile expected for the remote url
+func (cp *CheckPushProfileService) Execute(params CheckPushProfileServiceParams) (*CheckPushProfileServiceResult, error) {
+	url, err := cp.resolveUrl(params.Remote, params.Url)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := cp.resolveProfile(url)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &CheckPushProfileServiceResult{Profile: profile, Url: url, Refs: make([]*CheckPushRef, 0)}
+	mismatch := false
+
+	for _, update := range params.Updates {
+		if update.IsDelete() {
+			continue
+		}
+
+		ref, err := cp.checkUpdate(update, profile)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Refs = append(result.Refs, ref)
+		if len(ref.Violations) > 0 {
+			mismatch = true
+		}
+	}
+
+	if mismatch {
+		return result, ErrProfileMismatch
+	}
+
+	return result, nil
+}
+
+// resolveUrl returns the url pushed to, git gives the name of the remote as
+// url when the push names no remote
+func (cp *CheckPushProfileService) resolveUrl(remote string, url string) (string, error) {
+	if url != "" {
+		return url, nil
+	}
+
+	remotes, err := cp.scmRemoteRepository.List()
+	if err != nil {
+		return "", err
+	}
+
+	for _, candidate := range remotes {
+		if candidate.Name == remote {
+			return candidate.Url, nil
+		}
+	}
+
+	return remote, nil
+}
+
+// resolveProfile returns the only profile with a remote url pattern matching
+// the url, the configured one among several, and the configured profile when
+// none matches
+func (cp *CheckPushProfileService) resolveProfile(url string) (*domain.Profile, error) {
+	profiles, err := cp.profileRepository.List()
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make([]*domain.Profile, 0)
+	for _, profile := range profiles {
+		if profile.MatchRemote(url) {
+			matches = append(matches, profile)
+		}
+	}
+
+	if len(matches) == 1 {
+		return matches[0], nil
+	}
+
+	configured := ""
+	if identity, err := cp.scmIdentityRepository.Get(); err == nil {
+		configured = identity.Workspace
+	}
+
+	if len(matches) > 1 {
+		index := slices.IndexFunc(matches, func(profile *domain.Profile) bool {
+			return profile.Workspace().String() == configured
+		})
+		if index < 0 {
+			return nil, ErrProfileAmbiguous
+		}
+
+		return matches[index], nil
+	}
+
+	if configured == "" {
+		return nil, ErrProfileNotConfigured
+	}
+
+	workspace, err := domain.NewProfileWorkspace(configured)
+	if err != nil {
+		return nil, ErrProfileNotExists
+	}
+
+	profile, err := cp.profileRepository.Get(workspace)
+	if err != nil {
+		return nil, ErrProfileNotExists
+	}
+
+	return profile, nil
+}
+
+// checkUpdate walks the commits of the ref the remote does not have, the ones
+// up to its remote hash or, for a new ref or a hash unknown locally, the ones
+// not on any remote-tracking branch as git rev-list --not --remotes does, so a
+// new branch pushed to an empty remote or a fork skips the commits of the others
+func (cp *CheckPushProfileService) checkUpdate(update *domain.ScmPushUpdate, profile *domain.Profile) (*CheckPushRef, error) {
+	if !update.IsNew() {
+		ref, err := cp.walkUpdate(update, []domain.ScmCommitHash{update.RemoteHash}, profile)
+		if err == nil {
+			ref.Base = &update.RemoteHash
+		}
+
+		if err != domain.ErrScmCommitNotFound {
+			return ref, err
+		}
+	}
+
+	exclude, err := cp.scmRemoteRepository.Refs("")
+	if err != nil {
+		return nil, err
+	}
+
+	return cp.walkUpdate(update, exclude, profile)
+}
+
+func (cp *CheckPushProfileService) walkUpdate(update *domain.ScmPushUpdate, exclude []domain.ScmCommitHash, profile *domain.Profile) (*CheckPushRef, error) {
+	name := profile.Name().String()
+	email := profile.Email().String()
+	matches := func(recordName string, recordEmail string) bool {
+		return domain.IsSameScmIdentity(recordName, recordEmail, name, email)
+	}
+
+	ref := &CheckPushRef{Update: update, Violations: make([]*VerifyViolation, 0)}
+	err := cp.scmCommitRepository.Walk(&update.LocalHash, exclude, func(record *domain.ScmCommitRecord) error {
+		ref.Commits++
+
+		if !matches(record.AuthorName, record.AuthorEmail) {
+			ref.Violations = append(ref.Violations, &VerifyViolation{
+				Hash:  record.Hash,
+				Role:  VerifyRoleAuthor,
+				Name:  record.AuthorName,
+				Email: record.AuthorEmail,
+			})
+		}
+
+		if !matches(record.CommitterName, record.CommitterEmail) {
+			ref.Violations = append(ref.Violations, &VerifyViolation{
+				Hash:  record.Hash,
+				Role:  VerifyRoleCommitter,
+				Name:  record.CommitterName,
+				Email: record.CommitterEmail,
+			})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ref, nil
+}
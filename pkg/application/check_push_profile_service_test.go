@@ -0,0 +1,285 @@
+package application_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/b4nd/git-profile/pkg/application"
+	"github.com/b4nd/git-profile/pkg/domain"
+
+	"github.com/jaswdr/faker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCheckPushProfileServiceExecute(t *testing.T) {
+	faker := faker.New()
+
+	newProfile := func(workspace string, email string, pattern string) *domain.Profile {
+		profile, err := domain.NewProfile(workspace, email, "Jane Doe")
+		assert.NoError(t, err)
+
+		remote, err := domain.NewProfileRemotePattern(pattern)
+		assert.NoError(t, err)
+
+		return profile.WithRemotes([]domain.ProfileRemotePattern{remote})
+	}
+
+	work := newProfile("work", "jane@acme.com", "github.com:acme-corp/*")
+	personal := newProfile("personal", "jane@home.org", "github.com:jane/*")
+
+	newHash := func() domain.ScmCommitHash {
+		hash, err := domain.NewScmCommitHash(faker.Hash().SHA256()[:40])
+		assert.NoError(t, err)
+
+		return hash
+	}
+
+	newRecord := func(author string, committer string) *domain.ScmCommitRecord {
+		return &domain.ScmCommitRecord{
+			Hash:           newHash(),
+			AuthorName:     "Jane Doe",
+			AuthorEmail:    author,
+			Date:           time.Now(),
+			CommitterName:  "Jane Doe",
+			CommitterEmail: committer,
+			CommitterDate:  time.Now(),
+		}
+	}
+
+	newUpdate := func(local domain.ScmCommitHash, remote string) *domain.ScmPushUpdate {
+		update, err := domain.NewScmPushUpdate("refs/heads/main " + local.String() + " refs/heads/main " + remote)
+		assert.NoError(t, err)
+
+		return update
+	}
+
+	zero := "0000000000000000000000000000000000000000"
+	url := "git@github.com:acme-corp/api.git"
+	noIdentity := (*domain.ScmIdentity)(nil)
+
+	t.Run("should check the new commits against the profile of the remote url", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+		mockIdentityRepository := &MockIdentityRepository{}
+		mockRemoteRepository := &MockRemoteRepository{}
+		mockCommitRepository := &MockCommitRepository{}
+
+		records := []*domain.ScmCommitRecord{
+			newRecord("Jane@Acme.com", "jane@acme.com"),
+			newRecord("jane@home.org", "jane@home.org"),
+			newRecord("jane@acme.com", "jane@home.org"),
+		}
+
+		remote := newHash()
+		update := newUpdate(records[0].Hash, remote.String())
+
+		mockProfileRepository.On("List").Return([]*domain.Profile{work, personal}, nil)
+		mockCommitRepository.On("Walk", &update.LocalHash, []domain.ScmCommitHash{remote}, mock.Anything).Return(records, nil)
+
+		service := application.NewCheckPushProfileService(mockProfileRepository, mockIdentityRepository, mockRemoteRepository, mockCommitRepository)
+		result, err := service.Execute(application.CheckPushProfileServiceParams{
+			Remote:  "origin",
+			Url:     url,
+			Updates: []*domain.ScmPushUpdate{update},
+		})
+
+		assert.ErrorIs(t, err, application.ErrProfileMismatch)
+		assert.Equal(t, work, result.Profile)
+		assert.Equal(t, url, result.Url)
+		assert.Len(t, result.Refs, 1)
+		assert.Equal(t, &remote, result.Refs[0].Base)
+		assert.Equal(t, 3, result.Refs[0].Commits)
+		assert.Len(t, result.Refs[0].Violations, 3)
+
+		assert.Equal(t, records[1].Hash, result.Refs[0].Violations[0].Hash)
+		assert.Equal(t, application.VerifyRoleAuthor, result.Refs[0].Violations[0].Role)
+		assert.Equal(t, application.VerifyRoleCommitter, result.Refs[0].Violations[1].Role)
+		assert.Equal(t, records[2].Hash, result.Refs[0].Violations[2].Hash)
+		assert.Equal(t, application.VerifyRoleCommitter, result.Refs[0].Violations[2].Role)
+
+		mockIdentityRepository.AssertNotCalled(t, "Get")
+		mockRemoteRepository.AssertNotCalled(t, "Refs", mock.Anything)
+		mockCommitRepository.AssertExpectations(t)
+	})
+
+	t.Run("should exclude the remote-tracking branches of every remote for a new ref and skip deletions", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+		mockIdentityRepository := &MockIdentityRepository{}
+		mockRemoteRepository := &MockRemoteRepository{}
+		mockCommitRepository := &MockCommitRepository{}
+
+		records := []*domain.ScmCommitRecord{newRecord("jane@acme.com", "jane@acme.com")}
+		tracking := []domain.ScmCommitHash{newHash()}
+		update := newUpdate(records[0].Hash, zero)
+		deleted, err := domain.NewScmPushUpdate("(delete) " + zero + " refs/heads/old " + newHash().String())
+		assert.NoError(t, err)
+
+		mockRemoteRepository.On("List").Return([]*domain.ScmRemote{domain.NewScmRemote("origin", url)}, nil)
+		mockRemoteRepository.On("Refs", "").Return(tracking, nil)
+		mockProfileRepository.On("List").Return([]*domain.Profile{work, personal}, nil)
+		mockCommitRepository.On("Walk", &update.LocalHash, tracking, mock.Anything).Return(records, nil)
+
+		service := application.NewCheckPushProfileService(mockProfileRepository, mockIdentityRepository, mockRemoteRepository, mockCommitRepository)
+		result, err := service.Execute(application.CheckPushProfileServiceParams{
+			Remote:  "origin",
+			Updates: []*domain.ScmPushUpdate{deleted, update},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, work, result.Profile)
+		assert.Equal(t, url, result.Url)
+		assert.Len(t, result.Refs, 1)
+		assert.Equal(t, update, result.Refs[0].Update)
+		assert.Nil(t, result.Refs[0].Base)
+		assert.Empty(t, result.Refs[0].Violations)
+
+		mockRemoteRepository.AssertExpectations(t)
+		mockCommitRepository.AssertExpectations(t)
+	})
+
+	t.Run("should fall back to the remote-tracking branches when the remote hash is unknown", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+		mockIdentityRepository := &MockIdentityRepository{}
+		mockRemoteRepository := &MockRemoteRepository{}
+		mockCommitRepository := &MockCommitRepository{}
+
+		records := []*domain.ScmCommitRecord{newRecord("jane@acme.com", "jane@acme.com")}
+		tracking := []domain.ScmCommitHash{newHash()}
+		remote := newHash()
+		update := newUpdate(records[0].Hash, remote.String())
+
+		mockRemoteRepository.On("Refs", "").Return(tracking, nil)
+		mockProfileRepository.On("List").Return([]*domain.Profile{work}, nil)
+		mockCommitRepository.On("Walk", &update.LocalHash, []domain.ScmCommitHash{remote}, mock.Anything).Return(nil, domain.ErrScmCommitNotFound)
+		mockCommitRepository.On("Walk", &update.LocalHash, tracking, mock.Anything).Return(records, nil)
+
+		service := application.NewCheckPushProfileService(mockProfileRepository, mockIdentityRepository, mockRemoteRepository, mockCommitRepository)
+		result, err := service.Execute(application.CheckPushProfileServiceParams{
+			Remote:  "origin",
+			Url:     url,
+			Updates: []*domain.ScmPushUpdate{update},
+		})
+
+		assert.NoError(t, err)
+		assert.Nil(t, result.Refs[0].Base)
+		assert.Equal(t, 1, result.Refs[0].Commits)
+		mockCommitRepository.AssertExpectations(t)
+	})
+
+	t.Run("should exclude the commits of the other remotes for a new branch pushed to a fork url", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+		mockIdentityRepository := &MockIdentityRepository{}
+		mockRemoteRepository := &MockRemoteRepository{}
+		mockCommitRepository := &MockCommitRepository{}
+
+		// The branch is on top of the history of origin, which other people authored
+		records := []*domain.ScmCommitRecord{newRecord("jane@home.org", "jane@home.org")}
+		upstream := []domain.ScmCommitHash{newHash(), newHash()}
+		fork := "git@github.com:jane/api.git"
+		update := newUpdate(records[0].Hash, zero)
+
+		mockRemoteRepository.On("List").Return([]*domain.ScmRemote{domain.NewScmRemote("origin", url)}, nil)
+		mockRemoteRepository.On("Refs", "").Return(upstream, nil)
+		mockProfileRepository.On("List").Return([]*domain.Profile{work, personal}, nil)
+		mockCommitRepository.On("Walk", &update.LocalHash, upstream, mock.Anything).Return(records, nil)
+
+		service := application.NewCheckPushProfileService(mockProfileRepository, mockIdentityRepository, mockRemoteRepository, mockCommitRepository)
+		result, err := service.Execute(application.CheckPushProfileServiceParams{
+			Remote:  fork,
+			Updates: []*domain.ScmPushUpdate{update},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, personal, result.Profile)
+		assert.Equal(t, fork, result.Url)
+		assert.Equal(t, 1, result.Refs[0].Commits)
+		assert.Empty(t, result.Refs[0].Violations)
+		mockCommitRepository.AssertExpectations(t)
+	})
+
+	t.Run("should accept the names git wrote without the characters it strips", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+		mockIdentityRepository := &MockIdentityRepository{}
+		mockRemoteRepository := &MockRemoteRepository{}
+		mockCommitRepository := &MockCommitRepository{}
+
+		junior, err := domain.NewProfile("junior", "jane@acme.com", "Jane Doe Jr.")
+		assert.NoError(t, err)
+		pattern, err := domain.NewProfileRemotePattern("github.com:acme-corp/*")
+		assert.NoError(t, err)
+		junior = junior.WithRemotes([]domain.ProfileRemotePattern{pattern})
+
+		record := newRecord("Jane@Acme.com", "jane@acme.com")
+		record.AuthorName = "Jane Doe Jr"
+		record.CommitterName = "Jane Doe Jr"
+		remote := newHash()
+		update := newUpdate(record.Hash, remote.String())
+
+		mockProfileRepository.On("List").Return([]*domain.Profile{junior}, nil)
+		mockCommitRepository.On("Walk", &update.LocalHash, []domain.ScmCommitHash{remote}, mock.Anything).Return([]*domain.ScmCommitRecord{record}, nil)
+
+		service := application.NewCheckPushProfileService(mockProfileRepository, mockIdentityRepository, mockRemoteRepository, mockCommitRepository)
+		result, err := service.Execute(application.CheckPushProfileServiceParams{Remote: "origin", Url: url, Updates: []*domain.ScmPushUpdate{update}})
+
+		assert.NoError(t, err)
+		assert.Empty(t, result.Refs[0].Violations)
+	})
+
+	t.Run("should expect the configured profile when no remote url pattern matches", func(t *testing.T) {
+		records := []*domain.ScmCommitRecord{newRecord("jane@acme.com", "jane@acme.com")}
+		remote := newHash()
+		update := newUpdate(records[0].Hash, remote.String())
+
+		mockProfileRepository := &MockProfileRepository{}
+		mockIdentityRepository := &MockIdentityRepository{}
+		mockRemoteRepository := &MockRemoteRepository{}
+		mockCommitRepository := &MockCommitRepository{}
+
+		mockProfileRepository.On("List").Return([]*domain.Profile{work, personal}, nil)
+		mockProfileRepository.On("Get", personal.Workspace()).Return(personal, nil)
+		mockIdentityRepository.On("Get").Return(domain.NewScmIdentity("personal", "jane@home.org", "Jane Doe"), nil)
+		mockCommitRepository.On("Walk", &update.LocalHash, []domain.ScmCommitHash{remote}, mock.Anything).Return(records, nil)
+
+		service := application.NewCheckPushProfileService(mockProfileRepository, mockIdentityRepository, mockRemoteRepository, mockCommitRepository)
+		result, err := service.Execute(application.CheckPushProfileServiceParams{
+			Remote:  "mirror",
+			Url:     "https://gitlab.internal/acme/api.git",
+			Updates: []*domain.ScmPushUpdate{update},
+		})
+
+		assert.ErrorIs(t, err, application.ErrProfileMismatch)
+		assert.Equal(t, personal, result.Profile)
+		assert.Len(t, result.Refs[0].Violations, 2)
+	})
+
+	t.Run("should return an error when the expected profile cannot be resolved", func(t *testing.T) {
+		update := newUpdate(newHash(), zero)
+
+		// Several profiles match the url and none is configured
+		mockProfileRepository := &MockProfileRepository{}
+		mockIdentityRepository := &MockIdentityRepository{}
+		mockRemoteRepository := &MockRemoteRepository{}
+		mockCommitRepository := &MockCommitRepository{}
+
+		other := newProfile("other", "jane@other.org", "github.com:acme-corp/*")
+		mockProfileRepository.On("List").Return([]*domain.Profile{work, other}, nil)
+		mockIdentityRepository.On("Get").Return(noIdentity, domain.ErrScmIdentityNotFound)
+
+		service := application.NewCheckPushProfileService(mockProfileRepository, mockIdentityRepository, mockRemoteRepository, mockCommitRepository)
+		result, err := service.Execute(application.CheckPushProfileServiceParams{Remote: "origin", Url: url, Updates: []*domain.ScmPushUpdate{update}})
+
+		assert.ErrorIs(t, err, application.ErrProfileAmbiguous)
+		assert.Nil(t, result)
+
+		// No profile matches the url and none is configured
+		mockProfileRepository = &MockProfileRepository{}
+		mockProfileRepository.On("List").Return([]*domain.Profile{work}, nil)
+
+		service = application.NewCheckPushProfileService(mockProfileRepository, mockIdentityRepository, mockRemoteRepository, mockCommitRepository)
+		result, err = service.Execute(application.CheckPushProfileServiceParams{Remote: "mirror", Url: "https://gitlab.internal/acme/api.git", Updates: []*domain.ScmPushUpdate{update}})
+
+		assert.ErrorIs(t, err, application.ErrProfileNotConfigured)
+		assert.Nil(t, result)
+		mockCommitRepository.AssertNotCalled(t, "Walk", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
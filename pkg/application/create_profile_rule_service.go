@@ -0,0 +1,84 @@
+package application
+
+import (
+	"github.com/b4nd/git-profile/pkg/domain"
+)
+
+type CreateProfileRuleService struct {
+	profileRepository     domain.ProfileRepository
+	profileRuleRepository domain.ProfileRuleRepository
+}
+
+type CreateProfileRuleServiceParams struct {
+	Workspace string
+	GitDirs   []string
+	Remotes   []string
+}
+
+func NewCreateProfileRuleService(
+	profileRepository domain.ProfileRepository,
+	profileRuleRepository domain.ProfileRuleRepository,
+) *CreateProfileRuleService {
+	return &CreateProfileRuleService{profileRepository, profileRuleRepository}
+}
+
+func (cr *CreateProfileRuleService) Execute(params CreateProfileRuleServiceParams) ([]*domain.ProfileRule, error) {
+	workspace, err := domain.NewProfileWorkspace(params.Workspace)
+	if err != nil {
+		return nil, err
+	}
+
+	conditions, err := newProfileRuleConditions(params.GitDirs, params.Remotes)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(conditions) == 0 {
+		return nil, domain.ErrInvalidRuleCondition
+	}
+
+	if _, err := cr.profileRepository.Get(workspace); err != nil {
+		return nil, ErrProfileNotExists
+	}
+
+	rules := make([]*domain.ProfileRule, 0, len(conditions))
+	for _, condition := range conditions {
+		rule, err := domain.NewProfileRule(workspace.String(), condition.String())
+		if err != nil {
+			return nil, err
+		}
+
+		if err := cr.profileRuleRepository.Save(rule); err != nil {
+			return nil, err
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+// newProfileRuleConditions builds the conditions of the given directories and remote urls
+func newProfileRuleConditions(gitDirs []string, remotes []string) ([]domain.ProfileRuleCondition, error) {
+	conditions := make([]domain.ProfileRuleCondition, 0, len(gitDirs)+len(remotes))
+
+	for _, gitDir := range gitDirs {
+		condition, err := domain.NewProfileRuleGitDir(gitDir)
+		if err != nil {
+			return nil, err
+		}
+
+		conditions = append(conditions, condition)
+	}
+
+	for _, remote := range remotes {
+		condition, err := domain.NewProfileRuleRemote(remote)
+		if err != nil {
+			return nil, err
+		}
+
+		conditions = append(conditions, condition)
+	}
+
+	return conditions, nil
+}
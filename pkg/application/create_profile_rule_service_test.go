@@ -0,0 +1,90 @@
+package application_test
+
+import (
+	"testing"
+
+	"github.com/b4nd/git-profile/pkg/application"
+	"github.com/b4nd/git-profile/pkg/domain"
+
+	"github.com/jaswdr/faker"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateProfileRuleServiceExecute(t *testing.T) {
+	faker := faker.New()
+
+	profile, err := domain.NewProfile(
+		faker.Internet().User(),
+		faker.Internet().Email(),
+		faker.Person().Name(),
+	)
+	assert.NoError(t, err)
+
+	t.Run("should create a rule for each directory and remote", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+		mockProfileRuleRepository := &MockProfileRuleRepository{}
+
+		gitDirRule, err := domain.NewProfileRule(profile.Workspace().String(), "gitdir:~/work/")
+		assert.NoError(t, err)
+
+		remoteRule, err := domain.NewProfileRule(profile.Workspace().String(), "hasconfig:remote.*.url:git@github.com:acme/**")
+		assert.NoError(t, err)
+
+		mockProfileRepository.On("Get", profile.Workspace()).Return(profile, nil)
+		mockProfileRuleRepository.On("Save", gitDirRule).Return(nil)
+		mockProfileRuleRepository.On("Save", remoteRule).Return(nil)
+
+		createProfileRuleService := application.NewCreateProfileRuleService(mockProfileRepository, mockProfileRuleRepository)
+		rules, err := createProfileRuleService.Execute(application.CreateProfileRuleServiceParams{
+			Workspace: profile.Workspace().String(),
+			GitDirs:   []string{"~/work"},
+			Remotes:   []string{"git@github.com:acme/**"},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []*domain.ProfileRule{gitDirRule, remoteRule}, rules)
+
+		mockProfileRepository.AssertExpectations(t)
+		mockProfileRuleRepository.AssertExpectations(t)
+	})
+
+	t.Run("should return error when the profile does not exist", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+		mockProfileRuleRepository := &MockProfileRuleRepository{}
+
+		mockProfileRepository.On("Get", profile.Workspace()).Return(&domain.Profile{}, assert.AnError)
+
+		createProfileRuleService := application.NewCreateProfileRuleService(mockProfileRepository, mockProfileRuleRepository)
+		rules, err := createProfileRuleService.Execute(application.CreateProfileRuleServiceParams{
+			Workspace: profile.Workspace().String(),
+			GitDirs:   []string{"~/work"},
+		})
+
+		assert.ErrorIs(t, err, application.ErrProfileNotExists)
+		assert.Nil(t, rules)
+
+		mockProfileRepository.AssertExpectations(t)
+		mockProfileRuleRepository.AssertExpectations(t)
+	})
+
+	t.Run("should return error when there is no condition", func(t *testing.T) {
+		createProfileRuleService := application.NewCreateProfileRuleService(nil, nil)
+		rules, err := createProfileRuleService.Execute(application.CreateProfileRuleServiceParams{
+			Workspace: profile.Workspace().String(),
+		})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidRuleCondition)
+		assert.Nil(t, rules)
+	})
+
+	t.Run("should return error when the workspace is invalid", func(t *testing.T) {
+		createProfileRuleService := application.NewCreateProfileRuleService(nil, nil)
+		rules, err := createProfileRuleService.Execute(application.CreateProfileRuleServiceParams{
+			Workspace: "test invalid",
+			GitDirs:   []string{"~/work"},
+		})
+
+		assert.Error(t, err)
+		assert.Nil(t, rules)
+	})
+}
@@ -14,8 +14,18 @@ type CreateProfileService struct {
 
 type CreateProfileServiceParams struct {
 	Workspace string
-	Email     string
-	Name      string
+	// Extends is the workspace of the profile whose fields are inherited
+	Extends       string
+	Email         string
+	Name          string
+	SigningKey    string
+	SigningFormat string
+	CommitSign    bool
+	TagSign       bool
+	SshKey        string
+	Remotes       []string
+	Usernames     map[string]string
+	Config        map[string]string
 }
 
 func NewCreateProfileService(profileRepository domain.ProfileRepository) *CreateProfileService {
@@ -23,6 +33,28 @@ func NewCreateProfileService(profileRepository domain.ProfileRepository) *Create
 }
 
 func (cp *CreateProfileService) Execute(params CreateProfileServiceParams) (*domain.Profile, error) {
+	profile, err := newProfileFromParams(params)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := cp.profileRepository.Get(profile.Workspace()); err == nil {
+		return nil, ErrProfileAlreadyExists
+	}
+
+	if err := checkProfileExtends(cp.profileRepository, profile); err != nil {
+		return nil, err
+	}
+
+	if err = cp.profileRepository.Save(profile); err != nil {
+		return nil, err
+	}
+
+	return profile, nil
+}
+
+// newProfileFromParams validates the params with the value objects of the profile
+func newProfileFromParams(params CreateProfileServiceParams) (*domain.Profile, error) {
 	profile, err := domain.NewProfile(
 		params.Workspace,
 		params.Email,
@@ -33,13 +65,115 @@ func (cp *CreateProfileService) Execute(params CreateProfileServiceParams) (*dom
 		return nil, err
 	}
 
-	if _, err := cp.profileRepository.Get(profile.Workspace()); err == nil {
-		return nil, ErrProfileAlreadyExists
+	if params.SigningKey != "" {
+		signing, err := domain.NewProfileSigning(
+			params.SigningKey,
+			params.SigningFormat,
+			params.CommitSign,
+			params.TagSign,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		profile = profile.WithSigning(signing)
 	}
 
-	if err = cp.profileRepository.Save(profile); err != nil {
-		return nil, err
+	if params.SshKey != "" {
+		sshKey, err := domain.NewProfileSshKey(params.SshKey)
+		if err != nil {
+			return nil, err
+		}
+
+		profile = profile.WithSshKey(sshKey)
+	}
+
+	if len(params.Remotes) > 0 {
+		remotes, err := newProfileRemotePatterns(params.Remotes)
+		if err != nil {
+			return nil, err
+		}
+
+		profile = profile.WithRemotes(remotes)
+	}
+
+	if len(params.Usernames) > 0 {
+		usernames := make([]domain.ProfileUsername, 0, len(params.Usernames))
+		for host, value := range params.Usernames {
+			username, err := domain.NewProfileUsername(host, value)
+			if err != nil {
+				return nil, err
+			}
+
+			usernames = append(usernames, username)
+		}
+
+		profile = profile.WithUsernames(usernames)
+	}
+
+	if len(params.Config) > 0 {
+		config, err := domain.NewProfileConfig(params.Config)
+		if err != nil {
+			return nil, err
+		}
+
+		profile = profile.WithConfig(config)
+	}
+
+	if params.Extends != "" {
+		extends, err := domain.NewProfileWorkspace(params.Extends)
+		if err != nil {
+			return nil, err
+		}
+
+		profile = profile.WithExtends(extends)
 	}
 
 	return profile, nil
 }
+
+// checkProfileExtends checks that the profile extended exists and does not
+// extend the profile in turn
+func checkProfileExtends(profileRepository domain.ProfileRepository, profile *domain.Profile) error {
+	for parent := profile.Extends(); parent.String() != ""; {
+		if parent.Equals(profile.Workspace()) {
+			return domain.ErrProfileExtendsCycle
+		}
+
+		found, err := profileRepository.Get(parent)
+		if errors.Is(err, domain.ErrProfileExtendsCycle) {
+			return err
+		}
+
+		if err != nil {
+			return domain.ErrProfileParentNotExists
+		}
+
+		parent = found.Extends()
+	}
+
+	return nil
+}
+
+// newProfileRemotePatterns creates the remote url patterns, ignoring the duplicated ones
+func newProfileRemotePatterns(values []string) ([]domain.ProfileRemotePattern, error) {
+	remotes := make([]domain.ProfileRemotePattern, 0, len(values))
+	for _, value := range values {
+		remote, err := domain.NewProfileRemotePattern(value)
+		if err != nil {
+			return nil, err
+		}
+
+		duplicated := false
+		for _, r := range remotes {
+			duplicated = duplicated || r.Equals(remote)
+		}
+
+		if !duplicated {
+			remotes = append(remotes, remote)
+		}
+	}
+
+	return remotes, nil
+}
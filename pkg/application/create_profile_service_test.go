@@ -132,4 +132,141 @@ func TestCreateProfileServiceExecute(t *testing.T) {
 		assert.Error(t, err)
 		assert.Nil(t, newProfile)
 	})
+
+	t.Run("should create it with the signing configuration", func(t *testing.T) {
+		testParams := params
+		testParams.SigningKey = "~/.ssh/id_ed25519.pub"
+		testParams.SigningFormat = domain.SigningFormatSSH
+		testParams.CommitSign = true
+
+		signing, err := domain.NewProfileSigning(testParams.SigningKey, testParams.SigningFormat, true, false)
+		assert.NoError(t, err)
+
+		signedProfile := profile.WithSigning(signing)
+
+		mockProfileRepository := &MockProfileRepository{}
+		mockProfileRepository.On("Get", profile.Workspace()).Return(&domain.Profile{}, assert.AnError)
+		mockProfileRepository.On("Save", signedProfile).Return(nil)
+
+		createProfileService := application.NewCreateProfileService(mockProfileRepository)
+		newProfile, err := createProfileService.Execute(testParams)
+
+		assert.NoError(t, err)
+		assert.Equal(t, signedProfile, newProfile)
+		assert.Equal(t, domain.SigningFormatSSH, newProfile.Signing().Format())
+
+		mockProfileRepository.AssertExpectations(t)
+	})
+
+	t.Run("should return error when signing format is invalid", func(t *testing.T) {
+		testParams := params
+		testParams.SigningKey = faker.Hash().MD5()
+		testParams.SigningFormat = "pgp"
+
+		createProfileService := application.NewCreateProfileService(nil)
+		newProfile, err := createProfileService.Execute(testParams)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidSigningFormat)
+		assert.Nil(t, newProfile)
+	})
+
+	t.Run("should create it with the remote url patterns", func(t *testing.T) {
+		testParams := params
+		testParams.Remotes = []string{"github.com:acme-corp/*", "gitlab.internal/*", "github.com:acme-corp/*"}
+
+		first, err := domain.NewProfileRemotePattern("github.com:acme-corp/*")
+		assert.NoError(t, err)
+
+		second, err := domain.NewProfileRemotePattern("gitlab.internal/*")
+		assert.NoError(t, err)
+
+		remoteProfile := profile.WithRemotes([]domain.ProfileRemotePattern{first, second})
+
+		mockProfileRepository := &MockProfileRepository{}
+		mockProfileRepository.On("Get", profile.Workspace()).Return(&domain.Profile{}, assert.AnError)
+		mockProfileRepository.On("Save", remoteProfile).Return(nil)
+
+		createProfileService := application.NewCreateProfileService(mockProfileRepository)
+		newProfile, err := createProfileService.Execute(testParams)
+
+		assert.NoError(t, err)
+		assert.True(t, remoteProfile.Equals(newProfile))
+		assert.True(t, newProfile.MatchRemote("git@github.com:acme-corp/api.git"))
+
+		mockProfileRepository.AssertExpectations(t)
+	})
+
+	t.Run("should return error when a remote url pattern is invalid", func(t *testing.T) {
+		testParams := params
+		testParams.Remotes = []string{"github.com acme"}
+
+		createProfileService := application.NewCreateProfileService(nil)
+		newProfile, err := createProfileService.Execute(testParams)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidRemotePattern)
+		assert.Nil(t, newProfile)
+	})
+
+	t.Run("should create it with the extra git config keys", func(t *testing.T) {
+		testParams := params
+		testParams.Config = map[string]string{"Pull.Rebase": "true", "url.git@github.com:Acme/.insteadOf": "https://github.com/Acme/"}
+
+		config, err := domain.NewProfileConfig(testParams.Config)
+		assert.NoError(t, err)
+
+		configProfile := profile.WithConfig(config)
+
+		mockProfileRepository := &MockProfileRepository{}
+		mockProfileRepository.On("Get", profile.Workspace()).Return(&domain.Profile{}, assert.AnError)
+		mockProfileRepository.On("Save", configProfile).Return(nil)
+
+		createProfileService := application.NewCreateProfileService(mockProfileRepository)
+		newProfile, err := createProfileService.Execute(testParams)
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"pull.rebase": "true", "url.git@github.com:Acme/.insteadof": "https://github.com/Acme/"}, newProfile.Config().Values())
+
+		mockProfileRepository.AssertExpectations(t)
+	})
+
+	t.Run("should return error when a git config key is reserved or invalid", func(t *testing.T) {
+		for _, c := range []struct {
+			key      string
+			value    string
+			expected error
+		}{
+			{"user.email", "jane@gmail.com", domain.ErrReservedConfigKey},
+			{"gitprofile.appliedkeys", "pull.rebase", domain.ErrReservedConfigKey},
+			{"rebase", "true", domain.ErrInvalidConfigKey},
+			{"pull..rebase", "true", domain.ErrInvalidConfigKey},
+			{"alias.co", "checkout # comment", domain.ErrInvalidConfigValue},
+		} {
+			testParams := params
+			testParams.Config = map[string]string{c.key: c.value}
+
+			createProfileService := application.NewCreateProfileService(nil)
+			newProfile, err := createProfileService.Execute(testParams)
+
+			assert.ErrorIs(t, err, c.expected, c.key)
+			assert.Nil(t, newProfile)
+		}
+	})
+
+	t.Run("should return error when the extended profile does not exist", func(t *testing.T) {
+		missing, err := domain.NewProfileWorkspace("missing")
+		assert.NoError(t, err)
+
+		mockProfileRepository := &MockProfileRepository{}
+		mockProfileRepository.On("Get", profile.Workspace()).Return(&domain.Profile{}, assert.AnError)
+		mockProfileRepository.On("Get", missing).Return(&domain.Profile{}, domain.ErrInvalidWorkspace)
+
+		withExtends := params
+		withExtends.Extends = "missing"
+
+		createProfileService := application.NewCreateProfileService(mockProfileRepository)
+		_, err = createProfileService.Execute(withExtends)
+
+		assert.ErrorIs(t, err, domain.ErrProfileParentNotExists)
+		mockProfileRepository.AssertExpectations(t)
+	})
 }
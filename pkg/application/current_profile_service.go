@@ -9,19 +9,32 @@ import (
 var ErrProfileNotConfigured = errors.New("profile not configured")
 
 type CurrentProfileService struct {
-	profileRepository domain.ProfileRepository
-	scmUserRepository domain.ScmUserRepository
+	profileRepository     domain.ProfileRepository
+	scmUserRepository     domain.ScmUserRepository
+	scmIdentityRepository domain.ScmIdentityRepository
 }
 
+// NewCurrentProfileService returns the service reading the workspace of the
+// configuration file, the identity repository is asked for the workspace git
+// resolves through the includes when the file has none, it can be nil
 func NewCurrentProfileService(
 	profileRepository domain.ProfileRepository,
 	scmUserRepository domain.ScmUserRepository,
+	scmIdentityRepository domain.ScmIdentityRepository,
 ) *CurrentProfileService {
-	return &CurrentProfileService{profileRepository, scmUserRepository}
+	return &CurrentProfileService{profileRepository, scmUserRepository, scmIdentityRepository}
 }
 
 func (cp *CurrentProfileService) Execute() (*domain.Profile, error) {
 	scmUser, err := cp.scmUserRepository.Get()
+
+	// A profile applied by an includeIf rule is only seen in the effective configuration
+	if err == domain.ErrScmUserNotFound || (err == nil && scmUser != nil && scmUser.Workespace == "") {
+		if identity := cp.effectiveUser(); identity != nil {
+			scmUser, err = identity, nil
+		}
+	}
+
 	if err != nil {
 		return nil, err
 	}
@@ -46,3 +59,18 @@ func (cp *CurrentProfileService) Execute() (*domain.Profile, error) {
 
 	return profile, nil
 }
+
+// effectiveUser returns the identity git resolves with its workspace, nil when
+// it has no workspace or there is no identity repository
+func (cp *CurrentProfileService) effectiveUser() *domain.ScmUser {
+	if cp.scmIdentityRepository == nil {
+		return nil
+	}
+
+	identity, err := cp.scmIdentityRepository.Get()
+	if err != nil || identity.Workspace == "" {
+		return nil
+	}
+
+	return domain.NewScmUser(identity.Workspace, identity.Email, identity.Name)
+}
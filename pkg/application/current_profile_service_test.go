@@ -37,7 +37,7 @@ func TestCurrentProfileServiceExecute(t *testing.T) {
 		mockGitUserRepository.On("Get").Return(scmUser, nil)
 		mockProfileRepository.On("Get", workspace).Return(profile, nil)
 
-		currentProfileService := application.NewCurrentProfileService(mockProfileRepository, mockGitUserRepository)
+		currentProfileService := application.NewCurrentProfileService(mockProfileRepository, mockGitUserRepository, nil)
 		currentProfile, err := currentProfileService.Execute()
 
 		assert.NoError(t, err)
@@ -54,7 +54,7 @@ func TestCurrentProfileServiceExecute(t *testing.T) {
 		mockGitUserRepository.On("Get").Return(scmUser, nil)
 		mockProfileRepository.On("Get", workspace).Return(&domain.Profile{}, assert.AnError)
 
-		currentProfileService := application.NewCurrentProfileService(mockProfileRepository, mockGitUserRepository)
+		currentProfileService := application.NewCurrentProfileService(mockProfileRepository, mockGitUserRepository, nil)
 		currentProfile, err := currentProfileService.Execute()
 
 		assert.Error(t, err)
@@ -71,7 +71,7 @@ func TestCurrentProfileServiceExecute(t *testing.T) {
 
 		mockGitUserRepository.On("Get").Return(&domain.ScmUser{}, assert.AnError)
 
-		currentProfileService := application.NewCurrentProfileService(mockProfileRepository, mockGitUserRepository)
+		currentProfileService := application.NewCurrentProfileService(mockProfileRepository, mockGitUserRepository, nil)
 		profile, err := currentProfileService.Execute()
 
 		assert.Error(t, err)
@@ -88,7 +88,7 @@ func TestCurrentProfileServiceExecute(t *testing.T) {
 
 		mockGitUserRepository.On("Get").Return(&domain.ScmUser{}, application.ErrProfileNotConfigured)
 
-		currentProfileService := application.NewCurrentProfileService(mockProfileRepository, mockGitUserRepository)
+		currentProfileService := application.NewCurrentProfileService(mockProfileRepository, mockGitUserRepository, nil)
 		profile, err := currentProfileService.Execute()
 
 		assert.Error(t, err)
@@ -99,6 +99,40 @@ func TestCurrentProfileServiceExecute(t *testing.T) {
 		mockProfileRepository.AssertExpectations(t)
 	})
 
+	t.Run("should return the profile of the effective configuration when the file has none", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+		mockGitUserRepository := &MockUserRepository{}
+		mockIdentityRepository := &MockIdentityRepository{}
+
+		mockGitUserRepository.On("Get").Return((*domain.ScmUser)(nil), domain.ErrScmUserNotFound)
+		mockIdentityRepository.On("Get").Return(domain.NewScmIdentity(workspace.String(), scmUser.Email, scmUser.Name), nil)
+		mockProfileRepository.On("Get", workspace).Return(profile, nil)
+
+		currentProfileService := application.NewCurrentProfileService(mockProfileRepository, mockGitUserRepository, mockIdentityRepository)
+		currentProfile, err := currentProfileService.Execute()
+
+		assert.NoError(t, err)
+		assert.Equal(t, profile, currentProfile)
+
+		mockIdentityRepository.AssertExpectations(t)
+		mockProfileRepository.AssertExpectations(t)
+	})
+
+	t.Run("should not read the effective configuration outside of a repository", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+		mockGitUserRepository := &MockUserRepository{}
+		mockIdentityRepository := &MockIdentityRepository{}
+
+		mockGitUserRepository.On("Get").Return((*domain.ScmUser)(nil), domain.ErrScmRepositoryNotFound)
+
+		currentProfileService := application.NewCurrentProfileService(mockProfileRepository, mockGitUserRepository, mockIdentityRepository)
+		currentProfile, err := currentProfileService.Execute()
+
+		assert.ErrorIs(t, err, domain.ErrScmRepositoryNotFound)
+		assert.Nil(t, currentProfile)
+		mockIdentityRepository.AssertNotCalled(t, "Get")
+	})
+
 	t.Run("should return an error when the profile is invalid", func(t *testing.T) {
 		mockProfileRepository := &MockProfileRepository{}
 		mockGitUserRepository := &MockUserRepository{}
@@ -107,7 +141,7 @@ func TestCurrentProfileServiceExecute(t *testing.T) {
 			Workespace: "test invalid",
 		}, nil)
 
-		currentProfileService := application.NewCurrentProfileService(mockProfileRepository, mockGitUserRepository)
+		currentProfileService := application.NewCurrentProfileService(mockProfileRepository, mockGitUserRepository, nil)
 		profile, err := currentProfileService.Execute()
 
 		assert.Error(t, err)
@@ -0,0 +1,84 @@
+package application
+
+import (
+	"errors"
+
+	"github.com/b4nd/git-profile/pkg/domain"
+)
+
+var ErrProfileRuleNotExists = errors.New("profile rule not exists")
+
+type DeleteProfileRuleService struct {
+	profileRuleRepository domain.ProfileRuleRepository
+}
+
+// DeleteProfileRuleServiceParams selects the rules to delete, every rule of the
+// workspace is deleted when no condition is given
+type DeleteProfileRuleServiceParams struct {
+	Workspace string
+	GitDirs   []string
+	Remotes   []string
+}
+
+func NewDeleteProfileRuleService(profileRuleRepository domain.ProfileRuleRepository) *DeleteProfileRuleService {
+	return &DeleteProfileRuleService{profileRuleRepository}
+}
+
+func (dr *DeleteProfileRuleService) Execute(params DeleteProfileRuleServiceParams) ([]*domain.ProfileRule, error) {
+	conditions, err := newProfileRuleConditions(params.GitDirs, params.Remotes)
+	if err != nil {
+		return nil, err
+	}
+
+	var workspace *domain.ProfileWorkspace
+	if params.Workspace != "" {
+		w, err := domain.NewProfileWorkspace(params.Workspace)
+		if err != nil {
+			return nil, err
+		}
+
+		workspace = &w
+	}
+
+	if workspace == nil && len(conditions) == 0 {
+		return nil, domain.ErrInvalidRuleCondition
+	}
+
+	rules, err := dr.profileRuleRepository.List()
+	if err != nil {
+		return nil, err
+	}
+
+	deleted := make([]*domain.ProfileRule, 0)
+	for _, rule := range rules {
+		if workspace != nil && !rule.Workspace().Equals(*workspace) {
+			continue
+		}
+
+		if len(conditions) > 0 && !containsCondition(conditions, rule.Condition()) {
+			continue
+		}
+
+		if err := dr.profileRuleRepository.Delete(rule.Condition()); err != nil {
+			return nil, err
+		}
+
+		deleted = append(deleted, rule)
+	}
+
+	if len(deleted) == 0 {
+		return nil, ErrProfileRuleNotExists
+	}
+
+	return deleted, nil
+}
+
+func containsCondition(conditions []domain.ProfileRuleCondition, condition domain.ProfileRuleCondition) bool {
+	for _, c := range conditions {
+		if c.Equals(condition) {
+			return true
+		}
+	}
+
+	return false
+}
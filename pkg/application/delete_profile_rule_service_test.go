@@ -0,0 +1,80 @@
+package application_test
+
+import (
+	"testing"
+
+	"github.com/b4nd/git-profile/pkg/application"
+	"github.com/b4nd/git-profile/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeleteProfileRuleServiceExecute(t *testing.T) {
+	workRule, err := domain.NewProfileRule("work", "gitdir:~/work/")
+	assert.NoError(t, err)
+
+	workRemoteRule, err := domain.NewProfileRule("work", "hasconfig:remote.*.url:git@github.com:acme/**")
+	assert.NoError(t, err)
+
+	personalRule, err := domain.NewProfileRule("personal", "gitdir:~/personal/")
+	assert.NoError(t, err)
+
+	rules := []*domain.ProfileRule{workRule, workRemoteRule, personalRule}
+
+	t.Run("should delete every rule of the workspace", func(t *testing.T) {
+		mockProfileRuleRepository := &MockProfileRuleRepository{}
+		mockProfileRuleRepository.On("List").Return(rules, nil)
+		mockProfileRuleRepository.On("Delete", workRule.Condition()).Return(nil)
+		mockProfileRuleRepository.On("Delete", workRemoteRule.Condition()).Return(nil)
+
+		deleteProfileRuleService := application.NewDeleteProfileRuleService(mockProfileRuleRepository)
+		deleted, err := deleteProfileRuleService.Execute(application.DeleteProfileRuleServiceParams{
+			Workspace: "work",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []*domain.ProfileRule{workRule, workRemoteRule}, deleted)
+
+		mockProfileRuleRepository.AssertExpectations(t)
+	})
+
+	t.Run("should delete only the rule of the condition", func(t *testing.T) {
+		mockProfileRuleRepository := &MockProfileRuleRepository{}
+		mockProfileRuleRepository.On("List").Return(rules, nil)
+		mockProfileRuleRepository.On("Delete", personalRule.Condition()).Return(nil)
+
+		deleteProfileRuleService := application.NewDeleteProfileRuleService(mockProfileRuleRepository)
+		deleted, err := deleteProfileRuleService.Execute(application.DeleteProfileRuleServiceParams{
+			GitDirs: []string{"~/personal"},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []*domain.ProfileRule{personalRule}, deleted)
+
+		mockProfileRuleRepository.AssertExpectations(t)
+	})
+
+	t.Run("should return error when no rule matches", func(t *testing.T) {
+		mockProfileRuleRepository := &MockProfileRuleRepository{}
+		mockProfileRuleRepository.On("List").Return(rules, nil)
+
+		deleteProfileRuleService := application.NewDeleteProfileRuleService(mockProfileRuleRepository)
+		deleted, err := deleteProfileRuleService.Execute(application.DeleteProfileRuleServiceParams{
+			Workspace: "personal",
+			GitDirs:   []string{"~/work"},
+		})
+
+		assert.ErrorIs(t, err, application.ErrProfileRuleNotExists)
+		assert.Nil(t, deleted)
+
+		mockProfileRuleRepository.AssertExpectations(t)
+	})
+
+	t.Run("should return error when nothing is selected", func(t *testing.T) {
+		deleteProfileRuleService := application.NewDeleteProfileRuleService(nil)
+		deleted, err := deleteProfileRuleService.Execute(application.DeleteProfileRuleServiceParams{})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidRuleCondition)
+		assert.Nil(t, deleted)
+	})
+}
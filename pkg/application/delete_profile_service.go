@@ -1,9 +1,13 @@
 package application
 
 import (
+	"errors"
+
 	"github.com/b4nd/git-profile/pkg/domain"
 )
 
+var ErrProfileExtended = errors.New("profile is extended by other profiles")
+
 type DeleteProfileService struct {
 	profileRepository domain.ProfileRepository
 }
@@ -12,23 +16,49 @@ type DeleteProfileServiceParams struct {
 	Workspace string
 }
 
+// DeleteProfileServiceResult holds the profiles that extend the workspace, it
+// is returned along with ErrProfileExtended
+type DeleteProfileServiceResult struct {
+	Children []*domain.Profile
+}
+
 func NewDeleteProfileService(profileRepository domain.ProfileRepository) *DeleteProfileService {
 	return &DeleteProfileService{profileRepository}
 }
 
-func (cp *DeleteProfileService) Execute(params DeleteProfileServiceParams) error {
+// Execute deletes the profile, a profile that other profiles still extend is
+// refused so their extends chain is never broken
+func (cp *DeleteProfileService) Execute(params DeleteProfileServiceParams) (*DeleteProfileServiceResult, error) {
 	workspace, err := domain.NewProfileWorkspace(params.Workspace)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	// A profile whose extends chain is broken can still be deleted to repair it
+	_, err = cp.profileRepository.Get(workspace)
+	if err != nil && !errors.Is(err, domain.ErrProfileExtendsCycle) && !errors.Is(err, domain.ErrProfileParentNotExists) {
+		return nil, ErrProfileNotExists
+	}
+
+	profiles, err := cp.profileRepository.List()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &DeleteProfileServiceResult{Children: make([]*domain.Profile, 0)}
+	for _, profile := range profiles {
+		if profile.Extends().Equals(workspace) {
+			result.Children = append(result.Children, profile)
+		}
 	}
 
-	if _, err := cp.profileRepository.Get(workspace); err != nil {
-		return ErrProfileNotExists
+	if len(result.Children) > 0 {
+		return result, ErrProfileExtended
 	}
 
 	if err = cp.profileRepository.Delete(workspace); err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	return result, nil
 }
@@ -18,10 +18,11 @@ func TestDeleteProfileExecute(t *testing.T) {
 		profile := profiles[0]
 
 		mockProfileRepository.On("Get", profile.Workspace()).Return(profile, nil)
+		mockProfileRepository.On("List").Return(profiles, nil)
 		mockProfileRepository.On("Delete", profile.Workspace()).Return(nil)
 
 		deleteProfileService := application.NewDeleteProfileService(mockProfileRepository)
-		err := deleteProfileService.Execute(application.DeleteProfileServiceParams{
+		_, err := deleteProfileService.Execute(application.DeleteProfileServiceParams{
 			Workspace: profile.Workspace().String(),
 		})
 
@@ -40,7 +41,7 @@ func TestDeleteProfileExecute(t *testing.T) {
 		mockProfileRepository.On("Get", profile.Workspace()).Return(&domain.Profile{}, assert.AnError)
 
 		deleteProfileService := application.NewDeleteProfileService(mockProfileRepository)
-		err := deleteProfileService.Execute(application.DeleteProfileServiceParams{
+		_, err := deleteProfileService.Execute(application.DeleteProfileServiceParams{
 			Workspace: profile.Workspace().String(),
 		})
 
@@ -54,7 +55,7 @@ func TestDeleteProfileExecute(t *testing.T) {
 		mockProfileRepository := &MockProfileRepository{}
 
 		deleteProfileService := application.NewDeleteProfileService(mockProfileRepository)
-		err := deleteProfileService.Execute(application.DeleteProfileServiceParams{
+		_, err := deleteProfileService.Execute(application.DeleteProfileServiceParams{
 			Workspace: "test invalid",
 		})
 
@@ -71,10 +72,11 @@ func TestDeleteProfileExecute(t *testing.T) {
 		profile := profiles[0]
 
 		mockProfileRepository.On("Get", profile.Workspace()).Return(profile, nil)
+		mockProfileRepository.On("List").Return(profiles, nil)
 		mockProfileRepository.On("Delete", profile.Workspace()).Return(assert.AnError)
 
 		deleteProfileService := application.NewDeleteProfileService(mockProfileRepository)
-		err := deleteProfileService.Execute(application.DeleteProfileServiceParams{
+		_, err := deleteProfileService.Execute(application.DeleteProfileServiceParams{
 			Workspace: profile.Workspace().String(),
 		})
 
@@ -83,4 +85,49 @@ func TestDeleteProfileExecute(t *testing.T) {
 
 		mockProfileRepository.AssertExpectations(t)
 	})
+
+	t.Run("should refuse to delete a profile that other profiles extend", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+
+		profiles := generateProfiles(t, 3)
+
+		profile := profiles[0]
+		child := profiles[1].WithExtends(profile.Workspace())
+		profiles[1] = child
+
+		mockProfileRepository.On("Get", profile.Workspace()).Return(profile, nil)
+		mockProfileRepository.On("List").Return(profiles, nil)
+
+		deleteProfileService := application.NewDeleteProfileService(mockProfileRepository)
+		result, err := deleteProfileService.Execute(application.DeleteProfileServiceParams{
+			Workspace: profile.Workspace().String(),
+		})
+
+		assert.ErrorIs(t, err, application.ErrProfileExtended)
+		assert.Equal(t, []*domain.Profile{child}, result.Children)
+
+		mockProfileRepository.AssertExpectations(t)
+		mockProfileRepository.AssertNotCalled(t, "Delete", profile.Workspace())
+	})
+
+	t.Run("should delete a profile whose extends chain is broken", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+
+		profiles := generateProfiles(t, 2)
+
+		broken := profiles[0]
+
+		mockProfileRepository.On("Get", broken.Workspace()).Return(&domain.Profile{}, domain.ErrProfileParentNotExists)
+		mockProfileRepository.On("List").Return(profiles[1:], nil)
+		mockProfileRepository.On("Delete", broken.Workspace()).Return(nil)
+
+		deleteProfileService := application.NewDeleteProfileService(mockProfileRepository)
+		_, err := deleteProfileService.Execute(application.DeleteProfileServiceParams{
+			Workspace: broken.Workspace().String(),
+		})
+
+		assert.NoError(t, err)
+
+		mockProfileRepository.AssertExpectations(t)
+	})
 }
@@ -0,0 +1,337 @@
+package application
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/b4nd/git-profile/pkg/domain"
+)
+
+// DoctorSeverity tells whether a finding breaks the identity or may surprise the user
+type DoctorSeverity string
+
+const (
+	DoctorSeverityError   DoctorSeverity = "error"
+	DoctorSeverityWarning DoctorSeverity = "warning"
+)
+
+// DoctorCheck names the check a finding comes from
+type DoctorCheck string
+
+const (
+	DoctorCheckGitNotInstalled     DoctorCheck = "git-not-installed"
+	DoctorCheckGitOutdated         DoctorCheck = "git-outdated"
+	DoctorCheckSourceInvalid       DoctorCheck = "source-invalid"
+	DoctorCheckProfileInvalid      DoctorCheck = "profile-invalid"
+	DoctorCheckProfileDuplicated   DoctorCheck = "profile-duplicated"
+	DoctorCheckProfileShadowed     DoctorCheck = "profile-shadowed"
+	DoctorCheckIdentityMismatch    DoctorCheck = "identity-mismatch"
+	DoctorCheckWorkspaceNotExists  DoctorCheck = "workspace-not-exists"
+	DoctorCheckEnvironmentOverride DoctorCheck = "environment-override"
+	DoctorCheckIncludeConflict     DoctorCheck = "include-conflict"
+)
+
+// DoctorFinding is a problem found by the doctor. Subject is what it is about,
+// a workspace, a file, an environment variable or an include condition, and
+// Paths are the files involved. Fixed is set once the fix is applied.
+type DoctorFinding struct {
+	Check    DoctorCheck
+	Severity DoctorSeverity
+	Subject  string
+	Scope    domain.ProfileScope
+	Paths    []string
+	Detail   string
+	Err      error
+	Fixable  bool
+	Fixed    bool
+
+	fix func() error
+}
+
+type DoctorProfileService struct {
+	profileRepository       domain.ProfileRepository
+	profileSourceRepository domain.ProfileSourceRepository
+	scmVersionRepository    domain.ScmVersionRepository
+	scmUserRepository       domain.ScmUserRepository
+	scmGlobalUserRepository domain.ScmUserRepository
+	scmIdentityRepository   domain.ScmIdentityRepository
+	scmIncludeRepository    domain.ScmIncludeRepository
+}
+
+type DoctorProfileServiceParams struct {
+	// Fix applies the fix of the findings that have one
+	Fix bool
+}
+
+func NewDoctorProfileService(
+	profileRepository domain.ProfileRepository,
+	profileSourceRepository domain.ProfileSourceRepository,
+	scmVersionRepository domain.ScmVersionRepository,
+	scmUserRepository domain.ScmUserRepository,
+	scmGlobalUserRepository domain.ScmUserRepository,
+	scmIdentityRepository domain.ScmIdentityRepository,
+	scmIncludeRepository domain.ScmIncludeRepository,
+) *DoctorProfileService {
+	return &DoctorProfileService{
+		profileRepository,
+		profileSourceRepository,
+		scmVersionRepository,
+		scmUserRepository,
+		scmGlobalUserRepository,
+		scmIdentityRepository,
+		scmIncludeRepository,
+	}
+}
+
+func (dp *DoctorProfileService) Execute(params DoctorProfileServiceParams) ([]*DoctorFinding, error) {
+	findings := dp.checkGit()
+
+	sources, readable, err := dp.checkSources()
+	if err != nil {
+		return nil, err
+	}
+
+	findings = append(findings, sources...)
+
+	// The profiles cannot be read while a file does not parse, every profile would look deleted
+	if readable {
+		findings = append(findings, dp.checkIdentity(domain.ProfileScopeLocal, dp.scmUserRepository)...)
+		findings = append(findings, dp.checkIdentity(domain.ProfileScopeGlobal, dp.scmGlobalUserRepository)...)
+	}
+
+	findings = append(findings, dp.checkEnvironment()...)
+
+	includes, err := dp.checkIncludes()
+	if err != nil {
+		return nil, err
+	}
+
+	findings = append(findings, includes...)
+
+	if params.Fix {
+		for _, finding := range findings {
+			if finding.fix == nil {
+				continue
+			}
+
+			if err := finding.fix(); err != nil {
+				return nil, err
+			}
+
+			finding.Fixed = true
+		}
+	}
+
+	return findings, nil
+}
+
+// checkGit checks that git can be run and supports every feature used
+func (dp *DoctorProfileService) checkGit() []*DoctorFinding {
+	version, err := dp.scmVersionRepository.Get()
+	if err != nil {
+		return []*DoctorFinding{{Check: DoctorCheckGitNotInstalled, Severity: DoctorSeverityError, Err: err}}
+	}
+
+	if !version.AtLeast(domain.ScmMinimumVersion) {
+		return []*DoctorFinding{{
+			Check:    DoctorCheckGitOutdated,
+			Severity: DoctorSeverityWarning,
+			Subject:  version.String(),
+			Detail:   domain.ScmMinimumVersion.String(),
+		}}
+	}
+
+	return nil
+}
+
+// checkSources checks that every profile file parses, that its profiles
+// resolve and that no workspace is defined twice, the first file read wins.
+// The profiles are only resolved, and reported readable, when every file parses.
+func (dp *DoctorProfileService) checkSources() ([]*DoctorFinding, bool, error) {
+	reports, err := dp.profileSourceRepository.Reports()
+	if err != nil {
+		return nil, false, err
+	}
+
+	findings := make([]*DoctorFinding, 0)
+	readable := true
+	for _, report := range reports {
+		if report.Err != nil {
+			readable = false
+			findings = append(findings, &DoctorFinding{
+				Check:    DoctorCheckSourceInvalid,
+				Severity: DoctorSeverityError,
+				Subject:  report.Source.Path(),
+				Paths:    []string{report.Source.Path()},
+				Err:      report.Err,
+			})
+		}
+	}
+
+	seen := map[string]string{}
+	for _, report := range reports {
+		path := report.Source.Path()
+		if report.Err != nil {
+			continue
+		}
+
+		for _, workspace := range report.Duplicates {
+			findings = append(findings, &DoctorFinding{
+				Check:    DoctorCheckProfileDuplicated,
+				Severity: DoctorSeverityWarning,
+				Subject:  workspace,
+				Paths:    []string{path},
+			})
+		}
+
+		for _, workspace := range report.Workspaces {
+			if first, ok := seen[workspace]; ok {
+				findings = append(findings, &DoctorFinding{
+					Check:    DoctorCheckProfileShadowed,
+					Severity: DoctorSeverityWarning,
+					Subject:  workspace,
+					Paths:    []string{path, first},
+				})
+
+				continue
+			}
+
+			seen[workspace] = path
+			if !readable {
+				continue
+			}
+
+			if err := dp.resolveProfile(workspace); err != nil {
+				findings = append(findings, &DoctorFinding{
+					Check:    DoctorCheckProfileInvalid,
+					Severity: DoctorSeverityError,
+					Subject:  workspace,
+					Paths:    []string{path},
+					Err:      err,
+				})
+			}
+		}
+	}
+
+	return findings, readable, nil
+}
+
+func (dp *DoctorProfileService) resolveProfile(value string) error {
+	workspace, err := domain.NewProfileWorkspace(value)
+	if err != nil {
+		return err
+	}
+
+	_, err = dp.profileRepository.Get(workspace)
+	return err
+}
+
+// checkIdentity checks that the identity of the git configuration matches
+// the profile of its workspace. The fix applies the profile again, or removes
+// the workspace when its profile was deleted so the identity is kept as is.
+func (dp *DoctorProfileService) checkIdentity(scope domain.ProfileScope, repository domain.ScmUserRepository) []*DoctorFinding {
+	user, err := repository.Get()
+	if err != nil || user == nil || user.Workespace == "" {
+		return nil
+	}
+
+	workspace, err := domain.NewProfileWorkspace(user.Workespace)
+	if err == nil {
+		profile, err := dp.profileRepository.Get(workspace)
+		if err != nil && err != domain.ErrInvalidWorkspace {
+			// The profile exists but cannot be resolved, it is reported with the sources
+			return nil
+		}
+
+		if err == nil {
+			if profile.Email().String() == user.Email && profile.Name().String() == user.Name {
+				return nil
+			}
+
+			return []*DoctorFinding{{
+				Check:    DoctorCheckIdentityMismatch,
+				Severity: DoctorSeverityWarning,
+				Subject:  user.Workespace,
+				Scope:    scope,
+				Detail:   user.Name + " <" + user.Email + ">",
+				Fixable:  true,
+				fix: func() error {
+					scmUser := newScmUserFromProfile(profile)
+					scmUser.CredentialHelper = user.CredentialHelper
+					return repository.Save(scmUser)
+				},
+			}}
+		}
+	}
+
+	return []*DoctorFinding{{
+		Check:    DoctorCheckWorkspaceNotExists,
+		Severity: DoctorSeverityError,
+		Subject:  user.Workespace,
+		Scope:    scope,
+		Fixable:  true,
+		fix: func() error {
+			user.Workespace = ""
+			return repository.Save(user)
+		},
+	}}
+}
+
+// checkEnvironment reports the environment variables that replace the identity of the profile
+func (dp *DoctorProfileService) checkEnvironment() []*DoctorFinding {
+	overrides := dp.scmIdentityRepository.Overrides()
+
+	names := make([]string, 0, len(overrides))
+	for name := range overrides {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	findings := make([]*DoctorFinding, 0, len(names))
+	for _, name := range names {
+		findings = append(findings, &DoctorFinding{
+			Check:    DoctorCheckEnvironmentOverride,
+			Severity: DoctorSeverityWarning,
+			Subject:  name,
+			Detail:   overrides[name],
+		})
+	}
+
+	return findings
+}
+
+// checkIncludes reports the includeIf conditions that include different files,
+// the identity then depends on the order of the sections
+func (dp *DoctorProfileService) checkIncludes() ([]*DoctorFinding, error) {
+	sections, err := dp.scmIncludeRepository.List()
+	if err != nil {
+		return nil, err
+	}
+
+	conditions := make([]string, 0)
+	paths := map[string][]string{}
+	for _, section := range sections {
+		if _, ok := paths[section.Condition]; !ok {
+			conditions = append(conditions, section.Condition)
+		}
+
+		if !slices.Contains(paths[section.Condition], section.Path) {
+			paths[section.Condition] = append(paths[section.Condition], section.Path)
+		}
+	}
+
+	findings := make([]*DoctorFinding, 0)
+	for _, condition := range conditions {
+		if len(paths[condition]) > 1 {
+			findings = append(findings, &DoctorFinding{
+				Check:    DoctorCheckIncludeConflict,
+				Severity: DoctorSeverityWarning,
+				Subject:  condition,
+				Paths:    paths[condition],
+			})
+		}
+	}
+
+	return findings, nil
+}
@@ -0,0 +1,266 @@
+package application_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/b4nd/git-profile/pkg/application"
+	"github.com/b4nd/git-profile/pkg/domain"
+
+	"github.com/jaswdr/faker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDoctorProfileServiceExecute(t *testing.T) {
+	faker := faker.New()
+
+	profile, err := domain.NewProfile(
+		faker.Internet().User(),
+		faker.Internet().Email(),
+		faker.Person().Name(),
+	)
+	assert.NoError(t, err)
+
+	version, err := domain.NewScmVersion("git version 2.39.5")
+	assert.NoError(t, err)
+
+	global := domain.NewProfileSource("/home/user/.gitprofile", domain.ProfileScopeGlobal)
+	local := domain.NewProfileSource("/repo/.gitprofile", domain.ProfileScopeLocal)
+
+	type mocks struct {
+		profileRepository       *MockProfileRepository
+		profileSourceRepository *MockProfileSourceRepository
+		versionRepository       *MockVersionRepository
+		userRepository          *MockUserRepository
+		globalUserRepository    *MockUserRepository
+		identityRepository      *MockIdentityRepository
+		includeRepository       *MockIncludeRepository
+	}
+
+	// newMocks returns mocks of a healthy configuration, each test replaces the expectation it checks
+	newMocks := func() *mocks {
+		m := &mocks{
+			&MockProfileRepository{},
+			&MockProfileSourceRepository{},
+			&MockVersionRepository{},
+			&MockUserRepository{},
+			&MockUserRepository{},
+			&MockIdentityRepository{},
+			&MockIncludeRepository{},
+		}
+
+		m.versionRepository.On("Get").Return(version, nil).Maybe()
+		m.profileSourceRepository.On("Reports").Return([]*domain.ProfileSourceReport{
+			{Source: global, Workspaces: []string{profile.Workspace().String()}},
+		}, nil).Maybe()
+		m.profileRepository.On("Get", profile.Workspace()).Return(profile, nil).Maybe()
+		m.userRepository.On("Get").Return(newScmUser(profile), nil).Maybe()
+		m.globalUserRepository.On("Get").Return(&domain.ScmUser{}, domain.ErrScmUserNotFound).Maybe()
+		m.identityRepository.On("Overrides").Return(map[string]string{}).Maybe()
+		m.includeRepository.On("List").Return([]*domain.ScmIncludeSection{}, nil).Maybe()
+
+		return m
+	}
+
+	newService := func(m *mocks) *application.DoctorProfileService {
+		return application.NewDoctorProfileService(
+			m.profileRepository,
+			m.profileSourceRepository,
+			m.versionRepository,
+			m.userRepository,
+			m.globalUserRepository,
+			m.identityRepository,
+			m.includeRepository,
+		)
+	}
+
+	t.Run("should find no problem in a healthy configuration", func(t *testing.T) {
+		m := newMocks()
+
+		findings, err := newService(m).Execute(application.DoctorProfileServiceParams{})
+
+		assert.NoError(t, err)
+		assert.Empty(t, findings)
+	})
+
+	t.Run("should report a missing or outdated git", func(t *testing.T) {
+		m := newMocks()
+		m.versionRepository.ExpectedCalls = nil
+		m.versionRepository.On("Get").Return(domain.ScmVersion{}, domain.ErrScmNotInstalled)
+
+		findings, err := newService(m).Execute(application.DoctorProfileServiceParams{})
+
+		assert.NoError(t, err)
+		assert.Len(t, findings, 1)
+		assert.Equal(t, application.DoctorCheckGitNotInstalled, findings[0].Check)
+		assert.Equal(t, application.DoctorSeverityError, findings[0].Severity)
+
+		old, err := domain.NewScmVersion("git version 2.30.1")
+		assert.NoError(t, err)
+
+		m = newMocks()
+		m.versionRepository.ExpectedCalls = nil
+		m.versionRepository.On("Get").Return(old, nil)
+
+		findings, err = newService(m).Execute(application.DoctorProfileServiceParams{})
+
+		assert.NoError(t, err)
+		assert.Len(t, findings, 1)
+		assert.Equal(t, application.DoctorCheckGitOutdated, findings[0].Check)
+		assert.Equal(t, application.DoctorSeverityWarning, findings[0].Severity)
+		assert.Equal(t, "2.30.1", findings[0].Subject)
+	})
+
+	t.Run("should report invalid, duplicated and shadowed profiles", func(t *testing.T) {
+		m := newMocks()
+		m.profileSourceRepository.ExpectedCalls = nil
+		m.profileSourceRepository.On("Reports").Return([]*domain.ProfileSourceReport{
+			{Source: global, Workspaces: []string{profile.Workspace().String(), "broken"}, Duplicates: []string{"broken"}},
+			{Source: local, Workspaces: []string{profile.Workspace().String()}},
+		}, nil)
+
+		broken, err := domain.NewProfileWorkspace("broken")
+		assert.NoError(t, err)
+		m.profileRepository.On("Get", broken).Return(&domain.Profile{}, domain.ErrProfileExtendsCycle)
+
+		findings, err := newService(m).Execute(application.DoctorProfileServiceParams{})
+
+		assert.NoError(t, err)
+		assert.Len(t, findings, 3)
+
+		assert.Equal(t, application.DoctorCheckProfileDuplicated, findings[0].Check)
+		assert.Equal(t, "broken", findings[0].Subject)
+
+		assert.Equal(t, application.DoctorCheckProfileInvalid, findings[1].Check)
+		assert.Equal(t, application.DoctorSeverityError, findings[1].Severity)
+		assert.ErrorIs(t, findings[1].Err, domain.ErrProfileExtendsCycle)
+
+		assert.Equal(t, application.DoctorCheckProfileShadowed, findings[2].Check)
+		assert.Equal(t, []string{local.Path(), global.Path()}, findings[2].Paths)
+	})
+
+	t.Run("should not resolve the profiles while a profile file is invalid", func(t *testing.T) {
+		m := newMocks()
+		m.profileSourceRepository.ExpectedCalls = nil
+		m.profileSourceRepository.On("Reports").Return([]*domain.ProfileSourceReport{
+			{Source: global, Workspaces: []string{profile.Workspace().String()}},
+			{Source: local, Err: errors.New("unclosed section")},
+		}, nil)
+
+		findings, err := newService(m).Execute(application.DoctorProfileServiceParams{Fix: true})
+
+		assert.NoError(t, err)
+		assert.Len(t, findings, 1)
+		assert.Equal(t, application.DoctorCheckSourceInvalid, findings[0].Check)
+		assert.Equal(t, local.Path(), findings[0].Subject)
+		assert.False(t, findings[0].Fixed)
+
+		m.profileRepository.AssertNotCalled(t, "Get", mock.Anything)
+		m.userRepository.AssertNotCalled(t, "Get")
+		m.userRepository.AssertNotCalled(t, "Save", mock.Anything)
+	})
+
+	t.Run("should fix an identity that does not match its profile", func(t *testing.T) {
+		user := newScmUser(profile)
+		user.Email = faker.Internet().Email()
+		user.CredentialHelper = "store"
+
+		m := newMocks()
+		m.userRepository.ExpectedCalls = nil
+		m.userRepository.On("Get").Return(user, nil)
+		m.userRepository.On("Save", mock.MatchedBy(func(saved *domain.ScmUser) bool {
+			return saved.Email == profile.Email().String() && saved.CredentialHelper == "store"
+		})).Return(nil)
+
+		findings, err := newService(m).Execute(application.DoctorProfileServiceParams{Fix: true})
+
+		assert.NoError(t, err)
+		assert.Len(t, findings, 1)
+		assert.Equal(t, application.DoctorCheckIdentityMismatch, findings[0].Check)
+		assert.Equal(t, domain.ProfileScopeLocal, findings[0].Scope)
+		assert.True(t, findings[0].Fixable)
+		assert.True(t, findings[0].Fixed)
+
+		m.userRepository.AssertExpectations(t)
+	})
+
+	t.Run("should remove a workspace whose profile was deleted", func(t *testing.T) {
+		user := domain.NewScmUser("deleted", profile.Email().String(), profile.Name().String())
+		deleted, err := domain.NewProfileWorkspace("deleted")
+		assert.NoError(t, err)
+
+		m := newMocks()
+		m.globalUserRepository.ExpectedCalls = nil
+		m.globalUserRepository.On("Get").Return(user, nil)
+		m.profileRepository.On("Get", deleted).Return(&domain.Profile{}, domain.ErrInvalidWorkspace)
+
+		findings, err := newService(m).Execute(application.DoctorProfileServiceParams{})
+
+		assert.NoError(t, err)
+		assert.Len(t, findings, 1)
+		assert.Equal(t, application.DoctorCheckWorkspaceNotExists, findings[0].Check)
+		assert.Equal(t, domain.ProfileScopeGlobal, findings[0].Scope)
+		assert.False(t, findings[0].Fixed)
+		m.globalUserRepository.AssertNotCalled(t, "Save", mock.Anything)
+
+		m.globalUserRepository.On("Save", mock.MatchedBy(func(saved *domain.ScmUser) bool {
+			return saved.Workespace == "" && saved.Email == profile.Email().String()
+		})).Return(nil)
+
+		findings, err = newService(m).Execute(application.DoctorProfileServiceParams{Fix: true})
+
+		assert.NoError(t, err)
+		assert.True(t, findings[0].Fixed)
+		m.globalUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("should report environment overrides and conflicting includes", func(t *testing.T) {
+		m := newMocks()
+		m.identityRepository.ExpectedCalls = nil
+		m.identityRepository.On("Overrides").Return(map[string]string{
+			"GIT_COMMITTER_NAME": "Bot",
+			"GIT_AUTHOR_EMAIL":   "bot@example.com",
+		})
+		m.includeRepository.ExpectedCalls = nil
+		m.includeRepository.On("List").Return([]*domain.ScmIncludeSection{
+			{Condition: "gitdir:~/work/", Path: "~/.gitconfig-work"},
+			{Condition: "gitdir:~/oss/", Path: "~/.gitconfig-oss"},
+			{Condition: "gitdir:~/work/", Path: "~/.gitconfig-work"},
+			{Condition: "gitdir:~/work/", Path: "~/.gitconfig-other"},
+		}, nil)
+
+		findings, err := newService(m).Execute(application.DoctorProfileServiceParams{Fix: true})
+
+		assert.NoError(t, err)
+		assert.Len(t, findings, 3)
+
+		assert.Equal(t, application.DoctorCheckEnvironmentOverride, findings[0].Check)
+		assert.Equal(t, "GIT_AUTHOR_EMAIL", findings[0].Subject)
+		assert.Equal(t, "GIT_COMMITTER_NAME", findings[1].Subject)
+
+		assert.Equal(t, application.DoctorCheckIncludeConflict, findings[2].Check)
+		assert.Equal(t, "gitdir:~/work/", findings[2].Subject)
+		assert.Equal(t, []string{"~/.gitconfig-work", "~/.gitconfig-other"}, findings[2].Paths)
+		assert.False(t, findings[2].Fixed)
+	})
+
+	t.Run("should return an error when the profile files cannot be listed", func(t *testing.T) {
+		m := newMocks()
+		m.profileSourceRepository.ExpectedCalls = nil
+		m.profileSourceRepository.On("Reports").Return([]*domain.ProfileSourceReport{}, errors.New("unexpected"))
+
+		findings, err := newService(m).Execute(application.DoctorProfileServiceParams{})
+
+		assert.Error(t, err)
+		assert.Nil(t, findings)
+	})
+}
+
+func newScmUser(profile *domain.Profile) *domain.ScmUser {
+	return domain.NewScmUser(
+		profile.Workspace().String(),
+		profile.Email().String(),
+		profile.Name().String(),
+	)
+}
@@ -0,0 +1,71 @@
+package application
+
+import (
+	"github.com/b4nd/git-profile/pkg/domain"
+)
+
+type GetCredentialService struct {
+	profileRepository       domain.ProfileRepository
+	scmIdentityRepository   domain.ScmIdentityRepository
+	scmCredentialRepository domain.ScmCredentialRepository
+}
+
+type GetCredentialServiceParams struct {
+	Credential *domain.ScmCredential
+	// Helper is the credential helper storing the secrets, none when it is empty
+	Helper string
+}
+
+func NewGetCredentialService(
+	profileRepository domain.ProfileRepository,
+	scmIdentityRepository domain.ScmIdentityRepository,
+	scmCredentialRepository domain.ScmCredentialRepository,
+) *GetCredentialService {
+	return &GetCredentialService{profileRepository, scmIdentityRepository, scmCredentialRepository}
+}
+
+// Execute answers the credential with the username of the active profile for
+// the host, the password is asked to the helper for that username
+func (gc *GetCredentialService) Execute(params GetCredentialServiceParams) (*domain.ScmCredential, error) {
+	credential := *params.Credential
+	if credential.Username == "" {
+		credential.Username = gc.username(credential.Host)
+	}
+
+	if params.Helper == "" {
+		return &domain.ScmCredential{Username: credential.Username}, nil
+	}
+
+	found, err := gc.scmCredentialRepository.Get(params.Helper, &credential)
+	if err != nil {
+		return nil, err
+	}
+
+	if found.Username == "" {
+		found.Username = credential.Username
+	}
+
+	return found, nil
+}
+
+// username returns the username of the active profile for the host, empty
+// when no profile is active or it has no username for the host
+func (gc *GetCredentialService) username(host string) string {
+	identity, err := gc.scmIdentityRepository.Get()
+	if err != nil || identity.Workspace == "" {
+		return ""
+	}
+
+	workspace, err := domain.NewProfileWorkspace(identity.Workspace)
+	if err != nil {
+		return ""
+	}
+
+	profile, err := gc.profileRepository.Get(workspace)
+	if err != nil {
+		return ""
+	}
+
+	username, _ := profile.Username(host)
+	return username
+}
@@ -0,0 +1,97 @@
+package application_test
+
+import (
+	"testing"
+
+	"github.com/b4nd/git-profile/pkg/application"
+	"github.com/b4nd/git-profile/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetCredentialServiceExecute(t *testing.T) {
+	profile, err := domain.NewProfile("work", "jane@acme.com", "Jane Doe")
+	assert.NoError(t, err)
+
+	username, err := domain.NewProfileUsername("GitHub.com", "jane-acme")
+	assert.NoError(t, err)
+
+	profile = profile.WithUsernames([]domain.ProfileUsername{username})
+
+	t.Run("should answer the username of the active profile and the password of the helper", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+		mockIdentityRepository := &MockIdentityRepository{}
+		mockCredentialRepository := &MockCredentialRepository{}
+
+		mockIdentityRepository.On("Get").Return(domain.NewScmIdentity("work", "jane@acme.com", "Jane Doe"), nil)
+		mockProfileRepository.On("Get", profile.Workspace()).Return(profile, nil)
+		mockCredentialRepository.On("Get", "cache", &domain.ScmCredential{Protocol: "https", Host: "github.com", Username: "jane-acme"}).
+			Return(&domain.ScmCredential{Password: "secret"}, nil)
+
+		service := application.NewGetCredentialService(mockProfileRepository, mockIdentityRepository, mockCredentialRepository)
+		credential, err := service.Execute(application.GetCredentialServiceParams{
+			Credential: &domain.ScmCredential{Protocol: "https", Host: "github.com"},
+			Helper:     "cache",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, &domain.ScmCredential{Username: "jane-acme", Password: "secret"}, credential)
+
+		mockProfileRepository.AssertExpectations(t)
+		mockIdentityRepository.AssertExpectations(t)
+		mockCredentialRepository.AssertExpectations(t)
+	})
+
+	t.Run("should keep the username given by git", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+		mockIdentityRepository := &MockIdentityRepository{}
+
+		service := application.NewGetCredentialService(mockProfileRepository, mockIdentityRepository, nil)
+		credential, err := service.Execute(application.GetCredentialServiceParams{
+			Credential: &domain.ScmCredential{Protocol: "https", Host: "github.com", Username: "jane"},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, &domain.ScmCredential{Username: "jane"}, credential)
+
+		mockIdentityRepository.AssertNotCalled(t, "Get")
+	})
+
+	t.Run("should answer nothing when no profile is active", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+		mockIdentityRepository := &MockIdentityRepository{}
+		mockCredentialRepository := &MockCredentialRepository{}
+
+		mockIdentityRepository.On("Get").Return(&domain.ScmIdentity{}, domain.ErrScmIdentityNotFound)
+		mockCredentialRepository.On("Get", "cache", mock.Anything).Return(&domain.ScmCredential{}, nil)
+
+		service := application.NewGetCredentialService(mockProfileRepository, mockIdentityRepository, mockCredentialRepository)
+		credential, err := service.Execute(application.GetCredentialServiceParams{
+			Credential: &domain.ScmCredential{Protocol: "https", Host: "gitlab.com"},
+			Helper:     "cache",
+		})
+
+		assert.NoError(t, err)
+		assert.True(t, credential.IsEmpty())
+
+		mockProfileRepository.AssertNotCalled(t, "Get", mock.Anything)
+	})
+
+	t.Run("should return an error when the helper fails", func(t *testing.T) {
+		mockIdentityRepository := &MockIdentityRepository{}
+		mockCredentialRepository := &MockCredentialRepository{}
+
+		mockIdentityRepository.On("Get").Return(domain.NewScmIdentity("", "jane@acme.com", "Jane Doe"), nil)
+		mockCredentialRepository.On("Get", "cache", mock.Anything).Return(&domain.ScmCredential{}, domain.ErrScmCredentialHelperFailed)
+
+		service := application.NewGetCredentialService(nil, mockIdentityRepository, mockCredentialRepository)
+		credential, err := service.Execute(application.GetCredentialServiceParams{
+			Credential: &domain.ScmCredential{Protocol: "https", Host: "github.com"},
+			Helper:     "cache",
+		})
+
+		assert.ErrorIs(t, err, domain.ErrScmCredentialHelperFailed)
+		assert.Nil(t, credential)
+	})
+}
@@ -1,6 +1,8 @@
 package application
 
 import (
+	"errors"
+
 	"github.com/b4nd/git-profile/pkg/domain"
 )
 
@@ -23,6 +25,10 @@ func (cp *GetProfileService) Execute(params GetProfileServiceParams) (*domain.Pr
 	}
 
 	profile, err := cp.profileRepository.Get(workspace)
+	if errors.Is(err, domain.ErrProfileExtendsCycle) || errors.Is(err, domain.ErrProfileParentNotExists) {
+		return nil, err
+	}
+
 	if err != nil {
 		return nil, ErrProfileNotExists
 	}
@@ -65,4 +65,25 @@ func TestGetProfileExecute(t *testing.T) {
 
 		mockProfileRepository.AssertExpectations(t)
 	})
+
+	t.Run("should return the error when the profile cannot be resolved", func(t *testing.T) {
+		for _, expected := range []error{domain.ErrProfileExtendsCycle, domain.ErrProfileParentNotExists} {
+			mockProfileRepository := &MockProfileRepository{}
+
+			workspace, err := domain.NewProfileWorkspace("client")
+			assert.NoError(t, err)
+
+			mockProfileRepository.On("Get", workspace).Return(&domain.Profile{}, expected)
+
+			getProfileService := application.NewGetProfileService(mockProfileRepository)
+			profile, err := getProfileService.Execute(application.GetProfileServiceParams{
+				Workspace: "client",
+			})
+
+			assert.ErrorIs(t, err, expected)
+			assert.Nil(t, profile)
+
+			mockProfileRepository.AssertExpectations(t)
+		}
+	})
 }
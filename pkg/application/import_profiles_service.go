@@ -0,0 +1,393 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/b4nd/git-profile/pkg/domain"
+)
+
+var ErrInvalidImportStrategy = errors.New("invalid import strategy")
+var ErrInvalidProfileRecord = errors.New("invalid profile record")
+
+// ImportStrategy tells what is done with a record whose workspace is already a profile
+type ImportStrategy string
+
+const (
+	ImportStrategySkip      ImportStrategy = "skip"
+	ImportStrategyOverwrite ImportStrategy = "overwrite"
+	ImportStrategyRename    ImportStrategy = "rename"
+)
+
+// ImportAction is what was done with a record
+type ImportAction string
+
+const (
+	ImportActionCreated     ImportAction = "created"
+	ImportActionOverwritten ImportAction = "overwritten"
+	ImportActionRenamed     ImportAction = "renamed"
+	ImportActionSkipped     ImportAction = "skipped"
+)
+
+// importPersonalDomains are the email providers whose identities are proposed as the personal workspace
+var importPersonalDomains = []string{
+	"gmail.com", "googlemail.com", "outlook.com", "hotmail.com", "live.com",
+	"yahoo.com", "icloud.com", "me.com", "proton.me", "protonmail.com",
+}
+
+const importPersonalWorkspace = "personal"
+const importDefaultWorkspace = "profile"
+
+var importInvalidWorkspaceCharacters = regexp.MustCompile(`[^a-z0-9_\.-]+`)
+
+// ImportProfileCandidate is an identity of the git configuration that can be
+// imported as a profile under the proposed workspace
+type ImportProfileCandidate struct {
+	// Source is the configuration file that defines the identity
+	Source string
+	Params CreateProfileServiceParams
+}
+
+type ImportProfilesServiceParams struct {
+	Records  []CreateProfileServiceParams
+	Strategy ImportStrategy
+}
+
+// ImportProfileResult is the outcome of a record, the workspace of the
+// profile differs from the record one when it was renamed
+type ImportProfileResult struct {
+	Workspace string
+	Profile   *domain.Profile
+	Action    ImportAction
+}
+
+type ImportProfilesService struct {
+	profileRepository    domain.ProfileRepository
+	scmConfigRepository  domain.ScmConfigRepository
+	createProfileService *CreateProfileService
+}
+
+func NewImportProfilesService(
+	profileRepository domain.ProfileRepository,
+	scmConfigRepository domain.ScmConfigRepository,
+	createProfileService *CreateProfileService,
+) *ImportProfilesService {
+	return &ImportProfilesService{
+		profileRepository,
+		scmConfigRepository,
+		createProfileService,
+	}
+}
+
+// Candidates returns the distinct identities of the git configuration that
+// are not already a profile, each one with a free workspace proposed
+func (ip *ImportProfilesService) Candidates() ([]*ImportProfileCandidate, error) {
+	identities, err := ip.scmConfigRepository.List()
+	if err != nil {
+		return nil, err
+	}
+
+	profiles, err := ip.profileRepository.List()
+	if err != nil {
+		return nil, err
+	}
+
+	taken := map[string]bool{}
+	seen := map[string]bool{}
+	for _, profile := range profiles {
+		taken[profile.Workspace().String()] = true
+		seen[importIdentityKey(profile.Email().String(), profile.Name().String())] = true
+	}
+
+	candidates := make([]*ImportProfileCandidate, 0)
+	for _, identity := range identities {
+		user := identity.User
+
+		// The identities that cannot be a profile are left out
+		email, err := domain.NewProfileEmail(user.Email)
+		if err != nil {
+			continue
+		}
+
+		name, err := domain.NewProfileName(user.Name)
+		if err != nil {
+			continue
+		}
+
+		key := importIdentityKey(email.String(), name.String())
+		if seen[key] {
+			continue
+		}
+
+		seen[key] = true
+
+		params := CreateProfileServiceParams{
+			Email:  email.String(),
+			Name:   name.String(),
+			SshKey: domain.SshCommandKey(user.SshCommand),
+		}
+
+		if _, err := domain.NewProfileSigning(user.SigningKey, user.SigningFormat, user.CommitGpgSign, user.TagGpgSign); err == nil {
+			params.SigningKey = user.SigningKey
+			params.SigningFormat = user.SigningFormat
+			params.CommitSign = user.CommitGpgSign
+			params.TagSign = user.TagGpgSign
+		}
+
+		params.Workspace = proposeImportWorkspace(user.Workespace, email.String(), taken)
+		taken[params.Workspace] = true
+
+		candidates = append(candidates, &ImportProfileCandidate{
+			Source: identity.Source,
+			Params: params,
+		})
+	}
+
+	return candidates, nil
+}
+
+// Execute creates the profile of the candidate
+func (ip *ImportProfilesService) Execute(candidate ImportProfileCandidate) (*domain.Profile, error) {
+	return ip.createProfileService.Execute(candidate.Params)
+}
+
+// Import validates every record and then saves the profiles at once, nothing
+// is saved when a record is invalid. A record that extends another profile
+// inherits the fields it leaves empty from the record of that workspace, or
+// from the existing profile when the record is skipped or not imported
+func (ip *ImportProfilesService) Import(params ImportProfilesServiceParams) ([]*ImportProfileResult, error) {
+	strategy := params.Strategy
+	if strategy == "" {
+		strategy = ImportStrategySkip
+	}
+
+	if strategy != ImportStrategySkip && strategy != ImportStrategyOverwrite && strategy != ImportStrategyRename {
+		return nil, ErrInvalidImportStrategy
+	}
+
+	profiles, err := ip.profileRepository.List()
+	if err != nil {
+		return nil, err
+	}
+
+	existing := map[string]*domain.Profile{}
+	taken := map[string]bool{}
+	for _, profile := range profiles {
+		existing[profile.Workspace().String()] = profile
+		taken[profile.Workspace().String()] = true
+	}
+
+	indexes := map[string]int{}
+	for i, record := range params.Records {
+		if _, ok := indexes[record.Workspace]; !ok {
+			indexes[record.Workspace] = i
+		}
+	}
+
+	results := make([]*ImportProfileResult, len(params.Records))
+	save := make([]*domain.Profile, 0, len(params.Records))
+	visiting := map[int]bool{}
+
+	// The parents are imported before the records extending them, so they are
+	// saved first and the fields equal to theirs are left inherited
+	var importRecord func(i int) (*domain.Profile, error)
+	importRecord = func(i int) (*domain.Profile, error) {
+		invalid := func(err error) error {
+			return fmt.Errorf("%w %d: %w", ErrInvalidProfileRecord, i+1, err)
+		}
+
+		if result := results[i]; result != nil {
+			if result.Action == ImportActionSkipped {
+				return existing[result.Workspace], nil
+			}
+
+			return result.Profile, nil
+		}
+
+		if visiting[i] {
+			return nil, invalid(domain.ErrProfileExtendsCycle)
+		}
+
+		visiting[i] = true
+		record := params.Records[i]
+
+		if record.Extends != "" {
+			var parent *domain.Profile
+			if j, ok := indexes[record.Extends]; ok {
+				p, err := importRecord(j)
+				if err != nil {
+					return nil, err
+				}
+
+				parent = p
+			} else if p, ok := existing[record.Extends]; ok {
+				parent = p
+			} else {
+				return nil, invalid(domain.ErrProfileParentNotExists)
+			}
+
+			record = inheritImportRecord(record, parent)
+		}
+
+		profile, err := newProfileFromParams(record)
+		if err != nil {
+			return nil, invalid(err)
+		}
+
+		result := &ImportProfileResult{
+			Workspace: profile.Workspace().String(),
+			Profile:   profile,
+			Action:    ImportActionCreated,
+		}
+
+		if taken[result.Workspace] {
+			switch strategy {
+			case ImportStrategySkip:
+				result.Action = ImportActionSkipped
+			case ImportStrategyOverwrite:
+				result.Action = ImportActionOverwritten
+			case ImportStrategyRename:
+				record.Workspace = freeImportWorkspace(result.Workspace, taken)
+				if profile, err = newProfileFromParams(record); err != nil {
+					return nil, invalid(err)
+				}
+
+				result.Profile = profile
+				result.Action = ImportActionRenamed
+			}
+		}
+
+		results[i] = result
+		if result.Action == ImportActionSkipped {
+			return existing[result.Workspace], nil
+		}
+
+		taken[profile.Workspace().String()] = true
+		save = append(save, profile)
+
+		return profile, nil
+	}
+
+	for i := range params.Records {
+		if _, err := importRecord(i); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(save) > 0 {
+		if err := ip.profileRepository.SaveAll(save); err != nil {
+			return nil, err
+		}
+	}
+
+	return results, nil
+}
+
+// inheritImportRecord fills the fields the record leaves empty with the ones
+// of the parent, the git configuration keys of the record override the parent ones
+func inheritImportRecord(record CreateProfileServiceParams, parent *domain.Profile) CreateProfileServiceParams {
+	record.Extends = parent.Workspace().String()
+
+	if record.Email == "" {
+		record.Email = parent.Email().String()
+	}
+
+	if record.Name == "" {
+		record.Name = parent.Name().String()
+	}
+
+	if signing := parent.Signing(); record.SigningKey == "" && !signing.IsEmpty() {
+		record.SigningKey = signing.Key()
+		record.SigningFormat = signing.Format()
+		record.CommitSign = signing.CommitSign()
+		record.TagSign = signing.TagSign()
+	}
+
+	if record.SshKey == "" {
+		record.SshKey = parent.SshKey().String()
+	}
+
+	if len(record.Remotes) == 0 {
+		for _, remote := range parent.Remotes() {
+			record.Remotes = append(record.Remotes, remote.String())
+		}
+	}
+
+	if len(record.Usernames) == 0 {
+		for _, username := range parent.Usernames() {
+			if record.Usernames == nil {
+				record.Usernames = map[string]string{}
+			}
+
+			record.Usernames[username.Host()] = username.Username()
+		}
+	}
+
+	if config := parent.Config(); !config.IsEmpty() {
+		values := config.Values()
+		for key, value := range record.Config {
+			values[key] = value
+		}
+
+		record.Config = values
+	}
+
+	return record
+}
+
+func importIdentityKey(email string, name string) string {
+	return strings.ToLower(email) + "\x00" + name
+}
+
+// proposeImportWorkspace proposes the workspace written by git profile or one
+// named after the email domain, a number is added when it is already taken
+func proposeImportWorkspace(workspace string, email string, taken map[string]bool) string {
+	base := ""
+	if value, err := domain.NewProfileWorkspace(workspace); err == nil && workspace != domain.NotConfiguredWorkspace {
+		base = value.String()
+	}
+
+	if base == "" {
+		base = importWorkspaceFromEmail(email)
+	}
+
+	return freeImportWorkspace(base, taken)
+}
+
+// freeImportWorkspace adds a number to the workspace until it is not taken
+func freeImportWorkspace(base string, taken map[string]bool) string {
+	proposal := base
+	for i := 2; taken[proposal]; i++ {
+		proposal = base + "-" + strconv.Itoa(i)
+	}
+
+	return proposal
+}
+
+// importWorkspaceFromEmail returns the first label of the email domain,
+// or the personal workspace for the public email providers
+func importWorkspaceFromEmail(email string) string {
+	_, host, _ := strings.Cut(strings.ToLower(email), "@")
+
+	for _, personal := range importPersonalDomains {
+		if host == personal {
+			return importPersonalWorkspace
+		}
+	}
+
+	// users.noreply.github.com is proposed as github
+	if strings.HasSuffix(host, ".noreply.github.com") {
+		return "github"
+	}
+
+	label, _, _ := strings.Cut(host, ".")
+	label = strings.Trim(importInvalidWorkspaceCharacters.ReplaceAllString(label, "-"), "-")
+	if label == "" {
+		return importDefaultWorkspace
+	}
+
+	return label
+}
@@ -0,0 +1,274 @@
+package application_test
+
+import (
+	"testing"
+
+	"github.com/b4nd/git-profile/pkg/application"
+	"github.com/b4nd/git-profile/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestImportProfilesServiceCandidates(t *testing.T) {
+	work := domain.NewScmUser("", "jane@acme.com", "Jane Doe")
+	work.SigningKey = "ABCDEF0123456789"
+	work.CommitGpgSign = true
+	work.SshCommand = "ssh -i ~/.ssh/id_acme -o IdentitiesOnly=yes"
+
+	personal := domain.NewScmUser("", "jane@gmail.com", "Jane Doe")
+	managed := domain.NewScmUser("oss", "jane@oss.dev", "Jane Doe")
+	other := domain.NewScmUser("", "jane@acme.io", "Jane Doe")
+
+	identities := []*domain.ScmConfigIdentity{
+		domain.NewScmConfigIdentity("/home/jane/.gitconfig", personal),
+		domain.NewScmConfigIdentity("/home/jane/work.gitconfig", work),
+		domain.NewScmConfigIdentity("/home/jane/copy.gitconfig", domain.NewScmUser("", "JANE@acme.com", "Jane Doe")),
+		domain.NewScmConfigIdentity("/home/jane/oss.gitconfig", managed),
+		domain.NewScmConfigIdentity("/home/jane/io.gitconfig", other),
+		domain.NewScmConfigIdentity("/home/jane/invalid.gitconfig", domain.NewScmUser("", "not an email", "Jane Doe")),
+	}
+
+	t.Run("should propose the distinct identities that are not profiles", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+		mockConfigRepository := &MockConfigRepository{}
+
+		existing, err := domain.NewProfile("acme", "jane@acme.io", "Jane Doe")
+		assert.NoError(t, err)
+
+		mockConfigRepository.On("List").Return(identities, nil)
+		mockProfileRepository.On("List").Return([]*domain.Profile{existing}, nil)
+
+		createProfileService := application.NewCreateProfileService(mockProfileRepository)
+		importProfilesService := application.NewImportProfilesService(mockProfileRepository, mockConfigRepository, createProfileService)
+		candidates, err := importProfilesService.Candidates()
+
+		assert.NoError(t, err)
+		assert.Equal(t, []*application.ImportProfileCandidate{
+			{
+				Source: "/home/jane/.gitconfig",
+				Params: application.CreateProfileServiceParams{Workspace: "personal", Email: "jane@gmail.com", Name: "Jane Doe"},
+			},
+			{
+				Source: "/home/jane/work.gitconfig",
+				Params: application.CreateProfileServiceParams{
+					Workspace:  "acme-2",
+					Email:      "jane@acme.com",
+					Name:       "Jane Doe",
+					SigningKey: "ABCDEF0123456789",
+					CommitSign: true,
+					SshKey:     "~/.ssh/id_acme",
+				},
+			},
+			{
+				Source: "/home/jane/oss.gitconfig",
+				Params: application.CreateProfileServiceParams{Workspace: "oss", Email: "jane@oss.dev", Name: "Jane Doe"},
+			},
+		}, candidates)
+
+		mockProfileRepository.AssertExpectations(t)
+		mockConfigRepository.AssertExpectations(t)
+	})
+
+	t.Run("should return an error when the configuration cannot be read", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+		mockConfigRepository := &MockConfigRepository{}
+
+		mockConfigRepository.On("List").Return([]*domain.ScmConfigIdentity{}, assert.AnError)
+
+		createProfileService := application.NewCreateProfileService(mockProfileRepository)
+		importProfilesService := application.NewImportProfilesService(mockProfileRepository, mockConfigRepository, createProfileService)
+		candidates, err := importProfilesService.Candidates()
+
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Nil(t, candidates)
+
+		mockProfileRepository.AssertExpectations(t)
+		mockConfigRepository.AssertExpectations(t)
+	})
+}
+
+func TestImportProfilesServiceExecute(t *testing.T) {
+	t.Run("should create the profile of the candidate", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+		mockConfigRepository := &MockConfigRepository{}
+
+		candidate := application.ImportProfileCandidate{
+			Source: "/home/jane/.gitconfig",
+			Params: application.CreateProfileServiceParams{Workspace: "personal", Email: "jane@gmail.com", Name: "Jane Doe"},
+		}
+
+		workspace, err := domain.NewProfileWorkspace("personal")
+		assert.NoError(t, err)
+
+		mockProfileRepository.On("Get", workspace).Return(&domain.Profile{}, domain.ErrInvalidWorkspace)
+		mockProfileRepository.On("Save", mock.Anything).Return(nil)
+
+		createProfileService := application.NewCreateProfileService(mockProfileRepository)
+		importProfilesService := application.NewImportProfilesService(mockProfileRepository, mockConfigRepository, createProfileService)
+		profile, err := importProfilesService.Execute(candidate)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "personal", profile.Workspace().String())
+		assert.Equal(t, "jane@gmail.com", profile.Email().String())
+
+		mockProfileRepository.AssertExpectations(t)
+		mockConfigRepository.AssertExpectations(t)
+	})
+}
+
+func TestImportProfilesServiceImport(t *testing.T) {
+	existing, err := domain.NewProfile("work", "jane@acme.com", "Jane Doe")
+	assert.NoError(t, err)
+
+	records := []application.CreateProfileServiceParams{
+		{Workspace: "work", Email: "jane@acme.io", Name: "Jane Doe"},
+		{Workspace: "personal", Email: "jane@gmail.com", Name: "Jane Doe", SshKey: "~/.ssh/id_personal"},
+	}
+
+	newProfile := func(workspace string, email string) *domain.Profile {
+		profile, err := domain.NewProfile(workspace, email, "Jane Doe")
+		assert.NoError(t, err)
+
+		return profile
+	}
+
+	personal := newProfile("personal", "jane@gmail.com")
+	sshKey, err := domain.NewProfileSshKey("~/.ssh/id_personal")
+	assert.NoError(t, err)
+	personal = personal.WithSshKey(sshKey)
+
+	tests := []struct {
+		strategy application.ImportStrategy
+		saved    []*domain.Profile
+		actions  []application.ImportAction
+	}{
+		{application.ImportStrategySkip, []*domain.Profile{personal}, []application.ImportAction{application.ImportActionSkipped, application.ImportActionCreated}},
+		{application.ImportStrategyOverwrite, []*domain.Profile{newProfile("work", "jane@acme.io"), personal}, []application.ImportAction{application.ImportActionOverwritten, application.ImportActionCreated}},
+		{application.ImportStrategyRename, []*domain.Profile{newProfile("work-2", "jane@acme.io"), personal}, []application.ImportAction{application.ImportActionRenamed, application.ImportActionCreated}},
+	}
+
+	for _, test := range tests {
+		t.Run("should save the records with the "+string(test.strategy)+" strategy", func(t *testing.T) {
+			mockProfileRepository := &MockProfileRepository{}
+			mockConfigRepository := &MockConfigRepository{}
+
+			mockProfileRepository.On("List").Return([]*domain.Profile{existing}, nil)
+			mockProfileRepository.On("SaveAll", test.saved).Return(nil)
+
+			createProfileService := application.NewCreateProfileService(mockProfileRepository)
+			importProfilesService := application.NewImportProfilesService(mockProfileRepository, mockConfigRepository, createProfileService)
+			results, err := importProfilesService.Import(application.ImportProfilesServiceParams{
+				Records:  records,
+				Strategy: test.strategy,
+			})
+
+			assert.NoError(t, err)
+			assert.Len(t, results, 2)
+			assert.Equal(t, "work", results[0].Workspace)
+			assert.Equal(t, test.actions, []application.ImportAction{results[0].Action, results[1].Action})
+
+			mockProfileRepository.AssertExpectations(t)
+		})
+	}
+
+	t.Run("should not save anything when a record is invalid", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+		mockConfigRepository := &MockConfigRepository{}
+
+		mockProfileRepository.On("List").Return([]*domain.Profile{existing}, nil)
+
+		createProfileService := application.NewCreateProfileService(mockProfileRepository)
+		importProfilesService := application.NewImportProfilesService(mockProfileRepository, mockConfigRepository, createProfileService)
+		results, err := importProfilesService.Import(application.ImportProfilesServiceParams{
+			Records: append(records, application.CreateProfileServiceParams{Workspace: "invalid", Email: "not an email", Name: "Jane Doe"}),
+		})
+
+		assert.ErrorIs(t, err, application.ErrInvalidProfileRecord)
+		assert.ErrorIs(t, err, domain.ErrInvalidEmail)
+		assert.Contains(t, err.Error(), "record 3")
+		assert.Nil(t, results)
+
+		mockProfileRepository.AssertExpectations(t)
+		mockProfileRepository.AssertNotCalled(t, "SaveAll", mock.Anything)
+	})
+
+	t.Run("should inherit the fields left empty from the extended profile", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+		mockConfigRepository := &MockConfigRepository{}
+
+		// The child comes first, its parent is a record and the parent of the parent is skipped
+		extending := []application.CreateProfileServiceParams{
+			{Workspace: "client", Extends: "acme", Email: "jane@client.com"},
+			{Workspace: "acme", Extends: "work", SshKey: "~/.ssh/id_acme"},
+			{Workspace: "work", Email: "jane@acme.io", Name: "Jane Doe"},
+		}
+
+		var saved []*domain.Profile
+		mockProfileRepository.On("List").Return([]*domain.Profile{existing}, nil)
+		mockProfileRepository.On("SaveAll", mock.Anything).Run(func(args mock.Arguments) {
+			saved = args.Get(0).([]*domain.Profile)
+		}).Return(nil)
+
+		createProfileService := application.NewCreateProfileService(mockProfileRepository)
+		importProfilesService := application.NewImportProfilesService(mockProfileRepository, mockConfigRepository, createProfileService)
+		results, err := importProfilesService.Import(application.ImportProfilesServiceParams{Records: extending})
+
+		assert.NoError(t, err)
+		assert.Equal(t, application.ImportActionSkipped, results[2].Action)
+		assert.Len(t, saved, 2)
+
+		// The parents are saved before the profiles extending them
+		assert.Equal(t, "acme", saved[0].Workspace().String())
+		assert.Equal(t, "work", saved[0].Extends().String())
+		assert.Equal(t, "jane@acme.com", saved[0].Email().String())
+		assert.Equal(t, "client", saved[1].Workspace().String())
+		assert.Equal(t, "acme", saved[1].Extends().String())
+		assert.Equal(t, "jane@client.com", saved[1].Email().String())
+		assert.Equal(t, "Jane Doe", saved[1].Name().String())
+		assert.Equal(t, "~/.ssh/id_acme", saved[1].SshKey().String())
+	})
+
+	t.Run("should return an error when a record extends a missing or its own profile", func(t *testing.T) {
+		tests := []struct {
+			records []application.CreateProfileServiceParams
+			err     error
+		}{
+			{[]application.CreateProfileServiceParams{{Workspace: "client", Extends: "missing"}}, domain.ErrProfileParentNotExists},
+			{[]application.CreateProfileServiceParams{{Workspace: "a", Extends: "b"}, {Workspace: "b", Extends: "a"}}, domain.ErrProfileExtendsCycle},
+		}
+
+		for _, test := range tests {
+			mockProfileRepository := &MockProfileRepository{}
+			mockConfigRepository := &MockConfigRepository{}
+
+			mockProfileRepository.On("List").Return([]*domain.Profile{existing}, nil)
+
+			createProfileService := application.NewCreateProfileService(mockProfileRepository)
+			importProfilesService := application.NewImportProfilesService(mockProfileRepository, mockConfigRepository, createProfileService)
+			results, err := importProfilesService.Import(application.ImportProfilesServiceParams{Records: test.records})
+
+			assert.ErrorIs(t, err, application.ErrInvalidProfileRecord)
+			assert.ErrorIs(t, err, test.err)
+			assert.Nil(t, results)
+			mockProfileRepository.AssertNotCalled(t, "SaveAll", mock.Anything)
+		}
+	})
+
+	t.Run("should return an error when the strategy is invalid", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+		mockConfigRepository := &MockConfigRepository{}
+
+		createProfileService := application.NewCreateProfileService(mockProfileRepository)
+		importProfilesService := application.NewImportProfilesService(mockProfileRepository, mockConfigRepository, createProfileService)
+		results, err := importProfilesService.Import(application.ImportProfilesServiceParams{
+			Records:  records,
+			Strategy: "merge",
+		})
+
+		assert.ErrorIs(t, err, application.ErrInvalidImportStrategy)
+		assert.Nil(t, results)
+
+		mockProfileRepository.AssertExpectations(t)
+	})
+}
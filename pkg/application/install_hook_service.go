@@ -0,0 +1,21 @@
+package application
+
+import (
+	"github.com/b4nd/git-profile/pkg/domain"
+)
+
+type InstallHookService struct {
+	scmHookRepository domain.ScmHookRepository
+}
+
+type InstallHookServiceParams struct {
+	Hook string
+}
+
+func NewInstallHookService(scmHookRepository domain.ScmHookRepository) *InstallHookService {
+	return &InstallHookService{scmHookRepository}
+}
+
+func (ih *InstallHookService) Execute(params InstallHookServiceParams) error {
+	return ih.scmHookRepository.Install(params.Hook)
+}
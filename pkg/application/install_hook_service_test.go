@@ -0,0 +1,40 @@
+package application_test
+
+import (
+	"testing"
+
+	"github.com/b4nd/git-profile/pkg/application"
+	"github.com/b4nd/git-profile/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInstallHookServiceExecute(t *testing.T) {
+	params := application.InstallHookServiceParams{
+		Hook: domain.ScmHookPreCommit,
+	}
+
+	t.Run("should install the hook", func(t *testing.T) {
+		mockHookRepository := &MockHookRepository{}
+		mockHookRepository.On("Install", domain.ScmHookPreCommit).Return(nil)
+
+		installHookService := application.NewInstallHookService(mockHookRepository)
+		err := installHookService.Execute(params)
+
+		assert.NoError(t, err)
+
+		mockHookRepository.AssertExpectations(t)
+	})
+
+	t.Run("should return an error when the hook cannot be installed", func(t *testing.T) {
+		mockHookRepository := &MockHookRepository{}
+		mockHookRepository.On("Install", domain.ScmHookPreCommit).Return(assert.AnError)
+
+		installHookService := application.NewInstallHookService(mockHookRepository)
+		err := installHookService.Execute(params)
+
+		assert.ErrorIs(t, err, assert.AnError)
+
+		mockHookRepository.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,22 @@
+package application
+
+import "github.com/b4nd/git-profile/pkg/domain"
+
+type ListProfileRuleService struct {
+	profileRuleRepository domain.ProfileRuleRepository
+}
+
+func NewListProfileRuleService(
+	profileRuleRepository domain.ProfileRuleRepository,
+) *ListProfileRuleService {
+	return &ListProfileRuleService{profileRuleRepository}
+}
+
+func (lr *ListProfileRuleService) Execute() ([]*domain.ProfileRule, error) {
+	rules, err := lr.profileRuleRepository.List()
+	if err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
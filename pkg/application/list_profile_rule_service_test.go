@@ -0,0 +1,45 @@
+package application_test
+
+import (
+	"testing"
+
+	"github.com/b4nd/git-profile/pkg/application"
+	"github.com/b4nd/git-profile/pkg/domain"
+
+	"github.com/jaswdr/faker"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListProfileRuleServiceExecute(t *testing.T) {
+	faker := faker.New()
+
+	t.Run("should return the rules", func(t *testing.T) {
+		mockProfileRuleRepository := &MockProfileRuleRepository{}
+
+		rule, err := domain.NewProfileRule(faker.Internet().User(), "gitdir:~/work/")
+		assert.NoError(t, err)
+
+		mockProfileRuleRepository.On("List").Return([]*domain.ProfileRule{rule}, nil)
+
+		listProfileRuleService := application.NewListProfileRuleService(mockProfileRuleRepository)
+		rules, err := listProfileRuleService.Execute()
+
+		assert.NoError(t, err)
+		assert.Equal(t, []*domain.ProfileRule{rule}, rules)
+
+		mockProfileRuleRepository.AssertExpectations(t)
+	})
+
+	t.Run("should return error when the repository fails", func(t *testing.T) {
+		mockProfileRuleRepository := &MockProfileRuleRepository{}
+		mockProfileRuleRepository.On("List").Return([]*domain.ProfileRule{}, assert.AnError)
+
+		listProfileRuleService := application.NewListProfileRuleService(mockProfileRuleRepository)
+		rules, err := listProfileRuleService.Execute()
+
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Nil(t, rules)
+
+		mockProfileRuleRepository.AssertExpectations(t)
+	})
+}
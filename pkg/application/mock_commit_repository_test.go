@@ -15,7 +15,35 @@ func (m *MockCommitRepository) Get(hash *domain.ScmCommitHash) (*domain.ScmCommi
 	return args.Get(0).(*domain.ScmCommit), args.Error(1)
 }
 
-func (m *MockCommitRepository) Save(author *domain.ScmCommitAuthor) error {
-	args := m.Called(author)
+func (m *MockCommitRepository) Save(amend *domain.ScmCommitAmend) error {
+	args := m.Called(amend)
 	return args.Error(0)
 }
+
+func (m *MockCommitRepository) List(tip *domain.ScmCommitHash, exclude []domain.ScmCommitHash) ([]*domain.ScmCommit, error) {
+	args := m.Called(tip, exclude)
+	return args.Get(0).([]*domain.ScmCommit), args.Error(1)
+}
+
+func (m *MockCommitRepository) IsPushed(hash *domain.ScmCommitHash) (bool, error) {
+	args := m.Called(hash)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCommitRepository) Rewrite(commits []*domain.ScmCommit, targets []domain.ScmCommitHash, amend *domain.ScmCommitAmend) ([]*domain.ScmCommitRewrite, error) {
+	args := m.Called(commits, targets, amend)
+	return args.Get(0).([]*domain.ScmCommitRewrite), args.Error(1)
+}
+
+func (m *MockCommitRepository) Walk(tip *domain.ScmCommitHash, exclude []domain.ScmCommitHash, visit func(record *domain.ScmCommitRecord) error) error {
+	args := m.Called(tip, exclude, visit)
+	if records, ok := args.Get(0).([]*domain.ScmCommitRecord); ok {
+		for _, record := range records {
+			if err := visit(record); err != nil {
+				return err
+			}
+		}
+	}
+
+	return args.Error(1)
+}
@@ -0,0 +1,16 @@
+package application_test
+
+import (
+	"github.com/b4nd/git-profile/pkg/domain"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockConfigRepository struct {
+	mock.Mock
+}
+
+func (m *MockConfigRepository) List() ([]*domain.ScmConfigIdentity, error) {
+	args := m.Called()
+	return args.Get(0).([]*domain.ScmConfigIdentity), args.Error(1)
+}
@@ -0,0 +1,26 @@
+package application_test
+
+import (
+	"github.com/b4nd/git-profile/pkg/domain"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCredentialRepository struct {
+	mock.Mock
+}
+
+func (m *MockCredentialRepository) Get(helper string, credential *domain.ScmCredential) (*domain.ScmCredential, error) {
+	args := m.Called(helper, credential)
+	return args.Get(0).(*domain.ScmCredential), args.Error(1)
+}
+
+func (m *MockCredentialRepository) Store(helper string, credential *domain.ScmCredential) error {
+	args := m.Called(helper, credential)
+	return args.Error(0)
+}
+
+func (m *MockCredentialRepository) Erase(helper string, credential *domain.ScmCredential) error {
+	args := m.Called(helper, credential)
+	return args.Error(0)
+}
@@ -0,0 +1,24 @@
+package application_test
+
+import (
+	"github.com/stretchr/testify/mock"
+)
+
+type MockHookRepository struct {
+	mock.Mock
+}
+
+func (m *MockHookRepository) Install(hook string) error {
+	args := m.Called(hook)
+	return args.Error(0)
+}
+
+func (m *MockHookRepository) Uninstall(hook string) error {
+	args := m.Called(hook)
+	return args.Error(0)
+}
+
+func (m *MockHookRepository) IsInstalled(hook string) (bool, error) {
+	args := m.Called(hook)
+	return args.Bool(0), args.Error(1)
+}
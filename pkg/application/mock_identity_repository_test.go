@@ -0,0 +1,21 @@
+package application_test
+
+import (
+	"github.com/b4nd/git-profile/pkg/domain"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockIdentityRepository struct {
+	mock.Mock
+}
+
+func (m *MockIdentityRepository) Get() (*domain.ScmIdentity, error) {
+	args := m.Called()
+	return args.Get(0).(*domain.ScmIdentity), args.Error(1)
+}
+
+func (m *MockIdentityRepository) Overrides() map[string]string {
+	args := m.Called()
+	return args.Get(0).(map[string]string)
+}
@@ -0,0 +1,21 @@
+package application_test
+
+import (
+	"github.com/b4nd/git-profile/pkg/domain"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockIncludeRepository struct {
+	mock.Mock
+}
+
+func (m *MockIncludeRepository) Save(includes []*domain.ScmInclude) error {
+	args := m.Called(includes)
+	return args.Error(0)
+}
+
+func (m *MockIncludeRepository) List() ([]*domain.ScmIncludeSection, error) {
+	args := m.Called()
+	return args.Get(0).([]*domain.ScmIncludeSection), args.Error(1)
+}
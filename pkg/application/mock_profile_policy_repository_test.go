@@ -0,0 +1,16 @@
+package application_test
+
+import (
+	"github.com/b4nd/git-profile/pkg/domain"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockProfilePolicyRepository struct {
+	mock.Mock
+}
+
+func (m *MockProfilePolicyRepository) Get(path string) (*domain.ProfilePolicy, error) {
+	args := m.Called(path)
+	return args.Get(0).(*domain.ProfilePolicy), args.Error(1)
+}
@@ -20,11 +20,21 @@ func (m *MockProfileRepository) Save(profile *domain.Profile) error {
 	return args.Error(0)
 }
 
+func (m *MockProfileRepository) SaveAll(profiles []*domain.Profile) error {
+	args := m.Called(profiles)
+	return args.Error(0)
+}
+
 func (m *MockProfileRepository) Delete(workspace domain.ProfileWorkspace) error {
 	args := m.Called(workspace)
 	return args.Error(0)
 }
 
+func (m *MockProfileRepository) Rename(workspace domain.ProfileWorkspace, newWorkspace domain.ProfileWorkspace) error {
+	args := m.Called(workspace, newWorkspace)
+	return args.Error(0)
+}
+
 func (m *MockProfileRepository) List() ([]*domain.Profile, error) {
 	args := m.Called()
 	return args.Get(0).([]*domain.Profile), args.Error(1)
@@ -0,0 +1,26 @@
+package application_test
+
+import (
+	"github.com/b4nd/git-profile/pkg/domain"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockProfileRuleRepository struct {
+	mock.Mock
+}
+
+func (m *MockProfileRuleRepository) List() ([]*domain.ProfileRule, error) {
+	args := m.Called()
+	return args.Get(0).([]*domain.ProfileRule), args.Error(1)
+}
+
+func (m *MockProfileRuleRepository) Save(rule *domain.ProfileRule) error {
+	args := m.Called(rule)
+	return args.Error(0)
+}
+
+func (m *MockProfileRuleRepository) Delete(condition domain.ProfileRuleCondition) error {
+	args := m.Called(condition)
+	return args.Error(0)
+}
@@ -0,0 +1,16 @@
+package application_test
+
+import (
+	"github.com/b4nd/git-profile/pkg/domain"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockProfileSourceRepository struct {
+	mock.Mock
+}
+
+func (m *MockProfileSourceRepository) Reports() ([]*domain.ProfileSourceReport, error) {
+	args := m.Called()
+	return args.Get(0).([]*domain.ProfileSourceReport), args.Error(1)
+}
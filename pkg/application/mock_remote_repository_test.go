@@ -0,0 +1,21 @@
+package application_test
+
+import (
+	"github.com/b4nd/git-profile/pkg/domain"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRemoteRepository struct {
+	mock.Mock
+}
+
+func (m *MockRemoteRepository) List() ([]*domain.ScmRemote, error) {
+	args := m.Called()
+	return args.Get(0).([]*domain.ScmRemote), args.Error(1)
+}
+
+func (m *MockRemoteRepository) Refs(remote string) ([]domain.ScmCommitHash, error) {
+	args := m.Called(remote)
+	return args.Get(0).([]domain.ScmCommitHash), args.Error(1)
+}
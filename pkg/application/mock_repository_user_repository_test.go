@@ -0,0 +1,26 @@
+package application_test
+
+import (
+	"github.com/b4nd/git-profile/pkg/domain"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRepositoryUserRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepositoryUserRepository) List(dir string) ([]*domain.ScmRepositoryUser, error) {
+	args := m.Called(dir)
+	return args.Get(0).([]*domain.ScmRepositoryUser), args.Error(1)
+}
+
+func (m *MockRepositoryUserRepository) Save(user *domain.ScmRepositoryUser) error {
+	args := m.Called(user)
+	return args.Error(0)
+}
+
+func (m *MockRepositoryUserRepository) SaveWorkspace(path string, workspace string) error {
+	args := m.Called(path, workspace)
+	return args.Error(0)
+}
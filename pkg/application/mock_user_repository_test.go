@@ -20,6 +20,11 @@ func (m *MockUserRepository) Save(user *domain.ScmUser) error {
 	return args.Error(0)
 }
 
+func (m *MockUserRepository) SaveWorkspace(workspace string) error {
+	args := m.Called(workspace)
+	return args.Error(0)
+}
+
 func (m *MockUserRepository) Delete() error {
 	args := m.Called()
 	return args.Error(0)
@@ -0,0 +1,16 @@
+package application_test
+
+import (
+	"github.com/b4nd/git-profile/pkg/domain"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockVersionRepository struct {
+	mock.Mock
+}
+
+func (m *MockVersionRepository) Get() (domain.ScmVersion, error) {
+	args := m.Called()
+	return args.Get(0).(domain.ScmVersion), args.Error(1)
+}
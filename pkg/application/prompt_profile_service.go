@@ -0,0 +1,61 @@
+package application
+
+import (
+	"github.com/b4nd/git-profile/pkg/domain"
+)
+
+// PromptProfileService returns the workspace of the repository for the shell
+// prompt, it reads the local git configuration and only asks git for the
+// effective configuration when the repository has no workspace
+type PromptProfileService struct {
+	profileRepository     domain.ProfileRepository
+	scmUserRepository     domain.ScmUserRepository
+	scmIdentityRepository domain.ScmIdentityRepository
+}
+
+// PromptProfileServiceResult holds the workspace configured in the repository,
+// it is also returned along with ErrProfileNotExists and ErrProfileMismatch
+type PromptProfileServiceResult struct {
+	Workspace string
+}
+
+func NewPromptProfileService(
+	profileRepository domain.ProfileRepository,
+	scmUserRepository domain.ScmUserRepository,
+	scmIdentityRepository domain.ScmIdentityRepository,
+) *PromptProfileService {
+	return &PromptProfileService{profileRepository, scmUserRepository, scmIdentityRepository}
+}
+
+func (pp *PromptProfileService) Execute() (*PromptProfileServiceResult, error) {
+	scmUser, err := pp.scmUserRepository.Get()
+
+	// A profile applied by an includeIf rule is only seen in the effective configuration
+	if err == domain.ErrScmUserNotFound || (err == nil && scmUser != nil && scmUser.Workespace == "") {
+		if identity, identityErr := pp.scmIdentityRepository.Get(); identityErr == nil && identity.Workspace != "" {
+			scmUser, err = domain.NewScmUser(identity.Workspace, identity.Email, identity.Name), nil
+		}
+	}
+
+	if err != nil || scmUser == nil || scmUser.Workespace == "" {
+		return nil, ErrProfileNotConfigured
+	}
+
+	result := &PromptProfileServiceResult{Workspace: scmUser.Workespace}
+
+	workspace, err := domain.NewProfileWorkspace(scmUser.Workespace)
+	if err != nil {
+		return result, ErrProfileNotExists
+	}
+
+	profile, err := pp.profileRepository.Get(workspace)
+	if err != nil {
+		return result, ErrProfileNotExists
+	}
+
+	if !domain.IsSameScmIdentity(scmUser.Name, scmUser.Email, profile.Name().String(), profile.Email().String()) {
+		return result, ErrProfileMismatch
+	}
+
+	return result, nil
+}
@@ -0,0 +1,151 @@
+package application_test
+
+import (
+	"testing"
+
+	"github.com/b4nd/git-profile/pkg/application"
+	"github.com/b4nd/git-profile/pkg/domain"
+
+	"github.com/jaswdr/faker"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPromptProfileServiceExecute(t *testing.T) {
+	faker := faker.New()
+
+	profile, err := domain.NewProfile(
+		faker.Internet().User(),
+		faker.Internet().Email(),
+		faker.Person().Name(),
+	)
+	assert.NoError(t, err)
+
+	scmUser := domain.NewScmUser(
+		profile.Workspace().String(),
+		profile.Email().String(),
+		profile.Name().String(),
+	)
+
+	t.Run("should return the workspace when the repository matches the profile", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+		mockGitUserRepository := &MockUserRepository{}
+		mockIdentityRepository := &MockIdentityRepository{}
+
+		mockGitUserRepository.On("Get").Return(scmUser, nil)
+		mockProfileRepository.On("Get", profile.Workspace()).Return(profile, nil)
+
+		promptProfileService := application.NewPromptProfileService(mockProfileRepository, mockGitUserRepository, mockIdentityRepository)
+		result, err := promptProfileService.Execute()
+
+		assert.NoError(t, err)
+		assert.Equal(t, profile.Workspace().String(), result.Workspace)
+
+		mockProfileRepository.AssertExpectations(t)
+		mockGitUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("should return an error when the repository has no profile", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+		mockGitUserRepository := &MockUserRepository{}
+		mockIdentityRepository := &MockIdentityRepository{}
+
+		mockGitUserRepository.On("Get").Return(&domain.ScmUser{}, domain.ErrScmUserNotFound)
+		mockIdentityRepository.On("Get").Return((*domain.ScmIdentity)(nil), domain.ErrScmIdentityNotFound)
+
+		promptProfileService := application.NewPromptProfileService(mockProfileRepository, mockGitUserRepository, mockIdentityRepository)
+		result, err := promptProfileService.Execute()
+
+		assert.ErrorIs(t, err, application.ErrProfileNotConfigured)
+		assert.Nil(t, result)
+
+		mockProfileRepository.AssertExpectations(t)
+		mockGitUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("should return an error when the repository has a user without workspace", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+		mockGitUserRepository := &MockUserRepository{}
+		mockIdentityRepository := &MockIdentityRepository{}
+
+		mockGitUserRepository.On("Get").Return(domain.NewScmUser("", scmUser.Email, scmUser.Name), nil)
+		mockIdentityRepository.On("Get").Return((*domain.ScmIdentity)(nil), domain.ErrScmIdentityNotFound)
+
+		promptProfileService := application.NewPromptProfileService(mockProfileRepository, mockGitUserRepository, mockIdentityRepository)
+		result, err := promptProfileService.Execute()
+
+		assert.ErrorIs(t, err, application.ErrProfileNotConfigured)
+		assert.Nil(t, result)
+	})
+
+	t.Run("should return the workspace applied by an include when the repository has none", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+		mockGitUserRepository := &MockUserRepository{}
+		mockIdentityRepository := &MockIdentityRepository{}
+
+		mockGitUserRepository.On("Get").Return((*domain.ScmUser)(nil), domain.ErrScmUserNotFound)
+		mockIdentityRepository.On("Get").Return(domain.NewScmIdentity(scmUser.Workespace, scmUser.Email, scmUser.Name), nil)
+		mockProfileRepository.On("Get", profile.Workspace()).Return(profile, nil)
+
+		promptProfileService := application.NewPromptProfileService(mockProfileRepository, mockGitUserRepository, mockIdentityRepository)
+		result, err := promptProfileService.Execute()
+
+		assert.NoError(t, err)
+		assert.Equal(t, profile.Workspace().String(), result.Workspace)
+
+		mockIdentityRepository.AssertExpectations(t)
+	})
+
+	t.Run("should return the workspace and an error when the profile does not exist", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+		mockGitUserRepository := &MockUserRepository{}
+		mockIdentityRepository := &MockIdentityRepository{}
+
+		mockGitUserRepository.On("Get").Return(scmUser, nil)
+		mockProfileRepository.On("Get", profile.Workspace()).Return(&domain.Profile{}, domain.ErrInvalidWorkspace)
+
+		promptProfileService := application.NewPromptProfileService(mockProfileRepository, mockGitUserRepository, mockIdentityRepository)
+		result, err := promptProfileService.Execute()
+
+		assert.ErrorIs(t, err, application.ErrProfileNotExists)
+		assert.Equal(t, profile.Workspace().String(), result.Workspace)
+
+		mockProfileRepository.AssertExpectations(t)
+		mockGitUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("should return the workspace when the name only differs by the characters git strips", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+		mockGitUserRepository := &MockUserRepository{}
+		mockIdentityRepository := &MockIdentityRepository{}
+
+		junior, err := domain.NewProfile(profile.Workspace().String(), "jane@acme.com", "Jane Doe Jr.")
+		assert.NoError(t, err)
+
+		mockGitUserRepository.On("Get").Return(domain.NewScmUser(scmUser.Workespace, "Jane@Acme.com", "Jane Doe Jr"), nil)
+		mockProfileRepository.On("Get", profile.Workspace()).Return(junior, nil)
+
+		promptProfileService := application.NewPromptProfileService(mockProfileRepository, mockGitUserRepository, mockIdentityRepository)
+		result, err := promptProfileService.Execute()
+
+		assert.NoError(t, err)
+		assert.Equal(t, profile.Workspace().String(), result.Workspace)
+	})
+
+	t.Run("should return the workspace and an error when the repository differs from the profile", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+		mockGitUserRepository := &MockUserRepository{}
+		mockIdentityRepository := &MockIdentityRepository{}
+
+		mockGitUserRepository.On("Get").Return(domain.NewScmUser(scmUser.Workespace, faker.Internet().Email(), scmUser.Name), nil)
+		mockProfileRepository.On("Get", profile.Workspace()).Return(profile, nil)
+
+		promptProfileService := application.NewPromptProfileService(mockProfileRepository, mockGitUserRepository, mockIdentityRepository)
+		result, err := promptProfileService.Execute()
+
+		assert.ErrorIs(t, err, application.ErrProfileMismatch)
+		assert.Equal(t, profile.Workspace().String(), result.Workspace)
+
+		mockProfileRepository.AssertExpectations(t)
+		mockGitUserRepository.AssertExpectations(t)
+	})
+}
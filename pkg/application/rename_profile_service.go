@@ -0,0 +1,149 @@
+package application
+
+import (
+	"github.com/b4nd/git-profile/pkg/domain"
+)
+
+type RenameProfileService struct {
+	profileRepository           domain.ProfileRepository
+	profileRuleRepository       domain.ProfileRuleRepository
+	scmUserRepository           domain.ScmUserRepository
+	scmGlobalUserRepository     domain.ScmUserRepository
+	scmRepositoryUserRepository domain.ScmRepositoryUserRepository
+}
+
+type RenameProfileServiceParams struct {
+	Workspace    string
+	NewWorkspace string
+	// Dirs are walked to update the git repositories that use the profile
+	Dirs []string
+}
+
+// RenameProfileResult tells what references to the old workspace were updated
+type RenameProfileResult struct {
+	Profile      *domain.Profile
+	Rules        []*domain.ProfileRule
+	Local        bool
+	Global       bool
+	Repositories []string
+}
+
+func NewRenameProfileService(
+	profileRepository domain.ProfileRepository,
+	profileRuleRepository domain.ProfileRuleRepository,
+	scmUserRepository domain.ScmUserRepository,
+	scmGlobalUserRepository domain.ScmUserRepository,
+	scmRepositoryUserRepository domain.ScmRepositoryUserRepository,
+) *RenameProfileService {
+	return &RenameProfileService{
+		profileRepository,
+		profileRuleRepository,
+		scmUserRepository,
+		scmGlobalUserRepository,
+		scmRepositoryUserRepository,
+	}
+}
+
+// Execute renames the profile, its rules and the git configurations that
+// reference the old workspace
+func (rp *RenameProfileService) Execute(params RenameProfileServiceParams) (*RenameProfileResult, error) {
+	workspace, err := domain.NewProfileWorkspace(params.Workspace)
+	if err != nil {
+		return nil, err
+	}
+
+	newWorkspace, err := domain.NewProfileWorkspace(params.NewWorkspace)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := rp.profileRepository.Get(workspace); err != nil {
+		return nil, ErrProfileNotExists
+	}
+
+	if _, err := rp.profileRepository.Get(newWorkspace); err == nil {
+		return nil, ErrProfileAlreadyExists
+	}
+
+	if err := rp.profileRepository.Rename(workspace, newWorkspace); err != nil {
+		return nil, err
+	}
+
+	profile, err := rp.profileRepository.Get(newWorkspace)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RenameProfileResult{
+		Profile:      profile,
+		Rules:        make([]*domain.ProfileRule, 0),
+		Repositories: make([]string, 0),
+	}
+
+	rules, err := rp.profileRuleRepository.List()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rule := range rules {
+		if !rule.Workspace().Equals(workspace) {
+			continue
+		}
+
+		renamed, err := domain.NewProfileRule(newWorkspace.String(), rule.Condition().String())
+		if err != nil {
+			return nil, err
+		}
+
+		if err := rp.profileRuleRepository.Save(renamed); err != nil {
+			return nil, err
+		}
+
+		result.Rules = append(result.Rules, renamed)
+	}
+
+	if result.Local, err = renameScmUserWorkspace(rp.scmUserRepository, workspace, newWorkspace); err != nil {
+		return nil, err
+	}
+
+	if result.Global, err = renameScmUserWorkspace(rp.scmGlobalUserRepository, workspace, newWorkspace); err != nil {
+		return nil, err
+	}
+
+	for _, dir := range params.Dirs {
+		users, err := rp.scmRepositoryUserRepository.List(dir)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, user := range users {
+			if user.User.Workespace != workspace.String() {
+				continue
+			}
+
+			// Only the workspace is rewritten, the rest of the configuration of the repository is kept
+			if err := rp.scmRepositoryUserRepository.SaveWorkspace(user.Path, newWorkspace.String()); err != nil {
+				return nil, err
+			}
+
+			result.Repositories = append(result.Repositories, user.Path)
+		}
+	}
+
+	return result, nil
+}
+
+// renameScmUserWorkspace updates the workspace of the configured user when it
+// is the old one, the other keys are kept, it reports whether the user was updated
+func renameScmUserWorkspace(repository domain.ScmUserRepository, workspace domain.ProfileWorkspace, newWorkspace domain.ProfileWorkspace) (bool, error) {
+	user, err := repository.Get()
+	if err != nil || user.Workespace != workspace.String() {
+		return false, nil
+	}
+
+	if err := repository.SaveWorkspace(newWorkspace.String()); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
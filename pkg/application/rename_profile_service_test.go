@@ -0,0 +1,140 @@
+package application_test
+
+import (
+	"testing"
+
+	"github.com/b4nd/git-profile/pkg/application"
+	"github.com/b4nd/git-profile/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRenameProfileExecute(t *testing.T) {
+	newProfile := func(workspace string) *domain.Profile {
+		profile, err := domain.NewProfile(workspace, "jane@acme.com", "Jane Doe")
+		assert.NoError(t, err)
+
+		return profile
+	}
+
+	newRule := func(workspace string, condition string) *domain.ProfileRule {
+		rule, err := domain.NewProfileRule(workspace, condition)
+		assert.NoError(t, err)
+
+		return rule
+	}
+
+	t.Run("should rename the profile, its rules and the git configurations", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+		mockProfileRuleRepository := &MockProfileRuleRepository{}
+		mockUserRepository := &MockUserRepository{}
+		mockGlobalUserRepository := &MockUserRepository{}
+		mockRepositoryUserRepository := &MockRepositoryUserRepository{}
+
+		oldProfile, renamedProfile := newProfile("acme"), newProfile("acme-corp")
+
+		mockProfileRepository.On("Get", oldProfile.Workspace()).Return(oldProfile, nil)
+		mockProfileRepository.On("Get", renamedProfile.Workspace()).Return(&domain.Profile{}, assert.AnError).Once()
+		mockProfileRepository.On("Rename", oldProfile.Workspace(), renamedProfile.Workspace()).Return(nil)
+		mockProfileRepository.On("Get", renamedProfile.Workspace()).Return(renamedProfile, nil).Once()
+
+		mockProfileRuleRepository.On("List").Return([]*domain.ProfileRule{
+			newRule("acme", "gitdir:~/acme/"),
+			newRule("personal", "gitdir:~/personal/"),
+		}, nil)
+		mockProfileRuleRepository.On("Save", newRule("acme-corp", "gitdir:~/acme/")).Return(nil)
+
+		mockUserRepository.On("Get").Return(domain.NewScmUser("acme", "jane@acme.com", "Jane Doe"), nil)
+		mockUserRepository.On("SaveWorkspace", "acme-corp").Return(nil)
+		mockGlobalUserRepository.On("Get").Return(domain.NewScmUser("personal", "jane@gmail.com", "Jane Doe"), nil)
+
+		mockRepositoryUserRepository.On("List", "/src").Return([]*domain.ScmRepositoryUser{
+			domain.NewScmRepositoryUser("/src/api", domain.NewScmUser("acme", "jane@acme.com", "Jane Doe")),
+			domain.NewScmRepositoryUser("/src/blog", domain.NewScmUser("personal", "jane@gmail.com", "Jane Doe")),
+		}, nil)
+		mockRepositoryUserRepository.On("SaveWorkspace", "/src/api", "acme-corp").Return(nil)
+
+		renameProfileService := application.NewRenameProfileService(
+			mockProfileRepository,
+			mockProfileRuleRepository,
+			mockUserRepository,
+			mockGlobalUserRepository,
+			mockRepositoryUserRepository,
+		)
+
+		result, err := renameProfileService.Execute(application.RenameProfileServiceParams{
+			Workspace:    "acme",
+			NewWorkspace: "acme-corp",
+			Dirs:         []string{"/src"},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, renamedProfile, result.Profile)
+		assert.Equal(t, []*domain.ProfileRule{newRule("acme-corp", "gitdir:~/acme/")}, result.Rules)
+		assert.True(t, result.Local)
+		assert.False(t, result.Global)
+		assert.Equal(t, []string{"/src/api"}, result.Repositories)
+
+		mockProfileRepository.AssertExpectations(t)
+		mockProfileRuleRepository.AssertExpectations(t)
+		mockUserRepository.AssertExpectations(t)
+		mockGlobalUserRepository.AssertExpectations(t)
+		mockRepositoryUserRepository.AssertExpectations(t)
+		mockGlobalUserRepository.AssertNotCalled(t, "SaveWorkspace", mock.Anything)
+	})
+
+	t.Run("should return an error when the profile does not exist", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+
+		profile := newProfile("acme")
+		mockProfileRepository.On("Get", profile.Workspace()).Return(&domain.Profile{}, assert.AnError)
+
+		renameProfileService := application.NewRenameProfileService(mockProfileRepository, nil, nil, nil, nil)
+		result, err := renameProfileService.Execute(application.RenameProfileServiceParams{
+			Workspace:    "acme",
+			NewWorkspace: "acme-corp",
+		})
+
+		assert.ErrorIs(t, err, application.ErrProfileNotExists)
+		assert.Nil(t, result)
+
+		mockProfileRepository.AssertExpectations(t)
+		mockProfileRepository.AssertNotCalled(t, "Rename", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return an error when the new workspace is already a profile", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+
+		oldProfile, existingProfile := newProfile("acme"), newProfile("acme-corp")
+		mockProfileRepository.On("Get", oldProfile.Workspace()).Return(oldProfile, nil)
+		mockProfileRepository.On("Get", existingProfile.Workspace()).Return(existingProfile, nil)
+
+		renameProfileService := application.NewRenameProfileService(mockProfileRepository, nil, nil, nil, nil)
+		result, err := renameProfileService.Execute(application.RenameProfileServiceParams{
+			Workspace:    "acme",
+			NewWorkspace: "acme-corp",
+		})
+
+		assert.ErrorIs(t, err, application.ErrProfileAlreadyExists)
+		assert.Nil(t, result)
+
+		mockProfileRepository.AssertExpectations(t)
+		mockProfileRepository.AssertNotCalled(t, "Rename", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return an error when the new workspace is invalid", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+
+		renameProfileService := application.NewRenameProfileService(mockProfileRepository, nil, nil, nil, nil)
+		result, err := renameProfileService.Execute(application.RenameProfileServiceParams{
+			Workspace:    "acme",
+			NewWorkspace: "acme corp",
+		})
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+
+		mockProfileRepository.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,165 @@
+package application
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/b4nd/git-profile/pkg/domain"
+)
+
+var ErrCommitRangeHasMerges = errors.New("commit range contains merges")
+var ErrCommitRangePushed = errors.New("commit range is reachable from a remote branch")
+var ErrCommitNotInHead = errors.New("commit is not reachable from HEAD")
+
+type RewriteProfileCommitsService struct {
+	profileRepository   domain.ProfileRepository
+	scmCommitRepository domain.ScmCommitRepository
+}
+
+type RewriteProfileCommitsServiceParams struct {
+	Workspace string
+	// Revision is a single commit or a range in the form base..tip
+	Revision string
+	// Force rewrites merges and commits already pushed
+	Force bool
+	// KeepDate keeps the author and committer dates of the commits
+	KeepDate bool
+	// ResetDate sets the author and committer dates to now
+	ResetDate bool
+	// CommitterOnly keeps the authors and sets the profile as committer
+	CommitterOnly bool
+}
+
+func NewRewriteProfileCommitsService(
+	profileRepository domain.ProfileRepository,
+	scmCommitRepository domain.ScmCommitRepository,
+) *RewriteProfileCommitsService {
+	return &RewriteProfileCommitsService{
+		profileRepository,
+		scmCommitRepository,
+	}
+}
+
+// Execute sets the profile as author and committer of every commit of the
+// revision, or only as committer, the descendants up to HEAD are recreated on top of them
+func (rp *RewriteProfileCommitsService) Execute(params RewriteProfileCommitsServiceParams) ([]*domain.ScmCommitRewrite, error) {
+	workspace, err := domain.NewProfileWorkspace(params.Workspace)
+	if err != nil {
+		return nil, err
+	}
+
+	date, err := newScmCommitDate(params.KeepDate, params.ResetDate)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := rp.profileRepository.Get(workspace)
+	if err != nil {
+		return nil, ErrProfileNotExists
+	}
+
+	identity, err := domain.NewScmCommitAuthor(profile.Name().String(), profile.Email().String())
+	if err != nil {
+		return nil, err
+	}
+
+	amend := domain.NewScmCommitAmend(identity, params.CommitterOnly, date)
+
+	commits, exclude, err := rp.resolve(params.Revision)
+	if err != nil {
+		return nil, err
+	}
+
+	targets := make([]domain.ScmCommitHash, 0, len(commits))
+	for _, commit := range commits {
+		if commit.IsMerge() && !params.Force {
+			return nil, ErrCommitRangeHasMerges
+		}
+
+		if !amend.IsAppliedTo(commit) {
+			targets = append(targets, commit.Hash)
+		}
+	}
+
+	if len(targets) == 0 {
+		return []*domain.ScmCommitRewrite{}, nil
+	}
+
+	head := domain.NewScmCommitHashHead()
+	history, err := rp.scmCommitRepository.List(&head, exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	if !containsCommits(history, targets) {
+		return nil, ErrCommitNotInHead
+	}
+
+	// The pushed commits are closed under ancestors, checking the oldest target is enough
+	if !params.Force {
+		pushed, err := rp.scmCommitRepository.IsPushed(&targets[0])
+		if err != nil {
+			return nil, err
+		}
+
+		if pushed {
+			return nil, ErrCommitRangePushed
+		}
+	}
+
+	return rp.scmCommitRepository.Rewrite(history, targets, amend)
+}
+
+// resolve returns the commits of the revision, the parents first, and the
+// commits excluded from the history rewritten with them
+func (rp *RewriteProfileCommitsService) resolve(revision string) ([]*domain.ScmCommit, []domain.ScmCommitHash, error) {
+	if base, tip, ok := strings.Cut(revision, ".."); ok {
+		baseHash, err := domain.NewScmCommitHash(base)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if tip == "" {
+			tip = domain.NewScmCommitHashHead().String()
+		}
+
+		tipHash, err := domain.NewScmCommitHash(tip)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		commits, err := rp.scmCommitRepository.List(&tipHash, []domain.ScmCommitHash{baseHash})
+		if err != nil {
+			return nil, nil, domain.ErrScmCommitNotFound
+		}
+
+		return commits, []domain.ScmCommitHash{baseHash}, nil
+	}
+
+	hash, err := domain.NewScmCommitHash(revision)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	commit, err := rp.scmCommitRepository.Get(&hash)
+	if err != nil {
+		return nil, nil, domain.ErrScmCommitNotFound
+	}
+
+	return []*domain.ScmCommit{commit}, commit.Parents, nil
+}
+
+func containsCommits(commits []*domain.ScmCommit, hashes []domain.ScmCommitHash) bool {
+	for _, hash := range hashes {
+		found := false
+		for _, commit := range commits {
+			found = found || commit.Hash.String() == hash.String()
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
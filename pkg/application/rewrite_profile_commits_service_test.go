@@ -0,0 +1,255 @@
+package application_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/b4nd/git-profile/pkg/application"
+	"github.com/b4nd/git-profile/pkg/domain"
+
+	"github.com/jaswdr/faker"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRewriteProfileCommitsServiceExecute(t *testing.T) {
+	faker := faker.New()
+
+	profile, err := domain.NewProfile(
+		faker.Internet().User(),
+		faker.Internet().Email(),
+		faker.Person().Name(),
+	)
+	assert.NoError(t, err)
+
+	profileAuthor, err := domain.NewScmCommitAuthor(profile.Name().String(), profile.Email().String())
+	assert.NoError(t, err)
+
+	otherAuthor, err := domain.NewScmCommitAuthor(faker.Person().Name(), faker.Internet().Email())
+	assert.NoError(t, err)
+
+	newHash := func() domain.ScmCommitHash {
+		hash, err := domain.NewScmCommitHash(faker.Hash().SHA256())
+		assert.NoError(t, err)
+
+		return hash
+	}
+
+	newCommit := func(author domain.ScmCommitAuthor, parents ...domain.ScmCommitHash) *domain.ScmCommit {
+		commit := domain.NewScmCommit(newHash(), author, time.Now(), faker.Lorem().Sentence(3))
+		commit.Parents = parents
+
+		return commit
+	}
+
+	base, err := domain.NewScmCommitHash("main")
+	assert.NoError(t, err)
+
+	tip, err := domain.NewScmCommitHash("feature")
+	assert.NoError(t, err)
+
+	head := domain.NewScmCommitHashHead()
+
+	root := newCommit(otherAuthor)
+	first := newCommit(otherAuthor, root.Hash)
+	second := newCommit(profileAuthor, first.Hash)
+	commits := []*domain.ScmCommit{first, second}
+
+	params := application.RewriteProfileCommitsServiceParams{
+		Workspace: profile.Workspace().String(),
+		Revision:  "main..feature",
+	}
+
+	t.Run("should rewrite the commits of the range that do not match the profile", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+		mockScmCommitRepository := &MockCommitRepository{}
+
+		rewrites := []*domain.ScmCommitRewrite{
+			domain.NewScmCommitRewrite(first.Hash, newHash()),
+			domain.NewScmCommitRewrite(second.Hash, newHash()),
+		}
+
+		mockProfileRepository.On("Get", profile.Workspace()).Return(profile, nil)
+		mockScmCommitRepository.On("List", &tip, []domain.ScmCommitHash{base}).Return(commits, nil)
+		mockScmCommitRepository.On("List", &head, []domain.ScmCommitHash{base}).Return(commits, nil)
+		mockScmCommitRepository.On("IsPushed", &first.Hash).Return(false, nil)
+		mockScmCommitRepository.On("Rewrite", commits, []domain.ScmCommitHash{first.Hash}, domain.NewScmCommitAmend(profileAuthor, false, domain.ScmCommitDateDefault)).Return(rewrites, nil)
+
+		rewriteProfileCommitsService := application.NewRewriteProfileCommitsService(mockProfileRepository, mockScmCommitRepository)
+		rewritten, err := rewriteProfileCommitsService.Execute(params)
+
+		assert.NoError(t, err)
+		assert.Equal(t, rewrites, rewritten)
+
+		mockProfileRepository.AssertExpectations(t)
+		mockScmCommitRepository.AssertExpectations(t)
+	})
+
+	t.Run("should rewrite a single commit and its descendants", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+		mockScmCommitRepository := &MockCommitRepository{}
+
+		testParams := params
+		testParams.Revision = first.Hash.String()
+
+		rewrites := []*domain.ScmCommitRewrite{
+			domain.NewScmCommitRewrite(first.Hash, newHash()),
+		}
+
+		mockProfileRepository.On("Get", profile.Workspace()).Return(profile, nil)
+		mockScmCommitRepository.On("Get", &first.Hash).Return(first, nil)
+		mockScmCommitRepository.On("List", &head, first.Parents).Return(commits, nil)
+		mockScmCommitRepository.On("IsPushed", &first.Hash).Return(false, nil)
+		mockScmCommitRepository.On("Rewrite", commits, []domain.ScmCommitHash{first.Hash}, domain.NewScmCommitAmend(profileAuthor, false, domain.ScmCommitDateDefault)).Return(rewrites, nil)
+
+		rewriteProfileCommitsService := application.NewRewriteProfileCommitsService(mockProfileRepository, mockScmCommitRepository)
+		rewritten, err := rewriteProfileCommitsService.Execute(testParams)
+
+		assert.NoError(t, err)
+		assert.Equal(t, rewrites, rewritten)
+
+		mockProfileRepository.AssertExpectations(t)
+		mockScmCommitRepository.AssertExpectations(t)
+	})
+
+	t.Run("should not rewrite anything when every commit matches the profile", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+		mockScmCommitRepository := &MockCommitRepository{}
+
+		mockProfileRepository.On("Get", profile.Workspace()).Return(profile, nil)
+		mockScmCommitRepository.On("List", &tip, []domain.ScmCommitHash{base}).Return([]*domain.ScmCommit{second}, nil)
+
+		rewriteProfileCommitsService := application.NewRewriteProfileCommitsService(mockProfileRepository, mockScmCommitRepository)
+		rewritten, err := rewriteProfileCommitsService.Execute(params)
+
+		assert.NoError(t, err)
+		assert.Empty(t, rewritten)
+
+		mockProfileRepository.AssertExpectations(t)
+		mockScmCommitRepository.AssertExpectations(t)
+	})
+
+	t.Run("should refuse to rewrite a range with merges unless forced", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+		mockScmCommitRepository := &MockCommitRepository{}
+
+		merge := newCommit(otherAuthor, first.Hash, root.Hash)
+		merges := []*domain.ScmCommit{first, merge}
+
+		mockProfileRepository.On("Get", profile.Workspace()).Return(profile, nil)
+		mockScmCommitRepository.On("List", &tip, []domain.ScmCommitHash{base}).Return(merges, nil)
+
+		rewriteProfileCommitsService := application.NewRewriteProfileCommitsService(mockProfileRepository, mockScmCommitRepository)
+		rewritten, err := rewriteProfileCommitsService.Execute(params)
+
+		assert.ErrorIs(t, err, application.ErrCommitRangeHasMerges)
+		assert.Nil(t, rewritten)
+
+		mockScmCommitRepository.On("List", &head, []domain.ScmCommitHash{base}).Return(merges, nil)
+		mockScmCommitRepository.On("Rewrite", merges, []domain.ScmCommitHash{first.Hash, merge.Hash}, domain.NewScmCommitAmend(profileAuthor, false, domain.ScmCommitDateDefault)).Return([]*domain.ScmCommitRewrite{}, nil)
+
+		testParams := params
+		testParams.Force = true
+
+		_, err = rewriteProfileCommitsService.Execute(testParams)
+		assert.NoError(t, err)
+
+		mockProfileRepository.AssertExpectations(t)
+		mockScmCommitRepository.AssertExpectations(t)
+	})
+
+	t.Run("should refuse to rewrite pushed commits unless forced", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+		mockScmCommitRepository := &MockCommitRepository{}
+
+		mockProfileRepository.On("Get", profile.Workspace()).Return(profile, nil)
+		mockScmCommitRepository.On("List", &tip, []domain.ScmCommitHash{base}).Return(commits, nil)
+		mockScmCommitRepository.On("List", &head, []domain.ScmCommitHash{base}).Return(commits, nil)
+		mockScmCommitRepository.On("IsPushed", &first.Hash).Return(true, nil)
+
+		rewriteProfileCommitsService := application.NewRewriteProfileCommitsService(mockProfileRepository, mockScmCommitRepository)
+		rewritten, err := rewriteProfileCommitsService.Execute(params)
+
+		assert.ErrorIs(t, err, application.ErrCommitRangePushed)
+		assert.Nil(t, rewritten)
+
+		mockScmCommitRepository.On("Rewrite", commits, []domain.ScmCommitHash{first.Hash}, domain.NewScmCommitAmend(profileAuthor, false, domain.ScmCommitDateDefault)).Return([]*domain.ScmCommitRewrite{}, nil)
+
+		testParams := params
+		testParams.Force = true
+
+		_, err = rewriteProfileCommitsService.Execute(testParams)
+		assert.NoError(t, err)
+
+		mockProfileRepository.AssertExpectations(t)
+		mockScmCommitRepository.AssertExpectations(t)
+	})
+
+	t.Run("should only set the committer of the commits when committer only is set", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+		mockScmCommitRepository := &MockCommitRepository{}
+
+		// The author of the commit is kept, the committer is the profile already
+		kept := newCommit(otherAuthor, first.Hash)
+		kept.Committer = profileAuthor
+		committed := []*domain.ScmCommit{first, kept}
+
+		rewrites := []*domain.ScmCommitRewrite{
+			domain.NewScmCommitRewrite(first.Hash, newHash()),
+			domain.NewScmCommitRewrite(kept.Hash, newHash()),
+		}
+
+		mockProfileRepository.On("Get", profile.Workspace()).Return(profile, nil)
+		mockScmCommitRepository.On("List", &tip, []domain.ScmCommitHash{base}).Return(committed, nil)
+		mockScmCommitRepository.On("List", &head, []domain.ScmCommitHash{base}).Return(committed, nil)
+		mockScmCommitRepository.On("IsPushed", &first.Hash).Return(false, nil)
+		mockScmCommitRepository.On("Rewrite", committed, []domain.ScmCommitHash{first.Hash}, domain.NewScmCommitAmend(profileAuthor, true, domain.ScmCommitDateReset)).Return(rewrites, nil)
+
+		rewriteProfileCommitsService := application.NewRewriteProfileCommitsService(mockProfileRepository, mockScmCommitRepository)
+		rewritten, err := rewriteProfileCommitsService.Execute(application.RewriteProfileCommitsServiceParams{
+			Workspace:     params.Workspace,
+			Revision:      params.Revision,
+			ResetDate:     true,
+			CommitterOnly: true,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, rewrites, rewritten)
+
+		mockProfileRepository.AssertExpectations(t)
+		mockScmCommitRepository.AssertExpectations(t)
+	})
+
+	t.Run("should return an error when the range is not reachable from HEAD", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+		mockScmCommitRepository := &MockCommitRepository{}
+
+		mockProfileRepository.On("Get", profile.Workspace()).Return(profile, nil)
+		mockScmCommitRepository.On("List", &tip, []domain.ScmCommitHash{base}).Return(commits, nil)
+		mockScmCommitRepository.On("List", &head, []domain.ScmCommitHash{base}).Return([]*domain.ScmCommit{}, nil)
+
+		rewriteProfileCommitsService := application.NewRewriteProfileCommitsService(mockProfileRepository, mockScmCommitRepository)
+		rewritten, err := rewriteProfileCommitsService.Execute(params)
+
+		assert.ErrorIs(t, err, application.ErrCommitNotInHead)
+		assert.Nil(t, rewritten)
+
+		mockProfileRepository.AssertExpectations(t)
+		mockScmCommitRepository.AssertExpectations(t)
+	})
+
+	t.Run("should return an error when the profile does not exist", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+		mockScmCommitRepository := &MockCommitRepository{}
+
+		mockProfileRepository.On("Get", profile.Workspace()).Return(&domain.Profile{}, assert.AnError)
+
+		rewriteProfileCommitsService := application.NewRewriteProfileCommitsService(mockProfileRepository, mockScmCommitRepository)
+		rewritten, err := rewriteProfileCommitsService.Execute(params)
+
+		assert.ErrorIs(t, err, application.ErrProfileNotExists)
+		assert.Nil(t, rewritten)
+
+		mockProfileRepository.AssertExpectations(t)
+		mockScmCommitRepository.AssertExpectations(t)
+	})
+}
@@ -15,6 +15,8 @@ type SetProfileService struct {
 
 type SetProfileServiceParams struct {
 	Workspace string
+	// CredentialHelper is registered as the git credential helper when it is given
+	CredentialHelper string
 }
 
 func NewSetProfileService(
@@ -35,16 +37,45 @@ func (up *SetProfileService) Execute(params SetProfileServiceParams) (*domain.Pr
 		return nil, err
 	}
 
+	scmUser := newScmUserFromProfile(profile)
+	scmUser.CredentialHelper = params.CredentialHelper
+
+	err = up.scmUserRepository.Save(scmUser)
+	if err != nil {
+		return nil, err
+	}
+
+	return profile, nil
+}
+
+// newScmUserFromProfile maps a profile to the git configuration that applies it
+func newScmUserFromProfile(profile *domain.Profile) *domain.ScmUser {
 	scmUser := domain.NewScmUser(
 		profile.Workspace().String(),
 		profile.Email().String(),
 		profile.Name().String(),
 	)
 
-	err = up.scmUserRepository.Save(scmUser)
-	if err != nil {
-		return nil, err
+	if signing := profile.Signing(); !signing.IsEmpty() {
+		scmUser.SigningKey = signing.Key()
+		scmUser.SigningFormat = signing.Format()
+		scmUser.CommitGpgSign = signing.CommitSign()
+		scmUser.TagGpgSign = signing.TagSign()
 	}
 
-	return profile, nil
+	scmUser.SshCommand = profile.SshKey().SshCommand()
+
+	for _, username := range profile.Usernames() {
+		if scmUser.Usernames == nil {
+			scmUser.Usernames = map[string]string{}
+		}
+
+		scmUser.Usernames[username.Host()] = username.Username()
+	}
+
+	if config := profile.Config(); !config.IsEmpty() {
+		scmUser.Config = config.Values()
+	}
+
+	return scmUser
 }
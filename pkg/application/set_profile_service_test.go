@@ -101,4 +101,79 @@ func TestSetProfileServiceExecute(t *testing.T) {
 		mockGitUserRepository.AssertExpectations(t)
 		mockProfileRepository.AssertExpectations(t)
 	})
+
+	t.Run("should apply the signing configuration of the profile", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+		mockGitUserRepository := &MockUserRepository{}
+
+		signing, err := domain.NewProfileSigning(faker.Hash().MD5(), domain.SigningFormatOpenPGP, true, true)
+		assert.NoError(t, err)
+
+		signedProfile := profile.WithSigning(signing)
+		signedScmUser := domain.NewScmUser(scmUser.Workespace, scmUser.Email, scmUser.Name)
+		signedScmUser.SigningKey = signing.Key()
+		signedScmUser.SigningFormat = signing.Format()
+		signedScmUser.CommitGpgSign = true
+		signedScmUser.TagGpgSign = true
+
+		mockProfileRepository.On("Get", workspace).Return(signedProfile, nil)
+		mockGitUserRepository.On("Save", signedScmUser).Return(nil)
+
+		currentProfileService := application.NewSetProfileService(mockProfileRepository, mockGitUserRepository)
+		currentProfile, err := currentProfileService.Execute(params)
+
+		assert.NoError(t, err)
+		assert.Equal(t, signedProfile, currentProfile)
+
+		mockGitUserRepository.AssertExpectations(t)
+		mockProfileRepository.AssertExpectations(t)
+	})
+
+	t.Run("should apply the ssh command of the profile ssh key", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+		mockGitUserRepository := &MockUserRepository{}
+
+		sshKey, err := domain.NewProfileSshKey("~/.ssh/id_ed25519_work")
+		assert.NoError(t, err)
+
+		sshProfile := profile.WithSshKey(sshKey)
+		sshScmUser := domain.NewScmUser(scmUser.Workespace, scmUser.Email, scmUser.Name)
+		sshScmUser.SshCommand = "ssh -i ~/.ssh/id_ed25519_work -o IdentitiesOnly=yes"
+
+		mockProfileRepository.On("Get", workspace).Return(sshProfile, nil)
+		mockGitUserRepository.On("Save", sshScmUser).Return(nil)
+
+		currentProfileService := application.NewSetProfileService(mockProfileRepository, mockGitUserRepository)
+		currentProfile, err := currentProfileService.Execute(params)
+
+		assert.NoError(t, err)
+		assert.Equal(t, sshProfile, currentProfile)
+
+		mockGitUserRepository.AssertExpectations(t)
+		mockProfileRepository.AssertExpectations(t)
+	})
+
+	t.Run("should apply the extra git config keys of the profile", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+		mockGitUserRepository := &MockUserRepository{}
+
+		config, err := domain.NewProfileConfig(map[string]string{"pull.rebase": "true"})
+		assert.NoError(t, err)
+
+		configProfile := profile.WithConfig(config)
+		configScmUser := domain.NewScmUser(scmUser.Workespace, scmUser.Email, scmUser.Name)
+		configScmUser.Config = map[string]string{"pull.rebase": "true"}
+
+		mockProfileRepository.On("Get", workspace).Return(configProfile, nil)
+		mockGitUserRepository.On("Save", configScmUser).Return(nil)
+
+		currentProfileService := application.NewSetProfileService(mockProfileRepository, mockGitUserRepository)
+		currentProfile, err := currentProfileService.Execute(params)
+
+		assert.NoError(t, err)
+		assert.Equal(t, configProfile, currentProfile)
+
+		mockGitUserRepository.AssertExpectations(t)
+		mockProfileRepository.AssertExpectations(t)
+	})
 }
@@ -0,0 +1,33 @@
+package application
+
+import (
+	"github.com/b4nd/git-profile/pkg/domain"
+)
+
+type StoreCredentialService struct {
+	scmCredentialRepository domain.ScmCredentialRepository
+}
+
+type StoreCredentialServiceParams struct {
+	Credential *domain.ScmCredential
+	Helper     string
+	// Erase removes the credential from the helper instead of storing it
+	Erase bool
+}
+
+func NewStoreCredentialService(scmCredentialRepository domain.ScmCredentialRepository) *StoreCredentialService {
+	return &StoreCredentialService{scmCredentialRepository}
+}
+
+// Execute hands the credential git approved or rejected to the helper
+func (sc *StoreCredentialService) Execute(params StoreCredentialServiceParams) error {
+	if params.Helper == "" {
+		return nil
+	}
+
+	if params.Erase {
+		return sc.scmCredentialRepository.Erase(params.Helper, params.Credential)
+	}
+
+	return sc.scmCredentialRepository.Store(params.Helper, params.Credential)
+}
@@ -0,0 +1,41 @@
+package application_test
+
+import (
+	"testing"
+
+	"github.com/b4nd/git-profile/pkg/application"
+	"github.com/b4nd/git-profile/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestStoreCredentialServiceExecute(t *testing.T) {
+	credential := &domain.ScmCredential{Protocol: "https", Host: "github.com", Username: "jane-acme", Password: "secret"}
+
+	t.Run("should store and erase the credential in the helper", func(t *testing.T) {
+		mockCredentialRepository := &MockCredentialRepository{}
+		mockCredentialRepository.On("Store", "cache", credential).Return(nil)
+		mockCredentialRepository.On("Erase", "cache", credential).Return(nil)
+
+		service := application.NewStoreCredentialService(mockCredentialRepository)
+
+		err := service.Execute(application.StoreCredentialServiceParams{Credential: credential, Helper: "cache"})
+		assert.NoError(t, err)
+
+		err = service.Execute(application.StoreCredentialServiceParams{Credential: credential, Helper: "cache", Erase: true})
+		assert.NoError(t, err)
+
+		mockCredentialRepository.AssertExpectations(t)
+	})
+
+	t.Run("should do nothing without a helper", func(t *testing.T) {
+		mockCredentialRepository := &MockCredentialRepository{}
+
+		service := application.NewStoreCredentialService(mockCredentialRepository)
+		err := service.Execute(application.StoreCredentialServiceParams{Credential: credential})
+
+		assert.NoError(t, err)
+		mockCredentialRepository.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,21 @@
+package application
+
+import (
+	"github.com/b4nd/git-profile/pkg/domain"
+)
+
+type UninstallHookService struct {
+	scmHookRepository domain.ScmHookRepository
+}
+
+type UninstallHookServiceParams struct {
+	Hook string
+}
+
+func NewUninstallHookService(scmHookRepository domain.ScmHookRepository) *UninstallHookService {
+	return &UninstallHookService{scmHookRepository}
+}
+
+func (uh *UninstallHookService) Execute(params UninstallHookServiceParams) error {
+	return uh.scmHookRepository.Uninstall(params.Hook)
+}
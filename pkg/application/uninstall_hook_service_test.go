@@ -0,0 +1,40 @@
+package application_test
+
+import (
+	"testing"
+
+	"github.com/b4nd/git-profile/pkg/application"
+	"github.com/b4nd/git-profile/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUninstallHookServiceExecute(t *testing.T) {
+	params := application.UninstallHookServiceParams{
+		Hook: domain.ScmHookPreCommit,
+	}
+
+	t.Run("should uninstall the hook", func(t *testing.T) {
+		mockHookRepository := &MockHookRepository{}
+		mockHookRepository.On("Uninstall", domain.ScmHookPreCommit).Return(nil)
+
+		uninstallHookService := application.NewUninstallHookService(mockHookRepository)
+		err := uninstallHookService.Execute(params)
+
+		assert.NoError(t, err)
+
+		mockHookRepository.AssertExpectations(t)
+	})
+
+	t.Run("should return an error when the hook cannot be uninstalled", func(t *testing.T) {
+		mockHookRepository := &MockHookRepository{}
+		mockHookRepository.On("Uninstall", domain.ScmHookPreCommit).Return(assert.AnError)
+
+		uninstallHookService := application.NewUninstallHookService(mockHookRepository)
+		err := uninstallHookService.Execute(params)
+
+		assert.ErrorIs(t, err, assert.AnError)
+
+		mockHookRepository.AssertExpectations(t)
+	})
+}
@@ -10,8 +10,19 @@ type UpdateProfileService struct {
 
 type UpdateProfileServiceParams struct {
 	Workspace string
-	Email     string
-	Name      string
+	// Extends is the workspace of the profile whose fields are inherited, the
+	// profile stops inheriting when it is empty
+	Extends       string
+	Email         string
+	Name          string
+	SigningKey    string
+	SigningFormat string
+	CommitSign    bool
+	TagSign       bool
+	SshKey        string
+	Remotes       []string
+	Usernames     map[string]string
+	Config        map[string]string
 }
 
 func NewUpdateProfileService(profileRepository domain.ProfileRepository) *UpdateProfileService {
@@ -19,12 +30,7 @@ func NewUpdateProfileService(profileRepository domain.ProfileRepository) *Update
 }
 
 func (cp *UpdateProfileService) Execute(params UpdateProfileServiceParams) (*domain.Profile, error) {
-	profile, err := domain.NewProfile(
-		params.Workspace,
-		params.Email,
-		params.Name,
-	)
-
+	profile, err := newProfileFromParams(CreateProfileServiceParams(params))
 	if err != nil {
 		return nil, err
 	}
@@ -33,6 +39,10 @@ func (cp *UpdateProfileService) Execute(params UpdateProfileServiceParams) (*dom
 		return nil, ErrProfileNotExists
 	}
 
+	if err := checkProfileExtends(cp.profileRepository, profile); err != nil {
+		return nil, err
+	}
+
 	if err = cp.profileRepository.Save(profile); err != nil {
 		return nil, err
 	}
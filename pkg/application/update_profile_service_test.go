@@ -8,6 +8,7 @@ import (
 
 	"github.com/jaswdr/faker"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestUpdateProfileServiceExecute(t *testing.T) {
@@ -84,4 +85,40 @@ func TestUpdateProfileServiceExecute(t *testing.T) {
 
 		mockProfileRepository.AssertExpectations(t)
 	})
+
+	t.Run("should stop extending a profile when extends is empty", func(t *testing.T) {
+		parent, err := domain.NewProfileWorkspace("parent")
+		assert.NoError(t, err)
+
+		mockProfileRepository := &MockProfileRepository{}
+		mockProfileRepository.On("Get", profile.Workspace()).Return(profile.WithExtends(parent), nil)
+		mockProfileRepository.On("Save", profile).Return(nil)
+
+		updateProfileService := application.NewUpdateProfileService(mockProfileRepository)
+		newProfile, err := updateProfileService.Execute(params)
+
+		assert.NoError(t, err)
+		assert.Empty(t, newProfile.Extends().String())
+
+		mockProfileRepository.AssertExpectations(t)
+	})
+
+	t.Run("should return an error when the profile would extend itself", func(t *testing.T) {
+		child, err := domain.NewProfile("child", params.Email, params.Name)
+		assert.NoError(t, err)
+		child = child.WithExtends(profile.Workspace())
+
+		mockProfileRepository := &MockProfileRepository{}
+		mockProfileRepository.On("Get", profile.Workspace()).Return(profile, nil)
+		mockProfileRepository.On("Get", child.Workspace()).Return(child, nil)
+
+		withExtends := params
+		withExtends.Extends = "child"
+
+		updateProfileService := application.NewUpdateProfileService(mockProfileRepository)
+		_, err = updateProfileService.Execute(withExtends)
+
+		assert.ErrorIs(t, err, domain.ErrProfileExtendsCycle)
+		mockProfileRepository.AssertNotCalled(t, "Save", mock.Anything)
+	})
 }
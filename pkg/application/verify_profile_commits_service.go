@@ -0,0 +1,177 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/b4nd/git-profile/pkg/domain"
+)
+
+var ErrPolicyEmpty = errors.New("no identity is allowed")
+var ErrPolicyProfileNotExists = errors.New("allowed profile does not exist")
+
+// Roles of the identities of a commit
+const (
+	VerifyRoleAuthor    = "author"
+	VerifyRoleCommitter = "committer"
+)
+
+// VerifyViolation is an identity of a commit outside of the policy
+type VerifyViolation struct {
+	Hash  domain.ScmCommitHash
+	Role  string
+	Name  string
+	Email string
+}
+
+type VerifyProfileCommitsResult struct {
+	Commits    int
+	Violations []*VerifyViolation
+}
+
+type VerifyProfileCommitsService struct {
+	profileRepository       domain.ProfileRepository
+	profilePolicyRepository domain.ProfilePolicyRepository
+	scmCommitRepository     domain.ScmCommitRepository
+}
+
+type VerifyProfileCommitsServiceParams struct {
+	// Range is the history up to a commit or a range in the form base..tip or base...tip, HEAD when it is empty
+	Range string
+	// Allow are workspaces of profiles or emails
+	Allow []string
+	// AllowDomains are the domains whose emails are allowed
+	AllowDomains []string
+	// Policy is the policy file, the policy of the repository is read when it exists if it is empty
+	Policy string
+}
+
+func NewVerifyProfileCommitsService(
+	profileRepository domain.ProfileRepository,
+	profilePolicyRepository domain.ProfilePolicyRepository,
+	scmCommitRepository domain.ScmCommitRepository,
+) *VerifyProfileCommitsService {
+	return &VerifyProfileCommitsService{
+		profileRepository,
+		profilePolicyRepository,
+		scmCommitRepository,
+	}
+}
+
+// Execute checks that every author and committer of the range is allowed by
+// the flags or by the policy file
+func (vp *VerifyProfileCommitsService) Execute(params VerifyProfileCommitsServiceParams) (*VerifyProfileCommitsResult, error) {
+	policy := &domain.ProfilePolicy{Allow: params.Allow, AllowDomains: params.AllowDomains}
+
+	filePolicy, err := vp.profilePolicyRepository.Get(params.Policy)
+	switch {
+	case err == nil:
+		policy.Allow = append(policy.Allow, filePolicy.Allow...)
+		policy.AllowDomains = append(policy.AllowDomains, filePolicy.AllowDomains...)
+	case err == domain.ErrProfilePolicyNotFound && params.Policy == "":
+	default:
+		return nil, err
+	}
+
+	if policy.IsEmpty() {
+		return nil, ErrPolicyEmpty
+	}
+
+	emails, domains, err := vp.resolvePolicy(policy)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed := func(email string) bool {
+		email = strings.ToLower(strings.TrimSpace(email))
+		if emails[email] {
+			return true
+		}
+
+		_, host, ok := strings.Cut(email, "@")
+		for _, domain := range domains {
+			if ok && (host == domain || strings.HasSuffix(host, "."+domain)) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	tip, exclude, err := resolveCommitRange(params.Range)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &VerifyProfileCommitsResult{Violations: make([]*VerifyViolation, 0)}
+	err = vp.scmCommitRepository.Walk(tip, exclude, func(record *domain.ScmCommitRecord) error {
+		result.Commits++
+
+		if !allowed(record.AuthorEmail) {
+			result.Violations = append(result.Violations, &VerifyViolation{
+				Hash:  record.Hash,
+				Role:  VerifyRoleAuthor,
+				Name:  record.AuthorName,
+				Email: record.AuthorEmail,
+			})
+		}
+
+		if !allowed(record.CommitterEmail) {
+			result.Violations = append(result.Violations, &VerifyViolation{
+				Hash:  record.Hash,
+				Role:  VerifyRoleCommitter,
+				Name:  record.CommitterName,
+				Email: record.CommitterEmail,
+			})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// resolvePolicy returns the allowed emails, the entries that are not emails
+// are the workspaces of profiles, and the normalized allowed domains
+func (vp *VerifyProfileCommitsService) resolvePolicy(policy *domain.ProfilePolicy) (map[string]bool, []string, error) {
+	emails := map[string]bool{}
+	for _, allow := range policy.Allow {
+		if strings.Contains(allow, "@") {
+			email, err := domain.NewProfileEmail(allow)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%w: %s", err, allow)
+			}
+
+			emails[email.String()] = true
+			continue
+		}
+
+		workspace, err := domain.NewProfileWorkspace(allow)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %s", ErrPolicyProfileNotExists, allow)
+		}
+
+		profile, err := vp.profileRepository.Get(workspace)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %s", ErrPolicyProfileNotExists, allow)
+		}
+
+		emails[profile.Email().String()] = true
+	}
+
+	domains := make([]string, 0, len(policy.AllowDomains))
+	for _, value := range policy.AllowDomains {
+		domain, err := domain.NewProfilePolicyDomain(value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %s", err, value)
+		}
+
+		domains = append(domains, domain)
+	}
+
+	return emails, domains, nil
+}
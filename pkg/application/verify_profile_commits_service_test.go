@@ -0,0 +1,208 @@
+package application_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/b4nd/git-profile/pkg/application"
+	"github.com/b4nd/git-profile/pkg/domain"
+
+	"github.com/jaswdr/faker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestVerifyProfileCommitsServiceExecute(t *testing.T) {
+	faker := faker.New()
+
+	work, err := domain.NewProfile("work", "jane@acme.com", "Jane Doe")
+	assert.NoError(t, err)
+
+	newRecord := func(email string, committerEmail string) *domain.ScmCommitRecord {
+		hash, err := domain.NewScmCommitHash(faker.Hash().SHA256())
+		assert.NoError(t, err)
+
+		return &domain.ScmCommitRecord{
+			Hash:           hash,
+			AuthorName:     faker.Person().Name(),
+			AuthorEmail:    email,
+			Date:           time.Now(),
+			CommitterName:  faker.Person().Name(),
+			CommitterEmail: committerEmail,
+			CommitterDate:  time.Now(),
+		}
+	}
+
+	records := []*domain.ScmCommitRecord{
+		newRecord("Jane@Acme.com", "jane@acme.com"),
+		newRecord("jane@gmail.com", "bot@ci.eng.acme.io"),
+		newRecord("john@acme.io", "noreply@github.com"),
+	}
+
+	base, err := domain.NewScmCommitHash("origin/main")
+	assert.NoError(t, err)
+
+	head := domain.NewScmCommitHashHead()
+	noPolicy := (*domain.ProfilePolicy)(nil)
+
+	t.Run("should report the identities outside of the allowed profiles and domains", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+		mockProfilePolicyRepository := &MockProfilePolicyRepository{}
+		mockCommitRepository := &MockCommitRepository{}
+
+		mockProfilePolicyRepository.On("Get", "").Return(noPolicy, domain.ErrProfilePolicyNotFound)
+		mockProfileRepository.On("Get", work.Workspace()).Return(work, nil)
+		mockCommitRepository.On("Walk", &head, []domain.ScmCommitHash{base}, mock.Anything).Return(records, nil)
+
+		service := application.NewVerifyProfileCommitsService(mockProfileRepository, mockProfilePolicyRepository, mockCommitRepository)
+		result, err := service.Execute(application.VerifyProfileCommitsServiceParams{
+			Range:        "origin/main..HEAD",
+			Allow:        []string{"work"},
+			AllowDomains: []string{"@ACME.io"},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, result.Commits)
+		assert.Len(t, result.Violations, 2)
+
+		assert.Equal(t, records[1].Hash, result.Violations[0].Hash)
+		assert.Equal(t, application.VerifyRoleAuthor, result.Violations[0].Role)
+		assert.Equal(t, "jane@gmail.com", result.Violations[0].Email)
+
+		assert.Equal(t, records[2].Hash, result.Violations[1].Hash)
+		assert.Equal(t, application.VerifyRoleCommitter, result.Violations[1].Role)
+		assert.Equal(t, "noreply@github.com", result.Violations[1].Email)
+
+		mockProfileRepository.AssertExpectations(t)
+		mockCommitRepository.AssertExpectations(t)
+	})
+
+	t.Run("should walk the commits of the range as git reads it", func(t *testing.T) {
+		hash := func(value string) domain.ScmCommitHash {
+			hash, err := domain.NewScmCommitHash(value)
+			assert.NoError(t, err)
+
+			return hash
+		}
+
+		tests := []struct {
+			revision string
+			tip      domain.ScmCommitHash
+			exclude  []domain.ScmCommitHash
+		}{
+			{"", head, nil},
+			{"v1.0", hash("v1.0"), nil},
+			{"origin/main..HEAD", head, []domain.ScmCommitHash{base}},
+			{"origin/main..", head, []domain.ScmCommitHash{base}},
+			{"..origin/main", base, []domain.ScmCommitHash{head}},
+			{"..HEAD", head, []domain.ScmCommitHash{head}},
+			{"origin/main...HEAD", hash("origin/main...HEAD"), nil},
+			{"origin/main...", hash("origin/main...HEAD"), nil},
+			{"...origin/main", hash("HEAD...origin/main"), nil},
+		}
+
+		for _, test := range tests {
+			mockProfileRepository := &MockProfileRepository{}
+			mockProfilePolicyRepository := &MockProfilePolicyRepository{}
+			mockCommitRepository := &MockCommitRepository{}
+
+			mockProfilePolicyRepository.On("Get", "").Return(noPolicy, domain.ErrProfilePolicyNotFound)
+			mockCommitRepository.On("Walk", &test.tip, test.exclude, mock.Anything).Return(records, nil)
+
+			service := application.NewVerifyProfileCommitsService(mockProfileRepository, mockProfilePolicyRepository, mockCommitRepository)
+			result, err := service.Execute(application.VerifyProfileCommitsServiceParams{
+				Range:        test.revision,
+				AllowDomains: []string{"acme.com"},
+			})
+
+			assert.NoError(t, err, test.revision)
+			assert.Equal(t, 3, result.Commits, test.revision)
+			mockCommitRepository.AssertExpectations(t)
+		}
+	})
+
+	t.Run("should allow the identities of the policy file without profiles", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+		mockProfilePolicyRepository := &MockProfilePolicyRepository{}
+		mockCommitRepository := &MockCommitRepository{}
+
+		mockProfilePolicyRepository.On("Get", "policy.ini").Return(&domain.ProfilePolicy{
+			Allow:        []string{"jane@gmail.com", "noreply@github.com"},
+			AllowDomains: []string{"acme.com", "acme.io"},
+		}, nil)
+		mockCommitRepository.On("Walk", &head, []domain.ScmCommitHash(nil), mock.Anything).Return(records, nil)
+
+		service := application.NewVerifyProfileCommitsService(mockProfileRepository, mockProfilePolicyRepository, mockCommitRepository)
+		result, err := service.Execute(application.VerifyProfileCommitsServiceParams{Policy: "policy.ini"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, result.Commits)
+		assert.Empty(t, result.Violations)
+
+		mockProfileRepository.AssertNotCalled(t, "Get", mock.Anything)
+	})
+
+	t.Run("should return an error when nothing is allowed", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+		mockProfilePolicyRepository := &MockProfilePolicyRepository{}
+		mockCommitRepository := &MockCommitRepository{}
+
+		mockProfilePolicyRepository.On("Get", "").Return(noPolicy, domain.ErrProfilePolicyNotFound)
+
+		service := application.NewVerifyProfileCommitsService(mockProfileRepository, mockProfilePolicyRepository, mockCommitRepository)
+		result, err := service.Execute(application.VerifyProfileCommitsServiceParams{})
+
+		assert.ErrorIs(t, err, application.ErrPolicyEmpty)
+		assert.Nil(t, result)
+		mockCommitRepository.AssertNotCalled(t, "Walk", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return an error when the policy file given does not exist", func(t *testing.T) {
+		mockProfileRepository := &MockProfileRepository{}
+		mockProfilePolicyRepository := &MockProfilePolicyRepository{}
+		mockCommitRepository := &MockCommitRepository{}
+
+		mockProfilePolicyRepository.On("Get", "missing.ini").Return(noPolicy, domain.ErrProfilePolicyNotFound)
+
+		service := application.NewVerifyProfileCommitsService(mockProfileRepository, mockProfilePolicyRepository, mockCommitRepository)
+		result, err := service.Execute(application.VerifyProfileCommitsServiceParams{
+			Allow:  []string{"jane@acme.com"},
+			Policy: "missing.ini",
+		})
+
+		assert.ErrorIs(t, err, domain.ErrProfilePolicyNotFound)
+		assert.Nil(t, result)
+	})
+
+	t.Run("should return an error when an allowed entry is not valid", func(t *testing.T) {
+		for allow, expected := range map[string]error{
+			"personal": application.ErrPolicyProfileNotExists,
+			"jane@":    domain.ErrInvalidEmail,
+		} {
+			mockProfileRepository := &MockProfileRepository{}
+			mockProfilePolicyRepository := &MockProfilePolicyRepository{}
+			mockCommitRepository := &MockCommitRepository{}
+
+			personal, err := domain.NewProfileWorkspace("personal")
+			assert.NoError(t, err)
+
+			mockProfilePolicyRepository.On("Get", "").Return(noPolicy, domain.ErrProfilePolicyNotFound)
+			mockProfileRepository.On("Get", personal).Return(&domain.Profile{}, domain.ErrInvalidWorkspace).Maybe()
+
+			service := application.NewVerifyProfileCommitsService(mockProfileRepository, mockProfilePolicyRepository, mockCommitRepository)
+			result, err := service.Execute(application.VerifyProfileCommitsServiceParams{Allow: []string{allow}})
+
+			assert.ErrorIs(t, err, expected, allow)
+			assert.ErrorContains(t, err, allow)
+			assert.Nil(t, result)
+		}
+
+		mockProfilePolicyRepository := &MockProfilePolicyRepository{}
+		mockProfilePolicyRepository.On("Get", "").Return(noPolicy, domain.ErrProfilePolicyNotFound)
+
+		service := application.NewVerifyProfileCommitsService(&MockProfileRepository{}, mockProfilePolicyRepository, &MockCommitRepository{})
+		_, err := service.Execute(application.VerifyProfileCommitsServiceParams{AllowDomains: []string{"jane@acme.com"}})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidPolicyDomain)
+	})
+}
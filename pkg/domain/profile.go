@@ -1,9 +1,22 @@
 package domain
 
+import (
+	"sort"
+	"strings"
+)
+
 type Profile struct {
 	workspace ProfileWorkspace
 	email     ProfileEmail
 	name      ProfileName
+	signing   ProfileSigning
+	sshKey    ProfileSshKey
+	remotes   []ProfileRemotePattern
+	usernames []ProfileUsername
+	config    ProfileConfig
+	source    ProfileSource
+	extends   ProfileWorkspace
+	origins   map[ProfileField]ProfileWorkspace
 }
 
 const NotConfiguredWorkspace = "(not configured)"
@@ -63,8 +76,159 @@ func (p Profile) Name() ProfileName {
 	return p.name
 }
 
+func (p Profile) Signing() ProfileSigning {
+	return p.signing
+}
+
+// WithSigning returns a copy of the profile using the given signing configuration
+func (p Profile) WithSigning(signing ProfileSigning) *Profile {
+	p.signing = signing
+	return &p
+}
+
+func (p Profile) SshKey() ProfileSshKey {
+	return p.sshKey
+}
+
+// WithSshKey returns a copy of the profile using the given ssh key
+func (p Profile) WithSshKey(sshKey ProfileSshKey) *Profile {
+	p.sshKey = sshKey
+	return &p
+}
+
+func (p Profile) Remotes() []ProfileRemotePattern {
+	return p.remotes
+}
+
+// WithRemotes returns a copy of the profile using the given remote url patterns
+func (p Profile) WithRemotes(remotes []ProfileRemotePattern) *Profile {
+	p.remotes = remotes
+	return &p
+}
+
+func (p Profile) Usernames() []ProfileUsername {
+	return p.usernames
+}
+
+// WithUsernames returns a copy of the profile using the given usernames, sorted by host
+func (p Profile) WithUsernames(usernames []ProfileUsername) *Profile {
+	p.usernames = make([]ProfileUsername, len(usernames))
+	copy(p.usernames, usernames)
+	sort.Slice(p.usernames, func(i, j int) bool {
+		return p.usernames[i].host < p.usernames[j].host
+	})
+
+	return &p
+}
+
+// Username returns the username of the profile on the host, the host is
+// given as git sends it to a credential helper: github.com or example.com:8443
+func (p Profile) Username(host string) (string, bool) {
+	for _, username := range p.usernames {
+		if strings.EqualFold(username.host, host) {
+			return username.username, true
+		}
+	}
+
+	return "", false
+}
+
+// Config returns the extra git configuration applied with the profile
+func (p Profile) Config() ProfileConfig {
+	return p.config
+}
+
+// WithConfig returns a copy of the profile using the given git configuration
+func (p Profile) WithConfig(config ProfileConfig) *Profile {
+	p.config = config
+	return &p
+}
+
+// MatchRemote reports whether any remote url pattern of the profile matches the url
+func (p Profile) MatchRemote(url string) bool {
+	for _, remote := range p.remotes {
+		if remote.Match(url) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Source returns the file the profile was read from, it is not part of the profile identity
+func (p Profile) Source() ProfileSource {
+	return p.source
+}
+
+// WithSource returns a copy of the profile read from the given file
+func (p Profile) WithSource(source ProfileSource) *Profile {
+	p.source = source
+	return &p
+}
+
+// Extends returns the workspace of the profile whose fields are inherited,
+// it is empty when the profile does not extend another one
+func (p Profile) Extends() ProfileWorkspace {
+	return p.extends
+}
+
+// WithExtends returns a copy of the profile extending the given workspace
+func (p Profile) WithExtends(extends ProfileWorkspace) *Profile {
+	p.extends = extends
+	return &p
+}
+
+// Origin returns the workspace of the profile that defines the field, the
+// profile itself unless the field is inherited
+func (p Profile) Origin(field ProfileField) ProfileWorkspace {
+	if origin, ok := p.origins[field]; ok {
+		return origin
+	}
+
+	return p.workspace
+}
+
+// WithOrigins returns a copy of the profile whose fields are defined by the given workspaces
+func (p Profile) WithOrigins(origins map[ProfileField]ProfileWorkspace) *Profile {
+	p.origins = origins
+	return &p
+}
+
 func (p Profile) Equals(profile *Profile) bool {
 	return p.workspace.Equals(profile.workspace) &&
 		p.email.Equals(profile.email) &&
-		p.name.Equals(profile.name)
+		p.name.Equals(profile.name) &&
+		p.signing.Equals(profile.signing) &&
+		p.sshKey.Equals(profile.sshKey) &&
+		equalsRemotes(p.remotes, profile.remotes) &&
+		equalsUsernames(p.usernames, profile.usernames) &&
+		p.config.Equals(profile.config)
+}
+
+func equalsRemotes(a []ProfileRemotePattern, b []ProfileRemotePattern) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if !a[i].Equals(b[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func equalsUsernames(a []ProfileUsername, b []ProfileUsername) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if !a[i].Equals(b[i]) {
+			return false
+		}
+	}
+
+	return true
 }
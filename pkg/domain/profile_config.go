@@ -0,0 +1,146 @@
+package domain
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var ErrInvalidConfigKey = errors.New("invalid config key")
+var ErrReservedConfigKey = errors.New("reserved config key")
+var ErrInvalidConfigValue = errors.New("invalid config value")
+
+// invalidConfigValueCharacters would need quoting or escaping in the git configuration
+const invalidConfigValueCharacters = "\n\r\"\\#;`"
+
+// reservedConfigKeys are applied from the other fields of the profile
+var reservedConfigKeys = []string{
+	"user.workspace", "user.name", "user.email", "user.signingkey",
+	"gpg.format", "commit.gpgsign", "tag.gpgsign", "core.sshcommand",
+	"credential.helper",
+}
+
+// reservedConfigSection holds the keys git profile writes for itself
+const reservedConfigSection = "gitprofile"
+
+var regexConfigSection = regexp.MustCompile(`^[a-zA-Z0-9-]+$`)
+var regexConfigName = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9-]*$`)
+
+// ProfileConfigEntry is a git configuration key applied with the profile,
+// such as pull.rebase or url.git@github.com:.insteadOf
+type ProfileConfigEntry struct {
+	key   string
+	value string
+}
+
+func (e ProfileConfigEntry) Key() string {
+	return e.key
+}
+
+func (e ProfileConfigEntry) Value() string {
+	return e.value
+}
+
+// ProfileConfig is the extra git configuration of a profile, sorted by key
+type ProfileConfig struct {
+	entries []ProfileConfigEntry
+}
+
+// NewProfileConfig validates the keys as git does, the section and the name
+// of the keys are case insensitive and stored in lower case
+func NewProfileConfig(values map[string]string) (ProfileConfig, error) {
+	entries := make([]ProfileConfigEntry, 0, len(values))
+	seen := map[string]bool{}
+
+	for key, value := range values {
+		normalized, err := NormalizeConfigKey(key)
+		if err != nil {
+			return ProfileConfig{}, err
+		}
+
+		if seen[normalized] {
+			return ProfileConfig{}, ErrInvalidConfigKey
+		}
+
+		if strings.ContainsAny(value, invalidConfigValueCharacters) {
+			return ProfileConfig{}, ErrInvalidConfigValue
+		}
+
+		seen[normalized] = true
+		entries = append(entries, ProfileConfigEntry{key: normalized, value: value})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].key < entries[j].key
+	})
+
+	return ProfileConfig{entries: entries}, nil
+}
+
+// NormalizeConfigKey returns the key as it is stored in the profile,
+// an error when git would not accept it or git profile applies it itself
+func NormalizeConfigKey(key string) (string, error) {
+	key = strings.TrimSpace(key)
+
+	first, last := strings.Index(key, "."), strings.LastIndex(key, ".")
+	if first <= 0 || last == len(key)-1 {
+		return "", ErrInvalidConfigKey
+	}
+
+	// The subsection, between the section and the name, keeps its case
+	section, subsection, name := strings.ToLower(key[:first]), key[first:last], strings.ToLower(key[last+1:])
+	if !regexConfigSection.MatchString(section) || !regexConfigName.MatchString(name) || subsection == "." {
+		return "", ErrInvalidConfigKey
+	}
+
+	if section == reservedConfigSection {
+		return "", ErrReservedConfigKey
+	}
+
+	normalized := section + subsection + "." + name
+
+	if section == "credential" && name == "username" {
+		return "", ErrReservedConfigKey
+	}
+
+	for _, reserved := range reservedConfigKeys {
+		if normalized == reserved {
+			return "", ErrReservedConfigKey
+		}
+	}
+
+	return normalized, nil
+}
+
+func (c ProfileConfig) Entries() []ProfileConfigEntry {
+	return c.entries
+}
+
+func (c ProfileConfig) IsEmpty() bool {
+	return len(c.entries) == 0
+}
+
+// Values returns the keys and values of the configuration
+func (c ProfileConfig) Values() map[string]string {
+	values := make(map[string]string, len(c.entries))
+	for _, entry := range c.entries {
+		values[entry.key] = entry.value
+	}
+
+	return values
+}
+
+func (c ProfileConfig) Equals(config ProfileConfig) bool {
+	if len(c.entries) != len(config.entries) {
+		return false
+	}
+
+	for i := range c.entries {
+		if c.entries[i] != config.entries[i] {
+			return false
+		}
+	}
+
+	return true
+}
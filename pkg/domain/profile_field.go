@@ -0,0 +1,30 @@
+package domain
+
+import "errors"
+
+var ErrProfileExtendsCycle = errors.New("profile extends cycle")
+var ErrProfileParentNotExists = errors.New("profile parent not exists")
+
+// ProfileField is a field of a profile that can be inherited from the profile it extends
+type ProfileField string
+
+const (
+	ProfileFieldEmail     ProfileField = "email"
+	ProfileFieldName      ProfileField = "name"
+	ProfileFieldSigning   ProfileField = "signing"
+	ProfileFieldSshKey    ProfileField = "sshKey"
+	ProfileFieldRemotes   ProfileField = "remotes"
+	ProfileFieldUsernames ProfileField = "usernames"
+	ProfileFieldConfig    ProfileField = "config"
+)
+
+// ProfileFields are the inheritable fields, in the order they are shown
+var ProfileFields = []ProfileField{
+	ProfileFieldEmail,
+	ProfileFieldName,
+	ProfileFieldSigning,
+	ProfileFieldSshKey,
+	ProfileFieldRemotes,
+	ProfileFieldUsernames,
+	ProfileFieldConfig,
+}
@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrInvalidPolicyDomain = errors.New("invalid policy domain")
+
+// ProfilePolicy lists the identities allowed to author and commit in a
+// repository. Allow holds workspaces of profiles or emails, AllowDomains the
+// email domains whose addresses are all allowed, with their subdomains.
+type ProfilePolicy struct {
+	Allow        []string
+	AllowDomains []string
+}
+
+// NewProfilePolicyDomain normalizes a domain of a policy, a leading @ is ignored
+func NewProfilePolicyDomain(value string) (string, error) {
+	domain := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(value), "@"))
+	if domain == "" || strings.ContainsAny(domain, "@ \t") || strings.HasPrefix(domain, ".") || strings.HasSuffix(domain, ".") {
+		return "", ErrInvalidPolicyDomain
+	}
+
+	return domain, nil
+}
+
+// IsEmpty reports whether the policy allows no identity
+func (p ProfilePolicy) IsEmpty() bool {
+	return len(p.Allow) == 0 && len(p.AllowDomains) == 0
+}
@@ -0,0 +1,11 @@
+package domain
+
+import "errors"
+
+var ErrProfilePolicyNotFound = errors.New("profile policy not found")
+
+type ProfilePolicyRepository interface {
+	// Get reads the policy of the file, or the policy of the repository when
+	// the path is empty
+	Get(path string) (*ProfilePolicy, error)
+}
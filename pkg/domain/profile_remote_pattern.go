@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+type ProfileRemotePattern struct {
+	value string
+	regex *regexp.Regexp
+}
+
+var ErrInvalidRemotePattern = errors.New("invalid remote pattern")
+
+// NewProfileRemotePattern creates a pattern matching remote urls, such as
+// github.com:acme-corp/* or gitlab.internal/*. The ssh, scp-like and https
+// forms of an url are equivalent and "*" matches any sequence of characters.
+func NewProfileRemotePattern(value string) (ProfileRemotePattern, error) {
+	pattern := strings.TrimSpace(value)
+
+	if utf8.RuneCountInString(pattern) == 0 || strings.ContainsAny(pattern, ", \t\n") {
+		return ProfileRemotePattern{}, ErrInvalidRemotePattern
+	}
+
+	expression := regexp.QuoteMeta(normalizeRemoteUrl(pattern))
+	expression = strings.ReplaceAll(expression, `\*`, `.*`)
+	expression = strings.ReplaceAll(expression, `\?`, `.`)
+
+	regex, err := regexp.Compile("^" + expression + "$")
+	if err != nil {
+		return ProfileRemotePattern{}, ErrInvalidRemotePattern
+	}
+
+	return ProfileRemotePattern{value: pattern, regex: regex}, nil
+}
+
+// Match reports whether the remote url matches the pattern
+func (p ProfileRemotePattern) Match(url string) bool {
+	return p.regex != nil && p.regex.MatchString(normalizeRemoteUrl(url))
+}
+
+func (p ProfileRemotePattern) Equals(pattern ProfileRemotePattern) bool {
+	return p.value == pattern.value
+}
+
+func (p ProfileRemotePattern) String() string {
+	return p.value
+}
+
+// normalizeRemoteUrl reduces an url to host/path, so that
+// git@github.com:acme/repo.git and https://github.com/acme/repo are equal
+func normalizeRemoteUrl(url string) string {
+	url = strings.TrimSpace(url)
+
+	if scheme := strings.Index(url, "://"); scheme >= 0 {
+		url = url[scheme+3:]
+
+		host, path, _ := strings.Cut(url, "/")
+		if at := strings.LastIndex(host, "@"); at >= 0 {
+			host = host[at+1:]
+		}
+
+		if port := strings.LastIndex(host, ":"); port >= 0 {
+			host = host[:port]
+		}
+
+		url = strings.ToLower(host) + "/" + path
+	} else if colon := strings.Index(url, ":"); colon >= 0 && !strings.Contains(url[:colon], "/") {
+		// scp-like syntax: [user@]host:path
+		host := url[:colon]
+		if at := strings.LastIndex(host, "@"); at >= 0 {
+			host = host[at+1:]
+		}
+
+		url = strings.ToLower(host) + "/" + strings.TrimPrefix(url[colon+1:], "/")
+	}
+
+	url = strings.TrimSuffix(url, "/")
+	return strings.TrimSuffix(url, ".git")
+}
@@ -5,7 +5,13 @@ type ProfileRepository interface {
 
 	Save(profile *Profile) error
 
+	// SaveAll saves the profiles at once, none of them is saved on error
+	SaveAll(profiles []*Profile) error
+
 	Delete(workspace ProfileWorkspace) error
 
+	// Rename moves the profile to the new workspace in the files that hold it
+	Rename(workspace ProfileWorkspace, newWorkspace ProfileWorkspace) error
+
 	List() ([]*Profile, error)
 }
@@ -0,0 +1,36 @@
+package domain
+
+type ProfileRule struct {
+	workspace ProfileWorkspace
+	condition ProfileRuleCondition
+}
+
+func NewProfileRule(workspace string, condition string) (*ProfileRule, error) {
+	w, err := NewProfileWorkspace(workspace)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := NewProfileRuleCondition(condition)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ProfileRule{
+		workspace: w,
+		condition: c,
+	}, nil
+}
+
+func (r ProfileRule) Workspace() ProfileWorkspace {
+	return r.workspace
+}
+
+func (r ProfileRule) Condition() ProfileRuleCondition {
+	return r.condition
+}
+
+func (r ProfileRule) Equals(rule *ProfileRule) bool {
+	return r.workspace.Equals(rule.workspace) &&
+		r.condition.Equals(rule.condition)
+}
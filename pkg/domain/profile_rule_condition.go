@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+)
+
+type ProfileRuleCondition struct {
+	value string
+}
+
+var ErrInvalidRuleCondition = errors.New("invalid rule condition")
+
+// Prefixes of the includeIf conditions supported by git
+const (
+	RuleConditionGitDir  = "gitdir:"
+	RuleConditionGitDirI = "gitdir/i:"
+	RuleConditionBranch  = "onbranch:"
+	RuleConditionRemote  = "hasconfig:remote.*.url:"
+)
+
+var ruleConditionPrefixes = []string{
+	RuleConditionGitDir,
+	RuleConditionGitDirI,
+	RuleConditionBranch,
+	RuleConditionRemote,
+}
+
+func NewProfileRuleCondition(value string) (ProfileRuleCondition, error) {
+	condition := strings.TrimSpace(value)
+
+	if strings.ContainsAny(condition, "\"\n\r") {
+		return ProfileRuleCondition{}, ErrInvalidRuleCondition
+	}
+
+	for _, prefix := range ruleConditionPrefixes {
+		if !strings.HasPrefix(condition, prefix) {
+			continue
+		}
+
+		if utf8.RuneCountInString(strings.TrimPrefix(condition, prefix)) == 0 {
+			return ProfileRuleCondition{}, ErrInvalidRuleCondition
+		}
+
+		return ProfileRuleCondition{value: condition}, nil
+	}
+
+	return ProfileRuleCondition{}, ErrInvalidRuleCondition
+}
+
+// NewProfileRuleGitDir creates a condition matching every repository inside the directory
+func NewProfileRuleGitDir(dir string) (ProfileRuleCondition, error) {
+	dir = strings.TrimSpace(dir)
+	if dir != "" && !strings.HasSuffix(dir, "/") {
+		dir += "/"
+	}
+
+	return NewProfileRuleCondition(RuleConditionGitDir + dir)
+}
+
+// NewProfileRuleRemote creates a condition matching every repository with a remote url matching the pattern
+func NewProfileRuleRemote(url string) (ProfileRuleCondition, error) {
+	return NewProfileRuleCondition(RuleConditionRemote + strings.TrimSpace(url))
+}
+
+func (c ProfileRuleCondition) Equals(condition ProfileRuleCondition) bool {
+	return c.value == condition.value
+}
+
+func (c ProfileRuleCondition) String() string {
+	return c.value
+}
@@ -0,0 +1,9 @@
+package domain
+
+type ProfileRuleRepository interface {
+	List() ([]*ProfileRule, error)
+
+	Save(rule *ProfileRule) error
+
+	Delete(condition ProfileRuleCondition) error
+}
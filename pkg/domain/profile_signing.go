@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+)
+
+type ProfileSigning struct {
+	key        string
+	format     string
+	commitSign bool
+	tagSign    bool
+}
+
+var ErrInvalidSigningKey = errors.New("invalid signing key")
+var ErrInvalidSigningFormat = errors.New("invalid signing format")
+
+// Signing formats supported by git through the gpg.format option
+const (
+	SigningFormatOpenPGP = "openpgp"
+	SigningFormatSSH     = "ssh"
+	SigningFormatX509    = "x509"
+)
+
+var signingFormats = []string{SigningFormatOpenPGP, SigningFormatSSH, SigningFormatX509}
+
+func NewProfileSigning(key string, format string, commitSign bool, tagSign bool) (ProfileSigning, error) {
+	key = strings.TrimSpace(key)
+	if utf8.RuneCountInString(key) == 0 {
+		return ProfileSigning{}, ErrInvalidSigningKey
+	}
+
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "" {
+		format = SigningFormatOpenPGP
+	}
+
+	if !isSigningFormat(format) {
+		return ProfileSigning{}, ErrInvalidSigningFormat
+	}
+
+	return ProfileSigning{
+		key:        key,
+		format:     format,
+		commitSign: commitSign,
+		tagSign:    tagSign,
+	}, nil
+}
+
+func isSigningFormat(format string) bool {
+	for _, f := range signingFormats {
+		if f == format {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s ProfileSigning) Key() string {
+	return s.key
+}
+
+func (s ProfileSigning) Format() string {
+	return s.format
+}
+
+func (s ProfileSigning) CommitSign() bool {
+	return s.commitSign
+}
+
+func (s ProfileSigning) TagSign() bool {
+	return s.tagSign
+}
+
+// IsEmpty reports whether the profile does not define a signing key
+func (s ProfileSigning) IsEmpty() bool {
+	return s.key == ""
+}
+
+func (s ProfileSigning) Equals(signing ProfileSigning) bool {
+	return s.key == signing.key &&
+		s.format == signing.format &&
+		s.commitSign == signing.commitSign &&
+		s.tagSign == signing.tagSign
+}
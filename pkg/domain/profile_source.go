@@ -0,0 +1,37 @@
+package domain
+
+// ProfileScope tells whether a profile file is shared by every repository or
+// belongs to the current directory
+type ProfileScope string
+
+const (
+	ProfileScopeGlobal ProfileScope = "global"
+	ProfileScopeLocal  ProfileScope = "local"
+)
+
+// ProfileSource is the file a profile was read from
+type ProfileSource struct {
+	path  string
+	scope ProfileScope
+}
+
+func NewProfileSource(path string, scope ProfileScope) ProfileSource {
+	return ProfileSource{path: path, scope: scope}
+}
+
+// IsEmpty reports whether the profile was not read from a file
+func (s ProfileSource) IsEmpty() bool {
+	return s.path == ""
+}
+
+func (s ProfileSource) Path() string {
+	return s.path
+}
+
+func (s ProfileSource) Scope() ProfileScope {
+	return s.scope
+}
+
+func (s ProfileSource) String() string {
+	return s.path
+}
@@ -0,0 +1,17 @@
+package domain
+
+// ProfileSourceReport tells what a profile file holds, Err is set when the
+// file cannot be parsed
+type ProfileSourceReport struct {
+	Source ProfileSource
+	// Workspaces are the profiles of the file, in order
+	Workspaces []string
+	// Duplicates are the workspaces with more than one section in the file
+	Duplicates []string
+	Err        error
+}
+
+type ProfileSourceRepository interface {
+	// Reports returns the report of every existing profile file, in the order they are read
+	Reports() ([]*ProfileSourceReport, error)
+}
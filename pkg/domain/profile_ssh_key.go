@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+)
+
+type ProfileSshKey struct {
+	value string
+}
+
+var ErrInvalidSshKey = errors.New("invalid ssh key")
+
+const sshCommandPrefix = "ssh -i "
+const sshCommandSuffix = " -o IdentitiesOnly=yes"
+
+func NewProfileSshKey(value string) (ProfileSshKey, error) {
+	key := strings.TrimSpace(value)
+
+	if utf8.RuneCountInString(key) == 0 {
+		return ProfileSshKey{}, ErrInvalidSshKey
+	}
+
+	if strings.ContainsAny(key, "\n\r'") {
+		return ProfileSshKey{}, ErrInvalidSshKey
+	}
+
+	return ProfileSshKey{value: key}, nil
+}
+
+// IsEmpty reports whether the profile does not define an ssh key
+func (k ProfileSshKey) IsEmpty() bool {
+	return k.value == ""
+}
+
+// SshCommand returns the core.sshCommand that forces ssh to use only this identity file
+func (k ProfileSshKey) SshCommand() string {
+	if k.IsEmpty() {
+		return ""
+	}
+
+	key := k.value
+	if strings.ContainsAny(key, " \t\"\\$`") {
+		key = "'" + key + "'"
+	}
+
+	return sshCommandPrefix + key + sshCommandSuffix
+}
+
+func (k ProfileSshKey) Equals(key ProfileSshKey) bool {
+	return k.value == key.value
+}
+
+func (k ProfileSshKey) String() string {
+	return k.value
+}
+
+// IsProfileSshCommand reports whether a core.sshCommand value was generated from a profile ssh key
+func IsProfileSshCommand(command string) bool {
+	return strings.HasPrefix(command, sshCommandPrefix) && strings.HasSuffix(command, sshCommandSuffix)
+}
+
+// SshCommandKey returns the identity file given with -i in a core.sshCommand,
+// it is empty when the command does not set one
+func SshCommandKey(command string) string {
+	if IsProfileSshCommand(command) {
+		key := strings.TrimSuffix(strings.TrimPrefix(command, sshCommandPrefix), sshCommandSuffix)
+		return strings.Trim(key, "'")
+	}
+
+	fields := strings.Fields(command)
+	for i, field := range fields {
+		key, ok := strings.CutPrefix(field, "-i")
+		if !ok {
+			continue
+		}
+
+		if key == "" && i+1 < len(fields) {
+			key = fields[i+1]
+		}
+
+		return strings.Trim(key, "'\"")
+	}
+
+	return ""
+}
@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+var ErrInvalidUsernameHost = errors.New("invalid username host")
+var ErrInvalidUsername = errors.New("invalid username")
+
+var regexUsernameHost = regexp.MustCompile(`^[a-z0-9]([a-z0-9.-]*[a-z0-9])?(:[0-9]+)?$`)
+
+// ProfileUsername is the account used by the profile to authenticate on an
+// https host, such as jane-acme on github.com
+type ProfileUsername struct {
+	host     string
+	username string
+}
+
+// NewProfileUsername creates the username of a host, the host is given without
+// scheme nor path and may have a port: github.com or gitlab.internal:8443
+func NewProfileUsername(host string, username string) (ProfileUsername, error) {
+	host = strings.ToLower(strings.TrimSpace(host))
+	if !regexUsernameHost.MatchString(host) {
+		return ProfileUsername{}, ErrInvalidUsernameHost
+	}
+
+	username = strings.TrimSpace(username)
+	if username == "" || strings.ContainsAny(username, ", \t\r\n\"\\#;`") {
+		return ProfileUsername{}, ErrInvalidUsername
+	}
+
+	return ProfileUsername{host: host, username: username}, nil
+}
+
+func (u ProfileUsername) Host() string {
+	return u.host
+}
+
+func (u ProfileUsername) Username() string {
+	return u.username
+}
+
+func (u ProfileUsername) Equals(username ProfileUsername) bool {
+	return u.host == username.host && u.username == username.username
+}
+
+// String returns the username as host=username
+func (u ProfileUsername) String() string {
+	return u.host + "=" + u.username
+}
@@ -1,19 +1,40 @@
 package domain
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 type ScmCommit struct {
-	Hash    ScmCommitHash
-	Author  ScmCommitAuthor
-	Date    time.Time
-	Message string
+	Hash          ScmCommitHash
+	Author        ScmCommitAuthor
+	Committer     ScmCommitAuthor
+	Date          time.Time
+	CommitterDate time.Time
+	Message       string
+	Parents       []ScmCommitHash
+	Signature     ScmCommitSignature
 }
 
 func NewScmCommit(hash ScmCommitHash, author ScmCommitAuthor, date time.Time, message string) *ScmCommit {
 	return &ScmCommit{
-		Hash:    hash,
-		Author:  author,
-		Date:    date,
-		Message: message,
+		Hash:          hash,
+		Author:        author,
+		Committer:     author,
+		Date:          date,
+		CommitterDate: date,
+		Message:       message,
+		Signature:     ScmCommitSignatureNone,
 	}
 }
+
+// Subject returns the first line of the message
+func (c ScmCommit) Subject() string {
+	subject, _, _ := strings.Cut(c.Message, "\n")
+	return subject
+}
+
+// IsMerge reports whether the commit has more than one parent
+func (c ScmCommit) IsMerge() bool {
+	return len(c.Parents) > 1
+}
@@ -0,0 +1,50 @@
+package domain
+
+// ScmCommitDate tells which dates of a commit are kept when it is amended
+type ScmCommitDate int
+
+const (
+	// ScmCommitDateDefault keeps the author date and sets the committer date to now, as git does
+	ScmCommitDateDefault ScmCommitDate = iota
+	// ScmCommitDateKeep keeps the author and committer dates
+	ScmCommitDateKeep
+	// ScmCommitDateReset sets the author and committer dates to now
+	ScmCommitDateReset
+)
+
+// ScmCommitAmend is the identity given to an amended commit
+type ScmCommitAmend struct {
+	// Author is nil when the author of the commit is kept
+	Author    *ScmCommitAuthor
+	Committer ScmCommitAuthor
+	Date      ScmCommitDate
+}
+
+// NewScmCommitAmend sets the identity as author and committer, or only as
+// committer when committerOnly is true
+func NewScmCommitAmend(identity ScmCommitAuthor, committerOnly bool, date ScmCommitDate) *ScmCommitAmend {
+	amend := &ScmCommitAmend{
+		Committer: identity,
+		Date:      date,
+	}
+
+	if !committerOnly {
+		amend.Author = &identity
+	}
+
+	return amend
+}
+
+// IsAppliedTo reports whether the commit already has the identities of the amend
+func (a ScmCommitAmend) IsAppliedTo(commit *ScmCommit) bool {
+	if a.Author != nil && !isSameScmCommitAuthor(commit.Author, *a.Author) {
+		return false
+	}
+
+	return isSameScmCommitAuthor(commit.Committer, a.Committer)
+}
+
+// isSameScmCommitAuthor compares the identities the way git writes them
+func isSameScmCommitAuthor(a ScmCommitAuthor, b ScmCommitAuthor) bool {
+	return IsSameScmIdentity(a.Name(), a.Email(), b.Name(), b.Email())
+}
@@ -35,6 +35,13 @@ func NewScmCommitAuthor(name string, email string) (ScmCommitAuthor, error) {
 	return ScmCommitAuthor{email, name}, nil
 }
 
+// NewScmCommitAuthorAsWritten keeps the identity of a commit read from git as
+// it was written, git accepts emails that are not valid addresses and only the
+// identities written with a profile are validated
+func NewScmCommitAuthorAsWritten(name string, email string) ScmCommitAuthor {
+	return ScmCommitAuthor{email, name}
+}
+
 func (n ScmCommitAuthor) Name() string {
 	return n.name
 }
@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+// ScmCommitRecord is a commit of the history read for an audit, the identities
+// are kept as written in the commit, old commits may have identities that are
+// not valid anymore
+type ScmCommitRecord struct {
+	Hash           ScmCommitHash
+	AuthorName     string
+	AuthorEmail    string
+	Date           time.Time
+	CommitterName  string
+	CommitterEmail string
+	CommitterDate  time.Time
+}
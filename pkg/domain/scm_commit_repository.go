@@ -7,5 +7,24 @@ var ErrScmCommitNotFound = errors.New("scm commit not found")
 type ScmCommitRepository interface {
 	Get(hash *ScmCommitHash) (*ScmCommit, error)
 
-	Save(author *ScmCommitAuthor) error
+	// List returns the commits reachable from tip and not from the excluded
+	// commits, the parents are listed before their children. The signatures
+	// are not verified, only Get reads them
+	List(tip *ScmCommitHash, exclude []ScmCommitHash) ([]*ScmCommit, error)
+
+	// Walk visits the records of the commits reachable from tip and not from
+	// the excluded commits, the children before their parents and the newest
+	// first otherwise, without holding the history
+	Walk(tip *ScmCommitHash, exclude []ScmCommitHash, visit func(record *ScmCommitRecord) error) error
+
+	// IsPushed reports whether the commit is reachable from a remote branch
+	IsPushed(hash *ScmCommitHash) (bool, error)
+
+	// Save amends the last commit with the identities of the amend
+	Save(amend *ScmCommitAmend) error
+
+	// Rewrite recreates the listed commits on top of their rewritten parents,
+	// the targets get the identities of the amend, and moves HEAD to the
+	// rewritten last commit
+	Rewrite(commits []*ScmCommit, targets []ScmCommitHash, amend *ScmCommitAmend) ([]*ScmCommitRewrite, error)
 }
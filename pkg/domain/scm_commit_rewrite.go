@@ -0,0 +1,14 @@
+package domain
+
+// ScmCommitRewrite maps a commit to the commit that replaced it
+type ScmCommitRewrite struct {
+	Old ScmCommitHash
+	New ScmCommitHash
+}
+
+func NewScmCommitRewrite(old ScmCommitHash, new ScmCommitHash) *ScmCommitRewrite {
+	return &ScmCommitRewrite{
+		Old: old,
+		New: new,
+	}
+}
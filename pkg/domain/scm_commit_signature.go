@@ -0,0 +1,33 @@
+package domain
+
+// ScmCommitSignature is the status of the signature of a commit, as reported
+// by git with the %G? placeholder
+type ScmCommitSignature string
+
+const (
+	ScmCommitSignatureNone           ScmCommitSignature = "N"
+	ScmCommitSignatureGood           ScmCommitSignature = "G"
+	ScmCommitSignatureBad            ScmCommitSignature = "B"
+	ScmCommitSignatureUnknown        ScmCommitSignature = "U"
+	ScmCommitSignatureExpired        ScmCommitSignature = "X"
+	ScmCommitSignatureExpiredKey     ScmCommitSignature = "Y"
+	ScmCommitSignatureRevokedKey     ScmCommitSignature = "R"
+	ScmCommitSignatureCannotValidate ScmCommitSignature = "E"
+	// ScmCommitSignatureNotRead is the status of the commits listed in bulk,
+	// their signatures are not verified
+	ScmCommitSignatureNotRead ScmCommitSignature = ""
+)
+
+// IsSigned reports whether the commit carries a signature, valid or not
+func (s ScmCommitSignature) IsSigned() bool {
+	return s != "" && s != ScmCommitSignatureNone
+}
+
+// IsValid reports whether the signature is good
+func (s ScmCommitSignature) IsValid() bool {
+	return s == ScmCommitSignatureGood || s == ScmCommitSignatureUnknown
+}
+
+func (s ScmCommitSignature) String() string {
+	return string(s)
+}
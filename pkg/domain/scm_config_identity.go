@@ -0,0 +1,15 @@
+package domain
+
+// ScmConfigIdentity is a user identity found in a git configuration file
+type ScmConfigIdentity struct {
+	// Source is the configuration file that defines the identity
+	Source string
+	User   *ScmUser
+}
+
+func NewScmConfigIdentity(source string, user *ScmUser) *ScmConfigIdentity {
+	return &ScmConfigIdentity{
+		Source: source,
+		User:   user,
+	}
+}
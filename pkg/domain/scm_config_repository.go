@@ -0,0 +1,7 @@
+package domain
+
+type ScmConfigRepository interface {
+	// List returns the identities of the configuration files and the files
+	// they include, in the order the files are read
+	List() ([]*ScmConfigIdentity, error)
+}
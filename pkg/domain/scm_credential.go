@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrInvalidScmCredential = errors.New("invalid scm credential")
+
+// ScmCredential is a credential exchanged with git through the credential
+// helper protocol, a key=value attribute per line
+type ScmCredential struct {
+	Protocol string
+	Host     string
+	Path     string
+	Username string
+	Password string
+	// Attributes holds the other key=value lines, such as capability[] or
+	// password_expiry_utc, in the order they were read
+	Attributes []string
+}
+
+// NewScmCredential parses the attributes of a credential, reading stops at
+// the first empty line as git ends the credential with one
+func NewScmCredential(input string) (*ScmCredential, error) {
+	credential := &ScmCredential{}
+
+	for _, line := range strings.Split(input, "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if line == "" {
+			break
+		}
+
+		key, value, found := strings.Cut(line, "=")
+		if !found || key == "" {
+			return nil, ErrInvalidScmCredential
+		}
+
+		switch key {
+		case "protocol":
+			credential.Protocol = value
+		case "host":
+			credential.Host = value
+		case "path":
+			credential.Path = value
+		case "username":
+			credential.Username = value
+		case "password":
+			credential.Password = value
+		default:
+			credential.Attributes = append(credential.Attributes, line)
+		}
+	}
+
+	return credential, nil
+}
+
+// IsEmpty reports whether the credential has no attribute
+func (c ScmCredential) IsEmpty() bool {
+	return c.String() == ""
+}
+
+// String returns the attributes that are set, a key=value per line
+func (c ScmCredential) String() string {
+	var builder strings.Builder
+
+	for _, attribute := range [][2]string{
+		{"protocol", c.Protocol},
+		{"host", c.Host},
+		{"path", c.Path},
+		{"username", c.Username},
+		{"password", c.Password},
+	} {
+		if attribute[1] != "" {
+			builder.WriteString(attribute[0] + "=" + attribute[1] + "\n")
+		}
+	}
+
+	for _, attribute := range c.Attributes {
+		builder.WriteString(attribute + "\n")
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,17 @@
+package domain
+
+import "errors"
+
+var ErrScmCredentialHelperFailed = errors.New("scm credential helper failed")
+
+// ScmCredentialRepository stores the secrets of the credentials in a git
+// credential helper, such as cache, store or osxkeychain
+type ScmCredentialRepository interface {
+	// Get returns the credential matching the given attributes, an empty
+	// credential when the helper has none
+	Get(helper string, credential *ScmCredential) (*ScmCredential, error)
+
+	Store(helper string, credential *ScmCredential) error
+
+	Erase(helper string, credential *ScmCredential) error
+}
@@ -0,0 +1,22 @@
+package domain
+
+import "errors"
+
+var ErrScmHookNotInstalled = errors.New("scm hook not installed")
+var ErrScmHookChainExists = errors.New("scm hook chain already exists")
+
+// Hooks installed by git profile
+const (
+	ScmHookPreCommit = "pre-commit"
+	ScmHookPrePush   = "pre-push"
+)
+
+type ScmHookRepository interface {
+	// Install writes the hook, an existing hook is kept and chained after it
+	Install(hook string) error
+
+	// Uninstall removes the hook and restores the chained one
+	Uninstall(hook string) error
+
+	IsInstalled(hook string) (bool, error)
+}
@@ -0,0 +1,44 @@
+package domain
+
+import "strings"
+
+// ScmIdentity is the identity git uses for a new commit, once the local,
+// global and included configurations and the environment are resolved
+type ScmIdentity struct {
+	Workspace string
+	Email     string
+	Name      string
+}
+
+func NewScmIdentity(workspace string, email string, name string) *ScmIdentity {
+	return &ScmIdentity{
+		Workspace: workspace,
+		Email:     email,
+		Name:      name,
+	}
+}
+
+// NormalizeScmIdentityValue returns the name or email as git writes it in a
+// commit, without the spaces and the ".,:;<>\"\\'" characters around it and
+// without the "<", ">" and new lines inside it
+func NormalizeScmIdentityValue(value string) string {
+	crud := func(r rune) bool {
+		return r <= ' ' || strings.ContainsRune(".,:;<>\"\\'", r)
+	}
+
+	value = strings.TrimRightFunc(strings.TrimLeftFunc(value, crud), crud)
+	return strings.Map(func(r rune) rune {
+		if r == '\n' || r == '<' || r == '>' {
+			return -1
+		}
+
+		return r
+	}, value)
+}
+
+// IsSameScmIdentity reports whether the names and emails are the same once
+// normalized as git does, the emails ignoring the case
+func IsSameScmIdentity(name string, email string, otherName string, otherEmail string) bool {
+	return NormalizeScmIdentityValue(name) == NormalizeScmIdentityValue(otherName) &&
+		strings.EqualFold(NormalizeScmIdentityValue(email), NormalizeScmIdentityValue(otherEmail))
+}
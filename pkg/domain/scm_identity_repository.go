@@ -0,0 +1,13 @@
+package domain
+
+import "errors"
+
+var ErrScmIdentityNotFound = errors.New("scm identity not found")
+
+type ScmIdentityRepository interface {
+	Get() (*ScmIdentity, error)
+
+	// Overrides returns the environment variables that replace the identity
+	// of the configuration, such as GIT_AUTHOR_NAME, with their values
+	Overrides() map[string]string
+}
@@ -0,0 +1,23 @@
+package domain
+
+// ScmInclude is a git configuration included only when its condition matches
+type ScmInclude struct {
+	Condition string
+	User      *ScmUser
+}
+
+func NewScmInclude(condition string, user *ScmUser) *ScmInclude {
+	return &ScmInclude{
+		Condition: condition,
+		User:      user,
+	}
+}
+
+// ScmIncludeSection is an includeIf section read from the File of the git
+// configuration, Managed when it is written by git profile
+type ScmIncludeSection struct {
+	Condition string
+	Path      string
+	File      string
+	Managed   bool
+}
@@ -0,0 +1,9 @@
+package domain
+
+type ScmIncludeRepository interface {
+	// Save replaces every include managed by git profile with the given ones
+	Save(includes []*ScmInclude) error
+
+	// List returns the includeIf sections of the git configuration, managed or not
+	List() ([]*ScmIncludeSection, error)
+}
@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrInvalidScmPushUpdate = errors.New("invalid push update")
+
+// ScmPushUpdate is a ref sent by a push, as read by the pre-push hook in the
+// form: <local ref> <local hash> <remote ref> <remote hash>
+type ScmPushUpdate struct {
+	LocalRef   string
+	LocalHash  ScmCommitHash
+	RemoteRef  string
+	RemoteHash ScmCommitHash
+}
+
+func NewScmPushUpdate(line string) (*ScmPushUpdate, error) {
+	fields := strings.Fields(line)
+	if len(fields) != 4 {
+		return nil, ErrInvalidScmPushUpdate
+	}
+
+	localHash, err := NewScmCommitHash(fields[1])
+	if err != nil {
+		return nil, ErrInvalidScmPushUpdate
+	}
+
+	remoteHash, err := NewScmCommitHash(fields[3])
+	if err != nil {
+		return nil, ErrInvalidScmPushUpdate
+	}
+
+	return &ScmPushUpdate{
+		LocalRef:   fields[0],
+		LocalHash:  localHash,
+		RemoteRef:  fields[2],
+		RemoteHash: remoteHash,
+	}, nil
+}
+
+// IsDelete reports whether the push deletes the remote ref
+func (u ScmPushUpdate) IsDelete() bool {
+	return isZeroHash(u.LocalHash)
+}
+
+// IsNew reports whether the push creates the remote ref
+func (u ScmPushUpdate) IsNew() bool {
+	return isZeroHash(u.RemoteHash)
+}
+
+// isZeroHash reports whether the hash is the null object id git uses for a
+// missing ref, whatever the length of the hash algorithm
+func isZeroHash(hash ScmCommitHash) bool {
+	return strings.Trim(hash.String(), "0") == ""
+}
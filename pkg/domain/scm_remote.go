@@ -0,0 +1,13 @@
+package domain
+
+type ScmRemote struct {
+	Name string
+	Url  string
+}
+
+func NewScmRemote(name string, url string) *ScmRemote {
+	return &ScmRemote{
+		Name: name,
+		Url:  url,
+	}
+}
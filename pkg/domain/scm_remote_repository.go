@@ -0,0 +1,9 @@
+package domain
+
+type ScmRemoteRepository interface {
+	List() ([]*ScmRemote, error)
+
+	// Refs returns the remote-tracking refs of the remote, of every remote when
+	// it is empty, the commits already known to be on the remote
+	Refs(remote string) ([]ScmCommitHash, error)
+}
@@ -0,0 +1,15 @@
+package domain
+
+// ScmRepositoryUser is the user configured in the local configuration of a git repository
+type ScmRepositoryUser struct {
+	// Path is the working directory of the repository
+	Path string
+	User *ScmUser
+}
+
+func NewScmRepositoryUser(path string, user *ScmUser) *ScmRepositoryUser {
+	return &ScmRepositoryUser{
+		Path: path,
+		User: user,
+	}
+}
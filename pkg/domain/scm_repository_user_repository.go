@@ -0,0 +1,13 @@
+package domain
+
+type ScmRepositoryUserRepository interface {
+	// List returns the users of the git repositories found under the directory,
+	// the repositories without a configured user are left out
+	List(dir string) ([]*ScmRepositoryUser, error)
+
+	Save(user *ScmRepositoryUser) error
+
+	// SaveWorkspace only sets the workspace of the user of the repository at
+	// the path, every other key of its configuration is kept as it is
+	SaveWorkspace(path string, workspace string) error
+}
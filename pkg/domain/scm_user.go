@@ -1,9 +1,20 @@
 package domain
 
 type ScmUser struct {
-	Workespace string
-	Email      string
-	Name       string
+	Workespace    string
+	Email         string
+	Name          string
+	SigningKey    string
+	SigningFormat string
+	CommitGpgSign bool
+	TagGpgSign    bool
+	SshCommand    string
+	// Usernames holds the username of the profile for each https host
+	Usernames map[string]string
+	// CredentialHelper is the credential helper registered with the profile
+	CredentialHelper string
+	// Config holds the extra git configuration keys applied with the profile
+	Config map[string]string
 }
 
 func NewScmUser(workspace string, email string, name string) *ScmUser {
@@ -3,11 +3,16 @@ package domain
 import "errors"
 
 var ErrScmUserNotFound = errors.New("scm user not found")
+var ErrScmRepositoryNotFound = errors.New("not a git repository")
 
 type ScmUserRepository interface {
 	Get() (*ScmUser, error)
 
 	Save(user *ScmUser) error
 
+	// SaveWorkspace only sets the workspace of the configured user, every
+	// other key of the configuration is kept as it is
+	SaveWorkspace(workspace string) error
+
 	Delete() error
 }
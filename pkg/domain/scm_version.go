@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidScmVersion = errors.New("invalid git version")
+
+// ScmMinimumVersion is the oldest git supported, the includes selecting a
+// profile by remote url need hasconfig:remote.*.url conditions
+var ScmMinimumVersion = ScmVersion{major: 2, minor: 36}
+
+// ScmVersion is the version of the git binary
+type ScmVersion struct {
+	major int
+	minor int
+	patch int
+}
+
+// NewScmVersion reads the output of git --version, such as "git version 2.39.5"
+// or "git version 2.39.5 (Apple Git-154)", the suffixes of the version are ignored
+func NewScmVersion(value string) (ScmVersion, error) {
+	fields := strings.Fields(strings.TrimPrefix(strings.TrimSpace(value), "git version"))
+	if len(fields) == 0 {
+		return ScmVersion{}, ErrInvalidScmVersion
+	}
+
+	parts := strings.Split(fields[0], ".")
+	if len(parts) < 2 {
+		return ScmVersion{}, ErrInvalidScmVersion
+	}
+
+	numbers := make([]int, 3)
+	for i := 0; i < len(parts) && i < len(numbers); i++ {
+		number, err := strconv.Atoi(parts[i])
+		if err != nil {
+			// Release candidates are written 2.45.0-rc1 or 2.45.0.rc1
+			if i < 2 {
+				return ScmVersion{}, ErrInvalidScmVersion
+			}
+
+			break
+		}
+
+		numbers[i] = number
+	}
+
+	return ScmVersion{major: numbers[0], minor: numbers[1], patch: numbers[2]}, nil
+}
+
+// AtLeast reports whether the version is the given one or a newer one
+func (v ScmVersion) AtLeast(other ScmVersion) bool {
+	if v.major != other.major {
+		return v.major > other.major
+	}
+
+	if v.minor != other.minor {
+		return v.minor > other.minor
+	}
+
+	return v.patch >= other.patch
+}
+
+func (v ScmVersion) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.major, v.minor, v.patch)
+}
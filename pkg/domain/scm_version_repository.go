@@ -0,0 +1,10 @@
+package domain
+
+import "errors"
+
+var ErrScmNotInstalled = errors.New("git is not installed")
+
+type ScmVersionRepository interface {
+	// Get returns the version of git, ErrScmNotInstalled when it cannot be run
+	Get() (ScmVersion, error)
+}
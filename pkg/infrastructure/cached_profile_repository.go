@@ -0,0 +1,166 @@
+package infrastructure
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/b4nd/git-profile/pkg/domain"
+)
+
+// PROFILE_CACHE_DIR is the directory of the cache files, in the user cache directory
+const PROFILE_CACHE_DIR = "git-profile"
+
+// CachedProfileRepository keeps the workspace, email and name of the profiles
+// in a cache file that is only built again when a profile file changes, so
+// Get does not parse the profile files. It is meant for the commands run on
+// every prompt, the other fields of the profiles are not cached.
+type CachedProfileRepository struct {
+	*IniFileProfileRepository
+	cachePath string
+}
+
+// profileCache is the cache file, the profiles are valid while the files
+// have the recorded modification time and size
+type profileCache struct {
+	Files    map[string]profileCacheFile  `json:"files"`
+	Profiles map[string]profileCacheEntry `json:"profiles"`
+}
+
+type profileCacheFile struct {
+	ModTime int64 `json:"modTime"`
+	Size    int64 `json:"size"`
+}
+
+type profileCacheEntry struct {
+	Email  string `json:"email"`
+	Name   string `json:"name"`
+	Source string `json:"source"`
+}
+
+func NewCachedProfileRepository(repository *IniFileProfileRepository, cacheDir string) (*CachedProfileRepository, error) {
+	if cacheDir == "" {
+		return nil, fmt.Errorf("cache dir cannot be empty")
+	}
+
+	// Every set of profile files, which depends on the directory, has its own cache file
+	hash := sha256.Sum256([]byte(strings.Join(repository.paths, "\n")))
+	cachePath := filepath.Join(cacheDir, PROFILE_CACHE_DIR, "profiles-"+hex.EncodeToString(hash[:8])+".json")
+
+	return &CachedProfileRepository{repository, cachePath}, nil
+}
+
+// Get returns the workspace, email and name of the profile from the cache,
+// the cache is built again from the profile files when one of them changed
+func (r *CachedProfileRepository) Get(workspace domain.ProfileWorkspace) (*domain.Profile, error) {
+	files := r.stat()
+
+	cache, err := r.read()
+	if err != nil || !reflect.DeepEqual(cache.Files, files) {
+		cache, err = r.refresh(files)
+		if err != nil {
+			return r.IniFileProfileRepository.Get(workspace)
+		}
+	}
+
+	entry, ok := cache.Profiles[workspace.String()]
+	if !ok {
+		return nil, domain.ErrInvalidWorkspace
+	}
+
+	profile, err := domain.NewProfile(workspace.String(), entry.Email, entry.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return profile.WithSource(r.source(entry.Source)), nil
+}
+
+// stat returns the modification time and size of the profile files, the
+// missing files are recorded too so that creating them refreshes the cache
+func (r *CachedProfileRepository) stat() map[string]profileCacheFile {
+	files := make(map[string]profileCacheFile, len(r.paths))
+	for _, path := range r.paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			files[path] = profileCacheFile{ModTime: 0, Size: -1}
+			continue
+		}
+
+		files[path] = profileCacheFile{ModTime: info.ModTime().UnixNano(), Size: info.Size()}
+	}
+
+	return files
+}
+
+func (r *CachedProfileRepository) read() (*profileCache, error) {
+	content, err := os.ReadFile(r.cachePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var cache profileCache
+	if err := json.Unmarshal(content, &cache); err != nil {
+		return nil, err
+	}
+
+	return &cache, nil
+}
+
+// refresh builds the cache from the profile files, the cache is still used
+// when it cannot be written
+func (r *CachedProfileRepository) refresh(files map[string]profileCacheFile) (*profileCache, error) {
+	profiles, err := r.List()
+	if err != nil {
+		return nil, err
+	}
+
+	cache := &profileCache{Files: files, Profiles: make(map[string]profileCacheEntry, len(profiles))}
+	for _, profile := range profiles {
+		cache.Profiles[profile.Workspace().String()] = profileCacheEntry{
+			Email:  profile.Email().String(),
+			Name:   profile.Name().String(),
+			Source: profile.Source().Path(),
+		}
+	}
+
+	_ = r.write(cache)
+
+	return cache, nil
+}
+
+// write replaces the cache file atomically, a prompt running at the same
+// time reads either the old or the new cache
+func (r *CachedProfileRepository) write(cache *profileCache) error {
+	content, err := json.Marshal(cache)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.cachePath), 0750); err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(r.cachePath), filepath.Base(r.cachePath)+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	if _, err := file.Write(content); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		os.Remove(file.Name())
+		return err
+	}
+
+	return os.Rename(file.Name(), r.cachePath)
+}
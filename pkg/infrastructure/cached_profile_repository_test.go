@@ -0,0 +1,151 @@
+package infrastructure_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/b4nd/git-profile/pkg/domain"
+	"github.com/b4nd/git-profile/pkg/infrastructure"
+
+	"github.com/jaswdr/faker"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCachedProfileRepository(t *testing.T) {
+	faker := faker.New()
+
+	t.Run("should return an error when the cache dir is empty", func(t *testing.T) {
+		iniFileProfileRepository, err := infrastructure.NewIniFileProfileRepository([]string{"profiles"})
+		assert.NoError(t, err)
+
+		repository, err := infrastructure.NewCachedProfileRepository(iniFileProfileRepository, "")
+
+		assert.Error(t, err)
+		assert.Nil(t, repository)
+	})
+
+	t.Run("should get the profile and write the cache", func(t *testing.T) {
+		file, profiles, cleanup := generateTempFileAndProfiles(t, 3)
+		defer cleanup()
+
+		cacheDir := t.TempDir()
+		iniFileProfileRepository, err := infrastructure.NewIniFileProfileRepository([]string{file.Name()})
+		assert.NoError(t, err)
+
+		repository, err := infrastructure.NewCachedProfileRepository(iniFileProfileRepository, cacheDir)
+		assert.NoError(t, err)
+
+		workspace, err := domain.NewProfileWorkspace(profiles[1].Workspace)
+		assert.NoError(t, err)
+
+		profile, err := repository.Get(workspace)
+
+		assert.NoError(t, err)
+		assert.Equal(t, profiles[1].Email, profile.Email().String())
+		assert.Equal(t, profiles[1].Name, profile.Name().String())
+		assert.Equal(t, file.Name(), profile.Source().Path())
+
+		caches, err := filepath.Glob(filepath.Join(cacheDir, infrastructure.PROFILE_CACHE_DIR, "*.json"))
+		assert.NoError(t, err)
+		assert.Len(t, caches, 1)
+	})
+
+	t.Run("should return an error when the workspace does not exist", func(t *testing.T) {
+		file, _, cleanup := generateTempFileAndProfiles(t, 1)
+		defer cleanup()
+
+		iniFileProfileRepository, err := infrastructure.NewIniFileProfileRepository([]string{file.Name()})
+		assert.NoError(t, err)
+
+		repository, err := infrastructure.NewCachedProfileRepository(iniFileProfileRepository, t.TempDir())
+		assert.NoError(t, err)
+
+		workspace, err := domain.NewProfileWorkspace(WorkspaceInvalid)
+		assert.NoError(t, err)
+
+		profile, err := repository.Get(workspace)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidWorkspace)
+		assert.Nil(t, profile)
+	})
+
+	t.Run("should refresh the cache when the profile file changes", func(t *testing.T) {
+		file, profiles, cleanup := generateTempFileAndProfiles(t, 1)
+		defer cleanup()
+
+		cacheDir := t.TempDir()
+		iniFileProfileRepository, err := infrastructure.NewIniFileProfileRepository([]string{file.Name()})
+		assert.NoError(t, err)
+
+		repository, err := infrastructure.NewCachedProfileRepository(iniFileProfileRepository, cacheDir)
+		assert.NoError(t, err)
+
+		workspace, err := domain.NewProfileWorkspace(profiles[0].Workspace)
+		assert.NoError(t, err)
+
+		_, err = repository.Get(workspace)
+		assert.NoError(t, err)
+
+		profile, err := domain.NewProfile(profiles[0].Workspace, faker.Internet().Email(), faker.Person().Name())
+		assert.NoError(t, err)
+		assert.NoError(t, repository.Save(profile))
+
+		cached, err := repository.Get(workspace)
+
+		assert.NoError(t, err)
+		assert.Equal(t, profile.Email(), cached.Email())
+		assert.Equal(t, profile.Name(), cached.Name())
+	})
+
+	t.Run("should refresh the cache when a profile file is created", func(t *testing.T) {
+		file, profiles, cleanup := generateTempFileAndProfiles(t, 1)
+		defer cleanup()
+
+		localPath := filepath.Join(t.TempDir(), ".gitprofile")
+		iniFileProfileRepository, err := infrastructure.NewIniFileProfileRepository([]string{file.Name(), localPath})
+		assert.NoError(t, err)
+
+		repository, err := infrastructure.NewCachedProfileRepository(iniFileProfileRepository.WithLocalPath(localPath), t.TempDir())
+		assert.NoError(t, err)
+
+		workspace, err := domain.NewProfileWorkspace(profiles[0].Workspace)
+		assert.NoError(t, err)
+
+		profile, err := repository.Get(workspace)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.ProfileScopeGlobal, profile.Source().Scope())
+
+		content := "[" + profiles[0].Workspace + "]\nemail = " + faker.Internet().Email() + "\nname = Jane Doe\n"
+		assert.NoError(t, os.WriteFile(localPath, []byte(content), 0600))
+
+		profile, err = repository.Get(workspace)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Jane Doe", profile.Name().String())
+		assert.Equal(t, domain.ProfileScopeLocal, profile.Source().Scope())
+	})
+
+	t.Run("should get the profile when the cache cannot be written", func(t *testing.T) {
+		file, profiles, cleanup := generateTempFileAndProfiles(t, 1)
+		defer cleanup()
+
+		// The cache dir is a file, so the cache directory cannot be created
+		cacheDir := filepath.Join(t.TempDir(), "cache")
+		assert.NoError(t, os.WriteFile(cacheDir, []byte{}, 0600))
+
+		iniFileProfileRepository, err := infrastructure.NewIniFileProfileRepository([]string{file.Name()})
+		assert.NoError(t, err)
+
+		repository, err := infrastructure.NewCachedProfileRepository(iniFileProfileRepository, cacheDir)
+		assert.NoError(t, err)
+
+		workspace, err := domain.NewProfileWorkspace(profiles[0].Workspace)
+		assert.NoError(t, err)
+
+		profile, err := repository.Get(workspace)
+
+		assert.NoError(t, err)
+		assert.Equal(t, profiles[0].Email, profile.Email().String())
+	})
+}
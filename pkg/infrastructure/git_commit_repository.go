@@ -1,7 +1,11 @@
 package infrastructure
 
 import (
+	"bufio"
+	"bytes"
 	"fmt"
+	"io"
+	"os"
 	"os/exec"
 	"strings"
 	"time"
@@ -9,6 +13,23 @@ import (
 	"github.com/b4nd/git-profile/pkg/domain"
 )
 
+// gitCommitFormat prints the fields of a commit separated by NUL characters,
+// which cannot appear in names nor messages, the output is read with -z
+const gitCommitFormat = "--format=%H%x00%P%x00%an%x00%ae%x00%aI%x00%cn%x00%ce%x00%cI%x00%G?%x00%B"
+
+// gitCommitListFormat prints the fields of gitCommitFormat with an empty
+// signature, %G? runs the verifier of the signature of every commit listed
+const gitCommitListFormat = "--format=%H%x00%P%x00%an%x00%ae%x00%aI%x00%cn%x00%ce%x00%cI%x00%x00%B"
+
+// gitCommitFields is the number of fields printed by gitCommitFormat
+const gitCommitFields = 10
+
+// gitCommitRecordFormat prints the identities of a commit, without the message
+const gitCommitRecordFormat = "--format=%H%x00%an%x00%ae%x00%aI%x00%cn%x00%ce%x00%cI"
+
+// gitCommitRecordFields is the number of fields printed by gitCommitRecordFormat
+const gitCommitRecordFields = 7
+
 type GitCommitRepository struct {
 	path string
 }
@@ -22,7 +43,7 @@ func NewGitCommitRepository(path string) (*GitCommitRepository, error) {
 }
 
 func (r *GitCommitRepository) Get(hash *domain.ScmCommitHash) (*domain.ScmCommit, error) {
-	cmd := exec.Command("git", "log", "-1", "--format=%ai,%H,%an,%ae,%s", hash.String()) // #nosec G204
+	cmd := exec.Command("git", "log", "-1", "-z", gitCommitFormat, hash.String()) // #nosec G204
 	cmd.Dir = r.path
 
 	output, err := cmd.Output()
@@ -30,39 +51,426 @@ func (r *GitCommitRepository) Get(hash *domain.ScmCommitHash) (*domain.ScmCommit
 		return nil, err
 	}
 
-	parts := strings.SplitN(string(output), ",", 5)
-	if len(parts) != 5 {
+	commits, err := parseGitCommits(string(output))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(commits) != 1 {
 		return nil, domain.ErrScmCommitNotFound
 	}
 
-	scmHash, err := domain.NewScmCommitHash(parts[1])
+	return commits[0], nil
+}
+
+func (r *GitCommitRepository) List(tip *domain.ScmCommitHash, exclude []domain.ScmCommitHash) ([]*domain.ScmCommit, error) {
+	args := []string{"log", "--reverse", "--topo-order", "-z", gitCommitListFormat, tip.String()}
+	for _, hash := range exclude {
+		args = append(args, "^"+hash.String())
+	}
+
+	cmd := exec.Command("git", args...) // #nosec G204
+	cmd.Dir = r.path
+
+	output, err := cmd.Output()
 	if err != nil {
 		return nil, err
 	}
 
-	scmAuthor, err := domain.NewScmCommitAuthor(parts[2], parts[3])
+	return parseGitCommits(string(output))
+}
+
+func (r *GitCommitRepository) Walk(tip *domain.ScmCommitHash, exclude []domain.ScmCommitHash, visit func(record *domain.ScmCommitRecord) error) error {
+	// A missing repository is told apart from a missing commit
+	if _, err := FindGitDir(r.path); err != nil {
+		return err
+	}
+
+	args := []string{"log", "--topo-order", "-z", gitCommitRecordFormat, tip.String()}
+	for _, hash := range exclude {
+		args = append(args, "^"+hash.String())
+	}
+
+	cmd := exec.Command("git", args...) // #nosec G204
+	cmd.Dir = r.path
+
+	stdout, err := cmd.StdoutPipe()
 	if err != nil {
-		return nil, err
+		return err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	// The records are read one at a time, the history can be larger than the memory
+	reader := bufio.NewReader(stdout)
+	fields := make([]string, 0, gitCommitRecordFields)
+	for {
+		field, err := reader.ReadString('\x00')
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			_ = cmd.Process.Kill()
+			_ = cmd.Wait()
+			return err
+		}
+
+		fields = append(fields, strings.TrimSuffix(field, "\x00"))
+		if len(fields) < gitCommitRecordFields {
+			continue
+		}
+
+		record, err := parseGitCommitRecord(fields)
+		if err == nil {
+			err = visit(record)
+		}
+
+		if err != nil {
+			_ = cmd.Process.Kill()
+			_ = cmd.Wait()
+			return err
+		}
+
+		fields = fields[:0]
+	}
+
+	// git log fails when a revision does not exist
+	if err := cmd.Wait(); err != nil {
+		return domain.ErrScmCommitNotFound
+	}
+
+	return nil
+}
+
+func (r *GitCommitRepository) IsPushed(hash *domain.ScmCommitHash) (bool, error) {
+	cmd := exec.Command("git", "for-each-ref", "--contains", hash.String(), "--format=%(refname)", "refs/remotes") // #nosec G204
+	cmd.Dir = r.path
+
+	output, err := cmd.Output()
+	if err != nil {
+		return false, err
+	}
+
+	return strings.TrimSpace(string(output)) != "", nil
+}
+
+func (r *GitCommitRepository) Save(amend *domain.ScmCommitAmend) error {
+	// The hooks are skipped, only the identity of the commit changes
+	args := []string{"commit", "--amend", "--no-edit", "--allow-empty", "--no-verify"}
+	if amend.Author != nil {
+		args = append(args, "--author="+amend.Author.String())
+	}
+
+	env := append(os.Environ(),
+		"GIT_COMMITTER_NAME="+amend.Committer.Name(),
+		"GIT_COMMITTER_EMAIL="+amend.Committer.Email(),
+	)
+
+	switch amend.Date {
+	case domain.ScmCommitDateKeep:
+		raw, err := r.readRawCommit(domain.NewScmCommitHashHead().String())
+		if err != nil {
+			return err
+		}
+
+		_, _, committerDate := parseGitIdent(raw.committer)
+		env = append(env, "GIT_COMMITTER_DATE="+committerDate)
+	case domain.ScmCommitDateReset:
+		args = append(args, "--date=now")
+	}
+
+	cmd := exec.Command("git", args...) // #nosec G204
+	cmd.Dir = r.path
+	cmd.Env = env
+
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(output)))
 	}
 
-	scmDate, err := time.Parse("2006-01-02 15:04:05 -0700", parts[0])
+	return nil
+}
+
+func (r *GitCommitRepository) Rewrite(commits []*domain.ScmCommit, targets []domain.ScmCommitHash, amend *domain.ScmCommitAmend) ([]*domain.ScmCommitRewrite, error) {
+	isTarget := map[string]bool{}
+	for _, target := range targets {
+		isTarget[target.String()] = true
+	}
+
+	rewritten := map[string]string{}
+	rewrites := make([]*domain.ScmCommitRewrite, 0)
+
+	for _, commit := range commits {
+		parents := make([]string, 0, len(commit.Parents))
+		changed := isTarget[commit.Hash.String()]
+		for _, parent := range commit.Parents {
+			if hash, ok := rewritten[parent.String()]; ok {
+				parents = append(parents, hash)
+				changed = true
+				continue
+			}
+
+			parents = append(parents, parent.String())
+		}
+
+		// The commits that are not re-authored and keep their parents are left as they are
+		if !changed {
+			continue
+		}
+
+		var target *domain.ScmCommitAmend
+		if isTarget[commit.Hash.String()] {
+			target = amend
+		}
+
+		hash, err := r.recreate(commit.Hash.String(), parents, target)
+		if err != nil {
+			return nil, err
+		}
+
+		newHash, err := domain.NewScmCommitHash(hash)
+		if err != nil {
+			return nil, err
+		}
+
+		rewritten[commit.Hash.String()] = hash
+		rewrites = append(rewrites, domain.NewScmCommitRewrite(commit.Hash, newHash))
+	}
+
+	if len(commits) == 0 {
+		return rewrites, nil
+	}
+
+	last := commits[len(commits)-1].Hash.String()
+	if head, ok := rewritten[last]; ok {
+		// The old value guards against a HEAD moved while the commits were rewritten
+		cmd := exec.Command("git", "update-ref", "-m", "git profile: rewrite author", "HEAD", head, last) // #nosec G204
+		cmd.Dir = r.path
+
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(string(output)))
+		}
+	}
+
+	return rewrites, nil
+}
+
+// gitRawCommit holds the fields of a commit as stored by git
+type gitRawCommit struct {
+	tree      string
+	author    string
+	committer string
+	// encoding is the encoding of the message, empty for UTF-8
+	encoding string
+	message  string
+}
+
+// readRawCommit reads the commit object, the message is kept byte for byte
+func (r *GitCommitRepository) readRawCommit(hash string) (*gitRawCommit, error) {
+	cmd := exec.Command("git", "cat-file", "commit", hash) // #nosec G204
+	cmd.Dir = r.path
+
+	output, err := cmd.Output()
 	if err != nil {
 		return nil, err
 	}
 
-	scmMessage := strings.TrimSpace(parts[4])
-	scmMessage = strings.ReplaceAll(scmMessage, "\n", " ")
+	header, message, _ := strings.Cut(string(output), "\n\n")
+
+	raw := &gitRawCommit{message: message}
+	for _, line := range strings.Split(header, "\n") {
+		key, value, _ := strings.Cut(line, " ")
+		switch key {
+		case "tree":
+			raw.tree = value
+		case "author":
+			raw.author = value
+		case "committer":
+			raw.committer = value
+		case "encoding":
+			raw.encoding = value
+		}
+	}
 
-	return domain.NewScmCommit(scmHash, scmAuthor, scmDate.UTC(), scmMessage), nil
+	return raw, nil
 }
 
-func (r *GitCommitRepository) Save(author *domain.ScmCommitAuthor) error {
-	cmd := exec.Command("git", "commit", "--amend", "--author=\""+author.String()+"\"", "--no-edit", "--allow-empty") // #nosec G204
+// recreate writes a copy of the commit with the given parents, the tree and
+// the message are kept, the identities and dates are kept unless the amend is given
+func (r *GitCommitRepository) recreate(hash string, parents []string, amend *domain.ScmCommitAmend) (string, error) {
+	raw, err := r.readRawCommit(hash)
+	if err != nil {
+		return "", err
+	}
+
+	authorName, authorEmail, authorDate := parseGitIdent(raw.author)
+	committerName, committerEmail, committerDate := parseGitIdent(raw.committer)
+
+	if amend != nil {
+		if amend.Author != nil {
+			authorName, authorEmail = amend.Author.Name(), amend.Author.Email()
+		}
+
+		committerName, committerEmail = amend.Committer.Name(), amend.Committer.Email()
+
+		// An empty date is set to now by git
+		switch amend.Date {
+		case domain.ScmCommitDateDefault:
+			committerDate = ""
+		case domain.ScmCommitDateReset:
+			authorDate, committerDate = "", ""
+		}
+	}
+
+	env := append(os.Environ(),
+		"GIT_AUTHOR_NAME="+authorName,
+		"GIT_AUTHOR_EMAIL="+authorEmail,
+		"GIT_COMMITTER_NAME="+committerName,
+		"GIT_COMMITTER_EMAIL="+committerEmail,
+	)
+
+	if authorDate != "" {
+		env = append(env, "GIT_AUTHOR_DATE="+authorDate)
+	}
+
+	if committerDate != "" {
+		env = append(env, "GIT_COMMITTER_DATE="+committerDate)
+	}
+
+	// The message is given as is, git writes the encoding header from i18n.commitEncoding
+	args := []string{"commit-tree", raw.tree}
+	if raw.encoding != "" {
+		args = append([]string{"-c", "i18n.commitEncoding=" + raw.encoding}, args...)
+	}
+
+	for _, parent := range parents {
+		args = append(args, "-p", parent)
+	}
+
+	cmd := exec.Command("git", args...) // #nosec G204
 	cmd.Dir = r.path
+	cmd.Env = env
+	cmd.Stdin = strings.NewReader(raw.message)
 
-	if err := cmd.Run(); err != nil {
-		return err
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
 	}
 
-	return nil
+	return strings.TrimSpace(string(output)), nil
+}
+
+// parseGitCommits parses the commits printed with gitCommitFormat or gitCommitListFormat and -z
+func parseGitCommits(output string) ([]*domain.ScmCommit, error) {
+	fields := strings.Split(output, "\x00")
+
+	// Every commit is terminated by a NUL character, which leaves an empty last field
+	if len(fields) > 0 && fields[len(fields)-1] == "" {
+		fields = fields[:len(fields)-1]
+	}
+
+	if len(fields)%gitCommitFields != 0 {
+		return nil, domain.ErrScmCommitNotFound
+	}
+
+	commits := make([]*domain.ScmCommit, 0, len(fields)/gitCommitFields)
+	for i := 0; i < len(fields); i += gitCommitFields {
+		commit, err := parseGitCommit(fields[i : i+gitCommitFields])
+		if err != nil {
+			return nil, err
+		}
+
+		commits = append(commits, commit)
+	}
+
+	return commits, nil
+}
+
+// parseGitCommit builds a commit from the fields printed by gitCommitFormat
+func parseGitCommit(fields []string) (*domain.ScmCommit, error) {
+	scmHash, err := domain.NewScmCommitHash(fields[0])
+	if err != nil {
+		return nil, err
+	}
+
+	var scmParents []domain.ScmCommitHash
+	for _, parent := range strings.Fields(fields[1]) {
+		scmParent, err := domain.NewScmCommitHash(parent)
+		if err != nil {
+			return nil, err
+		}
+
+		scmParents = append(scmParents, scmParent)
+	}
+
+	// The identities are kept as written, a commit with an odd email is still read
+	scmAuthor := domain.NewScmCommitAuthorAsWritten(fields[2], fields[3])
+
+	scmDate, err := time.Parse(time.RFC3339, fields[4])
+	if err != nil {
+		return nil, err
+	}
+
+	scmCommitter := domain.NewScmCommitAuthorAsWritten(fields[5], fields[6])
+
+	scmCommitterDate, err := time.Parse(time.RFC3339, fields[7])
+	if err != nil {
+		return nil, err
+	}
+
+	commit := domain.NewScmCommit(scmHash, scmAuthor, scmDate.UTC(), strings.TrimRight(fields[9], "\n"))
+	commit.Committer = scmCommitter
+	commit.CommitterDate = scmCommitterDate.UTC()
+	commit.Parents = scmParents
+	if fields[8] != "" {
+		commit.Signature = domain.ScmCommitSignature(fields[8])
+	} else {
+		commit.Signature = domain.ScmCommitSignatureNotRead
+	}
+
+	return commit, nil
+}
+
+// parseGitCommitRecord builds a record from the fields printed by gitCommitRecordFormat
+func parseGitCommitRecord(fields []string) (*domain.ScmCommitRecord, error) {
+	scmHash, err := domain.NewScmCommitHash(fields[0])
+	if err != nil {
+		return nil, err
+	}
+
+	scmDate, err := time.Parse(time.RFC3339, fields[3])
+	if err != nil {
+		return nil, err
+	}
+
+	scmCommitterDate, err := time.Parse(time.RFC3339, fields[6])
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.ScmCommitRecord{
+		Hash:           scmHash,
+		AuthorName:     fields[1],
+		AuthorEmail:    fields[2],
+		Date:           scmDate.UTC(),
+		CommitterName:  fields[4],
+		CommitterEmail: fields[5],
+		CommitterDate:  scmCommitterDate.UTC(),
+	}, nil
+}
+
+// parseGitIdent splits an identity of a raw commit: Name <email> timestamp timezone
+func parseGitIdent(ident string) (string, string, string) {
+	start := strings.LastIndex(ident, "<")
+	end := strings.LastIndex(ident, ">")
+	if start < 0 || end < start {
+		return "", "", ""
+	}
+
+	return strings.TrimSpace(ident[:start]), ident[start+1 : end], strings.TrimSpace(ident[end+1:])
 }
@@ -2,8 +2,12 @@ package infrastructure_test
 
 import (
 	"archive/zip"
+	"errors"
 	"io"
 	"os"
+	"os/exec"
+	"path"
+	"strings"
 	"testing"
 	"time"
 
@@ -68,8 +72,8 @@ func initializateZipGitRepository(t *testing.T, zipName string) string {
 	return gitPath
 }
 
-// newGitCommit creates a new domain.ScmCommit instance.
-func newGitCommit(t *testing.T, hash string, authorName string, authorEmail string, date time.Time, message string) *domain.ScmCommit {
+// newGitCommit creates a new domain.ScmCommit instance, committed by its author.
+func newGitCommit(t *testing.T, hash string, authorName string, authorEmail string, date time.Time, message string, parents ...string) *domain.ScmCommit {
 	author, err := domain.NewScmCommitAuthor(authorName, authorEmail)
 	assert.NoError(t, err)
 
@@ -79,6 +83,13 @@ func newGitCommit(t *testing.T, hash string, authorName string, authorEmail stri
 	commit := domain.NewScmCommit(hashValue, author, date, message)
 	assert.NotNil(t, commit)
 
+	for _, parent := range parents {
+		parentValue, err := domain.NewScmCommitHash(parent)
+		assert.NoError(t, err)
+
+		commit.Parents = append(commit.Parents, parentValue)
+	}
+
 	return commit
 }
 
@@ -105,8 +116,8 @@ func TestGitCommitRepositoryNewGitCommitRepository(t *testing.T) {
 	// The git repository used in the tests is a zip file that contains the following commits:
 	var commits = []*domain.ScmCommit{
 		newGitCommit(t, "fc8d711d866b6fac0e4dce8cbe8209f035cda82d", "Your Name", "you@example.com", time.Date(2025, 2, 1, 18, 56, 36, 0, time.UTC), "Initial Commit"),
-		newGitCommit(t, "518836dd1dcc766d8f5a972583b253db856cc4dd", "Your Name", "you@example.com", time.Date(2025, 2, 1, 18, 57, 25, 0, time.UTC), "Second Commit"),
-		newGitCommit(t, "4094389632c66e23559d46b1899110e9368d79e7", "Dev Name", "dev@example.com", time.Date(2025, 2, 1, 19, 5, 59, 0, time.UTC), "Develop Commit"),
+		newGitCommit(t, "518836dd1dcc766d8f5a972583b253db856cc4dd", "Your Name", "you@example.com", time.Date(2025, 2, 1, 18, 57, 25, 0, time.UTC), "Second Commit", "fc8d711d866b6fac0e4dce8cbe8209f035cda82d"),
+		newGitCommit(t, "4094389632c66e23559d46b1899110e9368d79e7", "Dev Name", "dev@example.com", time.Date(2025, 2, 1, 19, 5, 59, 0, time.UTC), "Develop Commit", "518836dd1dcc766d8f5a972583b253db856cc4dd"),
 	}
 
 	var commitHead = commits[1]
@@ -270,12 +281,12 @@ func TestGitCommitRepositoryNewGitCommitRepository(t *testing.T) {
 		assert.Equal(t, commitHead, commit)
 
 		email := faker.Internet().Email()
-		name := faker.Person().Name()
+		name := faker.Person().FirstName() + " " + faker.Person().LastName()
 
 		author, err := domain.NewScmCommitAuthor(name, email)
 		assert.NoError(t, err)
 
-		err = repo.Save(&author)
+		err = repo.Save(domain.NewScmCommitAmend(author, false, domain.ScmCommitDateDefault))
 		assert.NoError(t, err)
 
 		newCommit, err := repo.Get(&hash)
@@ -283,9 +294,418 @@ func TestGitCommitRepositoryNewGitCommitRepository(t *testing.T) {
 
 		assert.Equal(t, author.Name(), newCommit.Author.Name())
 		assert.Equal(t, author.Email(), newCommit.Author.Email())
+		assert.Equal(t, author.Name(), newCommit.Committer.Name())
+		assert.Equal(t, author.Email(), newCommit.Committer.Email())
 
 		assert.NotEqual(t, newCommit.Hash.String(), commit.Hash.String())
 		assert.Equal(t, newCommit.Message, commit.Message)
 		assert.Equal(t, newCommit.Date, commit.Date)
 	})
+
+	t.Run("should only update the committer and keep the dates of the last commit", func(t *testing.T) {
+		dir := t.TempDir()
+		gitRun(t, dir, nil, "init", "-q")
+		gitCommit(t, dir, "Your Name", "you@example.com", "Initial Commit")
+
+		repo, err := infrastructure.NewGitCommitRepository(dir)
+		assert.NoError(t, err)
+
+		committer, err := domain.NewScmCommitAuthor(faker.Person().FirstName()+" "+faker.Person().LastName(), faker.Internet().Email())
+		assert.NoError(t, err)
+
+		err = repo.Save(domain.NewScmCommitAmend(committer, true, domain.ScmCommitDateKeep))
+		assert.NoError(t, err)
+
+		assert.Equal(t, "Your Name <you@example.com> 1738436196", gitRun(t, dir, nil, "log", "-1", "--format=%an <%ae> %at"))
+		assert.Equal(t, committer.String()+" 1738436196", gitRun(t, dir, nil, "log", "-1", "--format=%cn <%ce> %ct"))
+	})
+
+	t.Run("should reset the dates of the last commit", func(t *testing.T) {
+		dir := t.TempDir()
+		gitRun(t, dir, nil, "init", "-q")
+		gitCommit(t, dir, "Your Name", "you@example.com", "Initial Commit")
+
+		repo, err := infrastructure.NewGitCommitRepository(dir)
+		assert.NoError(t, err)
+
+		author, err := domain.NewScmCommitAuthor(faker.Person().FirstName()+" "+faker.Person().LastName(), faker.Internet().Email())
+		assert.NoError(t, err)
+
+		err = repo.Save(domain.NewScmCommitAmend(author, false, domain.ScmCommitDateReset))
+		assert.NoError(t, err)
+
+		assert.Equal(t, author.String(), gitRun(t, dir, nil, "log", "-1", "--format=%an <%ae>"))
+		assert.NotEqual(t, "1738436196", gitRun(t, dir, nil, "log", "-1", "--format=%at"))
+		assert.NotEqual(t, "1738436196", gitRun(t, dir, nil, "log", "-1", "--format=%ct"))
+	})
+}
+
+// gitRun runs a git command in the directory and returns its trimmed output.
+func gitRun(t *testing.T, dir string, env []string, args ...string) string {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), env...)
+	output, err := cmd.Output()
+	assert.NoError(t, err)
+
+	return strings.TrimSpace(string(output))
+}
+
+// gitCommit creates an empty commit authored and committed by the given identity.
+// It returns the hash of the created commit.
+func gitCommit(t *testing.T, dir string, name string, email string, message string) string {
+	gitRun(t, dir, []string{
+		"GIT_AUTHOR_NAME=" + name, "GIT_AUTHOR_EMAIL=" + email, "GIT_AUTHOR_DATE=1738436196 +0100",
+		"GIT_COMMITTER_NAME=" + name, "GIT_COMMITTER_EMAIL=" + email, "GIT_COMMITTER_DATE=1738436196 +0100",
+	}, "commit", "--allow-empty", "-q", "--cleanup=verbatim", "-m", message)
+
+	return gitRun(t, dir, nil, "rev-parse", "HEAD")
+}
+
+func TestGitCommitRepositoryParse(t *testing.T) {
+	date := time.Date(2025, 2, 1, 18, 56, 36, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		author  string
+		email   string
+		message string
+	}{
+		{"simple commit", "Your Name", "you@example.com", "Initial Commit"},
+		{"name with commas", "Doe, Jane", "jane@example.com", "Fix, refactor, and test"},
+		{"multi-line message", "Your Name", "you@example.com", "Subject line\n\nBody, with commas\nand a second line"},
+		{"message with a trailer", "Your Name", "you@example.com", "Subject\n\nSigned-off-by: Your Name <you@example.com>"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			gitRun(t, dir, nil, "init", "-q")
+
+			parent := gitCommit(t, dir, "Root Name", "root@example.com", "Root")
+			gitRun(t, dir, []string{
+				"GIT_AUTHOR_NAME=" + test.author, "GIT_AUTHOR_EMAIL=" + test.email, "GIT_AUTHOR_DATE=" + date.Format(time.RFC3339),
+				"GIT_COMMITTER_NAME=Committer, Name", "GIT_COMMITTER_EMAIL=committer@example.com", "GIT_COMMITTER_DATE=1738436196 +0100",
+			}, "commit", "--allow-empty", "-q", "--cleanup=verbatim", "-m", test.message)
+
+			repo, err := infrastructure.NewGitCommitRepository(dir)
+			assert.NoError(t, err)
+
+			hash := domain.NewScmCommitHashHead()
+			commit, err := repo.Get(&hash)
+			assert.NoError(t, err)
+
+			assert.Equal(t, gitRun(t, dir, nil, "rev-parse", "HEAD"), commit.Hash.String())
+			assert.Equal(t, test.author, commit.Author.Name())
+			assert.Equal(t, test.email, commit.Author.Email())
+			assert.Equal(t, date, commit.Date)
+			assert.Equal(t, "Committer, Name", commit.Committer.Name())
+			assert.Equal(t, "committer@example.com", commit.Committer.Email())
+			assert.Equal(t, time.Unix(1738436196, 0).UTC(), commit.CommitterDate)
+			assert.Equal(t, test.message, commit.Message)
+			assert.Equal(t, strings.Split(test.message, "\n")[0], commit.Subject())
+			assert.Len(t, commit.Parents, 1)
+			assert.Equal(t, parent, commit.Parents[0].String())
+			assert.Equal(t, domain.ScmCommitSignatureNone, commit.Signature)
+			assert.False(t, commit.Signature.IsSigned())
+		})
+	}
+}
+
+func TestGitCommitRepositorySignature(t *testing.T) {
+	dir := t.TempDir()
+	gitRun(t, dir, nil, "init", "-q")
+	gitCommit(t, dir, "Root Name", "root@example.com", "Root Commit")
+
+	// A commit with a signature, verified by a program recording its calls
+	object := gitRun(t, dir, nil, "cat-file", "commit", "HEAD")
+	header, message, _ := strings.Cut(object, "\n\n")
+	signed := header + "\ngpgsig -----BEGIN PGP SIGNATURE-----\n \n iQEzBAABCAAdFiEE\n -----END PGP SIGNATURE-----\n\n" + message + "\n"
+
+	file := path.Join(t.TempDir(), "commit")
+	assert.NoError(t, os.WriteFile(file, []byte(signed), 0600))
+	hash := gitRun(t, dir, nil, "hash-object", "-t", "commit", "-w", "--literally", file)
+	gitRun(t, dir, nil, "reset", "-q", "--soft", hash)
+
+	calls := path.Join(t.TempDir(), "calls")
+	program := path.Join(t.TempDir(), "gpg")
+	assert.NoError(t, os.WriteFile(program, []byte("#!/bin/sh\necho called >> "+calls+"\nexit 1\n"), 0755)) // #nosec G306
+	gitRun(t, dir, nil, "config", "gpg.program", program)
+
+	repo, err := infrastructure.NewGitCommitRepository(dir)
+	assert.NoError(t, err)
+
+	t.Run("should not verify the signatures of the listed commits", func(t *testing.T) {
+		tip := domain.NewScmCommitHashHead()
+		commits, err := repo.List(&tip, nil)
+		assert.NoError(t, err)
+		assert.Len(t, commits, 1)
+		assert.Equal(t, domain.ScmCommitSignatureNotRead, commits[0].Signature)
+
+		_, err = os.Stat(calls)
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("should verify the signature of a commit", func(t *testing.T) {
+		tip := domain.NewScmCommitHashHead()
+		commit, err := repo.Get(&tip)
+		assert.NoError(t, err)
+		assert.NotEqual(t, domain.ScmCommitSignatureNotRead, commit.Signature)
+
+		_, err = os.Stat(calls)
+		assert.NoError(t, err)
+	})
+}
+
+func TestGitCommitRepositoryRewrite(t *testing.T) {
+	faker := faker.New()
+
+	newRepository := func(t *testing.T) (string, []string) {
+		dir := t.TempDir()
+		gitRun(t, dir, nil, "init", "-q")
+
+		return dir, []string{
+			gitCommit(t, dir, "Root Name", "root@example.com", "Root Commit"),
+			gitCommit(t, dir, "Wrong Name", "wrong@example.com", "Wrong Commit\n\nWith a body"),
+			gitCommit(t, dir, "Other Name", "other@example.com", "Other Commit"),
+		}
+	}
+
+	t.Run("should list the commits of a range, parents first", func(t *testing.T) {
+		dir, hashes := newRepository(t)
+
+		repo, err := infrastructure.NewGitCommitRepository(dir)
+		assert.NoError(t, err)
+
+		head := domain.NewScmCommitHashHead()
+		root, err := domain.NewScmCommitHash(hashes[0])
+		assert.NoError(t, err)
+
+		commits, err := repo.List(&head, []domain.ScmCommitHash{root})
+		assert.NoError(t, err)
+		assert.Len(t, commits, 2)
+		assert.Equal(t, hashes[1], commits[0].Hash.String())
+		assert.Equal(t, hashes[2], commits[1].Hash.String())
+		assert.Equal(t, []domain.ScmCommitHash{root}, commits[0].Parents)
+		assert.Equal(t, "wrong@example.com", commits[0].Committer.Email())
+	})
+
+	t.Run("should rewrite the targets and replay their descendants", func(t *testing.T) {
+		dir, hashes := newRepository(t)
+
+		repo, err := infrastructure.NewGitCommitRepository(dir)
+		assert.NoError(t, err)
+
+		head := domain.NewScmCommitHashHead()
+		root, err := domain.NewScmCommitHash(hashes[0])
+		assert.NoError(t, err)
+
+		commits, err := repo.List(&head, []domain.ScmCommitHash{root})
+		assert.NoError(t, err)
+
+		author, err := domain.NewScmCommitAuthor(faker.Person().FirstName()+" "+faker.Person().LastName(), faker.Internet().Email())
+		assert.NoError(t, err)
+
+		rewrites, err := repo.Rewrite(commits, []domain.ScmCommitHash{commits[0].Hash}, domain.NewScmCommitAmend(author, false, domain.ScmCommitDateDefault))
+		assert.NoError(t, err)
+		assert.Len(t, rewrites, 2)
+		assert.Equal(t, hashes[1], rewrites[0].Old.String())
+		assert.Equal(t, hashes[2], rewrites[1].Old.String())
+
+		// HEAD points to the replayed descendant
+		assert.Equal(t, rewrites[1].New.String(), gitRun(t, dir, nil, "rev-parse", "HEAD"))
+		assert.Equal(t, "Other Name <other@example.com> 1738436196", gitRun(t, dir, nil, "log", "-1", "--format=%cn <%ce> %ct", "HEAD"))
+
+		// The target is re-authored and keeps its author date and message
+		target := rewrites[0].New.String()
+		assert.Equal(t, author.String(), gitRun(t, dir, nil, "log", "-1", "--format=%an <%ae>", target))
+		assert.Equal(t, author.String(), gitRun(t, dir, nil, "log", "-1", "--format=%cn <%ce>", target))
+		assert.Equal(t, "1738436196", gitRun(t, dir, nil, "log", "-1", "--format=%at", target))
+		assert.Equal(t, "Wrong Commit\n\nWith a body", gitRun(t, dir, nil, "log", "-1", "--format=%B", target))
+		assert.Equal(t, hashes[0], gitRun(t, dir, nil, "log", "-1", "--format=%P", target))
+	})
+
+	t.Run("should only set the committer and keep the dates of the targets", func(t *testing.T) {
+		dir, hashes := newRepository(t)
+
+		repo, err := infrastructure.NewGitCommitRepository(dir)
+		assert.NoError(t, err)
+
+		head := domain.NewScmCommitHashHead()
+		root, err := domain.NewScmCommitHash(hashes[0])
+		assert.NoError(t, err)
+
+		commits, err := repo.List(&head, []domain.ScmCommitHash{root})
+		assert.NoError(t, err)
+
+		committer, err := domain.NewScmCommitAuthor(faker.Person().FirstName()+" "+faker.Person().LastName(), faker.Internet().Email())
+		assert.NoError(t, err)
+
+		rewrites, err := repo.Rewrite(commits, []domain.ScmCommitHash{commits[0].Hash}, domain.NewScmCommitAmend(committer, true, domain.ScmCommitDateKeep))
+		assert.NoError(t, err)
+		assert.Len(t, rewrites, 2)
+
+		target := rewrites[0].New.String()
+		assert.Equal(t, "Wrong Name <wrong@example.com> 1738436196", gitRun(t, dir, nil, "log", "-1", "--format=%an <%ae> %at", target))
+		assert.Equal(t, committer.String()+" 1738436196", gitRun(t, dir, nil, "log", "-1", "--format=%cn <%ce> %ct", target))
+	})
+
+	t.Run("should read and rewrite the commits whose identity is not a valid address", func(t *testing.T) {
+		dir, hashes := newRepository(t)
+		odd := gitCommit(t, dir, "Jane", "jane", "Odd Commit")
+
+		repo, err := infrastructure.NewGitCommitRepository(dir)
+		assert.NoError(t, err)
+
+		head := domain.NewScmCommitHashHead()
+		commit, err := repo.Get(&head)
+		assert.NoError(t, err)
+		assert.Equal(t, "jane", commit.Author.Email())
+
+		root, err := domain.NewScmCommitHash(hashes[0])
+		assert.NoError(t, err)
+
+		commits, err := repo.List(&head, []domain.ScmCommitHash{root})
+		assert.NoError(t, err)
+		assert.Len(t, commits, 3)
+		assert.Equal(t, odd, commits[2].Hash.String())
+
+		author, err := domain.NewScmCommitAuthor("Jane Doe", "jane@acme.com")
+		assert.NoError(t, err)
+
+		rewrites, err := repo.Rewrite(commits, []domain.ScmCommitHash{commits[2].Hash}, domain.NewScmCommitAmend(author, false, domain.ScmCommitDateKeep))
+		assert.NoError(t, err)
+		assert.Len(t, rewrites, 1)
+		assert.Equal(t, "Jane Doe <jane@acme.com>", gitRun(t, dir, nil, "log", "-1", "--format=%an <%ae>"))
+	})
+
+	t.Run("should keep the encoding of the rewritten commits", func(t *testing.T) {
+		dir, _ := newRepository(t)
+
+		// A message in latin-1, git records its encoding in the commit header
+		message := "Caf\xe9 commit"
+		gitRun(t, dir, []string{
+			"GIT_AUTHOR_NAME=Wrong Name", "GIT_AUTHOR_EMAIL=wrong@example.com", "GIT_AUTHOR_DATE=1738436196 +0100",
+			"GIT_COMMITTER_NAME=Wrong Name", "GIT_COMMITTER_EMAIL=wrong@example.com", "GIT_COMMITTER_DATE=1738436196 +0100",
+		}, "-c", "i18n.commitEncoding=ISO-8859-1", "commit", "--allow-empty", "-q", "-m", message)
+
+		repo, err := infrastructure.NewGitCommitRepository(dir)
+		assert.NoError(t, err)
+
+		head := domain.NewScmCommitHashHead()
+		commit, err := repo.Get(&head)
+		assert.NoError(t, err)
+
+		author, err := domain.NewScmCommitAuthor("Jane Doe", "jane@acme.com")
+		assert.NoError(t, err)
+
+		rewrites, err := repo.Rewrite([]*domain.ScmCommit{commit}, []domain.ScmCommitHash{commit.Hash}, domain.NewScmCommitAmend(author, false, domain.ScmCommitDateKeep))
+		assert.NoError(t, err)
+		assert.Len(t, rewrites, 1)
+
+		object := gitRun(t, dir, nil, "cat-file", "commit", rewrites[0].New.String())
+		assert.Contains(t, object, "\nencoding ISO-8859-1\n")
+		assert.True(t, strings.HasSuffix(object, "\n\n"+message))
+		assert.Equal(t, "Café commit", gitRun(t, dir, nil, "log", "-1", "--format=%s", rewrites[0].New.String()))
+	})
+
+	t.Run("should report the commits reachable from a remote branch", func(t *testing.T) {
+		dir, hashes := newRepository(t)
+
+		repo, err := infrastructure.NewGitCommitRepository(dir)
+		assert.NoError(t, err)
+
+		hash, err := domain.NewScmCommitHash(hashes[2])
+		assert.NoError(t, err)
+
+		pushed, err := repo.IsPushed(&hash)
+		assert.NoError(t, err)
+		assert.False(t, pushed)
+
+		gitRun(t, dir, nil, "update-ref", "refs/remotes/origin/master", hashes[1])
+
+		pushed, err = repo.IsPushed(&hash)
+		assert.NoError(t, err)
+		assert.False(t, pushed)
+
+		hash, err = domain.NewScmCommitHash(hashes[1])
+		assert.NoError(t, err)
+
+		pushed, err = repo.IsPushed(&hash)
+		assert.NoError(t, err)
+		assert.True(t, pushed)
+	})
+}
+
+func TestGitCommitRepositoryWalk(t *testing.T) {
+	dir := t.TempDir()
+	gitRun(t, dir, nil, "init", "-q")
+
+	root := gitCommit(t, dir, "Root Name", "root@example.com", "Root Commit")
+	gitRun(t, dir, []string{
+		"GIT_AUTHOR_NAME=Old Name", "GIT_AUTHOR_EMAIL=old-machine", "GIT_AUTHOR_DATE=1600000000 +0200",
+		"GIT_COMMITTER_NAME=Root Name", "GIT_COMMITTER_EMAIL=root@example.com", "GIT_COMMITTER_DATE=1738436196 +0100",
+	}, "commit", "--allow-empty", "-q", "-m", "Old Commit")
+	head := gitRun(t, dir, nil, "rev-parse", "HEAD")
+
+	repo, err := infrastructure.NewGitCommitRepository(dir)
+	assert.NoError(t, err)
+
+	t.Run("should visit the commits newest first with the identities as written", func(t *testing.T) {
+		records := make([]*domain.ScmCommitRecord, 0)
+		tip := domain.NewScmCommitHashHead()
+
+		err := repo.Walk(&tip, nil, func(record *domain.ScmCommitRecord) error {
+			records = append(records, record)
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Len(t, records, 2)
+		assert.Equal(t, head, records[0].Hash.String())
+		assert.Equal(t, "Old Name", records[0].AuthorName)
+		assert.Equal(t, "old-machine", records[0].AuthorEmail)
+		assert.Equal(t, time.Unix(1600000000, 0).UTC(), records[0].Date)
+		assert.Equal(t, "root@example.com", records[0].CommitterEmail)
+		assert.Equal(t, time.Unix(1738436196, 0).UTC(), records[0].CommitterDate)
+		assert.Equal(t, root, records[1].Hash.String())
+	})
+
+	t.Run("should exclude the commits of the base and stop when the visit fails", func(t *testing.T) {
+		tip := domain.NewScmCommitHashHead()
+		base, err := domain.NewScmCommitHash(root)
+		assert.NoError(t, err)
+
+		count := 0
+		err = repo.Walk(&tip, []domain.ScmCommitHash{base}, func(record *domain.ScmCommitRecord) error {
+			count++
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+
+		expected := errors.New("stop")
+		err = repo.Walk(&tip, nil, func(record *domain.ScmCommitRecord) error {
+			return expected
+		})
+		assert.ErrorIs(t, err, expected)
+	})
+
+	t.Run("should return an error when the revision does not exist", func(t *testing.T) {
+		tip, err := domain.NewScmCommitHash("unknown")
+		assert.NoError(t, err)
+
+		err = repo.Walk(&tip, nil, func(record *domain.ScmCommitRecord) error {
+			return nil
+		})
+		assert.ErrorIs(t, err, domain.ErrScmCommitNotFound)
+
+		outside, err := infrastructure.NewGitCommitRepository(t.TempDir())
+		assert.NoError(t, err)
+
+		err = outside.Walk(&tip, nil, func(record *domain.ScmCommitRecord) error {
+			return nil
+		})
+		assert.ErrorIs(t, err, domain.ErrScmRepositoryNotFound)
+	})
 }
@@ -0,0 +1,686 @@
+package infrastructure
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+var ErrInvalidGitConfigFile = errors.New("invalid git config file")
+
+// gitConfigLockSuffix is the suffix of the lock file git takes while it writes a configuration file
+const gitConfigLockSuffix = ".lock"
+
+// gitConfigFile edits a git configuration file in place. The keys are read,
+// set and unset as git does and every other byte of the file is kept, such
+// as the comments, the quoted subsections, the escapes, the includes and
+// the keys with several values.
+type gitConfigFile struct {
+	path     string
+	content  []byte
+	sections []gitConfigSection
+	entries  []gitConfigEntry
+}
+
+// gitConfigSection is a section header and its body, given as offsets of the content
+type gitConfigSection struct {
+	name       string
+	subsection string
+	// start is the offset of the header line, headerEnd follows the closing bracket
+	start     int
+	headerEnd int
+	// bodyStart follows the header line and end is the start of the next header
+	bodyStart int
+	end       int
+}
+
+// gitConfigEntry is a key of a section and its value, the key of a header line
+// such as "[core] bare = true" is inline
+type gitConfigEntry struct {
+	section int
+	name    string
+	value   string
+	// start and end delimit the bytes of the key, lineEnd follows its last line
+	start   int
+	end     int
+	lineEnd int
+	inline  bool
+}
+
+// loadGitConfigFile reads and parses the file, a missing file is empty
+func loadGitConfigFile(path string) (*gitConfigFile, error) {
+	content, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	file := &gitConfigFile{path: path, content: content}
+	if err := file.parse(); err != nil {
+		return nil, err
+	}
+
+	return file, nil
+}
+
+// Get returns the value of the key, the last one when the key has several values
+func (f *gitConfigFile) Get(key string) (string, bool) {
+	matches := f.find(key)
+	if len(matches) == 0 {
+		return "", false
+	}
+
+	return f.entries[matches[len(matches)-1]].value, true
+}
+
+// GetAll returns every value of the key, in the order of the file
+func (f *gitConfigFile) GetAll(key string) []string {
+	values := make([]string, 0)
+	for _, index := range f.find(key) {
+		values = append(values, f.entries[index].value)
+	}
+
+	return values
+}
+
+// List returns the key and the value of every entry, in the order of the
+// file, with the section and the name of the key in lower case
+func (f *gitConfigFile) List() [][2]string {
+	entries := make([][2]string, 0, len(f.entries))
+	for _, entry := range f.entries {
+		key := f.sections[entry.section].name
+		if subsection := f.sections[entry.section].subsection; subsection != "" {
+			key += "." + subsection
+		}
+
+		entries = append(entries, [2]string{key + "." + entry.name, entry.value})
+	}
+
+	return entries
+}
+
+// gitConfigReader reads the values of keys of one or several git configuration files
+type gitConfigReader interface {
+	Get(key string) (string, bool)
+}
+
+// gitConfigFiles are files read in order, as git reads its global files, the last value wins
+type gitConfigFiles []*gitConfigFile
+
+func (files gitConfigFiles) Get(key string) (string, bool) {
+	for i := len(files) - 1; i >= 0; i-- {
+		if value, ok := files[i].Get(key); ok {
+			return value, true
+		}
+	}
+
+	return "", false
+}
+
+// HasSection reports whether the file has a section with the name, such as user or remote "origin"
+func (f *gitConfigFile) HasSection(key string) bool {
+	section, subsection, _ := parseGitConfigKey(key + ".name")
+	for _, s := range f.sections {
+		if s.name == section && s.subsection == subsection {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Set replaces the value of the key, the other values of a key with several
+// values are removed. A new key is added as Add does.
+func (f *gitConfigFile) Set(key string, value string) error {
+	_, _, name := parseGitConfigKey(key)
+	line := "\t" + name + " = " + quoteGitConfigValue(value)
+
+	matches := f.find(key)
+	if len(matches) > 0 {
+		last := f.entries[matches[len(matches)-1]]
+		replacement := line
+		if last.inline {
+			replacement = strings.TrimPrefix(line, "\t")
+		}
+
+		edits := []gitConfigEdit{{last.start, last.end, replacement}}
+		for _, index := range matches[:len(matches)-1] {
+			edits = append(edits, f.removal(f.entries[index]))
+		}
+
+		return f.apply(edits)
+	}
+
+	return f.Add(key, value)
+}
+
+// Add adds a value to the key, keeping its other values, after the last key
+// of the last matching section, or in a new section at the end of the file.
+func (f *gitConfigFile) Add(key string, value string) error {
+	section, subsection, name := parseGitConfigKey(key)
+	line := "\t" + name + " = " + quoteGitConfigValue(value)
+
+	for s := len(f.sections) - 1; s >= 0; s-- {
+		if f.sections[s].name != section || f.sections[s].subsection != subsection {
+			continue
+		}
+
+		at := f.sections[s].bodyStart
+		for _, entry := range f.entries {
+			if entry.section == s {
+				at = entry.lineEnd
+			}
+		}
+
+		if at > 0 && f.content[at-1] != '\n' {
+			line = "\n" + line
+		}
+
+		return f.apply([]gitConfigEdit{{at, at, line + "\n"}})
+	}
+
+	header := "[" + section + "]\n"
+	if subsection != "" {
+		header = "[" + section + " \"" + escapeGitConfigValue(subsection) + "\"]\n"
+	}
+
+	if len(f.content) > 0 && f.content[len(f.content)-1] != '\n' {
+		header = "\n" + header
+	}
+
+	return f.apply([]gitConfigEdit{{len(f.content), len(f.content), header + line + "\n"}})
+}
+
+// Unset removes every value of the key
+func (f *gitConfigFile) Unset(key string) error {
+	matches := f.find(key)
+	if len(matches) == 0 {
+		return nil
+	}
+
+	edits := make([]gitConfigEdit, 0, len(matches))
+	for _, index := range matches {
+		edits = append(edits, f.removal(f.entries[index]))
+	}
+
+	return f.apply(edits)
+}
+
+// UnsetValue removes the values of the key equal to the value, the other ones are kept
+func (f *gitConfigFile) UnsetValue(key string, value string) error {
+	edits := make([]gitConfigEdit, 0)
+	for _, index := range f.find(key) {
+		if f.entries[index].value == value {
+			edits = append(edits, f.removal(f.entries[index]))
+		}
+	}
+
+	if len(edits) == 0 {
+		return nil
+	}
+
+	return f.apply(edits)
+}
+
+// RemoveEmptySection removes the sections with the name that are left
+// without keys nor comments, such as gpg once its format is unset
+func (f *gitConfigFile) RemoveEmptySection(key string) error {
+	section, subsection, _ := parseGitConfigKey(key + ".name")
+
+	edits := []gitConfigEdit{}
+	for _, s := range f.sections {
+		if s.name != section || s.subsection != subsection {
+			continue
+		}
+
+		if strings.TrimSpace(string(f.content[s.headerEnd:s.end])) == "" {
+			edits = append(edits, gitConfigEdit{s.start, s.end, ""})
+		}
+	}
+
+	if len(edits) == 0 {
+		return nil
+	}
+
+	return f.apply(edits)
+}
+
+// Save writes the file as git does, through a lock file renamed over the
+// file, the target of a symbolic link is written instead of the link
+func (f *gitConfigFile) Save() error {
+	path := f.path
+	if target, err := filepath.EvalSymlinks(path); err == nil {
+		path = target
+	}
+
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return err
+	}
+
+	lock := path + gitConfigLockSuffix
+	file, err := os.OpenFile(lock, os.O_WRONLY|os.O_CREATE|os.O_EXCL, mode) // #nosec G304
+	if err != nil {
+		return fmt.Errorf("could not lock the git config file %s: %w", path, err)
+	}
+
+	if _, err := file.Write(f.content); err != nil {
+		file.Close()
+		os.Remove(lock)
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		os.Remove(lock)
+		return err
+	}
+
+	if err := os.Rename(lock, path); err != nil {
+		os.Remove(lock)
+		return err
+	}
+
+	return nil
+}
+
+// find returns the indexes of the entries of the key, in the order of the file
+func (f *gitConfigFile) find(key string) []int {
+	section, subsection, name := parseGitConfigKey(key)
+
+	matches := []int{}
+	for index, entry := range f.entries {
+		s := f.sections[entry.section]
+		if entry.name == name && s.name == section && s.subsection == subsection {
+			matches = append(matches, index)
+		}
+	}
+
+	return matches
+}
+
+// removal returns the edit removing the lines of the entry, the key of a
+// header line is removed with the spaces before it
+func (f *gitConfigFile) removal(entry gitConfigEntry) gitConfigEdit {
+	if entry.inline {
+		return gitConfigEdit{f.sections[entry.section].headerEnd, entry.end, ""}
+	}
+
+	return gitConfigEdit{entry.start, entry.lineEnd, ""}
+}
+
+// gitConfigEdit replaces the bytes from start to end of the content
+type gitConfigEdit struct {
+	start int
+	end   int
+	text  string
+}
+
+// apply replaces the bytes of the edits and parses the content again
+func (f *gitConfigFile) apply(edits []gitConfigEdit) error {
+	var content strings.Builder
+
+	sort.Slice(edits, func(i, j int) bool {
+		return edits[i].start < edits[j].start
+	})
+
+	at := 0
+	for _, edit := range edits {
+		content.Write(f.content[at:edit.start])
+		content.WriteString(edit.text)
+		at = edit.end
+	}
+
+	content.Write(f.content[at:])
+	f.content = []byte(content.String())
+
+	return f.parse()
+}
+
+// parse reads the sections and the keys of the content as git does
+func (f *gitConfigFile) parse() error {
+	p := &gitConfigParser{content: f.content}
+	f.sections, f.entries = nil, nil
+
+	// A byte order mark is skipped as git does
+	if strings.HasPrefix(string(f.content), "\xef\xbb\xbf") {
+		p.at = 3
+	}
+
+	for !p.eof() {
+		start := p.at
+		p.skipSpaces()
+
+		switch c := p.peek(); {
+		case p.eof():
+		case c == '\n' || c == '\r':
+			p.skipLine()
+		case c == '#' || c == ';':
+			p.skipLine()
+		case c == '[':
+			if err := f.parseSection(p, start); err != nil {
+				return err
+			}
+		case isGitConfigKeyStart(c):
+			if len(f.sections) == 0 {
+				return fmt.Errorf("%w: %s: key outside of a section", ErrInvalidGitConfigFile, f.path)
+			}
+
+			entry, err := p.entry(start, len(f.sections)-1)
+			if err != nil {
+				return fmt.Errorf("%w: %s: %v", ErrInvalidGitConfigFile, f.path, err)
+			}
+
+			f.entries = append(f.entries, entry)
+		default:
+			return fmt.Errorf("%w: %s: unexpected character %q", ErrInvalidGitConfigFile, f.path, c)
+		}
+	}
+
+	if len(f.sections) > 0 {
+		f.sections[len(f.sections)-1].end = len(f.content)
+	}
+
+	return nil
+}
+
+// parseSection reads the header of a section and the key that may follow it on the same line
+func (f *gitConfigFile) parseSection(p *gitConfigParser, start int) error {
+	if len(f.sections) > 0 {
+		f.sections[len(f.sections)-1].end = start
+	}
+
+	name, subsection, err := p.header()
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidGitConfigFile, f.path, err)
+	}
+
+	section := gitConfigSection{name: name, subsection: subsection, start: start, headerEnd: p.at}
+	f.sections = append(f.sections, section)
+
+	p.skipSpaces()
+	switch c := p.peek(); {
+	case p.eof(), c == '\n', c == '\r', c == '#', c == ';':
+		p.skipLine()
+	case isGitConfigKeyStart(c):
+		entry, err := p.entry(p.at, len(f.sections)-1)
+		if err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrInvalidGitConfigFile, f.path, err)
+		}
+
+		entry.inline = true
+		f.entries = append(f.entries, entry)
+	default:
+		return fmt.Errorf("%w: %s: unexpected character %q", ErrInvalidGitConfigFile, f.path, c)
+	}
+
+	f.sections[len(f.sections)-1].bodyStart = p.at
+	return nil
+}
+
+// gitConfigParser reads the content of a git configuration file, "\r\n" is read as a new line
+type gitConfigParser struct {
+	content []byte
+	at      int
+}
+
+func (p *gitConfigParser) eof() bool {
+	return p.at >= len(p.content)
+}
+
+func (p *gitConfigParser) peek() byte {
+	if p.eof() {
+		return '\n'
+	}
+
+	return p.content[p.at]
+}
+
+// next returns the next character, the end of the file is a new line
+func (p *gitConfigParser) next() byte {
+	if p.eof() {
+		return '\n'
+	}
+
+	c := p.content[p.at]
+	p.at++
+
+	if c == '\r' && p.peek() == '\n' && !p.eof() {
+		p.at++
+		return '\n'
+	}
+
+	return c
+}
+
+func (p *gitConfigParser) skipSpaces() {
+	for !p.eof() && (p.content[p.at] == ' ' || p.content[p.at] == '\t') {
+		p.at++
+	}
+}
+
+func (p *gitConfigParser) skipLine() {
+	for !p.eof() && p.next() != '\n' {
+	}
+}
+
+// header reads a section header, [section], [section "subsection"] or the
+// deprecated [section.subsection], the section name is case insensitive
+func (p *gitConfigParser) header() (string, string, error) {
+	p.at++
+
+	var name strings.Builder
+	for {
+		c := p.next()
+		switch {
+		case c == ']':
+			section, subsection, _ := strings.Cut(strings.ToLower(name.String()), ".")
+			if section == "" {
+				return "", "", errors.New("empty section name")
+			}
+
+			return section, subsection, nil
+		case c == ' ' || c == '\t':
+			if name.Len() == 0 {
+				return "", "", errors.New("empty section name")
+			}
+
+			subsection, err := p.subsection()
+			return strings.ToLower(name.String()), subsection, err
+		case isGitConfigKeyChar(c) || c == '.':
+			name.WriteByte(c)
+		default:
+			return "", "", fmt.Errorf("invalid section name %q", name.String()+string(c))
+		}
+	}
+}
+
+// subsection reads the quoted subsection of a header up to the closing bracket
+func (p *gitConfigParser) subsection() (string, error) {
+	p.skipSpaces()
+	if p.next() != '"' {
+		return "", errors.New("the subsection must be quoted")
+	}
+
+	var subsection strings.Builder
+	for {
+		c := p.next()
+		switch c {
+		case '\n':
+			return "", errors.New("unterminated subsection")
+		case '\\':
+			c = p.next()
+			if c == '\n' {
+				return "", errors.New("unterminated subsection")
+			}
+
+			subsection.WriteByte(c)
+		case '"':
+			if p.next() != ']' {
+				return "", errors.New("the subsection must be followed by ]")
+			}
+
+			return subsection.String(), nil
+		default:
+			subsection.WriteByte(c)
+		}
+	}
+}
+
+// entry reads a key and its value, a key without value is a true boolean
+func (p *gitConfigParser) entry(start int, section int) (gitConfigEntry, error) {
+	entry := gitConfigEntry{section: section, start: start, value: "true"}
+
+	var name strings.Builder
+	for !p.eof() && isGitConfigKeyChar(p.content[p.at]) {
+		name.WriteByte(p.content[p.at])
+		p.at++
+	}
+
+	entry.name = strings.ToLower(name.String())
+
+	p.skipSpaces()
+	switch c := p.next(); c {
+	case '\n':
+	case '=':
+		value, err := p.value()
+		if err != nil {
+			return entry, fmt.Errorf("key %s: %w", entry.name, err)
+		}
+
+		entry.value = value
+	default:
+		return entry, fmt.Errorf("key %s: unexpected character %q", entry.name, c)
+	}
+
+	entry.lineEnd = p.at
+	entry.end = p.at
+	if entry.end > 0 && p.content[entry.end-1] == '\n' {
+		entry.end--
+		if entry.end > 0 && p.content[entry.end-1] == '\r' {
+			entry.end--
+		}
+	}
+
+	return entry, nil
+}
+
+// value reads a value up to the end of the line: the spaces around it are
+// trimmed, the comments removed, the quotes and the escapes resolved and a
+// backslash at the end of a line continues the value on the next line
+func (p *gitConfigParser) value() (string, error) {
+	var value strings.Builder
+	quoted, comment, spaces := false, false, 0
+
+	for {
+		c := p.next()
+		if c == '\n' {
+			if quoted {
+				return "", errors.New("unterminated quote")
+			}
+
+			return value.String(), nil
+		}
+
+		if comment {
+			continue
+		}
+
+		if (c == ' ' || c == '\t') && !quoted {
+			if value.Len() > 0 {
+				spaces++
+			}
+
+			continue
+		}
+
+		if !quoted && (c == '#' || c == ';') {
+			comment = true
+			continue
+		}
+
+		for ; spaces > 0; spaces-- {
+			value.WriteByte(' ')
+		}
+
+		switch c {
+		case '\\':
+			switch e := p.next(); e {
+			case '\n':
+			case 't':
+				value.WriteByte('\t')
+			case 'b':
+				value.WriteByte('\b')
+			case 'n':
+				value.WriteByte('\n')
+			case '\\', '"':
+				value.WriteByte(e)
+			default:
+				return "", fmt.Errorf("invalid escape \\%c", e)
+			}
+		case '"':
+			quoted = !quoted
+		default:
+			value.WriteByte(c)
+		}
+	}
+}
+
+func isGitConfigKeyStart(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isGitConfigKeyChar(c byte) bool {
+	return isGitConfigKeyStart(c) || (c >= '0' && c <= '9') || c == '-'
+}
+
+// parseGitConfigKey splits a key such as url.git@github.com:.insteadOf into the
+// section and the name, both in lower case, and the subsection that keeps its case
+func parseGitConfigKey(key string) (string, string, string) {
+	first, last := strings.Index(key, "."), strings.LastIndex(key, ".")
+	if first < 0 {
+		return strings.ToLower(key), "", ""
+	}
+
+	subsection := ""
+	if first != last {
+		subsection = key[first+1 : last]
+	}
+
+	return strings.ToLower(key[:first]), subsection, strings.ToLower(key[last+1:])
+}
+
+// quoteGitConfigValue writes a value as git does, quoted when it has spaces
+// around it or comment characters and with the special characters escaped
+func quoteGitConfigValue(value string) string {
+	quote := value != strings.TrimSpace(value) || strings.ContainsAny(value, "#;")
+
+	var quoted strings.Builder
+	for _, c := range value {
+		switch c {
+		case '\\':
+			quoted.WriteString(`\\`)
+		case '"':
+			quoted.WriteString(`\"`)
+		case '\n':
+			quoted.WriteString(`\n`)
+		case '\t':
+			quoted.WriteString(`\t`)
+		case '\b':
+			quoted.WriteString(`\b`)
+		default:
+			quoted.WriteRune(c)
+		}
+	}
+
+	if quote {
+		return `"` + quoted.String() + `"`
+	}
+
+	return quoted.String()
+}
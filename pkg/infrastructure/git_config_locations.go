@@ -0,0 +1,82 @@
+package infrastructure
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Environment variables git reads to locate its configuration files
+const (
+	GIT_CONFIG_GLOBAL_ENV   = "GIT_CONFIG_GLOBAL"
+	GIT_CONFIG_SYSTEM_ENV   = "GIT_CONFIG_SYSTEM"
+	GIT_CONFIG_NOSYSTEM_ENV = "GIT_CONFIG_NOSYSTEM"
+	XDG_CONFIG_HOME_ENV     = "XDG_CONFIG_HOME"
+)
+
+// GIT_XDG_CONFIG_FILE is the global configuration of git under $XDG_CONFIG_HOME
+const GIT_XDG_CONFIG_FILE = "git/config"
+
+// GIT_SYSTEM_CONFIG_FILE is the system configuration of git when GIT_CONFIG_SYSTEM is not set
+const GIT_SYSTEM_CONFIG_FILE = "/etc/gitconfig"
+
+// GitConfigLocations are the configuration files of git, resolved as git does
+type GitConfigLocations struct {
+	// Global are the global files git reads, in order, the last value wins
+	Global []string
+	// GlobalWrite is the global file git config --global writes
+	GlobalWrite string
+	// System is the system file, empty when GIT_CONFIG_NOSYSTEM disables it
+	System string
+}
+
+// NewGitConfigLocations resolves the configuration files of git. GIT_CONFIG_GLOBAL
+// replaces the global files, otherwise $XDG_CONFIG_HOME/git/config and then
+// ~/.gitconfig are read and the latter is written unless only the former
+// exists. GIT_CONFIG_SYSTEM replaces the system file, GIT_CONFIG_NOSYSTEM
+// disables it.
+func NewGitConfigLocations(userHomeDir string, xdgConfigHome string) *GitConfigLocations {
+	locations := &GitConfigLocations{}
+
+	if global := os.Getenv(GIT_CONFIG_GLOBAL_ENV); global != "" {
+		locations.Global = []string{global}
+		locations.GlobalWrite = global
+	} else {
+		xdg := filepath.Join(xdgConfigHome, GIT_XDG_CONFIG_FILE)
+		home := filepath.Join(userHomeDir, GIT_GLOBAL_CONFIG_FILE)
+
+		locations.Global = []string{xdg, home}
+		locations.GlobalWrite = home
+		if !fileExists(home) && fileExists(xdg) {
+			locations.GlobalWrite = xdg
+		}
+	}
+
+	if !gitEnvBool(GIT_CONFIG_NOSYSTEM_ENV) {
+		locations.System = GIT_SYSTEM_CONFIG_FILE
+		if system := os.Getenv(GIT_CONFIG_SYSTEM_ENV); system != "" {
+			locations.System = system
+		}
+	}
+
+	return locations
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// gitEnvBool reads a boolean environment variable as git does, an empty value is false
+func gitEnvBool(name string) bool {
+	switch value := strings.ToLower(strings.TrimSpace(os.Getenv(name))); value {
+	case "", "false", "no", "off":
+		return false
+	case "true", "yes", "on":
+		return true
+	default:
+		number, err := strconv.Atoi(value)
+		return err != nil || number != 0
+	}
+}
@@ -0,0 +1,69 @@
+package infrastructure_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/b4nd/git-profile/pkg/infrastructure"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitConfigLocations(t *testing.T) {
+	t.Setenv("GIT_CONFIG_GLOBAL", "")
+	t.Setenv("GIT_CONFIG_SYSTEM", "")
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "")
+
+	t.Run("should read the XDG and home files and write the home file", func(t *testing.T) {
+		home := t.TempDir()
+		xdg := filepath.Join(home, ".config")
+
+		locations := infrastructure.NewGitConfigLocations(home, xdg)
+		assert.Equal(t, []string{filepath.Join(xdg, "git", "config"), filepath.Join(home, ".gitconfig")}, locations.Global)
+		assert.Equal(t, filepath.Join(home, ".gitconfig"), locations.GlobalWrite)
+		assert.Equal(t, "/etc/gitconfig", locations.System)
+	})
+
+	t.Run("should write the XDG file when it is the only one", func(t *testing.T) {
+		home := t.TempDir()
+		xdg := filepath.Join(home, ".config")
+
+		assert.NoError(t, os.MkdirAll(filepath.Join(xdg, "git"), 0750))
+		assert.NoError(t, os.WriteFile(filepath.Join(xdg, "git", "config"), []byte("[user]\n\tname = Jane\n"), 0600))
+
+		locations := infrastructure.NewGitConfigLocations(home, xdg)
+		assert.Equal(t, filepath.Join(xdg, "git", "config"), locations.GlobalWrite)
+
+		// Once the home file exists, git writes it again
+		assert.NoError(t, os.WriteFile(filepath.Join(home, ".gitconfig"), []byte(""), 0600))
+
+		locations = infrastructure.NewGitConfigLocations(home, xdg)
+		assert.Equal(t, filepath.Join(home, ".gitconfig"), locations.GlobalWrite)
+	})
+
+	t.Run("should only use GIT_CONFIG_GLOBAL when it is set", func(t *testing.T) {
+		home := t.TempDir()
+		global := filepath.Join(t.TempDir(), "gitconfig")
+		t.Setenv("GIT_CONFIG_GLOBAL", global)
+
+		locations := infrastructure.NewGitConfigLocations(home, filepath.Join(home, ".config"))
+		assert.Equal(t, []string{global}, locations.Global)
+		assert.Equal(t, global, locations.GlobalWrite)
+	})
+
+	t.Run("should honour GIT_CONFIG_SYSTEM and GIT_CONFIG_NOSYSTEM", func(t *testing.T) {
+		home := t.TempDir()
+		t.Setenv("GIT_CONFIG_SYSTEM", "/opt/git/etc/gitconfig")
+
+		locations := infrastructure.NewGitConfigLocations(home, filepath.Join(home, ".config"))
+		assert.Equal(t, "/opt/git/etc/gitconfig", locations.System)
+
+		for value, disabled := range map[string]bool{"1": true, "true": true, "yes": true, "0": false, "false": false} {
+			t.Setenv("GIT_CONFIG_NOSYSTEM", value)
+
+			locations = infrastructure.NewGitConfigLocations(home, filepath.Join(home, ".config"))
+			assert.Equal(t, disabled, locations.System == "", value)
+		}
+	})
+}
@@ -0,0 +1,176 @@
+package infrastructure
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/b4nd/git-profile/pkg/domain"
+)
+
+// Keys read from the git configuration files, the section and the name in lower case
+const (
+	GIT_CONFIG_KEY_USER_WORKSPACE = "user.workspace"
+	GIT_CONFIG_KEY_USER_NAME      = "user.name"
+	GIT_CONFIG_KEY_USER_EMAIL     = "user.email"
+	GIT_CONFIG_KEY_SIGNING_KEY    = "user.signingkey"
+	GIT_CONFIG_KEY_GPG_FORMAT     = "gpg.format"
+	GIT_CONFIG_KEY_COMMIT_GPGSIGN = "commit.gpgsign"
+	GIT_CONFIG_KEY_TAG_GPGSIGN    = "tag.gpgsign"
+	GIT_CONFIG_KEY_SSH_COMMAND    = "core.sshcommand"
+	GIT_CONFIG_KEY_INCLUDE_PATH   = "include.path"
+)
+
+// GitConfigRepository reads the identities of git configuration files and
+// of the files included by them through include and includeIf sections
+type GitConfigRepository struct {
+	paths []string
+	home  string
+}
+
+func NewGitConfigRepository(paths []string, home string) (*GitConfigRepository, error) {
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no paths provided")
+	}
+
+	return &GitConfigRepository{paths, home}, nil
+}
+
+func (r *GitConfigRepository) List() ([]*domain.ScmConfigIdentity, error) {
+	identities := make([]*domain.ScmConfigIdentity, 0)
+	visited := map[string]bool{}
+
+	for _, path := range r.paths {
+		found, err := r.walk(path, visited)
+		if err != nil {
+			return nil, err
+		}
+
+		identities = append(identities, found...)
+	}
+
+	return identities, nil
+}
+
+// walk reads the identity of the file and then the identities of the files it
+// includes through includeIf sections, their conditions are ignored and every
+// file is listed once
+func (r *GitConfigRepository) walk(path string, visited map[string]bool) ([]*domain.ScmConfigIdentity, error) {
+	path = filepath.Clean(path)
+	if visited[path] {
+		return nil, nil
+	}
+
+	visited[path] = true
+
+	entries, conditionals, err := r.read(path, map[string]bool{})
+	if err != nil {
+		return nil, err
+	}
+
+	identities := make([]*domain.ScmConfigIdentity, 0)
+	if user := newScmUserFromGitConfig(entries); user != nil {
+		identities = append(identities, domain.NewScmConfigIdentity(path, user))
+	}
+
+	for _, conditional := range conditionals {
+		found, err := r.walk(conditional, visited)
+		if err != nil {
+			return nil, err
+		}
+
+		identities = append(identities, found...)
+	}
+
+	return identities, nil
+}
+
+// read returns the entries of the file with the entries of the files of its
+// include sections in their place, as git reads them, and the paths of the
+// files of its includeIf sections. A file that includes itself is read once.
+func (r *GitConfigRepository) read(path string, reading map[string]bool) ([][2]string, []string, error) {
+	path = filepath.Clean(path)
+	if reading[path] {
+		return nil, nil, nil
+	}
+
+	if info, err := os.Stat(path); err != nil || info.IsDir() {
+		return nil, nil, nil
+	}
+
+	reading[path] = true
+	defer delete(reading, path)
+
+	file, err := loadGitConfigFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	entries := make([][2]string, 0)
+	conditionals := make([]string, 0)
+	for _, entry := range file.List() {
+		switch key := entry[0]; {
+		case key == GIT_CONFIG_KEY_INCLUDE_PATH:
+			included, found, err := r.read(r.resolveIncludePath(path, entry[1]), reading)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			entries = append(entries, included...)
+			conditionals = append(conditionals, found...)
+		case strings.HasPrefix(key, GIT_SECTION_INCLUDE_IF+".") && strings.HasSuffix(key, ".path"):
+			conditionals = append(conditionals, r.resolveIncludePath(path, entry[1]))
+		default:
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries, conditionals, nil
+}
+
+// resolveIncludePath resolves the path of an include as git does, relative
+// paths are relative to the including file
+func (r *GitConfigRepository) resolveIncludePath(from string, include string) string {
+	if rest, ok := strings.CutPrefix(include, "~/"); ok {
+		return filepath.Join(r.home, rest)
+	}
+
+	if filepath.IsAbs(include) {
+		return include
+	}
+
+	return filepath.Join(filepath.Dir(from), include)
+}
+
+// newScmUserFromGitConfig returns the identity of the entries, nil when the
+// name or the email is not set, the last value of a key wins as in git
+func newScmUserFromGitConfig(entries [][2]string) *domain.ScmUser {
+	values := map[string]string{}
+	for _, entry := range entries {
+		values[entry[0]] = entry[1]
+	}
+
+	name, email := values[GIT_CONFIG_KEY_USER_NAME], values[GIT_CONFIG_KEY_USER_EMAIL]
+	if strings.TrimSpace(name) == "" || strings.TrimSpace(email) == "" {
+		return nil
+	}
+
+	user := domain.NewScmUser(values[GIT_CONFIG_KEY_USER_WORKSPACE], email, name)
+	user.SigningKey = values[GIT_CONFIG_KEY_SIGNING_KEY]
+	user.SigningFormat = values[GIT_CONFIG_KEY_GPG_FORMAT]
+	user.CommitGpgSign = isGitConfigTrue(values[GIT_CONFIG_KEY_COMMIT_GPGSIGN])
+	user.TagGpgSign = isGitConfigTrue(values[GIT_CONFIG_KEY_TAG_GPGSIGN])
+	user.SshCommand = values[GIT_CONFIG_KEY_SSH_COMMAND]
+
+	return user
+}
+
+func isGitConfigTrue(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "true", "yes", "on", "1":
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,127 @@
+package infrastructure_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/b4nd/git-profile/pkg/domain"
+	"github.com/b4nd/git-profile/pkg/infrastructure"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitConfigRepository(t *testing.T) {
+	t.Run("should return error when no path is provided", func(t *testing.T) {
+		gitConfigRepository, err := infrastructure.NewGitConfigRepository([]string{}, t.TempDir())
+
+		assert.Error(t, err)
+		assert.Nil(t, gitConfigRepository)
+	})
+
+	t.Run("should return the identities of the config and its includes", func(t *testing.T) {
+		home := t.TempDir()
+		global := filepath.Join(home, ".gitconfig")
+		ssh := filepath.Join(home, ".gitconfig.d", "ssh.gitconfig")
+		work := filepath.Join(home, "work", ".gitconfig")
+		oss := filepath.Join(home, "oss.gitconfig")
+
+		assert.NoError(t, os.MkdirAll(filepath.Dir(work), 0750))
+		assert.NoError(t, os.MkdirAll(filepath.Dir(ssh), 0750))
+		assert.NoError(t, os.WriteFile(global, []byte(`[user]
+	name = Jane Doe
+	email = jane@gmail.com
+[include]
+	path = .gitconfig.d/ssh.gitconfig
+[includeIf "gitdir:~/work/"]
+	path = ~/work/.gitconfig
+[includeIf "gitdir:~/missing/"]
+	path = ~/missing.gitconfig
+`), 0600))
+		assert.NoError(t, os.WriteFile(ssh, []byte(`[core]
+	sshCommand = ssh -i ~/.ssh/id_personal
+[includeIf "gitdir:~/oss/"]
+	path = ../oss.gitconfig
+`), 0600))
+		assert.NoError(t, os.WriteFile(work, []byte(`[user]
+	name = "Doe, Jane"
+	email = jane@acme.com
+	signingKey = ABCDEF0123456789
+[gpg]
+	format = openpgp
+[commit]
+	gpgSign
+[core]
+	sshCommand = ssh -i ~/.ssh/id_acme
+`), 0600))
+		assert.NoError(t, os.WriteFile(oss, []byte(`[user]
+	workspace = oss
+	name = Jane Doe
+	email = jane@oss.dev
+`), 0600))
+
+		gitConfigRepository, err := infrastructure.NewGitConfigRepository([]string{global, filepath.Join(home, "repo", ".git", "config")}, home)
+		assert.NoError(t, err)
+
+		identities, err := gitConfigRepository.List()
+		assert.NoError(t, err)
+
+		globalUser := domain.NewScmUser("", "jane@gmail.com", "Jane Doe")
+		globalUser.SshCommand = "ssh -i ~/.ssh/id_personal"
+
+		workUser := domain.NewScmUser("", "jane@acme.com", "Doe, Jane")
+		workUser.SigningKey = "ABCDEF0123456789"
+		workUser.SigningFormat = "openpgp"
+		workUser.CommitGpgSign = true
+		workUser.SshCommand = "ssh -i ~/.ssh/id_acme"
+
+		assert.Equal(t, []*domain.ScmConfigIdentity{
+			domain.NewScmConfigIdentity(global, globalUser),
+			domain.NewScmConfigIdentity(oss, domain.NewScmUser("oss", "jane@oss.dev", "Jane Doe")),
+			domain.NewScmConfigIdentity(work, workUser),
+		}, identities)
+	})
+
+	t.Run("should read the includes in their place and once when they include each other", func(t *testing.T) {
+		home := t.TempDir()
+		global := filepath.Join(home, ".gitconfig")
+		base := filepath.Join(home, "base.gitconfig")
+
+		assert.NoError(t, os.WriteFile(global, []byte(`[user]
+	name = Jane Doe
+	email = jane@gmail.com
+[include]
+	path = base.gitconfig
+[user]
+	email = jane@example.com
+`), 0600))
+		assert.NoError(t, os.WriteFile(base, []byte(`[user]
+	name = Jane Base
+	email = jane@base.dev
+[include]
+	path = ~/.gitconfig
+`), 0600))
+
+		gitConfigRepository, err := infrastructure.NewGitConfigRepository([]string{global}, home)
+		assert.NoError(t, err)
+
+		identities, err := gitConfigRepository.List()
+		assert.NoError(t, err)
+		assert.Equal(t, []*domain.ScmConfigIdentity{
+			domain.NewScmConfigIdentity(global, domain.NewScmUser("", "jane@example.com", "Jane Base")),
+		}, identities)
+	})
+
+	t.Run("should return an error when a config file is invalid", func(t *testing.T) {
+		home := t.TempDir()
+		global := filepath.Join(home, ".gitconfig")
+		assert.NoError(t, os.WriteFile(global, []byte("[user\n\tname = Jane\n"), 0600))
+
+		gitConfigRepository, err := infrastructure.NewGitConfigRepository([]string{global}, home)
+		assert.NoError(t, err)
+
+		identities, err := gitConfigRepository.List()
+		assert.Error(t, err)
+		assert.Nil(t, identities)
+	})
+}
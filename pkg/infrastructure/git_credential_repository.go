@@ -0,0 +1,64 @@
+package infrastructure
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/b4nd/git-profile/pkg/domain"
+)
+
+// GitCredentialRepository runs a git credential helper the way git does:
+// "cache" runs git credential-cache, "!command" and absolute paths run in a shell
+type GitCredentialRepository struct {
+	path string
+}
+
+func NewGitCredentialRepository(path string) (*GitCredentialRepository, error) {
+	if path == "" {
+		return nil, fmt.Errorf("path cannot be empty")
+	}
+
+	return &GitCredentialRepository{path}, nil
+}
+
+func (r *GitCredentialRepository) Get(helper string, credential *domain.ScmCredential) (*domain.ScmCredential, error) {
+	output, err := r.run(helper, "get", credential)
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.NewScmCredential(output)
+}
+
+func (r *GitCredentialRepository) Store(helper string, credential *domain.ScmCredential) error {
+	_, err := r.run(helper, "store", credential)
+	return err
+}
+
+func (r *GitCredentialRepository) Erase(helper string, credential *domain.ScmCredential) error {
+	_, err := r.run(helper, "erase", credential)
+	return err
+}
+
+func (r *GitCredentialRepository) run(helper string, operation string, credential *domain.ScmCredential) (string, error) {
+	command := strings.TrimSpace(helper)
+	switch {
+	case strings.HasPrefix(command, "!"):
+		command = command[1:]
+	case !filepath.IsAbs(command):
+		command = "git credential-" + command
+	}
+
+	cmd := exec.Command("sh", "-c", command+" "+operation) // #nosec G204
+	cmd.Dir = r.path
+	cmd.Stdin = strings.NewReader(credential.String() + "\n")
+
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", domain.ErrScmCredentialHelperFailed, err)
+	}
+
+	return string(output), nil
+}
@@ -0,0 +1,61 @@
+package infrastructure_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/b4nd/git-profile/pkg/domain"
+	"github.com/b4nd/git-profile/pkg/infrastructure"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitCredentialRepository(t *testing.T) {
+	t.Run("should return an error when the path is empty", func(t *testing.T) {
+		repository, err := infrastructure.NewGitCredentialRepository("")
+		assert.Error(t, err)
+		assert.Nil(t, repository)
+	})
+
+	t.Run("should run the helper with the credential on its standard input", func(t *testing.T) {
+		dir := t.TempDir()
+		stored := filepath.Join(dir, "stored")
+
+		// The helper answers a password to get and records what it is given to store and erase
+		helper := filepath.Join(dir, "git-credential-test")
+		err := os.WriteFile(helper, []byte(`#!/bin/sh
+case "$1" in
+get) cat > /dev/null; echo "password=secret" ;;
+*) echo "$1" >> `+stored+`; cat >> `+stored+` ;;
+esac
+`), 0700) // #nosec G306
+		assert.NoError(t, err)
+
+		repository, err := infrastructure.NewGitCredentialRepository(dir)
+		assert.NoError(t, err)
+
+		credential := &domain.ScmCredential{Protocol: "https", Host: "github.com", Username: "jane-acme"}
+
+		found, err := repository.Get(helper, credential)
+		assert.NoError(t, err)
+		assert.Equal(t, &domain.ScmCredential{Password: "secret"}, found)
+
+		credential.Password = "secret"
+		assert.NoError(t, repository.Store("!"+helper, credential))
+		assert.NoError(t, repository.Erase(helper, credential))
+
+		content, err := os.ReadFile(stored)
+		assert.NoError(t, err)
+		assert.Equal(t, "store\n"+credential.String()+"\nerase\n"+credential.String()+"\n", string(content))
+	})
+
+	t.Run("should return an error when the helper fails", func(t *testing.T) {
+		repository, err := infrastructure.NewGitCredentialRepository(t.TempDir())
+		assert.NoError(t, err)
+
+		found, err := repository.Get("!exit 1", &domain.ScmCredential{Host: "github.com"})
+		assert.ErrorIs(t, err, domain.ErrScmCredentialHelperFailed)
+		assert.Nil(t, found)
+	})
+}
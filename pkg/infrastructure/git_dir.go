@@ -0,0 +1,170 @@
+package infrastructure
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/b4nd/git-profile/pkg/domain"
+)
+
+// Environment variables git reads to locate the repository
+const (
+	GIT_DIR_ENV                 = "GIT_DIR"
+	GIT_WORK_TREE_ENV           = "GIT_WORK_TREE"
+	GIT_COMMON_DIR_ENV          = "GIT_COMMON_DIR"
+	GIT_CEILING_DIRECTORIES_ENV = "GIT_CEILING_DIRECTORIES"
+)
+
+const gitDirFilePrefix = "gitdir:"
+const gitCommonDirFile = "commondir"
+const gitConfigFileName = "config"
+
+// GitDir is a repository as git locates it: the git directory, the common
+// directory shared by its worktrees and the work tree, empty when it is bare
+type GitDir struct {
+	WorkTree  string
+	Dir       string
+	CommonDir string
+}
+
+// FindGitDir locates the repository of the directory the way git does. GIT_DIR
+// is used when it is set, otherwise the parents are walked up to the first
+// .git directory, .git file pointing to the git directory of a worktree or a
+// submodule, or bare repository, stopping at GIT_CEILING_DIRECTORIES.
+// domain.ErrScmRepositoryNotFound is returned outside of a repository.
+func FindGitDir(workingDir string) (*GitDir, error) {
+	workingDir, err := filepath.Abs(workingDir)
+	if err != nil {
+		return nil, err
+	}
+
+	gitDir := &GitDir{}
+	if dir := os.Getenv(GIT_DIR_ENV); dir != "" {
+		gitDir.Dir = absGitPath(workingDir, dir)
+		gitDir.WorkTree = workingDir
+		if !isGitDirectory(gitDir.Dir) {
+			return nil, fmt.Errorf("%w: %s", domain.ErrScmRepositoryNotFound, gitDir.Dir)
+		}
+	} else if found, err := walkGitDir(workingDir); err == nil {
+		gitDir = found
+	} else {
+		return nil, err
+	}
+
+	if workTree := os.Getenv(GIT_WORK_TREE_ENV); workTree != "" {
+		gitDir.WorkTree = absGitPath(workingDir, workTree)
+	}
+
+	gitDir.CommonDir = gitCommonDir(gitDir.Dir)
+	if commonDir := os.Getenv(GIT_COMMON_DIR_ENV); commonDir != "" {
+		gitDir.CommonDir = absGitPath(workingDir, commonDir)
+	}
+
+	return gitDir, nil
+}
+
+// ConfigFile returns the local configuration of the repository, shared by all its worktrees
+func (d *GitDir) ConfigFile() string {
+	return filepath.Join(d.CommonDir, gitConfigFileName)
+}
+
+// walkGitDir walks up the parents of the directory to the first repository
+func walkGitDir(dir string) (*GitDir, error) {
+	ceilings := map[string]bool{}
+	for _, ceiling := range filepath.SplitList(os.Getenv(GIT_CEILING_DIRECTORIES_ENV)) {
+		if ceiling != "" && filepath.IsAbs(ceiling) {
+			ceilings[filepath.Clean(ceiling)] = true
+		}
+	}
+
+	for {
+		candidate := filepath.Join(dir, GIT_DIR)
+		info, err := os.Stat(candidate)
+
+		switch {
+		case err == nil && info.IsDir() && isGitDirectory(candidate):
+			return &GitDir{WorkTree: dir, Dir: candidate}, nil
+		case err == nil && !info.IsDir():
+			gitDir, err := readGitDirFile(candidate)
+			if err != nil {
+				return nil, err
+			}
+
+			return &GitDir{WorkTree: dir, Dir: gitDir}, nil
+		case isGitDirectory(dir):
+			return &GitDir{Dir: dir}, nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir || ceilings[parent] {
+			return nil, domain.ErrScmRepositoryNotFound
+		}
+
+		dir = parent
+	}
+}
+
+// readGitDirFile reads the "gitdir: <path>" line of a .git file, the path is
+// relative to the directory of the file
+func readGitDirFile(file string) (string, error) {
+	content, err := os.ReadFile(file) // #nosec G304
+	if err != nil {
+		return "", err
+	}
+
+	line, _, _ := strings.Cut(string(content), "\n")
+	dir, ok := strings.CutPrefix(strings.TrimRight(line, "\r"), gitDirFilePrefix)
+	if !ok {
+		return "", fmt.Errorf("%w: invalid gitfile format: %s", domain.ErrScmRepositoryNotFound, file)
+	}
+
+	gitDir := absGitPath(filepath.Dir(file), strings.TrimSpace(dir))
+	if !isGitDirectory(gitDir) {
+		return "", fmt.Errorf("%w: not a git repository: %s", domain.ErrScmRepositoryNotFound, gitDir)
+	}
+
+	return gitDir, nil
+}
+
+// gitCommonDir returns the directory named by the commondir file of the git
+// directory of a worktree, or the git directory itself
+func gitCommonDir(gitDir string) string {
+	content, err := os.ReadFile(filepath.Join(gitDir, gitCommonDirFile)) // #nosec G304
+	if err != nil {
+		return gitDir
+	}
+
+	dir := strings.TrimSpace(string(content))
+	if dir == "" {
+		return gitDir
+	}
+
+	return absGitPath(gitDir, dir)
+}
+
+// isGitDirectory reports whether the directory has the HEAD file and, in its
+// common directory, the objects and refs directories
+func isGitDirectory(dir string) bool {
+	if info, err := os.Stat(filepath.Join(dir, "HEAD")); err != nil || info.IsDir() {
+		return false
+	}
+
+	commonDir := gitCommonDir(dir)
+	for _, name := range []string{"objects", "refs"} {
+		if info, err := os.Stat(filepath.Join(commonDir, name)); err != nil || !info.IsDir() {
+			return false
+		}
+	}
+
+	return true
+}
+
+func absGitPath(base string, path string) string {
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+
+	return filepath.Join(base, path)
+}
@@ -0,0 +1,90 @@
+package infrastructure_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/b4nd/git-profile/pkg/domain"
+	"github.com/b4nd/git-profile/pkg/infrastructure"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindGitDir(t *testing.T) {
+	t.Run("should find the repository from a subdirectory", func(t *testing.T) {
+		dir := t.TempDir()
+		gitInit(t, dir, t.TempDir())
+
+		subdir := filepath.Join(dir, "src", "pkg")
+		assert.NoError(t, os.MkdirAll(subdir, 0750))
+
+		gitDir, err := infrastructure.FindGitDir(subdir)
+		assert.NoError(t, err)
+		assert.Equal(t, dir, gitDir.WorkTree)
+		assert.Equal(t, filepath.Join(dir, ".git"), gitDir.Dir)
+		assert.Equal(t, filepath.Join(dir, ".git", "config"), gitDir.ConfigFile())
+	})
+
+	t.Run("should use the common directory of a worktree", func(t *testing.T) {
+		dir := t.TempDir()
+		gitInit(t, dir, t.TempDir())
+		gitCommit(t, dir, "Jane Doe", "jane@acme.com", "initial")
+
+		worktree := filepath.Join(t.TempDir(), "feature")
+		gitRun(t, dir, nil, "worktree", "add", "-q", "-b", "feature", worktree)
+
+		gitDir, err := infrastructure.FindGitDir(worktree)
+		assert.NoError(t, err)
+		assert.Equal(t, worktree, gitDir.WorkTree)
+		assert.Equal(t, filepath.Join(dir, ".git", "worktrees", "feature"), gitDir.Dir)
+		assert.Equal(t, filepath.Join(dir, ".git", "config"), gitDir.ConfigFile())
+	})
+
+	t.Run("should follow the .git file of a submodule", func(t *testing.T) {
+		module := t.TempDir()
+		gitInit(t, module, t.TempDir())
+		gitCommit(t, module, "Jane Doe", "jane@acme.com", "initial")
+
+		dir := t.TempDir()
+		gitInit(t, dir, t.TempDir())
+		gitRun(t, dir, []string{"GIT_ALLOW_PROTOCOL=file"}, "-c", "protocol.file.allow=always", "submodule", "add", "-q", module, "lib")
+
+		gitDir, err := infrastructure.FindGitDir(filepath.Join(dir, "lib"))
+		assert.NoError(t, err)
+		assert.Equal(t, filepath.Join(dir, "lib"), gitDir.WorkTree)
+		assert.Equal(t, filepath.Join(dir, ".git", "modules", "lib", "config"), gitDir.ConfigFile())
+	})
+
+	t.Run("should honour GIT_DIR and GIT_WORK_TREE", func(t *testing.T) {
+		dir := t.TempDir()
+		gitInit(t, dir, t.TempDir())
+
+		workTree := t.TempDir()
+		t.Setenv("GIT_DIR", filepath.Join(dir, ".git"))
+		t.Setenv("GIT_WORK_TREE", workTree)
+
+		gitDir, err := infrastructure.FindGitDir(t.TempDir())
+		assert.NoError(t, err)
+		assert.Equal(t, workTree, gitDir.WorkTree)
+		assert.Equal(t, filepath.Join(dir, ".git", "config"), gitDir.ConfigFile())
+	})
+
+	t.Run("should return an error outside of a repository", func(t *testing.T) {
+		dir := t.TempDir()
+		t.Setenv("GIT_CEILING_DIRECTORIES", filepath.Dir(dir))
+
+		gitDir, err := infrastructure.FindGitDir(dir)
+		assert.ErrorIs(t, err, domain.ErrScmRepositoryNotFound)
+		assert.Nil(t, gitDir)
+	})
+
+	t.Run("should return an error when the .git file is invalid", func(t *testing.T) {
+		dir := t.TempDir()
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, ".git"), []byte("gitdir: missing\n"), 0600))
+
+		gitDir, err := infrastructure.FindGitDir(dir)
+		assert.ErrorIs(t, err, domain.ErrScmRepositoryNotFound)
+		assert.Nil(t, gitDir)
+	})
+}
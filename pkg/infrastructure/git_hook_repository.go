@@ -0,0 +1,162 @@
+package infrastructure
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/b4nd/git-profile/pkg/domain"
+)
+
+// GIT_HOOK_CHAINED_EXTENSION is appended to the name of an existing hook,
+// which is run by the hook of git profile once its check succeeds
+const GIT_HOOK_CHAINED_EXTENSION = ".chained"
+
+const gitHookMarker = "# git profile hook (managed by git profile, do not edit)"
+
+// gitHookScripts are the lines of each hook, after the shebang and the marker.
+// The chained hook is run with the arguments of the hook once the check succeeds,
+// the pre-push hook buffers the refs of its input to give them to both.
+var gitHookScripts = map[string][]string{
+	domain.ScmHookPreCommit: {
+		"git profile check --quiet || exit $?",
+		"",
+		"chained=\"$0" + GIT_HOOK_CHAINED_EXTENSION + "\"",
+		"if [ -x \"$chained\" ]; then",
+		"\texec \"$chained\" \"$@\"",
+		"fi",
+	},
+	domain.ScmHookPrePush: {
+		"refs=$(cat)",
+		"print_refs() {",
+		"\t[ -z \"$refs\" ] || printf '%s\\n' \"$refs\"",
+		"}",
+		"",
+		"print_refs | git profile check --pre-push --quiet \"$@\" || exit $?",
+		"",
+		"chained=\"$0" + GIT_HOOK_CHAINED_EXTENSION + "\"",
+		"if [ -x \"$chained\" ]; then",
+		"\tprint_refs | \"$chained\" \"$@\"",
+		"fi",
+	},
+}
+
+type GitHookRepository struct {
+	path string
+}
+
+func NewGitHookRepository(path string) (*GitHookRepository, error) {
+	if path == "" {
+		return nil, fmt.Errorf("path cannot be empty")
+	}
+
+	return &GitHookRepository{path}, nil
+}
+
+func (r *GitHookRepository) Install(hook string) error {
+	lines, ok := gitHookScripts[hook]
+	if !ok {
+		return fmt.Errorf("unsupported hook %s", hook)
+	}
+
+	file, err := r.hookFile(hook)
+	if err != nil {
+		return err
+	}
+
+	installed, err := isGitProfileHook(file)
+	if err != nil {
+		return err
+	}
+
+	chained := file + GIT_HOOK_CHAINED_EXTENSION
+	if _, err := os.Stat(file); err == nil && !installed {
+		if _, err := os.Stat(chained); err == nil {
+			return domain.ErrScmHookChainExists
+		}
+
+		if err := os.Rename(file, chained); err != nil {
+			return err
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(file), 0750); err != nil {
+		return err
+	}
+
+	script := strings.Join(append(append([]string{"#!/bin/sh", gitHookMarker}, lines...), ""), "\n")
+
+	// #nosec G306 -- hooks must be executable
+	return os.WriteFile(file, []byte(script), 0755)
+}
+
+func (r *GitHookRepository) Uninstall(hook string) error {
+	file, err := r.hookFile(hook)
+	if err != nil {
+		return err
+	}
+
+	installed, err := isGitProfileHook(file)
+	if err != nil {
+		return err
+	}
+
+	if !installed {
+		return domain.ErrScmHookNotInstalled
+	}
+
+	if err := os.Remove(file); err != nil {
+		return err
+	}
+
+	chained := file + GIT_HOOK_CHAINED_EXTENSION
+	if _, err := os.Stat(chained); err == nil {
+		return os.Rename(chained, file)
+	}
+
+	return nil
+}
+
+func (r *GitHookRepository) IsInstalled(hook string) (bool, error) {
+	file, err := r.hookFile(hook)
+	if err != nil {
+		return false, err
+	}
+
+	return isGitProfileHook(file)
+}
+
+// hookFile returns the path of the hook, honoring core.hooksPath
+func (r *GitHookRepository) hookFile(hook string) (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--git-path", "hooks") // #nosec G204
+	cmd.Dir = r.path
+
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+
+	dir := strings.TrimSpace(string(output))
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(r.path, dir)
+	}
+
+	return filepath.Join(dir, hook), nil
+}
+
+// isGitProfileHook reports whether the hook file was written by git profile
+func isGitProfileHook(file string) (bool, error) {
+	content, err := os.ReadFile(filepath.Clean(file))
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	return strings.Contains(string(content), gitHookMarker), nil
+}
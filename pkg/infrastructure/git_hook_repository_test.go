@@ -0,0 +1,133 @@
+package infrastructure_test
+
+import (
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/b4nd/git-profile/pkg/domain"
+	"github.com/b4nd/git-profile/pkg/infrastructure"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitHookRepository(t *testing.T) {
+	t.Run("should return error when path is empty", func(t *testing.T) {
+		gitHookRepository, err := infrastructure.NewGitHookRepository("")
+
+		assert.Error(t, err)
+		assert.Nil(t, gitHookRepository)
+	})
+
+	t.Run("should install and uninstall the hook", func(t *testing.T) {
+		dir := t.TempDir()
+		gitInit(t, dir, t.TempDir())
+
+		gitHookRepository, err := infrastructure.NewGitHookRepository(dir)
+		assert.NoError(t, err)
+
+		err = gitHookRepository.Install(domain.ScmHookPreCommit)
+		assert.NoError(t, err)
+
+		info, err := os.Stat(path.Join(dir, ".git", "hooks", "pre-commit"))
+		assert.NoError(t, err)
+		assert.NotZero(t, info.Mode().Perm()&0100)
+
+		installed, err := gitHookRepository.IsInstalled(domain.ScmHookPreCommit)
+		assert.NoError(t, err)
+		assert.True(t, installed)
+
+		// Installing twice rewrites the hook
+		err = gitHookRepository.Install(domain.ScmHookPreCommit)
+		assert.NoError(t, err)
+
+		err = gitHookRepository.Uninstall(domain.ScmHookPreCommit)
+		assert.NoError(t, err)
+
+		_, err = os.Stat(path.Join(dir, ".git", "hooks", "pre-commit"))
+		assert.True(t, os.IsNotExist(err))
+
+		err = gitHookRepository.Uninstall(domain.ScmHookPreCommit)
+		assert.ErrorIs(t, err, domain.ErrScmHookNotInstalled)
+	})
+
+	t.Run("should chain and restore an existing hook in core.hooksPath", func(t *testing.T) {
+		dir := t.TempDir()
+		gitInit(t, dir, t.TempDir())
+
+		cmd := exec.Command("git", "config", "core.hooksPath", "githooks")
+		cmd.Dir = dir
+		assert.NoError(t, cmd.Run())
+
+		hook := path.Join(dir, "githooks", "pre-commit")
+		existing := "#!/bin/sh\necho existing\n"
+
+		assert.NoError(t, os.MkdirAll(path.Dir(hook), 0750))
+		assert.NoError(t, os.WriteFile(hook, []byte(existing), 0755)) // #nosec G306
+
+		gitHookRepository, err := infrastructure.NewGitHookRepository(dir)
+		assert.NoError(t, err)
+
+		err = gitHookRepository.Install(domain.ScmHookPreCommit)
+		assert.NoError(t, err)
+
+		chained, err := os.ReadFile(hook + infrastructure.GIT_HOOK_CHAINED_EXTENSION)
+		assert.NoError(t, err)
+		assert.Equal(t, existing, string(chained))
+
+		content, err := os.ReadFile(hook)
+		assert.NoError(t, err)
+		assert.Contains(t, string(content), "git profile check")
+
+		err = gitHookRepository.Uninstall(domain.ScmHookPreCommit)
+		assert.NoError(t, err)
+
+		content, err = os.ReadFile(hook)
+		assert.NoError(t, err)
+		assert.Equal(t, existing, string(content))
+
+		_, err = os.Stat(hook + infrastructure.GIT_HOOK_CHAINED_EXTENSION)
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("should give the refs of the pre-push hook to the check and to the chained hook", func(t *testing.T) {
+		dir := t.TempDir()
+		gitInit(t, dir, t.TempDir())
+
+		// git runs "git profile" as the git-profile program of the PATH
+		bin := t.TempDir()
+		output := t.TempDir()
+		fake := "#!/bin/sh\necho \"$@\" > " + path.Join(output, "check-args") + "\ncat > " + path.Join(output, "check-refs") + "\n"
+		assert.NoError(t, os.WriteFile(path.Join(bin, "git-profile"), []byte(fake), 0755)) // #nosec G306
+
+		hook := path.Join(dir, ".git", "hooks", "pre-push")
+		existing := "#!/bin/sh\ncat > " + path.Join(output, "chained-refs") + "\n"
+		assert.NoError(t, os.MkdirAll(path.Dir(hook), 0750))
+		assert.NoError(t, os.WriteFile(hook, []byte(existing), 0755)) // #nosec G306
+
+		gitHookRepository, err := infrastructure.NewGitHookRepository(dir)
+		assert.NoError(t, err)
+		assert.NoError(t, gitHookRepository.Install(domain.ScmHookPrePush))
+
+		refs := "refs/heads/main 1111111111111111111111111111111111111111 refs/heads/main 2222222222222222222222222222222222222222\n" +
+			"refs/heads/next 3333333333333333333333333333333333333333 refs/heads/next 0000000000000000000000000000000000000000\n"
+
+		cmd := exec.Command(hook, "origin", "git@github.com:acme-corp/api.git") // #nosec G204
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "PATH="+bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+		cmd.Stdin = strings.NewReader(refs)
+		assert.NoError(t, cmd.Run())
+
+		for _, file := range []string{"check-refs", "chained-refs"} {
+			content, err := os.ReadFile(path.Join(output, file))
+			assert.NoError(t, err)
+			assert.Equal(t, refs, string(content), file)
+		}
+
+		args, err := os.ReadFile(path.Join(output, "check-args"))
+		assert.NoError(t, err)
+		assert.Equal(t, "check --pre-push --quiet origin git@github.com:acme-corp/api.git\n", string(args))
+	})
+}
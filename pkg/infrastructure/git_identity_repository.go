@@ -0,0 +1,73 @@
+package infrastructure
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/b4nd/git-profile/pkg/domain"
+)
+
+// GitIdentityRepository asks git for the identity it uses in the repository,
+// so the includes, the global configuration and the environment are honored
+type GitIdentityRepository struct {
+	path string
+}
+
+func NewGitIdentityRepository(path string) (*GitIdentityRepository, error) {
+	if path == "" {
+		return nil, fmt.Errorf("path cannot be empty")
+	}
+
+	return &GitIdentityRepository{path}, nil
+}
+
+// gitIdentityEnvironment are the environment variables that replace the
+// identity of the configuration for the author and the committer of a commit
+var gitIdentityEnvironment = []string{
+	"GIT_AUTHOR_NAME",
+	"GIT_AUTHOR_EMAIL",
+	"GIT_COMMITTER_NAME",
+	"GIT_COMMITTER_EMAIL",
+}
+
+func (r *GitIdentityRepository) Overrides() map[string]string {
+	overrides := map[string]string{}
+	for _, name := range gitIdentityEnvironment {
+		if value, ok := os.LookupEnv(name); ok {
+			overrides[name] = value
+		}
+	}
+
+	return overrides
+}
+
+func (r *GitIdentityRepository) Get() (*domain.ScmIdentity, error) {
+	// git config exits with 1 when the key is not set
+	cmd := exec.Command("git", "config", "--get", GIT_SECTION_USER+".workspace")
+	cmd.Dir = r.path
+	workspace, _ := cmd.Output()
+
+	cmd = exec.Command("git", "var", "GIT_AUTHOR_IDENT")
+	cmd.Dir = r.path
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, domain.ErrScmIdentityNotFound
+	}
+
+	// The identity has the form: Name <email> timestamp timezone
+	ident := strings.TrimSpace(string(output))
+	start := strings.LastIndex(ident, "<")
+	end := strings.LastIndex(ident, ">")
+	if start < 0 || end < start {
+		return nil, domain.ErrScmIdentityNotFound
+	}
+
+	return domain.NewScmIdentity(
+		strings.TrimSpace(string(workspace)),
+		ident[start+1:end],
+		strings.TrimSpace(ident[:start]),
+	), nil
+}
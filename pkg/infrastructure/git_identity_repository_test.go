@@ -0,0 +1,82 @@
+package infrastructure_test
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/b4nd/git-profile/pkg/domain"
+	"github.com/b4nd/git-profile/pkg/infrastructure"
+
+	"github.com/jaswdr/faker"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitIdentityRepository(t *testing.T) {
+	faker := faker.New()
+
+	t.Run("should return error when path is empty", func(t *testing.T) {
+		gitIdentityRepository, err := infrastructure.NewGitIdentityRepository("")
+
+		assert.Error(t, err)
+		assert.Nil(t, gitIdentityRepository)
+	})
+
+	t.Run("should return the identity used by git", func(t *testing.T) {
+		dir := t.TempDir()
+		gitInit(t, dir, t.TempDir())
+
+		workspace := faker.Internet().User()
+		email := faker.Internet().Email()
+		name := faker.Person().FirstName() + " " + faker.Person().LastName()
+
+		for key, value := range map[string]string{
+			"user.workspace": workspace,
+			"user.email":     email,
+			"user.name":      name,
+		} {
+			cmd := exec.Command("git", "config", "--local", key, value)
+			cmd.Dir = dir
+			assert.NoError(t, cmd.Run())
+		}
+
+		gitIdentityRepository, err := infrastructure.NewGitIdentityRepository(dir)
+		assert.NoError(t, err)
+
+		identity, err := gitIdentityRepository.Get()
+		assert.NoError(t, err)
+		assert.Equal(t, domain.NewScmIdentity(workspace, email, name), identity)
+	})
+
+	t.Run("should honor the identity of the environment", func(t *testing.T) {
+		dir := t.TempDir()
+		gitInit(t, dir, t.TempDir())
+
+		t.Setenv("GIT_AUTHOR_NAME", "Environment Name")
+		t.Setenv("GIT_AUTHOR_EMAIL", "environment@example.com")
+
+		gitIdentityRepository, err := infrastructure.NewGitIdentityRepository(dir)
+		assert.NoError(t, err)
+
+		identity, err := gitIdentityRepository.Get()
+		assert.NoError(t, err)
+		assert.Equal(t, "Environment Name", identity.Name)
+		assert.Equal(t, "environment@example.com", identity.Email)
+	})
+
+	t.Run("should return the identity overridden by the environment", func(t *testing.T) {
+		gitIdentityRepository, err := infrastructure.NewGitIdentityRepository(t.TempDir())
+		assert.NoError(t, err)
+
+		for _, name := range []string{"GIT_AUTHOR_NAME", "GIT_AUTHOR_EMAIL", "GIT_COMMITTER_NAME", "GIT_COMMITTER_EMAIL"} {
+			t.Setenv(name, "")
+			assert.NoError(t, os.Unsetenv(name))
+		}
+
+		assert.Empty(t, gitIdentityRepository.Overrides())
+
+		t.Setenv("GIT_COMMITTER_EMAIL", "bot@example.com")
+
+		assert.Equal(t, map[string]string{"GIT_COMMITTER_EMAIL": "bot@example.com"}, gitIdentityRepository.Overrides())
+	})
+}
@@ -0,0 +1,204 @@
+package infrastructure
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/b4nd/git-profile/pkg/domain"
+)
+
+const GIT_INCLUDE_DIR = ".gitprofile.d"
+const GIT_INCLUDE_FILE_EXTENSION = ".gitconfig"
+
+// Section and key of the includes read from the git configuration, in lower case
+const (
+	GIT_SECTION_INCLUDE_IF = "includeif"
+	GIT_KEY_INCLUDE_PATH   = "path"
+)
+
+// Markers of the block of includeIf sections managed by git profile,
+// everything outside of the block is never modified
+const (
+	gitIncludeBlockBegin = "# BEGIN git profile rules (managed by git profile, do not edit)"
+	gitIncludeBlockEnd   = "# END git profile rules"
+)
+
+// GitIncludeRepository writes one include file per profile and the includeIf
+// sections that select them into a git configuration file
+type GitIncludeRepository struct {
+	path       string
+	includeDir string
+}
+
+func NewGitIncludeRepository(path string, includeDir string) (*GitIncludeRepository, error) {
+	if path == "" {
+		return nil, fmt.Errorf("path cannot be empty")
+	}
+
+	if includeDir == "" {
+		return nil, fmt.Errorf("include directory cannot be empty")
+	}
+
+	return &GitIncludeRepository{path, includeDir}, nil
+}
+
+func (r *GitIncludeRepository) Save(includes []*domain.ScmInclude) error {
+	files, err := r.saveIncludeFiles(includes)
+	if err != nil {
+		return err
+	}
+
+	var block strings.Builder
+	for _, include := range includes {
+		block.WriteString(fmt.Sprintf("[includeIf \"%s\"]\n", escapeGitConfigValue(include.Condition)))
+		block.WriteString(fmt.Sprintf("\tpath = \"%s\"\n", escapeGitConfigValue(files[include.User.Workespace])))
+	}
+
+	content, err := os.ReadFile(r.path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(r.path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0750); err != nil {
+		return err
+	}
+
+	return os.WriteFile(r.path, []byte(replaceManagedBlock(string(content), block.String())), mode)
+}
+
+func (r *GitIncludeRepository) List() ([]*domain.ScmIncludeSection, error) {
+	file, err := loadGitConfigFile(r.path)
+	if err != nil {
+		return nil, err
+	}
+
+	// The sections between the markers are the ones managed by git profile
+	content := string(file.content)
+	begin, end := strings.Index(content, gitIncludeBlockBegin), -1
+	if begin >= 0 {
+		if end = strings.Index(content[begin:], gitIncludeBlockEnd); end >= 0 {
+			end += begin
+		}
+	}
+
+	sections := make([]*domain.ScmIncludeSection, 0)
+	for _, entry := range file.entries {
+		section := file.sections[entry.section]
+		if section.name != GIT_SECTION_INCLUDE_IF || entry.name != GIT_KEY_INCLUDE_PATH {
+			continue
+		}
+
+		sections = append(sections, &domain.ScmIncludeSection{
+			Condition: section.subsection,
+			Path:      entry.value,
+			File:      r.path,
+			Managed:   begin >= 0 && end >= 0 && entry.start > begin && entry.start < end,
+		})
+	}
+
+	return sections, nil
+}
+
+// saveIncludeFiles writes the include file of every profile and removes the
+// include files of the profiles that are not used anymore
+func (r *GitIncludeRepository) saveIncludeFiles(includes []*domain.ScmInclude) (map[string]string, error) {
+	files := map[string]string{}
+
+	if err := os.MkdirAll(r.includeDir, 0750); err != nil {
+		return nil, err
+	}
+
+	for _, include := range includes {
+		workspace := include.User.Workespace
+		if _, ok := files[workspace]; ok {
+			continue
+		}
+
+		file, err := filepath.Abs(filepath.Join(r.includeDir, workspace+GIT_INCLUDE_FILE_EXTENSION))
+		if err != nil {
+			return nil, err
+		}
+
+		// The include files are owned by git profile, they are always written from scratch
+		if err := os.Remove(file); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+
+		repository, err := NewGitUserRepository(file)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := repository.Save(include.User); err != nil {
+			return nil, err
+		}
+
+		files[workspace] = file
+	}
+
+	entries, err := os.ReadDir(r.includeDir)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		workspace := strings.TrimSuffix(entry.Name(), GIT_INCLUDE_FILE_EXTENSION)
+		if entry.IsDir() || workspace == entry.Name() {
+			continue
+		}
+
+		if _, ok := files[workspace]; !ok {
+			if err := os.Remove(filepath.Join(r.includeDir, entry.Name())); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return files, nil
+}
+
+// replaceManagedBlock replaces the managed block of the content, the block is
+// appended when it does not exist and removed when it is empty
+func replaceManagedBlock(content string, block string) string {
+	managed := ""
+	if block != "" {
+		managed = gitIncludeBlockBegin + "\n" + block + gitIncludeBlockEnd + "\n"
+	}
+
+	begin := strings.Index(content, gitIncludeBlockBegin)
+	if begin >= 0 {
+		end := strings.Index(content[begin:], gitIncludeBlockEnd)
+		if end >= 0 {
+			end = begin + end + len(gitIncludeBlockEnd)
+			if end < len(content) && content[end] == '\n' {
+				end++
+			}
+
+			return content[:begin] + managed + content[end:]
+		}
+	}
+
+	if managed == "" {
+		return content
+	}
+
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+
+	return content + managed
+}
+
+// escapeGitConfigValue escapes a value to be written between double quotes
+func escapeGitConfigValue(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	return strings.ReplaceAll(value, `"`, `\"`)
+}
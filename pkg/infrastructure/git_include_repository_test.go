@@ -0,0 +1,138 @@
+package infrastructure_test
+
+import (
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/b4nd/git-profile/pkg/domain"
+	"github.com/b4nd/git-profile/pkg/infrastructure"
+
+	"github.com/jaswdr/faker"
+	"github.com/stretchr/testify/assert"
+)
+
+const GlobalGitConfig = `# my configuration
+[user]
+	name = Global Name
+	email = global@example.com
+[alias]
+	co = checkout
+[includeIf "gitdir:~/other/"]
+	path = ~/.other.gitconfig
+`
+
+func TestGitIncludeRepository(t *testing.T) {
+	faker := faker.New()
+
+	t.Run("should return error when the paths are empty", func(t *testing.T) {
+		repo, err := infrastructure.NewGitIncludeRepository("", "dir")
+		assert.Error(t, err)
+		assert.Nil(t, repo)
+
+		repo, err = infrastructure.NewGitIncludeRepository("config", "")
+		assert.Error(t, err)
+		assert.Nil(t, repo)
+	})
+
+	t.Run("should write the includes and let git select the identity by directory", func(t *testing.T) {
+		home := t.TempDir()
+		configPath := path.Join(home, ".gitconfig")
+		assert.NoError(t, os.WriteFile(configPath, []byte(GlobalGitConfig), 0600))
+
+		repo, err := infrastructure.NewGitIncludeRepository(configPath, path.Join(home, ".gitprofile.d"))
+		assert.NoError(t, err)
+
+		user := domain.NewScmUser("work", faker.Internet().Email(), faker.Person().Name())
+		includes := []*domain.ScmInclude{domain.NewScmInclude("gitdir:"+path.Join(home, "work")+"/", user)}
+
+		assert.NoError(t, repo.Save(includes))
+
+		content, err := os.ReadFile(configPath)
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(string(content), GlobalGitConfig))
+		assert.FileExists(t, path.Join(home, ".gitprofile.d", "work.gitconfig"))
+
+		// Saving twice does not duplicate the managed block
+		assert.NoError(t, repo.Save(includes))
+
+		again, err := os.ReadFile(configPath)
+		assert.NoError(t, err)
+		assert.Equal(t, string(content), string(again))
+
+		// Git selects the identity of the profile inside the directory only
+		workDir := path.Join(home, "work", "project")
+		assert.NoError(t, os.MkdirAll(workDir, 0750))
+		gitInit(t, workDir, home)
+
+		otherDir := path.Join(home, "personal")
+		assert.NoError(t, os.MkdirAll(otherDir, 0750))
+		gitInit(t, otherDir, home)
+
+		assert.Equal(t, user.Email, gitConfigGet(t, workDir, home, "user.email"))
+		assert.Equal(t, "global@example.com", gitConfigGet(t, otherDir, home, "user.email"))
+	})
+
+	t.Run("should remove the managed block and the unused include files", func(t *testing.T) {
+		home := t.TempDir()
+		configPath := path.Join(home, ".gitconfig")
+		assert.NoError(t, os.WriteFile(configPath, []byte(GlobalGitConfig), 0600))
+
+		repo, err := infrastructure.NewGitIncludeRepository(configPath, path.Join(home, ".gitprofile.d"))
+		assert.NoError(t, err)
+
+		user := domain.NewScmUser("work", faker.Internet().Email(), faker.Person().Name())
+		assert.NoError(t, repo.Save([]*domain.ScmInclude{domain.NewScmInclude("gitdir:~/work/", user)}))
+		assert.NoError(t, repo.Save([]*domain.ScmInclude{}))
+
+		content, err := os.ReadFile(configPath)
+		assert.NoError(t, err)
+		assert.Equal(t, GlobalGitConfig, string(content))
+		assert.NoFileExists(t, path.Join(home, ".gitprofile.d", "work.gitconfig"))
+	})
+
+	t.Run("should list the managed and the unmanaged includes", func(t *testing.T) {
+		home := t.TempDir()
+		configPath := path.Join(home, ".gitconfig")
+		assert.NoError(t, os.WriteFile(configPath, []byte(GlobalGitConfig), 0600))
+
+		repo, err := infrastructure.NewGitIncludeRepository(configPath, path.Join(home, ".gitprofile.d"))
+		assert.NoError(t, err)
+
+		user := domain.NewScmUser("work", faker.Internet().Email(), faker.Person().Name())
+		assert.NoError(t, repo.Save([]*domain.ScmInclude{domain.NewScmInclude("gitdir:~/work/", user)}))
+
+		sections, err := repo.List()
+		assert.NoError(t, err)
+		assert.Len(t, sections, 2)
+
+		assert.Equal(t, "gitdir:~/other/", sections[0].Condition)
+		assert.Equal(t, "~/.other.gitconfig", sections[0].Path)
+		assert.Equal(t, configPath, sections[0].File)
+		assert.False(t, sections[0].Managed)
+
+		assert.Equal(t, "gitdir:~/work/", sections[1].Condition)
+		assert.Equal(t, path.Join(home, ".gitprofile.d", "work.gitconfig"), sections[1].Path)
+		assert.True(t, sections[1].Managed)
+	})
+}
+
+func gitInit(t *testing.T, dir string, home string) {
+	cmd := exec.Command("git", "init")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "HOME="+home, "XDG_CONFIG_HOME="+home, "GIT_CONFIG_NOSYSTEM=1")
+	_, err := cmd.CombinedOutput()
+	assert.NoError(t, err)
+}
+
+func gitConfigGet(t *testing.T, dir string, home string, key string) string {
+	cmd := exec.Command("git", "config", "--get", key)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "HOME="+home, "XDG_CONFIG_HOME="+home, "GIT_CONFIG_NOSYSTEM=1")
+	output, err := cmd.Output()
+	assert.NoError(t, err)
+
+	return strings.TrimSpace(string(output))
+}
@@ -0,0 +1,69 @@
+package infrastructure
+
+import (
+	"fmt"
+
+	"github.com/b4nd/git-profile/pkg/domain"
+)
+
+// GitLocalUserRepository applies the profile to the local configuration of
+// the repository of the working directory, located on each call so that it
+// works from a subdirectory, a worktree or a submodule
+type GitLocalUserRepository struct {
+	workingDir string
+}
+
+func NewGitLocalUserRepository(workingDir string) (*GitLocalUserRepository, error) {
+	if workingDir == "" {
+		return nil, fmt.Errorf("working directory cannot be empty")
+	}
+
+	return &GitLocalUserRepository{workingDir}, nil
+}
+
+func (r *GitLocalUserRepository) Get() (*domain.ScmUser, error) {
+	repository, err := r.repository()
+	if err != nil {
+		return nil, err
+	}
+
+	return repository.Get()
+}
+
+func (r *GitLocalUserRepository) Save(user *domain.ScmUser) error {
+	repository, err := r.repository()
+	if err != nil {
+		return err
+	}
+
+	return repository.Save(user)
+}
+
+func (r *GitLocalUserRepository) SaveWorkspace(workspace string) error {
+	repository, err := r.repository()
+	if err != nil {
+		return err
+	}
+
+	return repository.SaveWorkspace(workspace)
+}
+
+func (r *GitLocalUserRepository) Delete() error {
+	repository, err := r.repository()
+	if err != nil {
+		return err
+	}
+
+	return repository.Delete()
+}
+
+// repository returns the repository of the configuration file, domain.ErrScmRepositoryNotFound
+// when the working directory is not inside a git repository
+func (r *GitLocalUserRepository) repository() (*GitUserRepository, error) {
+	gitDir, err := FindGitDir(r.workingDir)
+	if err != nil {
+		return nil, domain.ErrScmRepositoryNotFound
+	}
+
+	return NewGitUserRepository(gitDir.ConfigFile())
+}
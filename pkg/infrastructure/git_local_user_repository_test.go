@@ -0,0 +1,69 @@
+package infrastructure_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/b4nd/git-profile/pkg/domain"
+	"github.com/b4nd/git-profile/pkg/infrastructure"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitLocalUserRepository(t *testing.T) {
+	t.Run("should return an error when the working directory is empty", func(t *testing.T) {
+		repository, err := infrastructure.NewGitLocalUserRepository("")
+		assert.Error(t, err)
+		assert.Nil(t, repository)
+	})
+
+	t.Run("should apply the profile to the repository from a subdirectory", func(t *testing.T) {
+		dir := t.TempDir()
+		gitInit(t, dir, t.TempDir())
+
+		subdir := filepath.Join(dir, "src")
+		assert.NoError(t, os.MkdirAll(subdir, 0750))
+
+		repository, err := infrastructure.NewGitLocalUserRepository(subdir)
+		assert.NoError(t, err)
+
+		user := domain.NewScmUser("work", "jane@acme.com", "Jane Doe")
+		err = repository.Save(user)
+		assert.NoError(t, err)
+
+		_, err = os.Stat(filepath.Join(subdir, ".git"))
+		assert.True(t, os.IsNotExist(err))
+		assert.Equal(t, "jane@acme.com", gitRun(t, dir, nil, "config", "--get", "user.email"))
+
+		currentUser, err := repository.Get()
+		assert.NoError(t, err)
+		assert.Equal(t, user, currentUser)
+
+		err = repository.Delete()
+		assert.NoError(t, err)
+
+		_, err = repository.Get()
+		assert.ErrorIs(t, err, domain.ErrScmUserNotFound)
+	})
+
+	t.Run("should refuse outside of a repository", func(t *testing.T) {
+		dir := t.TempDir()
+		t.Setenv("GIT_CEILING_DIRECTORIES", filepath.Dir(dir))
+
+		repository, err := infrastructure.NewGitLocalUserRepository(dir)
+		assert.NoError(t, err)
+
+		_, err = repository.Get()
+		assert.ErrorIs(t, err, domain.ErrScmRepositoryNotFound)
+
+		err = repository.Save(domain.NewScmUser("work", "jane@acme.com", "Jane Doe"))
+		assert.ErrorIs(t, err, domain.ErrScmRepositoryNotFound)
+
+		err = repository.Delete()
+		assert.ErrorIs(t, err, domain.ErrScmRepositoryNotFound)
+
+		_, err = os.Stat(filepath.Join(dir, ".git"))
+		assert.True(t, os.IsNotExist(err))
+	})
+}
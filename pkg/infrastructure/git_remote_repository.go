@@ -0,0 +1,81 @@
+package infrastructure
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/b4nd/git-profile/pkg/domain"
+)
+
+type GitRemoteRepository struct {
+	path string
+}
+
+func NewGitRemoteRepository(path string) (*GitRemoteRepository, error) {
+	if path == "" {
+		return nil, fmt.Errorf("path cannot be empty")
+	}
+
+	return &GitRemoteRepository{path}, nil
+}
+
+// List returns the fetch url of every remote of the repository, in the order reported by git
+func (r *GitRemoteRepository) List() ([]*domain.ScmRemote, error) {
+	cmd := exec.Command("git", "remote", "-v")
+	cmd.Dir = r.path
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	remotes := make([]*domain.ScmRemote, 0)
+	for _, line := range strings.Split(string(output), "\n") {
+		// Each line has the form: <name>\t<url> (fetch|push)
+		name, rest, ok := strings.Cut(line, "\t")
+		if !ok || !strings.HasSuffix(rest, " (fetch)") {
+			continue
+		}
+
+		remotes = append(remotes, domain.NewScmRemote(name, strings.TrimSuffix(rest, " (fetch)")))
+	}
+
+	return remotes, nil
+}
+
+// Refs returns the commits of the remote-tracking branches of the remote, of
+// every remote when it is empty, none when the remote was never fetched or is
+// given as a url
+func (r *GitRemoteRepository) Refs(remote string) ([]domain.ScmCommitHash, error) {
+	pattern := "refs/remotes/"
+	if remote != "" {
+		pattern += remote + "/"
+	}
+
+	cmd := exec.Command("git", "for-each-ref", "--format=%(objectname)", pattern) // #nosec G204
+	cmd.Dir = r.path
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	refs := make([]domain.ScmCommitHash, 0)
+	for _, line := range strings.Fields(string(output)) {
+		if seen[line] {
+			continue
+		}
+
+		hash, err := domain.NewScmCommitHash(line)
+		if err != nil {
+			return nil, err
+		}
+
+		seen[line] = true
+		refs = append(refs, hash)
+	}
+
+	return refs, nil
+}
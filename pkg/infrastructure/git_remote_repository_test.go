@@ -0,0 +1,95 @@
+package infrastructure_test
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/b4nd/git-profile/pkg/domain"
+	"github.com/b4nd/git-profile/pkg/infrastructure"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitRemoteRepository(t *testing.T) {
+	t.Run("should return error when path is empty", func(t *testing.T) {
+		gitRemoteRepository, err := infrastructure.NewGitRemoteRepository("")
+
+		assert.Error(t, err)
+		assert.Nil(t, gitRemoteRepository)
+	})
+
+	t.Run("should return the fetch url of every remote", func(t *testing.T) {
+		dir := t.TempDir()
+		gitInit(t, dir, t.TempDir())
+
+		for _, args := range [][]string{
+			{"remote", "add", "origin", "git@github.com:acme-corp/api.git"},
+			{"remote", "add", "mirror", "https://gitlab.internal/acme/api.git"},
+			{"remote", "set-url", "--push", "mirror", "git@gitlab.internal:acme/api.git"},
+		} {
+			cmd := exec.Command("git", args...)
+			cmd.Dir = dir
+			assert.NoError(t, cmd.Run())
+		}
+
+		gitRemoteRepository, err := infrastructure.NewGitRemoteRepository(dir)
+		assert.NoError(t, err)
+
+		remotes, err := gitRemoteRepository.List()
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []*domain.ScmRemote{
+			domain.NewScmRemote("origin", "git@github.com:acme-corp/api.git"),
+			domain.NewScmRemote("mirror", "https://gitlab.internal/acme/api.git"),
+		}, remotes)
+	})
+
+	t.Run("should return no remotes when the repository has none", func(t *testing.T) {
+		dir := t.TempDir()
+		gitInit(t, dir, t.TempDir())
+
+		gitRemoteRepository, err := infrastructure.NewGitRemoteRepository(dir)
+		assert.NoError(t, err)
+
+		remotes, err := gitRemoteRepository.List()
+		assert.NoError(t, err)
+		assert.Empty(t, remotes)
+	})
+
+	t.Run("should return an error when path is not a git repository", func(t *testing.T) {
+		gitRemoteRepository, err := infrastructure.NewGitRemoteRepository(t.TempDir())
+		assert.NoError(t, err)
+
+		remotes, err := gitRemoteRepository.List()
+		assert.Error(t, err)
+		assert.Nil(t, remotes)
+	})
+
+	t.Run("should return the commits of the remote-tracking branches of a remote", func(t *testing.T) {
+		dir := t.TempDir()
+		gitRun(t, dir, nil, "init", "-q")
+
+		root := gitCommit(t, dir, "Root Name", "root@example.com", "Root Commit")
+		head := gitCommit(t, dir, "Root Name", "root@example.com", "Second Commit")
+
+		gitRun(t, dir, nil, "update-ref", "refs/remotes/origin/main", head)
+		gitRun(t, dir, nil, "update-ref", "refs/remotes/origin/feature", head)
+		gitRun(t, dir, nil, "update-ref", "refs/remotes/origin-fork/main", root)
+
+		gitRemoteRepository, err := infrastructure.NewGitRemoteRepository(dir)
+		assert.NoError(t, err)
+
+		refs, err := gitRemoteRepository.Refs("origin")
+		assert.NoError(t, err)
+		assert.Len(t, refs, 1)
+		assert.Equal(t, head, refs[0].String())
+
+		refs, err = gitRemoteRepository.Refs("git@github.com:acme-corp/api.git")
+		assert.NoError(t, err)
+		assert.Empty(t, refs)
+
+		refs, err = gitRemoteRepository.Refs("")
+		assert.NoError(t, err)
+		assert.Len(t, refs, 2)
+		assert.ElementsMatch(t, []string{head, root}, []string{refs[0].String(), refs[1].String()})
+	})
+}
@@ -0,0 +1,108 @@
+package infrastructure
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+
+	"github.com/b4nd/git-profile/pkg/domain"
+)
+
+const GIT_DIR = ".git"
+
+// GitRepositoryUserRepository reads and writes the user of the local
+// configuration of the git repositories found under a directory
+type GitRepositoryUserRepository struct{}
+
+func NewGitRepositoryUserRepository() *GitRepositoryUserRepository {
+	return &GitRepositoryUserRepository{}
+}
+
+func (r *GitRepositoryUserRepository) List(dir string) ([]*domain.ScmRepositoryUser, error) {
+	users := make([]*domain.ScmRepositoryUser, 0)
+	seen := map[string]bool{}
+
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		// The directories that cannot be read are skipped, only the root must exist
+		if err != nil {
+			if path == dir {
+				return err
+			}
+
+			return nil
+		}
+
+		// The worktrees and the submodules have a .git file instead of a directory
+		if entry.Name() != GIT_DIR {
+			return nil
+		}
+
+		if entry.IsDir() {
+			err = filepath.SkipDir
+		}
+
+		// The worktrees of a repository share its configuration, it is listed once
+		repository := filepath.Dir(path)
+		gitDir, findErr := FindGitDir(repository)
+		if findErr != nil || seen[gitDir.ConfigFile()] {
+			return err
+		}
+
+		seen[gitDir.ConfigFile()] = true
+		user, getErr := r.get(gitDir)
+		if getErr != nil && !errors.Is(getErr, domain.ErrScmUserNotFound) {
+			return getErr
+		}
+
+		if user != nil {
+			users = append(users, domain.NewScmRepositoryUser(repository, user))
+		}
+
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func (r *GitRepositoryUserRepository) Save(user *domain.ScmRepositoryUser) error {
+	gitDir, err := FindGitDir(user.Path)
+	if err != nil {
+		return err
+	}
+
+	repository, err := NewGitUserRepository(gitDir.ConfigFile())
+	if err != nil {
+		return err
+	}
+
+	return repository.Save(user.User)
+}
+
+func (r *GitRepositoryUserRepository) SaveWorkspace(path string, workspace string) error {
+	gitDir, err := FindGitDir(path)
+	if err != nil {
+		return err
+	}
+
+	repository, err := NewGitUserRepository(gitDir.ConfigFile())
+	if err != nil {
+		return err
+	}
+
+	return repository.SaveWorkspace(workspace)
+}
+
+// get reads the user of the local configuration of the repository, shared by
+// its worktrees and kept in the git directory of a submodule
+func (r *GitRepositoryUserRepository) get(gitDir *GitDir) (*domain.ScmUser, error) {
+	repository, err := NewGitUserRepository(gitDir.ConfigFile())
+	if err != nil {
+		return nil, err
+	}
+
+	return repository.Get()
+}
@@ -0,0 +1,132 @@
+package infrastructure_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/b4nd/git-profile/pkg/domain"
+	"github.com/b4nd/git-profile/pkg/infrastructure"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitRepositoryUserRepository(t *testing.T) {
+	t.Run("should list the users of the repositories under the directory", func(t *testing.T) {
+		dir := t.TempDir()
+
+		for _, name := range []string{"api", "clients/blog", "empty"} {
+			repository := filepath.Join(dir, name)
+			assert.NoError(t, os.MkdirAll(repository, 0750))
+			gitInit(t, repository, dir)
+		}
+
+		for name, workspace := range map[string]string{"api": "acme", "clients/blog": "personal"} {
+			repository, err := infrastructure.NewGitUserRepository(filepath.Join(dir, name) + GitConfigFile)
+			assert.NoError(t, err)
+			assert.NoError(t, repository.Save(domain.NewScmUser(workspace, workspace+"@example.com", "Jane Doe")))
+		}
+
+		repositoryUserRepository := infrastructure.NewGitRepositoryUserRepository()
+		users, err := repositoryUserRepository.List(dir)
+
+		assert.NoError(t, err)
+		assert.Len(t, users, 2)
+		assert.Equal(t, filepath.Join(dir, "api"), users[0].Path)
+		assert.Equal(t, "acme", users[0].User.Workespace)
+		assert.Equal(t, filepath.Join(dir, "clients/blog"), users[1].Path)
+		assert.Equal(t, "personal", users[1].User.Workespace)
+	})
+
+	t.Run("should save the user into the configuration of the repository", func(t *testing.T) {
+		dir := t.TempDir()
+		gitInit(t, dir, dir)
+
+		repositoryUserRepository := infrastructure.NewGitRepositoryUserRepository()
+		err := repositoryUserRepository.Save(domain.NewScmRepositoryUser(dir, domain.NewScmUser("acme", "jane@acme.com", "Jane Doe")))
+		assert.NoError(t, err)
+
+		users, err := repositoryUserRepository.List(dir)
+		assert.NoError(t, err)
+		assert.Len(t, users, 1)
+		assert.Equal(t, dir, users[0].Path)
+		assert.Equal(t, domain.NewScmUser("acme", "jane@acme.com", "Jane Doe"), users[0].User)
+	})
+
+	t.Run("should only rewrite the workspace of the repository", func(t *testing.T) {
+		dir := t.TempDir()
+		gitInit(t, dir, dir)
+
+		configFile := dir + GitConfigFile
+		config, err := os.ReadFile(configFile)
+		assert.NoError(t, err)
+
+		// A signing key without format, set by the user with signing left to the global configuration
+		user := "[user]\n\tname = Jane Doe\n\temail = jane@acme.com\n\tworkspace = acme\n\tsigningkey = MYKEY\n"
+		assert.NoError(t, os.WriteFile(configFile, append(config, user...), 0600))
+
+		repositoryUserRepository := infrastructure.NewGitRepositoryUserRepository()
+		assert.NoError(t, repositoryUserRepository.SaveWorkspace(dir, "acme-corp"))
+
+		content, err := os.ReadFile(configFile)
+		assert.NoError(t, err)
+		assert.Equal(t, string(config)+strings.Replace(user, "acme\n", "acme-corp\n", 1), string(content))
+		assert.Equal(t, "acme-corp", gitConfigGet(t, dir, dir, "user.workspace"))
+	})
+
+	t.Run("should not write an empty signing format", func(t *testing.T) {
+		dir := t.TempDir()
+		gitInit(t, dir, dir)
+
+		user := domain.NewScmUser("acme", "jane@acme.com", "Jane Doe")
+		user.SigningKey = "MYKEY"
+
+		repositoryUserRepository := infrastructure.NewGitRepositoryUserRepository()
+		assert.NoError(t, repositoryUserRepository.Save(domain.NewScmRepositoryUser(dir, user)))
+
+		content, err := os.ReadFile(dir + GitConfigFile)
+		assert.NoError(t, err)
+		assert.NotContains(t, string(content), "[gpg]")
+		assert.Equal(t, "MYKEY", gitConfigGet(t, dir, dir, "user.signingkey"))
+	})
+
+	t.Run("should find the repositories with a .git file", func(t *testing.T) {
+		dir := t.TempDir()
+		main := filepath.Join(dir, "api")
+		separate := filepath.Join(dir, "blog")
+		assert.NoError(t, os.MkdirAll(main, 0750))
+
+		gitRun(t, main, nil, "init", "-q")
+		gitCommit(t, main, "Jane Doe", "jane@acme.com", "Root Commit")
+		gitRun(t, main, nil, "worktree", "add", "-q", filepath.Join(dir, "api-feature"))
+		gitRun(t, dir, nil, "init", "-q", "--separate-git-dir", filepath.Join(t.TempDir(), "blog.git"), separate)
+
+		repositoryUserRepository := infrastructure.NewGitRepositoryUserRepository()
+		for path, workspace := range map[string]string{filepath.Join(dir, "api-feature"): "acme", separate: "personal"} {
+			err := repositoryUserRepository.Save(domain.NewScmRepositoryUser(path, domain.NewScmUser(workspace, workspace+"@example.com", "Jane Doe")))
+			assert.NoError(t, err)
+		}
+
+		// The worktree writes the configuration shared with the main worktree
+		assert.Equal(t, "acme", gitRun(t, main, nil, "config", "--get", "user.workspace"))
+		assert.Equal(t, "personal", gitRun(t, separate, nil, "config", "--get", "user.workspace"))
+		assert.NoFileExists(t, filepath.Join(separate, GitConfigFile))
+
+		users, err := repositoryUserRepository.List(dir)
+		assert.NoError(t, err)
+		assert.Len(t, users, 2)
+		assert.Equal(t, main, users[0].Path)
+		assert.Equal(t, "acme", users[0].User.Workespace)
+		assert.Equal(t, separate, users[1].Path)
+		assert.Equal(t, "personal", users[1].User.Workespace)
+	})
+
+	t.Run("should return an error when the directory does not exist", func(t *testing.T) {
+		repositoryUserRepository := infrastructure.NewGitRepositoryUserRepository()
+		users, err := repositoryUserRepository.List(filepath.Join(t.TempDir(), "missing"))
+
+		assert.Error(t, err)
+		assert.Nil(t, users)
+	})
+}
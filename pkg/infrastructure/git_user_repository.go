@@ -4,19 +4,45 @@ import (
 	"errors"
 	"fmt"
 	"os"
-	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/b4nd/git-profile/pkg/domain"
-
-	"gopkg.in/ini.v1"
 )
 
 const GIT_LOCAL_CONFIG_FILE = ".git/config"
 const GIT_GLOBAL_CONFIG_FILE = ".gitconfig"
 const GIT_SECTION_USER = "user"
+const GIT_SECTION_GPG = "gpg"
+const GIT_SECTION_COMMIT = "commit"
+const GIT_SECTION_TAG = "tag"
+const GIT_SECTION_CORE = "core"
+const GIT_SECTION_GIT_PROFILE = "gitprofile"
+const GIT_KEY_APPLIED_KEYS = "appliedkeys"
+
+// GIT_KEY_APPLIED_HELPER records the credential.helper value added with the
+// profile, the key has several values and only this one is removed
+const GIT_KEY_APPLIED_HELPER = "appliedhelper"
+
+// GIT_KEY_PREVIOUS_VALUE holds the values a key had before the profile
+// applied it, as gitprofile.<key>.previous, so they are restored on removal
+const GIT_KEY_PREVIOUS_VALUE = "previous"
+
+// Keys of the credential configuration applied with the profile
+const (
+	GIT_KEY_CREDENTIAL_HELPER          = "credential.helper"
+	GIT_KEY_CREDENTIAL_USERNAME_PREFIX = "credential.https://"
+	GIT_KEY_CREDENTIAL_USERNAME_SUFFIX = ".username"
+)
 
+// GitUserRepository applies the profile to a git configuration file, only the
+// keys it owns are edited and every other byte of the file is kept
 type GitUserRepository struct {
 	path string
+	// readPaths are the files the user is read from, in order, the path by default
+	readPaths []string
 }
 
 func NewGitUserRepository(path string) (*GitUserRepository, error) {
@@ -24,74 +50,181 @@ func NewGitUserRepository(path string) (*GitUserRepository, error) {
 		return nil, fmt.Errorf("path cannot be empty")
 	}
 
-	return &GitUserRepository{path}, nil
+	return &GitUserRepository{path, []string{path}}, nil
+}
+
+// WithReadPaths reads the user from the files, in order and the last value
+// winning as git reads its global files, the user is still written to the path
+func (i *GitUserRepository) WithReadPaths(paths []string) *GitUserRepository {
+	return &GitUserRepository{i.path, paths}
 }
 
 func (i *GitUserRepository) Get() (*domain.ScmUser, error) {
-	if _, err := os.Stat(i.path); errors.Is(err, os.ErrNotExist) {
-		return nil, domain.ErrScmUserNotFound
+	file := gitConfigFiles{}
+	for _, path := range i.readPaths {
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+
+		config, err := loadGitConfigFile(path)
+		if err != nil {
+			return nil, domain.ErrScmUserNotFound
+		}
+
+		file = append(file, config)
 	}
 
-	cfg, err := ini.Load(i.path)
-	if err != nil {
+	if len(file) == 0 {
 		return nil, domain.ErrScmUserNotFound
 	}
 
-	section, err := cfg.GetSection(GIT_SECTION_USER)
-	if err != nil {
-		return nil, domain.ErrScmUserNotFound
+	value := func(key string) string {
+		v, _ := file.Get(key)
+		return v
 	}
 
 	user := domain.NewScmUser(
-		section.Key("workspace").String(),
-		section.Key("email").String(),
-		section.Key("name").String(),
+		value(GIT_SECTION_USER+".workspace"),
+		value(GIT_SECTION_USER+".email"),
+		value(GIT_SECTION_USER+".name"),
 	)
 
 	if user.Workespace == "" && user.Email == "" && user.Name == "" {
 		return nil, domain.ErrScmUserNotFound
 	}
 
+	user.SigningKey = value(GIT_SECTION_USER + ".signingkey")
+	user.SigningFormat = value(GIT_SECTION_GPG + ".format")
+	user.CommitGpgSign = parseGitConfigBool(value(GIT_SECTION_COMMIT + ".gpgsign"))
+	user.TagGpgSign = parseGitConfigBool(value(GIT_SECTION_TAG + ".gpgsign"))
+	user.SshCommand = value(GIT_SECTION_CORE + ".sshCommand")
+
+	user.CredentialHelper = value(GIT_SECTION_GIT_PROFILE + "." + GIT_KEY_APPLIED_HELPER)
+
+	for _, key := range appliedConfigKeys(file) {
+		value, ok := file.Get(key)
+		if !ok {
+			continue
+		}
+
+		host, isUsername := strings.CutPrefix(key, GIT_KEY_CREDENTIAL_USERNAME_PREFIX)
+		host, isUsername = strings.CutSuffix(host, GIT_KEY_CREDENTIAL_USERNAME_SUFFIX)
+
+		switch {
+		case isSigningKey(key):
+			continue
+		case isUsername:
+			if user.Usernames == nil {
+				user.Usernames = map[string]string{}
+			}
+
+			user.Usernames[host] = value
+		default:
+			if user.Config == nil {
+				user.Config = map[string]string{}
+			}
+
+			user.Config[key] = value
+		}
+	}
+
 	return user, nil
 }
 
 func (i *GitUserRepository) Save(user *domain.ScmUser) error {
-	if _, err := os.Stat(i.path); errors.Is(err, os.ErrNotExist) {
-		if err := os.MkdirAll(filepath.Dir(i.path), 0750); err != nil {
-			return err
+	file, err := loadGitConfigFile(i.path)
+	if err != nil {
+		return domain.ErrInvalidWorkspace
+	}
+
+	if err := i.save(file, user); err != nil {
+		return err
+	}
+
+	return file.Save()
+}
+
+func (i *GitUserRepository) SaveWorkspace(workspace string) error {
+	file, err := loadGitConfigFile(i.path)
+	if err != nil {
+		return domain.ErrInvalidWorkspace
+	}
+
+	if err := file.Set(GIT_SECTION_USER+".workspace", workspace); err != nil {
+		return err
+	}
+
+	return file.Save()
+}
+
+func (i *GitUserRepository) save(file *gitConfigFile, user *domain.ScmUser) error {
+	for _, key := range []struct{ name, value string }{
+		{"workspace", user.Workespace},
+		{"name", user.Name},
+		{"email", user.Email},
+	} {
+		// An identity without workspace is not managed by a profile anymore
+		if key.value == "" && key.name == "workspace" {
+			if err := file.Unset(GIT_SECTION_USER + "." + key.name); err != nil {
+				return err
+			}
+
+			continue
 		}
 
-		file, err := os.Create(i.path)
-		if err != nil {
+		if err := file.Set(GIT_SECTION_USER+"."+key.name, key.value); err != nil {
 			return err
 		}
-
-		defer file.Close()
 	}
 
-	cfg, err := ini.Load(i.path)
-	if err != nil {
-		return domain.ErrInvalidWorkspace
+	if err := deleteSshCommand(file); err != nil {
+		return err
 	}
 
-	section, err := cfg.GetSection(GIT_SECTION_USER)
-	if err != nil {
-		section, err = cfg.NewSection(GIT_SECTION_USER)
-		if err != nil {
+	if user.SshCommand != "" {
+		if err := file.Set(GIT_SECTION_CORE+".sshCommand", user.SshCommand); err != nil {
 			return err
 		}
 	}
 
-	section.Key("workspace").SetValue(user.Workespace)
-	section.Key("name").SetValue(user.Name)
-	section.Key("email").SetValue(user.Email)
+	// Only the keys applied with the previous profile are removed, the ones
+	// written by the user are never touched. The signing configuration is
+	// applied the same way, so commits are never signed with a key of another
+	// profile and the signing configured by the user comes back with unset.
+	if err := deleteAppliedConfig(file); err != nil {
+		return err
+	}
 
-	err = cfg.SaveTo(i.path)
-	if err != nil {
+	if err := addCredentialHelper(file, user.CredentialHelper); err != nil {
 		return err
 	}
 
-	return nil
+	config := scmUserConfig(user)
+	if len(config) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(config))
+	for key := range config {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	for _, key := range keys {
+		// The values written by the user are kept aside and restored once
+		// the profile does not apply the key anymore
+		for _, value := range file.GetAll(key) {
+			if err := file.Add(previousConfigKey(key), value); err != nil {
+				return err
+			}
+		}
+
+		if err := file.Set(key, config[key]); err != nil {
+			return err
+		}
+	}
+
+	return file.Set(GIT_SECTION_GIT_PROFILE+"."+GIT_KEY_APPLIED_KEYS, strings.Join(keys, ","))
 }
 
 func (i *GitUserRepository) Delete() error {
@@ -99,24 +232,179 @@ func (i *GitUserRepository) Delete() error {
 		return nil
 	}
 
-	cfg, err := ini.Load(i.path)
-	if err != nil {
+	file, err := loadGitConfigFile(i.path)
+	if err != nil || !file.HasSection(GIT_SECTION_USER) {
 		return domain.ErrScmUserNotFound
 	}
 
-	section, err := cfg.GetSection(GIT_SECTION_USER)
-	if err != nil {
-		return domain.ErrScmUserNotFound
+	for _, name := range []string{"workspace", "name", "email"} {
+		if err := file.Unset(GIT_SECTION_USER + "." + name); err != nil {
+			return err
+		}
 	}
 
-	section.DeleteKey("workspace")
-	section.DeleteKey("name")
-	section.DeleteKey("email")
+	if err := deleteSshCommand(file); err != nil {
+		return err
+	}
 
-	err = cfg.SaveTo(i.path)
-	if err != nil {
+	if err := deleteAppliedConfig(file); err != nil {
+		return err
+	}
+
+	return file.Save()
+}
+
+// isSigningKey reports whether the key is one of the signing configuration,
+// read into the signing fields of the user rather than its config
+func isSigningKey(key string) bool {
+	switch strings.ToLower(key) {
+	case GIT_SECTION_USER + ".signingkey", GIT_SECTION_GPG + ".format", GIT_SECTION_COMMIT + ".gpgsign", GIT_SECTION_TAG + ".gpgsign":
+		return true
+	default:
+		return false
+	}
+}
+
+// deleteSshCommand removes the core.sshCommand written from a profile ssh key,
+// a command configured by the user is kept untouched
+func deleteSshCommand(file *gitConfigFile) error {
+	command, ok := file.Get(GIT_SECTION_CORE + ".sshCommand")
+	if !ok || !domain.IsProfileSshCommand(command) {
+		return nil
+	}
+
+	return file.Unset(GIT_SECTION_CORE + ".sshCommand")
+}
+
+// scmUserConfig returns the extra keys applied with the profile, its git
+// configuration, its signing configuration and the credential usernames
+func scmUserConfig(user *domain.ScmUser) map[string]string {
+	config := make(map[string]string, len(user.Config)+len(user.Usernames)+4)
+	for key, value := range user.Config {
+		config[key] = value
+	}
+
+	// An empty gpg.format is invalid for git, the format is only written when set
+	if user.SigningKey != "" {
+		config[GIT_SECTION_USER+".signingkey"] = user.SigningKey
+		config[GIT_SECTION_COMMIT+".gpgsign"] = strconv.FormatBool(user.CommitGpgSign)
+		config[GIT_SECTION_TAG+".gpgsign"] = strconv.FormatBool(user.TagGpgSign)
+		if user.SigningFormat != "" {
+			config[GIT_SECTION_GPG+".format"] = user.SigningFormat
+		}
+	}
+
+	for host, username := range user.Usernames {
+		config[GIT_KEY_CREDENTIAL_USERNAME_PREFIX+host+GIT_KEY_CREDENTIAL_USERNAME_SUFFIX] = username
+	}
+
+	return config
+}
+
+// addCredentialHelper appends the helper to the credential helpers of the
+// file, a helper already configured by the user is not recorded so that it
+// is never removed with the profile
+func addCredentialHelper(file *gitConfigFile, helper string) error {
+	if helper == "" || slices.Contains(file.GetAll(GIT_KEY_CREDENTIAL_HELPER), helper) {
+		return nil
+	}
+
+	if err := file.Add(GIT_KEY_CREDENTIAL_HELPER, helper); err != nil {
+		return err
+	}
+
+	return file.Set(GIT_SECTION_GIT_PROFILE+"."+GIT_KEY_APPLIED_HELPER, helper)
+}
+
+// deleteCredentialHelper removes the credential helper added with the
+// profile and its record, the helpers of the user are kept
+func deleteCredentialHelper(file *gitConfigFile) error {
+	key := GIT_SECTION_GIT_PROFILE + "." + GIT_KEY_APPLIED_HELPER
+	helper, ok := file.Get(key)
+	if !ok {
+		return nil
+	}
+
+	if err := file.UnsetValue(GIT_KEY_CREDENTIAL_HELPER, helper); err != nil {
+		return err
+	}
+
+	if err := file.RemoveEmptySection("credential"); err != nil {
+		return err
+	}
+
+	return file.Unset(key)
+}
+
+// appliedConfigKeys returns the extra keys applied with the profile, as
+// recorded in the gitprofile.appliedkeys key
+func appliedConfigKeys(file gitConfigReader) []string {
+	value, ok := file.Get(GIT_SECTION_GIT_PROFILE + "." + GIT_KEY_APPLIED_KEYS)
+	if !ok {
+		return nil
+	}
+
+	keys := make([]string, 0)
+	for _, key := range strings.Split(value, ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+// previousConfigKey returns the key holding the values the key had before
+// the profile applied it
+func previousConfigKey(key string) string {
+	return GIT_SECTION_GIT_PROFILE + "." + key + "." + GIT_KEY_PREVIOUS_VALUE
+}
+
+// deleteAppliedConfig removes the extra keys applied with the profile and
+// their record, restoring the values they had before the profile applied
+// them and dropping the sections that become empty
+func deleteAppliedConfig(file *gitConfigFile) error {
+	if err := deleteCredentialHelper(file); err != nil {
+		return err
+	}
+
+	for _, key := range appliedConfigKeys(file) {
+		if err := file.Unset(key); err != nil {
+			return err
+		}
+
+		previous := previousConfigKey(key)
+		for _, value := range file.GetAll(previous) {
+			if err := file.Add(key, value); err != nil {
+				return err
+			}
+		}
+
+		if err := file.Unset(previous); err != nil {
+			return err
+		}
+
+		for _, k := range []string{previous, key} {
+			if err := file.RemoveEmptySection(k[:strings.LastIndex(k, ".")]); err != nil {
+				return err
+			}
+		}
+	}
+
+	key := GIT_SECTION_GIT_PROFILE + "." + GIT_KEY_APPLIED_KEYS
+	if err := file.Unset(key); err != nil {
 		return err
 	}
 
-	return nil
+	return file.RemoveEmptySection(GIT_SECTION_GIT_PROFILE)
+}
+
+// parseGitConfigBool reads a boolean as git does, a wrong value is false
+func parseGitConfigBool(value string) bool {
+	switch strings.ToLower(value) {
+	case "true", "yes", "on", "1":
+		return true
+	default:
+		return false
+	}
 }
@@ -3,6 +3,7 @@ package infrastructure_test
 import (
 	"os"
 	"os/exec"
+	"strings"
 	"testing"
 
 	"github.com/b4nd/git-profile/pkg/domain"
@@ -231,4 +232,571 @@ func TestGitUserRepository(t *testing.T) {
 		assert.Error(t, err)
 	})
 
+	t.Run("should write and clear the signing configuration", func(t *testing.T) {
+		path := initializateGitRepository(t)
+
+		repository, err := infrastructure.NewGitUserRepository(path + GitConfigFile)
+		assert.NoError(t, err)
+
+		user := domain.NewScmUser(
+			faker.Internet().User(),
+			faker.Internet().Email(),
+			faker.Person().Name(),
+		)
+		user.SigningKey = faker.Hash().MD5()
+		user.SigningFormat = domain.SigningFormatOpenPGP
+		user.CommitGpgSign = true
+
+		err = repository.Save(user)
+		assert.NoError(t, err)
+
+		currentUser, err := repository.Get()
+		assert.NoError(t, err)
+		assert.Equal(t, user, currentUser)
+
+		cmd := exec.Command("git", "config", "--get", "commit.gpgsign")
+		cmd.Dir = path
+		output, err := cmd.Output()
+		assert.NoError(t, err)
+		assert.Equal(t, "true\n", string(output))
+
+		unsigned := domain.NewScmUser(user.Workespace, user.Email, user.Name)
+		err = repository.Save(unsigned)
+		assert.NoError(t, err)
+
+		currentUser, err = repository.Get()
+		assert.NoError(t, err)
+		assert.Equal(t, unsigned, currentUser)
+
+		cmd = exec.Command("git", "config", "--get", "user.signingkey")
+		cmd.Dir = path
+		_, err = cmd.Output()
+		assert.Error(t, err)
+	})
+
+	t.Run("should write and clear the ssh command", func(t *testing.T) {
+		path := initializateGitRepository(t)
+
+		repository, err := infrastructure.NewGitUserRepository(path + GitConfigFile)
+		assert.NoError(t, err)
+
+		user := domain.NewScmUser(
+			faker.Internet().User(),
+			faker.Internet().Email(),
+			faker.Person().Name(),
+		)
+		user.SshCommand = "ssh -i ~/.ssh/id_work -o IdentitiesOnly=yes"
+
+		err = repository.Save(user)
+		assert.NoError(t, err)
+
+		currentUser, err := repository.Get()
+		assert.NoError(t, err)
+		assert.Equal(t, user, currentUser)
+
+		err = repository.Delete()
+		assert.NoError(t, err)
+
+		cmd := exec.Command("git", "config", "--get", "core.sshCommand")
+		cmd.Dir = path
+		_, err = cmd.Output()
+		assert.Error(t, err)
+	})
+
+	t.Run("should keep the ssh command configured by the user", func(t *testing.T) {
+		path := initializateGitRepository(t)
+
+		cmd := exec.Command("git", "config", "core.sshCommand", "ssh -F ~/.ssh/custom_config")
+		cmd.Dir = path
+		assert.NoError(t, cmd.Run())
+
+		repository, err := infrastructure.NewGitUserRepository(path + GitConfigFile)
+		assert.NoError(t, err)
+
+		user := domain.NewScmUser(
+			faker.Internet().User(),
+			faker.Internet().Email(),
+			faker.Person().Name(),
+		)
+
+		err = repository.Save(user)
+		assert.NoError(t, err)
+
+		err = repository.Delete()
+		assert.NoError(t, err)
+
+		cmd = exec.Command("git", "config", "--get", "core.sshCommand")
+		cmd.Dir = path
+		output, err := cmd.Output()
+		assert.NoError(t, err)
+		assert.Equal(t, "ssh -F ~/.ssh/custom_config\n", string(output))
+	})
+
+	t.Run("should apply and remove the extra git configuration", func(t *testing.T) {
+		path := initializateGitRepository(t)
+
+		cmd := exec.Command("git", "config", "pull.ff", "only")
+		cmd.Dir = path
+		assert.NoError(t, cmd.Run())
+
+		repository, err := infrastructure.NewGitUserRepository(path + GitConfigFile)
+		assert.NoError(t, err)
+
+		user := domain.NewScmUser(
+			faker.Internet().User(),
+			faker.Internet().Email(),
+			faker.Person().Name(),
+		)
+		user.Config = map[string]string{
+			"pull.rebase":                        "true",
+			"url.git@github.com:acme/.insteadof": "https://github.com/acme/",
+		}
+
+		err = repository.Save(user)
+		assert.NoError(t, err)
+
+		currentUser, err := repository.Get()
+		assert.NoError(t, err)
+		assert.Equal(t, user, currentUser)
+
+		cmd = exec.Command("git", "config", "--get", "url.git@github.com:acme/.insteadOf")
+		cmd.Dir = path
+		output, err := cmd.Output()
+		assert.NoError(t, err)
+		assert.Equal(t, "https://github.com/acme/\n", string(output))
+
+		// Switching to another profile removes the keys applied with the previous one
+		user.Config = map[string]string{"push.autosetupremote": "true"}
+		err = repository.Save(user)
+		assert.NoError(t, err)
+
+		cmd = exec.Command("git", "config", "--get", "pull.rebase")
+		cmd.Dir = path
+		_, err = cmd.Output()
+		assert.Error(t, err)
+
+		err = repository.Delete()
+		assert.NoError(t, err)
+
+		for _, key := range []string{"push.autosetupremote", "url.git@github.com:acme/.insteadOf", "gitprofile.appliedkeys"} {
+			cmd = exec.Command("git", "config", "--get", key)
+			cmd.Dir = path
+			_, err = cmd.Output()
+			assert.Error(t, err, key)
+		}
+
+		cmd = exec.Command("git", "config", "--get", "pull.ff")
+		cmd.Dir = path
+		output, err = cmd.Output()
+		assert.NoError(t, err)
+		assert.Equal(t, "only\n", string(output))
+	})
+
+	t.Run("should restore the values of the keys set by the user", func(t *testing.T) {
+		path := initializateGitRepository(t)
+
+		gitConfig := func(args ...string) (string, error) {
+			cmd := exec.Command("git", append([]string{"config"}, args...)...)
+			cmd.Dir = path
+			output, err := cmd.Output()
+
+			return string(output), err
+		}
+
+		for _, args := range [][]string{
+			{"pull.rebase", "false"},
+			{"--add", "remote.origin.fetch", "+refs/heads/main:refs/remotes/origin/main"},
+			{"--add", "remote.origin.fetch", "+refs/heads/dev:refs/remotes/origin/dev"},
+		} {
+			_, err := gitConfig(args...)
+			assert.NoError(t, err)
+		}
+
+		repository, err := infrastructure.NewGitUserRepository(path + GitConfigFile)
+		assert.NoError(t, err)
+
+		user := domain.NewScmUser("work", "jane@acme.com", "Jane Doe")
+		user.Config = map[string]string{
+			"pull.rebase":         "true",
+			"remote.origin.fetch": "+refs/heads/*:refs/remotes/origin/*",
+		}
+
+		err = repository.Save(user)
+		assert.NoError(t, err)
+
+		output, err := gitConfig("--get-all", "remote.origin.fetch")
+		assert.NoError(t, err)
+		assert.Equal(t, "+refs/heads/*:refs/remotes/origin/*\n", output)
+
+		currentUser, err := repository.Get()
+		assert.NoError(t, err)
+		assert.Equal(t, user, currentUser)
+
+		// Switching to a profile without the key gives back the value of the user
+		other := domain.NewScmUser("personal", "jane@home.org", "Jane Doe")
+		other.Config = map[string]string{"push.autosetupremote": "true"}
+		err = repository.Save(other)
+		assert.NoError(t, err)
+
+		output, err = gitConfig("--get", "pull.rebase")
+		assert.NoError(t, err)
+		assert.Equal(t, "false\n", output)
+
+		err = repository.Save(user)
+		assert.NoError(t, err)
+
+		output, err = gitConfig("--get", "pull.rebase")
+		assert.NoError(t, err)
+		assert.Equal(t, "true\n", output)
+
+		err = repository.Delete()
+		assert.NoError(t, err)
+
+		output, err = gitConfig("--get", "pull.rebase")
+		assert.NoError(t, err)
+		assert.Equal(t, "false\n", output)
+
+		output, err = gitConfig("--get-all", "remote.origin.fetch")
+		assert.NoError(t, err)
+		assert.Equal(t, "+refs/heads/main:refs/remotes/origin/main\n+refs/heads/dev:refs/remotes/origin/dev\n", output)
+
+		_, err = gitConfig("--get-regexp", "^gitprofile\\.")
+		assert.Error(t, err)
+	})
+
+	t.Run("should restore the signing configuration set by the user", func(t *testing.T) {
+		path := initializateGitRepository(t)
+
+		gitConfig := func(args ...string) (string, error) {
+			cmd := exec.Command("git", append([]string{"config"}, args...)...)
+			cmd.Dir = path
+			output, err := cmd.Output()
+
+			return string(output), err
+		}
+
+		for _, args := range [][]string{
+			{"user.signingkey", "MYKEY"},
+			{"commit.gpgsign", "true"},
+		} {
+			_, err := gitConfig(args...)
+			assert.NoError(t, err)
+		}
+
+		repository, err := infrastructure.NewGitUserRepository(path + GitConfigFile)
+		assert.NoError(t, err)
+
+		// A profile without signing leaves the signing of the user as it is
+		err = repository.Save(domain.NewScmUser("other", "jane@home.org", "Jane Doe"))
+		assert.NoError(t, err)
+
+		output, err := gitConfig("--get", "user.signingkey")
+		assert.NoError(t, err)
+		assert.Equal(t, "MYKEY\n", output)
+
+		// A profile with signing but no format never writes an empty gpg.format
+		user := domain.NewScmUser("work", "jane@acme.com", "Jane Doe")
+		user.SigningKey = "WORKKEY"
+		err = repository.Save(user)
+		assert.NoError(t, err)
+
+		output, err = gitConfig("--get", "commit.gpgsign")
+		assert.NoError(t, err)
+		assert.Equal(t, "false\n", output)
+
+		_, err = gitConfig("--get", "gpg.format")
+		assert.Error(t, err)
+
+		currentUser, err := repository.Get()
+		assert.NoError(t, err)
+		assert.Equal(t, user, currentUser)
+
+		err = repository.Delete()
+		assert.NoError(t, err)
+
+		output, err = gitConfig("--get", "user.signingkey")
+		assert.NoError(t, err)
+		assert.Equal(t, "MYKEY\n", output)
+
+		output, err = gitConfig("--get", "commit.gpgsign")
+		assert.NoError(t, err)
+		assert.Equal(t, "true\n", output)
+
+		_, err = gitConfig("--get", "tag.gpgsign")
+		assert.Error(t, err)
+	})
+
+	t.Run("should apply and remove the credential usernames and helper", func(t *testing.T) {
+		path := initializateGitRepository(t)
+
+		repository, err := infrastructure.NewGitUserRepository(path + GitConfigFile)
+		assert.NoError(t, err)
+
+		user := domain.NewScmUser("work", "jane@acme.com", "Jane Doe")
+		user.Usernames = map[string]string{"github.com": "jane-acme", "gitlab.internal:8443": "jdoe"}
+		user.CredentialHelper = "!git profile credential --helper cache"
+
+		err = repository.Save(user)
+		assert.NoError(t, err)
+
+		currentUser, err := repository.Get()
+		assert.NoError(t, err)
+		assert.Equal(t, user, currentUser)
+
+		for key, expected := range map[string]string{
+			"credential.https://github.com.username":           "jane-acme",
+			"credential.https://gitlab.internal:8443.username": "jdoe",
+			"credential.helper":                                "!git profile credential --helper cache",
+		} {
+			cmd := exec.Command("git", "config", "--get", key)
+			cmd.Dir = path
+			output, err := cmd.Output()
+			assert.NoError(t, err, key)
+			assert.Equal(t, expected+"\n", string(output))
+		}
+
+		err = repository.Delete()
+		assert.NoError(t, err)
+
+		cmd := exec.Command("git", "config", "--get-regexp", "^credential\\.")
+		cmd.Dir = path
+		_, err = cmd.Output()
+		assert.Error(t, err)
+	})
+
+	t.Run("should add the credential helper next to the helpers of the user", func(t *testing.T) {
+		path := initializateGitRepository(t)
+
+		gitConfig := func(args ...string) (string, error) {
+			cmd := exec.Command("git", append([]string{"config"}, args...)...)
+			cmd.Dir = path
+			output, err := cmd.Output()
+
+			return string(output), err
+		}
+
+		for _, helper := range []string{"", "store"} {
+			_, err := gitConfig("--add", "credential.helper", helper)
+			assert.NoError(t, err)
+		}
+
+		repository, err := infrastructure.NewGitUserRepository(path + GitConfigFile)
+		assert.NoError(t, err)
+
+		user := domain.NewScmUser("work", "jane@acme.com", "Jane Doe")
+		user.CredentialHelper = "!git profile credential --helper cache"
+
+		err = repository.Save(user)
+		assert.NoError(t, err)
+
+		output, err := gitConfig("--get-all", "credential.helper")
+		assert.NoError(t, err)
+		assert.Equal(t, "\nstore\n!git profile credential --helper cache\n", output)
+
+		currentUser, err := repository.Get()
+		assert.NoError(t, err)
+		assert.Equal(t, user.CredentialHelper, currentUser.CredentialHelper)
+
+		// Setting the profile again does not add the helper twice
+		err = repository.Save(user)
+		assert.NoError(t, err)
+
+		output, err = gitConfig("--get-all", "credential.helper")
+		assert.NoError(t, err)
+		assert.Equal(t, "\nstore\n!git profile credential --helper cache\n", output)
+
+		err = repository.Delete()
+		assert.NoError(t, err)
+
+		output, err = gitConfig("--get-all", "credential.helper")
+		assert.NoError(t, err)
+		assert.Equal(t, "\nstore\n", output)
+
+		// A helper configured by the user is never removed with the profile
+		_, err = gitConfig("--add", "credential.helper", user.CredentialHelper)
+		assert.NoError(t, err)
+
+		err = repository.Save(user)
+		assert.NoError(t, err)
+
+		err = repository.Delete()
+		assert.NoError(t, err)
+
+		output, err = gitConfig("--get-all", "credential.helper")
+		assert.NoError(t, err)
+		assert.Equal(t, "\nstore\n!git profile credential --helper cache\n", output)
+	})
+
+	t.Run("should keep every other byte of a complex git config", func(t *testing.T) {
+		path := initializateGitRepository(t)
+		config := "# Written by hand, keep me\n" +
+			"[core]\n" +
+			"\trepositoryformatversion = 0\n" +
+			"\tfilemode = true\n" +
+			"\tbare = false\n" +
+			"\tlogallrefupdates = true\n" +
+			"[remote \"origin\"]\n" +
+			"\turl = git@github.com:acme/app.git\n" +
+			"\tfetch = +refs/heads/*:refs/remotes/origin/*\n" +
+			"\tfetch = +refs/tags/*:refs/tags/* ; the tags too\n" +
+			"[branch \"feature/\\\"quoted\\\"\"]\n" +
+			"\tremote = origin\n" +
+			"\tmerge = refs/heads/feature\n" +
+			"[include]\n" +
+			"\tpath = ~/.gitconfig.d/common\n" +
+			"\tpath = \"~/.gitconfig.d/with spaces\"\n" +
+			"[includeIf \"gitdir:~/work/\"]\n" +
+			"\tpath = ~/.gitconfig.d/work\n" +
+			"[alias]\n" +
+			"\tlg = \"log --graph --pretty=format:'%h %s' # not a comment\"\n" +
+			"\tst = status \\\n" +
+			"\t\t--short\n" +
+			"[Diff]\trenames\n" +
+			"[color.ui]\n" +
+			"\tdiff = auto\n"
+
+		err := os.WriteFile(path+GitConfigFile, []byte(config), 0600)
+		assert.NoError(t, err)
+		assert.NoError(t, os.Chmod(path+GitConfigFile, 0600))
+
+		list := func() string {
+			cmd := exec.Command("git", "config", "--file", path+GitConfigFile, "--list")
+			output, err := cmd.Output()
+			assert.NoError(t, err)
+			return string(output)
+		}
+
+		original := list()
+
+		repository, err := infrastructure.NewGitUserRepository(path + GitConfigFile)
+		assert.NoError(t, err)
+
+		user := domain.NewScmUser("work", "jane@acme.com", "Jane \"JD\" Doe # ops")
+		user.SigningKey = "ABCDEF"
+		user.SigningFormat = "openpgp"
+		user.CommitGpgSign = true
+		user.Config = map[string]string{"url.git@github.com:acme/.insteadOf": "https://github.com/acme/"}
+
+		err = repository.Save(user)
+		assert.NoError(t, err)
+
+		content, err := os.ReadFile(path + GitConfigFile)
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(string(content), config), string(content))
+		assert.True(t, strings.HasPrefix(list(), original))
+
+		currentUser, err := repository.Get()
+		assert.NoError(t, err)
+		assert.Equal(t, user, currentUser)
+
+		cmd := exec.Command("git", "config", "--file", path+GitConfigFile, "--get", "user.name")
+		output, err := cmd.Output()
+		assert.NoError(t, err)
+		assert.Equal(t, user.Name+"\n", string(output))
+
+		info, err := os.Stat(path + GitConfigFile)
+		assert.NoError(t, err)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+		err = repository.Delete()
+		assert.NoError(t, err)
+
+		content, err = os.ReadFile(path + GitConfigFile)
+		assert.NoError(t, err)
+		assert.Equal(t, config, string(content))
+	})
+
+	t.Run("should only edit the owned keys of an existing user section", func(t *testing.T) {
+		path := initializateGitRepository(t)
+		config := "[user]\n" +
+			"\t# The identity of this clone\n" +
+			"\tname = Old Name\n" +
+			"\tuseConfigOnly = true\n" +
+			"\temail = old@acme.com ; set by hand\n" +
+			"[pull] ff = only\n" +
+			"[user]\r\n" +
+			"\temail = other@acme.com\r\n"
+
+		err := os.WriteFile(path+GitConfigFile, []byte(config), 0644)
+		assert.NoError(t, err)
+
+		repository, err := infrastructure.NewGitUserRepository(path + GitConfigFile)
+		assert.NoError(t, err)
+
+		user, err := repository.Get()
+		assert.NoError(t, err)
+		assert.Equal(t, "Old Name", user.Name)
+		assert.Equal(t, "other@acme.com", user.Email)
+
+		err = repository.Save(domain.NewScmUser("work", "jane@acme.com", "Jane Doe"))
+		assert.NoError(t, err)
+
+		content, err := os.ReadFile(path + GitConfigFile)
+		assert.NoError(t, err)
+		assert.Equal(t, "[user]\n"+
+			"\t# The identity of this clone\n"+
+			"\tname = Jane Doe\n"+
+			"\tuseConfigOnly = true\n"+
+			"[pull] ff = only\n"+
+			"[user]\r\n"+
+			"\temail = jane@acme.com\r\n"+
+			"\tworkspace = work\n", string(content))
+
+		for key, expected := range map[string]string{"user.email": "jane@acme.com", "user.useconfigonly": "true", "pull.ff": "only"} {
+			cmd := exec.Command("git", "config", "--file", path+GitConfigFile, "--get", key)
+			output, err := cmd.Output()
+			assert.NoError(t, err, key)
+			assert.Equal(t, expected+"\n", string(output))
+		}
+	})
+
+	t.Run("should not write an invalid git config", func(t *testing.T) {
+		path := initializateGitRepository(t)
+		config := "[user\n\tname = broken\n"
+
+		err := os.WriteFile(path+GitConfigFile, []byte(config), 0644)
+		assert.NoError(t, err)
+
+		repository, err := infrastructure.NewGitUserRepository(path + GitConfigFile)
+		assert.NoError(t, err)
+
+		err = repository.Save(domain.NewScmUser("work", "jane@acme.com", "Jane Doe"))
+		assert.ErrorIs(t, err, domain.ErrInvalidWorkspace)
+
+		content, err := os.ReadFile(path + GitConfigFile)
+		assert.NoError(t, err)
+		assert.Equal(t, config, string(content))
+	})
+
+	t.Run("should read the user from several files and write it to the path", func(t *testing.T) {
+		home := t.TempDir()
+		xdg := home + "/.config/git/config"
+		gitconfig := home + "/.gitconfig"
+
+		assert.NoError(t, os.MkdirAll(home+"/.config/git", 0750))
+		assert.NoError(t, os.WriteFile(xdg, []byte("[user]\n\tname = Jane Doe\n\temail = jane@home.com\n"), 0600))
+		assert.NoError(t, os.WriteFile(gitconfig, []byte("[user]\n\temail = jane@acme.com\n"), 0600))
+
+		repository, err := infrastructure.NewGitUserRepository(gitconfig)
+		assert.NoError(t, err)
+
+		repository = repository.WithReadPaths([]string{xdg, gitconfig})
+
+		// The last file wins, as git reads ~/.gitconfig after the XDG file
+		user, err := repository.Get()
+		assert.NoError(t, err)
+		assert.Equal(t, "Jane Doe", user.Name)
+		assert.Equal(t, "jane@acme.com", user.Email)
+
+		err = repository.Save(domain.NewScmUser("work", "jane@acme.com", "Jane Doe"))
+		assert.NoError(t, err)
+
+		content, err := os.ReadFile(xdg)
+		assert.NoError(t, err)
+		assert.Equal(t, "[user]\n\tname = Jane Doe\n\temail = jane@home.com\n", string(content))
+
+		user, err = repository.Get()
+		assert.NoError(t, err)
+		assert.Equal(t, "work", user.Workespace)
+	})
 }
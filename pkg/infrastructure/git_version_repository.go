@@ -0,0 +1,23 @@
+package infrastructure
+
+import (
+	"os/exec"
+
+	"github.com/b4nd/git-profile/pkg/domain"
+)
+
+// GitVersionRepository reads the version of the git binary found in the PATH
+type GitVersionRepository struct{}
+
+func NewGitVersionRepository() *GitVersionRepository {
+	return &GitVersionRepository{}
+}
+
+func (r *GitVersionRepository) Get() (domain.ScmVersion, error) {
+	output, err := exec.Command("git", "--version").Output()
+	if err != nil {
+		return domain.ScmVersion{}, domain.ErrScmNotInstalled
+	}
+
+	return domain.NewScmVersion(string(output))
+}
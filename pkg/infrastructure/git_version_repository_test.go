@@ -0,0 +1,31 @@
+package infrastructure_test
+
+import (
+	"testing"
+
+	"github.com/b4nd/git-profile/pkg/domain"
+	"github.com/b4nd/git-profile/pkg/infrastructure"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitVersionRepository(t *testing.T) {
+	t.Run("should return the version of git", func(t *testing.T) {
+		version, err := infrastructure.NewGitVersionRepository().Get()
+
+		assert.NoError(t, err)
+
+		// Any git able to run the tests is newer than the first 2.x release
+		first, err := domain.NewScmVersion("git version 2.0.0")
+		assert.NoError(t, err)
+		assert.True(t, version.AtLeast(first))
+	})
+
+	t.Run("should return an error when git is not installed", func(t *testing.T) {
+		t.Setenv("PATH", t.TempDir())
+
+		_, err := infrastructure.NewGitVersionRepository().Get()
+
+		assert.ErrorIs(t, err, domain.ErrScmNotInstalled)
+	})
+}
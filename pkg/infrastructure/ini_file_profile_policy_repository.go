@@ -0,0 +1,55 @@
+package infrastructure
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/b4nd/git-profile/pkg/domain"
+
+	"gopkg.in/ini.v1"
+)
+
+// The policy is the [verify] section of the policy file, the values are lists separated by commas:
+//
+//	[verify]
+//	allow = work, ci-bot@acme.com
+//	allow-domain = acme.com
+const (
+	INI_SECTION_VERIFY      = "verify"
+	POLICY_KEY_ALLOW        = "allow"
+	POLICY_KEY_ALLOW_DOMAIN = "allow-domain"
+)
+
+// IniFileProfilePolicyRepository reads the policy file committed in a repository
+type IniFileProfilePolicyRepository struct {
+	path string
+}
+
+func NewIniFileProfilePolicyRepository(path string) (*IniFileProfilePolicyRepository, error) {
+	if path == "" {
+		return nil, fmt.Errorf("path cannot be empty")
+	}
+
+	return &IniFileProfilePolicyRepository{path}, nil
+}
+
+func (i *IniFileProfilePolicyRepository) Get(path string) (*domain.ProfilePolicy, error) {
+	if path == "" {
+		path = i.path
+	}
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, domain.ErrProfilePolicyNotFound
+	}
+
+	cfg, err := ini.Load(path)
+	if err != nil {
+		return nil, err
+	}
+
+	section := cfg.Section(INI_SECTION_VERIFY)
+	return &domain.ProfilePolicy{
+		Allow:        section.Key(POLICY_KEY_ALLOW).Strings(","),
+		AllowDomains: section.Key(POLICY_KEY_ALLOW_DOMAIN).Strings(","),
+	}, nil
+}
@@ -0,0 +1,60 @@
+package infrastructure_test
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/b4nd/git-profile/pkg/domain"
+	"github.com/b4nd/git-profile/pkg/infrastructure"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIniFileProfilePolicyRepository(t *testing.T) {
+	t.Run("should return error when no path is provided", func(t *testing.T) {
+		repo, err := infrastructure.NewIniFileProfilePolicyRepository("")
+		assert.Error(t, err)
+		assert.Nil(t, repo)
+	})
+
+	t.Run("should read the policy of the repository or of the file given", func(t *testing.T) {
+		dir := t.TempDir()
+		defaultPath := path.Join(dir, ".gitprofile-policy")
+		otherPath := path.Join(dir, "policy.ini")
+
+		assert.NoError(t, os.WriteFile(defaultPath, []byte("[verify]\nallow = work, ci-bot@acme.com\nallow-domain = acme.com\n"), 0600))
+		assert.NoError(t, os.WriteFile(otherPath, []byte("[verify]\nallow-domain = acme.io,acme.dev\n"), 0600))
+
+		repo, err := infrastructure.NewIniFileProfilePolicyRepository(defaultPath)
+		assert.NoError(t, err)
+
+		policy, err := repo.Get("")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"work", "ci-bot@acme.com"}, policy.Allow)
+		assert.Equal(t, []string{"acme.com"}, policy.AllowDomains)
+
+		policy, err = repo.Get(otherPath)
+		assert.NoError(t, err)
+		assert.Empty(t, policy.Allow)
+		assert.Equal(t, []string{"acme.io", "acme.dev"}, policy.AllowDomains)
+	})
+
+	t.Run("should return an error when the file does not exist or is invalid", func(t *testing.T) {
+		dir := t.TempDir()
+
+		repo, err := infrastructure.NewIniFileProfilePolicyRepository(path.Join(dir, ".gitprofile-policy"))
+		assert.NoError(t, err)
+
+		policy, err := repo.Get("")
+		assert.ErrorIs(t, err, domain.ErrProfilePolicyNotFound)
+		assert.Nil(t, policy)
+
+		invalidPath := path.Join(dir, "invalid.ini")
+		assert.NoError(t, os.WriteFile(invalidPath, []byte("[verify\n"), 0600))
+
+		policy, err = repo.Get(invalidPath)
+		assert.Error(t, err)
+		assert.Nil(t, policy)
+	})
+}
@@ -5,14 +5,50 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/b4nd/git-profile/pkg/domain"
 
 	"gopkg.in/ini.v1"
 )
 
+// Keys of the optional signing configuration stored in a profile section
+const (
+	PROFILE_KEY_SIGNING_KEY    = "signingkey"
+	PROFILE_KEY_SIGNING_FORMAT = "signingformat"
+	PROFILE_KEY_COMMIT_GPGSIGN = "commitgpgsign"
+	PROFILE_KEY_TAG_GPGSIGN    = "taggpgsign"
+)
+
+const PROFILE_KEY_SSH_KEY = "sshkey"
+
+// PROFILE_KEY_REMOTES holds the comma separated remote url patterns of a profile
+const PROFILE_KEY_REMOTES = "remotes"
+
+// PROFILE_KEY_USERNAMES holds the comma separated host=username pairs of a profile
+const PROFILE_KEY_USERNAMES = "usernames"
+
+// INI_SECTION_CONFIG names the sections holding the extra git configuration of the profiles
+const INI_SECTION_CONFIG = "config"
+
+// PROFILE_KEY_EXTENDS holds the workspace whose fields are inherited by the profile
+const PROFILE_KEY_EXTENDS = "extends"
+
+// profileFieldKeys are the keys of the section holding each inheritable field,
+// a section defines the field when it has the first key
+var profileFieldKeys = map[domain.ProfileField][]string{
+	domain.ProfileFieldEmail:     {"email"},
+	domain.ProfileFieldName:      {"name"},
+	domain.ProfileFieldSigning:   {PROFILE_KEY_SIGNING_KEY, PROFILE_KEY_SIGNING_FORMAT, PROFILE_KEY_COMMIT_GPGSIGN, PROFILE_KEY_TAG_GPGSIGN},
+	domain.ProfileFieldSshKey:    {PROFILE_KEY_SSH_KEY},
+	domain.ProfileFieldRemotes:   {PROFILE_KEY_REMOTES},
+	domain.ProfileFieldUsernames: {PROFILE_KEY_USERNAMES},
+}
+
 type IniFileProfileRepository struct {
-	paths []string
+	paths     []string
+	localPath string
 }
 
 func NewIniFileProfileRepository(paths []string) (*IniFileProfileRepository, error) {
@@ -20,7 +56,24 @@ func NewIniFileProfileRepository(paths []string) (*IniFileProfileRepository, err
 		return nil, fmt.Errorf("no paths provided")
 	}
 
-	return &IniFileProfileRepository{paths}, nil
+	return &IniFileProfileRepository{paths: paths}, nil
+}
+
+// WithLocalPath sets the profile file of the current directory, the profiles
+// read from it have the local scope and the others the global scope
+func (i *IniFileProfileRepository) WithLocalPath(path string) *IniFileProfileRepository {
+	i.localPath = path
+	return i
+}
+
+// source returns the source of the profiles read from the path
+func (i *IniFileProfileRepository) source(path string) domain.ProfileSource {
+	scope := domain.ProfileScopeGlobal
+	if i.localPath != "" && filepath.Clean(path) == filepath.Clean(i.localPath) {
+		scope = domain.ProfileScopeLocal
+	}
+
+	return domain.NewProfileSource(path, scope)
 }
 
 type iniFileSource struct {
@@ -29,8 +82,13 @@ type iniFileSource struct {
 }
 
 func (i *IniFileProfileRepository) load() ([]*iniFileSource, error) {
+	return loadIniFileSources(i.paths)
+}
+
+// loadIniFileSources loads the existing files of the given paths, in the same order
+func loadIniFileSources(paths []string) ([]*iniFileSource, error) {
 	var cfgs []*iniFileSource = make([]*iniFileSource, 0)
-	for _, path := range i.paths {
+	for _, path := range paths {
 		if info, err := os.Stat(path); err == nil && !info.IsDir() {
 			cfg, err := ini.Load(path)
 			if err != nil {
@@ -56,45 +114,58 @@ func (i *IniFileProfileRepository) Get(workspace domain.ProfileWorkspace) (*doma
 			continue
 		}
 
-		profile, err := domain.NewProfile(
-			workspace.String(),
-			section.Key("email").String(),
-			section.Key("name").String(),
-		)
-
+		profile, err := resolveProfileSection(sources, source, workspace.String(), section)
 		if err != nil {
 			return nil, err
 		}
 
-		return profile, nil
+		return profile.WithSource(i.source(source.path)), nil
 	}
 
 	return nil, domain.ErrInvalidWorkspace
 }
 
 func (i *IniFileProfileRepository) Save(profile *domain.Profile) error {
-	path := i.paths[0]
+	return i.SaveAll([]*domain.Profile{profile})
+}
 
-	// Create the file if it does not exist
-	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
-		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
-			return err
-		}
+// SaveAll writes the profiles into the files that hold them, new profiles go
+// to the first file. Nothing is written when a section cannot be created and
+// every file is replaced at once so a failed write never leaves it half saved
+func (i *IniFileProfileRepository) SaveAll(profiles []*domain.Profile) error {
+	if err := createIniFile(i.paths[0]); err != nil {
+		return err
+	}
 
-		path = filepath.Clean(path)
-		file, err := os.Create(path)
+	sources, err := i.load()
+	if err != nil {
+		return err
+	}
+
+	changed := make([]*iniFileSource, 0, len(sources))
+	for _, profile := range profiles {
+		source, err := setProfileSection(sources, profile)
 		if err != nil {
 			return err
 		}
 
-		defer file.Close()
+		if !containsIniFileSource(changed, source) {
+			changed = append(changed, source)
+		}
 	}
 
-	sources, err := i.load()
-	if err != nil {
-		return err
+	for _, source := range changed {
+		if err := saveIniFileSource(source); err != nil {
+			return err
+		}
 	}
 
+	return nil
+}
+
+// setProfileSection writes the keys of the profile into the section of the
+// last source that holds it, or into a new section of the first source
+func setProfileSection(sources []*iniFileSource, profile *domain.Profile) (*iniFileSource, error) {
 	var source *iniFileSource = sources[0]
 	var section *ini.Section = nil
 	for _, c := range sources {
@@ -106,21 +177,306 @@ func (i *IniFileProfileRepository) Save(profile *domain.Profile) error {
 	}
 
 	if section == nil {
+		var err error
 		section, err = source.cfg.NewSection(profile.Workspace().String())
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
 
+	if extends := profile.Extends(); extends.String() == "" {
+		section.DeleteKey(PROFILE_KEY_EXTENDS)
+	} else {
+		section.Key(PROFILE_KEY_EXTENDS).SetValue(extends.String())
+	}
+
 	section.Key("name").SetValue(profile.Name().String())
 	section.Key("email").SetValue(profile.Email().String())
 
-	err = source.cfg.SaveTo(source.path)
+	signing := profile.Signing()
+	if signing.IsEmpty() {
+		section.DeleteKey(PROFILE_KEY_SIGNING_KEY)
+		section.DeleteKey(PROFILE_KEY_SIGNING_FORMAT)
+		section.DeleteKey(PROFILE_KEY_COMMIT_GPGSIGN)
+		section.DeleteKey(PROFILE_KEY_TAG_GPGSIGN)
+	} else {
+		section.Key(PROFILE_KEY_SIGNING_KEY).SetValue(signing.Key())
+		section.Key(PROFILE_KEY_SIGNING_FORMAT).SetValue(signing.Format())
+		section.Key(PROFILE_KEY_COMMIT_GPGSIGN).SetValue(strconv.FormatBool(signing.CommitSign()))
+		section.Key(PROFILE_KEY_TAG_GPGSIGN).SetValue(strconv.FormatBool(signing.TagSign()))
+	}
+
+	if sshKey := profile.SshKey(); sshKey.IsEmpty() {
+		section.DeleteKey(PROFILE_KEY_SSH_KEY)
+	} else {
+		section.Key(PROFILE_KEY_SSH_KEY).SetValue(sshKey.String())
+	}
+
+	if remotes := profile.Remotes(); len(remotes) == 0 {
+		section.DeleteKey(PROFILE_KEY_REMOTES)
+	} else {
+		values := make([]string, 0, len(remotes))
+		for _, remote := range remotes {
+			values = append(values, remote.String())
+		}
+
+		section.Key(PROFILE_KEY_REMOTES).SetValue(strings.Join(values, ", "))
+	}
+
+	if usernames := profile.Usernames(); len(usernames) == 0 {
+		section.DeleteKey(PROFILE_KEY_USERNAMES)
+	} else {
+		values := make([]string, 0, len(usernames))
+		for _, username := range usernames {
+			values = append(values, username.String())
+		}
+
+		section.Key(PROFILE_KEY_USERNAMES).SetValue(strings.Join(values, ", "))
+	}
+
+	source.cfg.DeleteSection(configSectionName(profile.Workspace().String()))
+	if config := profile.Config(); !config.IsEmpty() {
+		configSection, err := source.cfg.NewSection(configSectionName(profile.Workspace().String()))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range config.Entries() {
+			configSection.Key(entry.Key()).SetValue(entry.Value())
+		}
+	}
+
+	// The fields equal to the ones of the extended profile are left inherited
+	for _, field := range inheritedProfileFields(sources, section, profile) {
+		if field == domain.ProfileFieldConfig {
+			source.cfg.DeleteSection(configSectionName(profile.Workspace().String()))
+		}
+
+		for _, key := range profileFieldKeys[field] {
+			section.DeleteKey(key)
+		}
+	}
+
+	return source, nil
+}
+
+// inheritedProfileFields returns the fields of the profile equal to the ones
+// of the profile extended by the section, none when it cannot be resolved
+func inheritedProfileFields(sources []*iniFileSource, section *ini.Section, profile *domain.Profile) []domain.ProfileField {
+	if !section.HasKey(PROFILE_KEY_EXTENDS) {
+		return nil
+	}
+
+	workspace := strings.TrimSpace(section.Key(PROFILE_KEY_EXTENDS).String())
+	parentSource, parentSection := findProfileSection(sources, workspace)
+	if parentSection == nil {
+		return nil
+	}
+
+	parent, err := resolveProfileSection(sources, parentSource, workspace, parentSection)
+	if err != nil {
+		return nil
+	}
+
+	equals := map[domain.ProfileField]bool{
+		domain.ProfileFieldEmail:     profile.Email().Equals(parent.Email()),
+		domain.ProfileFieldName:      profile.Name().Equals(parent.Name()),
+		domain.ProfileFieldSigning:   profile.Signing().Equals(parent.Signing()),
+		domain.ProfileFieldSshKey:    profile.SshKey().Equals(parent.SshKey()),
+		domain.ProfileFieldRemotes:   profile.WithRemotes(parent.Remotes()).Equals(profile),
+		domain.ProfileFieldUsernames: profile.WithUsernames(parent.Usernames()).Equals(profile),
+		domain.ProfileFieldConfig:    profile.Config().Equals(parent.Config()),
+	}
+
+	fields := make([]domain.ProfileField, 0, len(equals))
+	for _, field := range domain.ProfileFields {
+		if equals[field] {
+			fields = append(fields, field)
+		}
+	}
+
+	return fields
+}
+
+// findProfileSection returns the section of the workspace in the first source that holds it
+func findProfileSection(sources []*iniFileSource, workspace string) (*iniFileSource, *ini.Section) {
+	for _, source := range sources {
+		if section, err := source.cfg.GetSection(workspace); err == nil && isProfileSection(section) {
+			return source, section
+		}
+	}
+
+	return nil, nil
+}
+
+// iniProfileSection is a section of the extends chain of a profile
+type iniProfileSection struct {
+	source    *iniFileSource
+	workspace string
+	section   *ini.Section
+}
+
+// config returns the git configuration section of the profile, nil when it has none
+func (p iniProfileSection) config() *ini.Section {
+	section, err := p.source.cfg.GetSection(configSectionName(p.workspace))
+	if err != nil || len(section.Keys()) == 0 {
+		return nil
+	}
+
+	return section
+}
+
+// resolveProfileSection builds the profile of the section, the fields it does
+// not define are inherited through its extends chain across all the sources
+func resolveProfileSection(sources []*iniFileSource, source *iniFileSource, workspace string, section *ini.Section) (*domain.Profile, error) {
+	chain := []iniProfileSection{{source, workspace, section}}
+	visited := map[string]bool{workspace: true}
+
+	for current := section; current.HasKey(PROFILE_KEY_EXTENDS); {
+		parent := strings.TrimSpace(current.Key(PROFILE_KEY_EXTENDS).String())
+		if visited[parent] {
+			return nil, domain.ErrProfileExtendsCycle
+		}
+
+		visited[parent] = true
+		parentSource, parentSection := findProfileSection(sources, parent)
+		if parentSection == nil {
+			return nil, domain.ErrProfileParentNotExists
+		}
+
+		current = parentSection
+		chain = append(chain, iniProfileSection{parentSource, parent, parentSection})
+	}
+
+	// The keys of every field are copied from the nearest section defining it
+	resolved := ini.Empty().Section(workspace)
+	origins := map[domain.ProfileField]domain.ProfileWorkspace{}
+	setOrigin := func(field domain.ProfileField, i int) error {
+		if i == 0 {
+			return nil
+		}
+
+		origin, err := domain.NewProfileWorkspace(chain[i].workspace)
+		if err != nil {
+			return err
+		}
+
+		origins[field] = origin
+		return nil
+	}
+
+	for field, keys := range profileFieldKeys {
+		for i, c := range chain {
+			if !c.section.HasKey(keys[0]) {
+				continue
+			}
+
+			for _, key := range keys {
+				if c.section.HasKey(key) {
+					resolved.Key(key).SetValue(c.section.Key(key).Value())
+				}
+			}
+
+			if err := setOrigin(field, i); err != nil {
+				return nil, err
+			}
+
+			break
+		}
+	}
+
+	// The git configuration is merged, the keys of a profile override the ones it extends
+	values := map[string]string{}
+	for i := len(chain) - 1; i >= 0; i-- {
+		config := chain[i].config()
+		if config == nil {
+			continue
+		}
+
+		for _, key := range config.Keys() {
+			values[key.Name()] = key.Value()
+		}
+
+		if err := setOrigin(domain.ProfileFieldConfig, i); err != nil {
+			return nil, err
+		}
+
+		if i == 0 {
+			delete(origins, domain.ProfileFieldConfig)
+		}
+	}
+
+	profile, err := newProfileFromSection(workspace, resolved)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(values) > 0 {
+		config, err := domain.NewProfileConfig(values)
+		if err != nil {
+			return nil, err
+		}
+
+		profile = profile.WithConfig(config)
+	}
+
+	if len(chain) == 1 {
+		return profile, nil
+	}
+
+	extends, err := domain.NewProfileWorkspace(chain[1].workspace)
+	if err != nil {
+		return nil, err
+	}
+
+	return profile.WithExtends(extends).WithOrigins(origins), nil
+}
+
+// configSectionName returns the name of the section holding the git
+// configuration of the profile: [config "work"]
+func configSectionName(workspace string) string {
+	return INI_SECTION_CONFIG + ` "` + workspace + `"`
+}
+
+func containsIniFileSource(sources []*iniFileSource, source *iniFileSource) bool {
+	for _, s := range sources {
+		if s == source {
+			return true
+		}
+	}
+
+	return false
+}
+
+// saveIniFileSource writes the file to a temporary file of the same directory
+// and renames it over the original one, keeping its permissions
+func saveIniFileSource(source *iniFileSource) error {
+	mode := os.FileMode(0600)
+	if info, err := os.Stat(source.path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(source.path), filepath.Base(source.path)+".*.tmp")
 	if err != nil {
 		return err
 	}
 
-	return nil
+	defer os.Remove(file.Name())
+
+	if _, err := source.cfg.WriteTo(file); err != nil {
+		file.Close()
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Chmod(file.Name(), mode); err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), source.path)
 }
 
 func (i *IniFileProfileRepository) Delete(workspace domain.ProfileWorkspace) error {
@@ -149,6 +505,7 @@ func (i *IniFileProfileRepository) Delete(workspace domain.ProfileWorkspace) err
 	}
 
 	source.cfg.DeleteSection(workspace.String())
+	source.cfg.DeleteSection(configSectionName(workspace.String()))
 	err = source.cfg.SaveTo(source.path)
 	if err != nil {
 		return err
@@ -162,6 +519,77 @@ func (i *IniFileProfileRepository) Delete(workspace domain.ProfileWorkspace) err
 	return nil
 }
 
+// Rename moves the section of the profile to the new workspace in every file
+// that holds it, the keys and their comments are kept and the profiles that
+// extend it are updated
+func (i *IniFileProfileRepository) Rename(workspace domain.ProfileWorkspace, newWorkspace domain.ProfileWorkspace) error {
+	sources, err := i.load()
+	if err != nil {
+		return err
+	}
+
+	changed := make([]*iniFileSource, 0, len(sources))
+	for _, source := range sources {
+		section, err := source.cfg.GetSection(workspace.String())
+		if err != nil {
+			continue
+		}
+
+		renamed, err := source.cfg.NewSection(newWorkspace.String())
+		if err != nil {
+			return err
+		}
+
+		copyIniSection(section, renamed)
+		source.cfg.DeleteSection(workspace.String())
+
+		if config, err := source.cfg.GetSection(configSectionName(workspace.String())); err == nil {
+			renamedConfig, err := source.cfg.NewSection(configSectionName(newWorkspace.String()))
+			if err != nil {
+				return err
+			}
+
+			copyIniSection(config, renamedConfig)
+			source.cfg.DeleteSection(configSectionName(workspace.String()))
+		}
+
+		changed = append(changed, source)
+	}
+
+	// The profiles extending the old workspace extend the new one
+	for _, source := range sources {
+		for _, section := range source.cfg.Sections() {
+			if !section.HasKey(PROFILE_KEY_EXTENDS) || strings.TrimSpace(section.Key(PROFILE_KEY_EXTENDS).String()) != workspace.String() {
+				continue
+			}
+
+			section.Key(PROFILE_KEY_EXTENDS).SetValue(newWorkspace.String())
+			if !containsIniFileSource(changed, source) {
+				changed = append(changed, source)
+			}
+		}
+	}
+
+	for _, source := range changed {
+		if err := saveIniFileSource(source); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// copyIniSection copies the keys of a section and their comments into another one
+func copyIniSection(from *ini.Section, to *ini.Section) {
+	to.Comment = from.Comment
+	for _, key := range from.Keys() {
+		to.Key(key.Name()).SetValue(key.Value())
+		to.Key(key.Name()).Comment = key.Comment
+	}
+}
+
+// List returns the profiles of every file, a profile whose extends chain is
+// broken is left out so the others are still listed, doctor and get report it
 func (i *IniFileProfileRepository) List() ([]*domain.Profile, error) {
 	profiles := make([]*domain.Profile, 0)
 
@@ -172,23 +600,178 @@ func (i *IniFileProfileRepository) List() ([]*domain.Profile, error) {
 
 	for _, source := range sources {
 		for _, section := range source.cfg.Sections() {
-			if section.Name() == ini.DefaultSection {
+			if !isProfileSection(section) {
 				continue
 			}
 
-			profile, err := domain.NewProfile(
-				section.Name(),
-				section.Key("email").String(),
-				section.Key("name").String(),
-			)
+			profile, err := resolveProfileSection(sources, source, section.Name(), section)
+			if errors.Is(err, domain.ErrProfileExtendsCycle) || errors.Is(err, domain.ErrProfileParentNotExists) {
+				continue
+			}
 
 			if err != nil {
 				return nil, err
 			}
 
-			profiles = append(profiles, profile)
+			profiles = append(profiles, profile.WithSource(i.source(source.path)))
 		}
 	}
 
 	return profiles, nil
 }
+
+// Reports reads every existing profile file on its own, the files that cannot
+// be parsed are reported instead of hiding the profiles of the other files
+func (i *IniFileProfileRepository) Reports() ([]*domain.ProfileSourceReport, error) {
+	reports := make([]*domain.ProfileSourceReport, 0, len(i.paths))
+
+	for _, path := range i.paths {
+		if info, err := os.Stat(path); err != nil || info.IsDir() {
+			continue
+		}
+
+		report := &domain.ProfileSourceReport{Source: i.source(path)}
+		reports = append(reports, report)
+
+		cfg, err := ini.Load(path)
+		if err != nil {
+			report.Err = err
+			continue
+		}
+
+		for _, section := range cfg.Sections() {
+			if isProfileSection(section) {
+				report.Workspaces = append(report.Workspaces, section.Name())
+			}
+		}
+
+		duplicates, err := duplicateIniSections(path, report.Workspaces)
+		if err != nil {
+			return nil, err
+		}
+
+		report.Duplicates = duplicates
+	}
+
+	return reports, nil
+}
+
+// duplicateIniSections returns the sections with more than one header in the
+// file, the ini parser merges their keys silently
+func duplicateIniSections(path string, sections []string) ([]string, error) {
+	content, err := os.ReadFile(path) // #nosec G304
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[string]int{}
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if name, ok := strings.CutPrefix(line, "["); ok {
+			if name, _, ok := strings.Cut(name, "]"); ok {
+				counts[strings.TrimSpace(name)]++
+			}
+		}
+	}
+
+	duplicates := make([]string, 0)
+	for _, section := range sections {
+		if counts[section] > 1 {
+			duplicates = append(duplicates, section)
+		}
+	}
+
+	return duplicates, nil
+}
+
+// newProfileFromSection builds a profile from the keys of a profile section
+func newProfileFromSection(workspace string, section *ini.Section) (*domain.Profile, error) {
+	profile, err := domain.NewProfile(
+		workspace,
+		section.Key("email").String(),
+		section.Key("name").String(),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if section.HasKey(PROFILE_KEY_SIGNING_KEY) {
+		signing, err := domain.NewProfileSigning(
+			section.Key(PROFILE_KEY_SIGNING_KEY).String(),
+			section.Key(PROFILE_KEY_SIGNING_FORMAT).String(),
+			section.Key(PROFILE_KEY_COMMIT_GPGSIGN).MustBool(false),
+			section.Key(PROFILE_KEY_TAG_GPGSIGN).MustBool(false),
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		profile = profile.WithSigning(signing)
+	}
+
+	if section.HasKey(PROFILE_KEY_SSH_KEY) {
+		sshKey, err := domain.NewProfileSshKey(section.Key(PROFILE_KEY_SSH_KEY).String())
+		if err != nil {
+			return nil, err
+		}
+
+		profile = profile.WithSshKey(sshKey)
+	}
+
+	if section.HasKey(PROFILE_KEY_REMOTES) {
+		remotes := make([]domain.ProfileRemotePattern, 0)
+		for _, value := range section.Key(PROFILE_KEY_REMOTES).Strings(",") {
+			remote, err := domain.NewProfileRemotePattern(value)
+			if err != nil {
+				return nil, err
+			}
+
+			remotes = append(remotes, remote)
+		}
+
+		profile = profile.WithRemotes(remotes)
+	}
+
+	if section.HasKey(PROFILE_KEY_USERNAMES) {
+		usernames := make([]domain.ProfileUsername, 0)
+		for _, value := range section.Key(PROFILE_KEY_USERNAMES).Strings(",") {
+			host, name, _ := strings.Cut(value, "=")
+			username, err := domain.NewProfileUsername(host, name)
+			if err != nil {
+				return nil, err
+			}
+
+			usernames = append(usernames, username)
+		}
+
+		profile = profile.WithUsernames(usernames)
+	}
+
+	return profile, nil
+}
+
+// createIniFile creates an empty file and its directories if it does not exist
+func createIniFile(path string) error {
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return err
+	}
+
+	file, err := os.Create(filepath.Clean(path))
+	if err != nil {
+		return err
+	}
+
+	return file.Close()
+}
+
+// isProfileSection reports whether a section holds a profile, other sections
+// such as rules are named with a quoted subsection like [rule "gitdir:~/work/"]
+func isProfileSection(section *ini.Section) bool {
+	return section.Name() != ini.DefaultSection && !strings.Contains(section.Name(), " ")
+}
@@ -3,6 +3,7 @@ package infrastructure_test
 import (
 	"os"
 	"path"
+	"path/filepath"
 	"testing"
 	"text/template"
 
@@ -397,4 +398,490 @@ func TestIniFileProfileRepository(t *testing.T) {
 		err = iniFileProfileRepository.Delete(workspace)
 		assert.NoError(t, err)
 	})
+
+	t.Run("should save and remove the signing configuration", func(t *testing.T) {
+		file, _, closeAndRemoveFile := generateTempFileAndProfiles(t, 2)
+		defer closeAndRemoveFile()
+
+		iniFileProfileRepository, err := infrastructure.NewIniFileProfileRepository([]string{file.Name()})
+		assert.NoError(t, err)
+
+		profile, err := domain.NewProfile(
+			faker.Internet().User(),
+			faker.Internet().Email(),
+			faker.Person().Name(),
+		)
+		assert.NoError(t, err)
+
+		signing, err := domain.NewProfileSigning("~/.ssh/id_ed25519.pub", domain.SigningFormatSSH, true, false)
+		assert.NoError(t, err)
+
+		err = iniFileProfileRepository.Save(profile.WithSigning(signing))
+		assert.NoError(t, err)
+
+		gettedProfile, err := iniFileProfileRepository.Get(profile.Workspace())
+		assert.NoError(t, err)
+		assert.True(t, profile.WithSigning(signing).Equals(gettedProfile))
+
+		err = iniFileProfileRepository.Save(profile)
+		assert.NoError(t, err)
+
+		gettedProfile, err = iniFileProfileRepository.Get(profile.Workspace())
+		assert.NoError(t, err)
+		assert.True(t, profile.Equals(gettedProfile))
+		assert.True(t, gettedProfile.Signing().IsEmpty())
+	})
+
+	t.Run("should save and remove the ssh key", func(t *testing.T) {
+		file, _, closeAndRemoveFile := generateTempFileAndProfiles(t, 2)
+		defer closeAndRemoveFile()
+
+		iniFileProfileRepository, err := infrastructure.NewIniFileProfileRepository([]string{file.Name()})
+		assert.NoError(t, err)
+
+		profile, err := domain.NewProfile(
+			faker.Internet().User(),
+			faker.Internet().Email(),
+			faker.Person().Name(),
+		)
+		assert.NoError(t, err)
+
+		sshKey, err := domain.NewProfileSshKey("~/.ssh/id_ed25519_work")
+		assert.NoError(t, err)
+
+		err = iniFileProfileRepository.Save(profile.WithSshKey(sshKey))
+		assert.NoError(t, err)
+
+		gettedProfile, err := iniFileProfileRepository.Get(profile.Workspace())
+		assert.NoError(t, err)
+		assert.Equal(t, sshKey, gettedProfile.SshKey())
+
+		err = iniFileProfileRepository.Save(profile)
+		assert.NoError(t, err)
+
+		gettedProfile, err = iniFileProfileRepository.Get(profile.Workspace())
+		assert.NoError(t, err)
+		assert.True(t, gettedProfile.SshKey().IsEmpty())
+	})
+
+	t.Run("should save all the profiles into the files that hold them", func(t *testing.T) {
+		file, _, closeAndRemoveFile := generateTempFileAndProfiles(t, 1)
+		defer closeAndRemoveFile()
+
+		otherFile, _, closeAndRemoveOtherFile := generateTempFileAndProfiles(t, 1)
+		defer closeAndRemoveOtherFile()
+
+		existing, err := domain.NewProfile(faker.Internet().User()+"-other", faker.Internet().Email(), faker.Person().Name())
+		assert.NoError(t, err)
+
+		otherFileProfileRepository, err := infrastructure.NewIniFileProfileRepository([]string{otherFile.Name()})
+		assert.NoError(t, err)
+		assert.NoError(t, otherFileProfileRepository.Save(existing))
+
+		iniFileProfileRepository, err := infrastructure.NewIniFileProfileRepository([]string{file.Name(), otherFile.Name()})
+		assert.NoError(t, err)
+
+		updated, err := domain.NewProfile(existing.Workspace().String(), faker.Internet().Email(), faker.Person().Name())
+		assert.NoError(t, err)
+
+		created, err := domain.NewProfile(faker.Internet().User()+"-new", faker.Internet().Email(), faker.Person().Name())
+		assert.NoError(t, err)
+
+		err = iniFileProfileRepository.SaveAll([]*domain.Profile{updated, created})
+		assert.NoError(t, err)
+
+		gettedProfile, err := iniFileProfileRepository.Get(updated.Workspace())
+		assert.NoError(t, err)
+		assert.True(t, updated.Equals(gettedProfile))
+		assert.Equal(t, otherFile.Name(), gettedProfile.Source().Path())
+
+		gettedProfile, err = iniFileProfileRepository.Get(created.Workspace())
+		assert.NoError(t, err)
+		assert.True(t, created.Equals(gettedProfile))
+		assert.Equal(t, file.Name(), gettedProfile.Source().Path())
+
+		// The temporary files are renamed over the profile files
+		temporaryFiles, err := filepath.Glob(file.Name() + ".*.tmp")
+		assert.NoError(t, err)
+		assert.Empty(t, temporaryFiles)
+	})
+
+	t.Run("should rename the profile in the file that holds it", func(t *testing.T) {
+		file, _, closeAndRemoveFile := generateTempFileAndProfiles(t, 1)
+		defer closeAndRemoveFile()
+
+		otherFile, _, closeAndRemoveOtherFile := generateTempFileAndProfiles(t, 1)
+		defer closeAndRemoveOtherFile()
+
+		sshKey, err := domain.NewProfileSshKey("~/.ssh/id_acme")
+		assert.NoError(t, err)
+
+		profile, err := domain.NewProfile(faker.Internet().User()+"-old", faker.Internet().Email(), faker.Person().Name())
+		assert.NoError(t, err)
+		profile = profile.WithSshKey(sshKey)
+
+		otherFileProfileRepository, err := infrastructure.NewIniFileProfileRepository([]string{otherFile.Name()})
+		assert.NoError(t, err)
+		assert.NoError(t, otherFileProfileRepository.Save(profile))
+
+		iniFileProfileRepository, err := infrastructure.NewIniFileProfileRepository([]string{file.Name(), otherFile.Name()})
+		assert.NoError(t, err)
+
+		newWorkspace, err := domain.NewProfileWorkspace(faker.Internet().User() + "-new")
+		assert.NoError(t, err)
+
+		err = iniFileProfileRepository.Rename(profile.Workspace(), newWorkspace)
+		assert.NoError(t, err)
+
+		_, err = iniFileProfileRepository.Get(profile.Workspace())
+		assert.Error(t, err)
+
+		gettedProfile, err := iniFileProfileRepository.Get(newWorkspace)
+		assert.NoError(t, err)
+		assert.Equal(t, profile.Email(), gettedProfile.Email())
+		assert.Equal(t, profile.Name(), gettedProfile.Name())
+		assert.Equal(t, sshKey, gettedProfile.SshKey())
+		assert.Equal(t, otherFile.Name(), gettedProfile.Source().Path())
+	})
+
+	t.Run("should resolve the fields inherited across the files", func(t *testing.T) {
+		dir := t.TempDir()
+		file, localFile := filepath.Join(dir, "global"), filepath.Join(dir, "local")
+
+		err := os.WriteFile(file, []byte(`[acme]
+name = Jane Doe
+email = jane@acme.com
+signingkey = ABCDEF0123456789
+signingformat = openpgp
+commitgpgsign = true
+sshkey = ~/.ssh/id_acme
+`), 0600)
+		assert.NoError(t, err)
+
+		err = os.WriteFile(localFile, []byte(`[client]
+extends = acme
+email = jane@client.com
+
+[client-b]
+extends = client
+sshkey = ~/.ssh/id_client_b
+`), 0600)
+		assert.NoError(t, err)
+
+		iniFileProfileRepository, err := infrastructure.NewIniFileProfileRepository([]string{file, localFile})
+		assert.NoError(t, err)
+
+		workspace, err := domain.NewProfileWorkspace("client-b")
+		assert.NoError(t, err)
+
+		profile, err := iniFileProfileRepository.Get(workspace)
+		assert.NoError(t, err)
+		assert.Equal(t, "jane@client.com", profile.Email().String())
+		assert.Equal(t, "Jane Doe", profile.Name().String())
+		assert.Equal(t, "ABCDEF0123456789", profile.Signing().Key())
+		assert.True(t, profile.Signing().CommitSign())
+		assert.Equal(t, "~/.ssh/id_client_b", profile.SshKey().String())
+		assert.Equal(t, "client", profile.Extends().String())
+		assert.Equal(t, "client", profile.Origin(domain.ProfileFieldEmail).String())
+		assert.Equal(t, "acme", profile.Origin(domain.ProfileFieldName).String())
+		assert.Equal(t, "acme", profile.Origin(domain.ProfileFieldSigning).String())
+		assert.Equal(t, "client-b", profile.Origin(domain.ProfileFieldSshKey).String())
+
+		profiles, err := iniFileProfileRepository.List()
+		assert.NoError(t, err)
+		assert.Len(t, profiles, 3)
+		assert.Equal(t, "Jane Doe", profiles[1].Name().String())
+		assert.Equal(t, "~/.ssh/id_acme", profiles[1].SshKey().String())
+
+		// Only the fields that differ from the extended profile are saved
+		updated, err := domain.NewProfile("client", "jane@client.io", "Jane Doe")
+		assert.NoError(t, err)
+		updated = updated.WithSigning(profiles[0].Signing()).WithSshKey(profiles[0].SshKey()).WithExtends(profiles[1].Extends())
+
+		err = iniFileProfileRepository.Save(updated)
+		assert.NoError(t, err)
+
+		content, err := os.ReadFile(localFile)
+		assert.NoError(t, err)
+		assert.Contains(t, string(content), "extends = acme")
+		assert.Contains(t, string(content), "email   = jane@client.io")
+		assert.NotContains(t, string(content), "name")
+		assert.NotContains(t, string(content), "signingkey")
+
+		// The profiles extending a renamed profile extend the new workspace
+		acme, err := domain.NewProfileWorkspace("acme")
+		assert.NoError(t, err)
+
+		acmeCorp, err := domain.NewProfileWorkspace("acme-corp")
+		assert.NoError(t, err)
+
+		err = iniFileProfileRepository.Rename(acme, acmeCorp)
+		assert.NoError(t, err)
+
+		profile, err = iniFileProfileRepository.Get(workspace)
+		assert.NoError(t, err)
+		assert.Equal(t, "acme-corp", profile.Origin(domain.ProfileFieldName).String())
+	})
+
+	t.Run("should remove the extends key of a profile saved without it", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "profiles")
+
+		err := os.WriteFile(file, []byte(`[acme]
+name = Jane Doe
+email = jane@acme.com
+
+[client]
+extends = acme
+email = jane@client.com
+`), 0600)
+		assert.NoError(t, err)
+
+		iniFileProfileRepository, err := infrastructure.NewIniFileProfileRepository([]string{file})
+		assert.NoError(t, err)
+
+		workspace, err := domain.NewProfileWorkspace("client")
+		assert.NoError(t, err)
+
+		profile, err := iniFileProfileRepository.Get(workspace)
+		assert.NoError(t, err)
+		assert.Equal(t, "acme", profile.Extends().String())
+
+		// The resolved fields are saved on their own once the profile extends no other one
+		standalone, err := domain.NewProfile("client", profile.Email().String(), profile.Name().String())
+		assert.NoError(t, err)
+		assert.NoError(t, iniFileProfileRepository.Save(standalone))
+
+		content, err := os.ReadFile(file)
+		assert.NoError(t, err)
+		assert.NotContains(t, string(content), "extends")
+
+		profile, err = iniFileProfileRepository.Get(workspace)
+		assert.NoError(t, err)
+		assert.Empty(t, profile.Extends().String())
+		assert.Equal(t, "Jane Doe", profile.Name().String())
+		assert.Equal(t, "jane@client.com", profile.Email().String())
+	})
+
+	t.Run("should return an error when the profiles extend each other and list the others", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "profiles")
+
+		err := os.WriteFile(file, []byte(`[a]
+extends = b
+name = Jane Doe
+email = jane@acme.com
+
+[b]
+extends = a
+
+[c]
+extends = missing
+
+[d]
+name = Jane Doe
+email = jane@home.org
+`), 0600)
+		assert.NoError(t, err)
+
+		iniFileProfileRepository, err := infrastructure.NewIniFileProfileRepository([]string{file})
+		assert.NoError(t, err)
+
+		for workspace, expected := range map[string]error{"a": domain.ErrProfileExtendsCycle, "c": domain.ErrProfileParentNotExists} {
+			value, err := domain.NewProfileWorkspace(workspace)
+			assert.NoError(t, err)
+
+			profile, err := iniFileProfileRepository.Get(value)
+			assert.ErrorIs(t, err, expected)
+			assert.Nil(t, profile)
+		}
+
+		// The broken profiles are left out of the list instead of failing it
+		profiles, err := iniFileProfileRepository.List()
+		assert.NoError(t, err)
+		assert.Len(t, profiles, 1)
+		assert.Equal(t, "d", profiles[0].Workspace().String())
+	})
+
+	t.Run("should return the source file and scope of the profiles", func(t *testing.T) {
+		file, profiles, closeAndRemoveFile := generateTempFileAndProfiles(t, 1)
+		defer closeAndRemoveFile()
+
+		localFile, localProfiles, closeAndRemoveLocalFile := generateTempFileAndProfiles(t, 1)
+		defer closeAndRemoveLocalFile()
+
+		iniFileProfileRepository, err := infrastructure.NewIniFileProfileRepository([]string{file.Name(), localFile.Name()})
+		assert.NoError(t, err)
+
+		iniFileProfileRepository = iniFileProfileRepository.WithLocalPath(localFile.Name())
+
+		workspace, err := domain.NewProfileWorkspace(profiles[0].Workspace)
+		assert.NoError(t, err)
+
+		gettedProfile, err := iniFileProfileRepository.Get(workspace)
+		assert.NoError(t, err)
+		assert.Equal(t, file.Name(), gettedProfile.Source().Path())
+		assert.Equal(t, domain.ProfileScopeGlobal, gettedProfile.Source().Scope())
+
+		gettedProfiles, err := iniFileProfileRepository.List()
+		assert.NoError(t, err)
+		assert.Len(t, gettedProfiles, 2)
+		assert.Equal(t, localProfiles[0].Workspace, gettedProfiles[1].Workspace().String())
+		assert.Equal(t, localFile.Name(), gettedProfiles[1].Source().Path())
+		assert.Equal(t, domain.ProfileScopeLocal, gettedProfiles[1].Source().Scope())
+	})
+
+	t.Run("should save and remove the remote url patterns", func(t *testing.T) {
+		file, _, closeAndRemoveFile := generateTempFileAndProfiles(t, 2)
+		defer closeAndRemoveFile()
+
+		iniFileProfileRepository, err := infrastructure.NewIniFileProfileRepository([]string{file.Name()})
+		assert.NoError(t, err)
+
+		profile, err := domain.NewProfile(
+			faker.Internet().User(),
+			faker.Internet().Email(),
+			faker.Person().Name(),
+		)
+		assert.NoError(t, err)
+
+		first, err := domain.NewProfileRemotePattern("github.com:acme-corp/*")
+		assert.NoError(t, err)
+
+		second, err := domain.NewProfileRemotePattern("gitlab.internal/*")
+		assert.NoError(t, err)
+
+		remoteProfile := profile.WithRemotes([]domain.ProfileRemotePattern{first, second})
+		err = iniFileProfileRepository.Save(remoteProfile)
+		assert.NoError(t, err)
+
+		gettedProfile, err := iniFileProfileRepository.Get(profile.Workspace())
+		assert.NoError(t, err)
+		assert.True(t, remoteProfile.Equals(gettedProfile))
+		assert.True(t, gettedProfile.MatchRemote("https://gitlab.internal/team/api.git"))
+
+		err = iniFileProfileRepository.Save(profile)
+		assert.NoError(t, err)
+
+		gettedProfile, err = iniFileProfileRepository.Get(profile.Workspace())
+		assert.NoError(t, err)
+		assert.Empty(t, gettedProfile.Remotes())
+	})
+
+	t.Run("should save, inherit and remove the extra git configuration", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "profiles")
+
+		err := os.WriteFile(file, []byte(`[acme]
+name = Jane Doe
+email = jane@acme.com
+
+[config "acme"]
+pull.rebase = true
+init.defaultbranch = main
+
+[client]
+extends = acme
+email = jane@client.com
+`), 0600)
+		assert.NoError(t, err)
+
+		iniFileProfileRepository, err := infrastructure.NewIniFileProfileRepository([]string{file})
+		assert.NoError(t, err)
+
+		profiles, err := iniFileProfileRepository.List()
+		assert.NoError(t, err)
+		assert.Len(t, profiles, 2)
+		assert.Equal(t, map[string]string{"init.defaultbranch": "main", "pull.rebase": "true"}, profiles[1].Config().Values())
+		assert.Equal(t, "acme", profiles[1].Origin(domain.ProfileFieldConfig).String())
+
+		// The keys of a profile override the ones of the profile it extends
+		config, err := domain.NewProfileConfig(map[string]string{"init.defaultbranch": "main", "pull.rebase": "false"})
+		assert.NoError(t, err)
+
+		err = iniFileProfileRepository.Save(profiles[1].WithConfig(config))
+		assert.NoError(t, err)
+
+		gettedProfile, err := iniFileProfileRepository.Get(profiles[1].Workspace())
+		assert.NoError(t, err)
+		assert.True(t, config.Equals(gettedProfile.Config()))
+
+		clientCorp, err := domain.NewProfileWorkspace("client-corp")
+		assert.NoError(t, err)
+
+		err = iniFileProfileRepository.Rename(profiles[1].Workspace(), clientCorp)
+		assert.NoError(t, err)
+
+		content, err := os.ReadFile(file)
+		assert.NoError(t, err)
+		assert.Contains(t, string(content), `[config "client-corp"]`)
+		assert.NotContains(t, string(content), `[config "client"]`)
+
+		err = iniFileProfileRepository.Delete(clientCorp)
+		assert.NoError(t, err)
+
+		content, err = os.ReadFile(file)
+		assert.NoError(t, err)
+		assert.NotContains(t, string(content), `[config "client-corp"]`)
+		assert.Contains(t, string(content), `[config "acme"]`)
+	})
+
+	t.Run("should save and remove the usernames", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "profiles")
+
+		iniFileProfileRepository, err := infrastructure.NewIniFileProfileRepository([]string{file})
+		assert.NoError(t, err)
+
+		profile, err := domain.NewProfile("work", "jane@acme.com", "Jane Doe")
+		assert.NoError(t, err)
+
+		github, err := domain.NewProfileUsername("github.com", "jane-acme")
+		assert.NoError(t, err)
+
+		gitlab, err := domain.NewProfileUsername("gitlab.internal:8443", "jane.doe@acme.com")
+		assert.NoError(t, err)
+
+		usernamesProfile := profile.WithUsernames([]domain.ProfileUsername{gitlab, github})
+		assert.NoError(t, iniFileProfileRepository.Save(usernamesProfile))
+
+		content, err := os.ReadFile(file)
+		assert.NoError(t, err)
+		assert.Contains(t, string(content), "usernames = github.com=jane-acme, gitlab.internal:8443=jane.doe@acme.com")
+
+		gettedProfile, err := iniFileProfileRepository.Get(profile.Workspace())
+		assert.NoError(t, err)
+		assert.True(t, usernamesProfile.Equals(gettedProfile))
+
+		username, ok := gettedProfile.Username("GitLab.internal:8443")
+		assert.True(t, ok)
+		assert.Equal(t, "jane.doe@acme.com", username)
+
+		assert.NoError(t, iniFileProfileRepository.Save(profile))
+
+		gettedProfile, err = iniFileProfileRepository.Get(profile.Workspace())
+		assert.NoError(t, err)
+		assert.Empty(t, gettedProfile.Usernames())
+	})
+
+	t.Run("should report the profiles, the duplicates and the invalid files of every source", func(t *testing.T) {
+		dir := t.TempDir()
+		valid := path.Join(dir, "valid")
+		invalid := path.Join(dir, "invalid")
+		missing := path.Join(dir, "missing")
+
+		assert.NoError(t, os.WriteFile(valid, []byte("[work]\nemail = work@example.com\nname = Work\n\n[oss]\nemail = oss@example.com\nname = Oss\n\n[work]\nname = Other\n"), 0600))
+		assert.NoError(t, os.WriteFile(invalid, []byte("[work\nemail = work@example.com\n"), 0600))
+
+		iniFileProfileRepository, err := infrastructure.NewIniFileProfileRepository([]string{valid, missing, invalid})
+		assert.NoError(t, err)
+
+		reports, err := iniFileProfileRepository.Reports()
+		assert.NoError(t, err)
+		assert.Len(t, reports, 2)
+
+		assert.Equal(t, valid, reports[0].Source.Path())
+		assert.NoError(t, reports[0].Err)
+		assert.Equal(t, []string{"work", "oss"}, reports[0].Workspaces)
+		assert.Equal(t, []string{"work"}, reports[0].Duplicates)
+
+		assert.Equal(t, invalid, reports[1].Source.Path())
+		assert.Error(t, reports[1].Err)
+	})
 }
@@ -0,0 +1,114 @@
+package infrastructure
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/b4nd/git-profile/pkg/domain"
+)
+
+const INI_SECTION_RULE = "rule"
+
+// IniFileProfileRuleRepository stores the rules in the same files as the profiles,
+// each rule is a section named after its condition: [rule "gitdir:~/work/"]
+type IniFileProfileRuleRepository struct {
+	paths []string
+}
+
+func NewIniFileProfileRuleRepository(paths []string) (*IniFileProfileRuleRepository, error) {
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no paths provided")
+	}
+
+	return &IniFileProfileRuleRepository{paths}, nil
+}
+
+func (i *IniFileProfileRuleRepository) List() ([]*domain.ProfileRule, error) {
+	rules := make([]*domain.ProfileRule, 0)
+
+	sources, err := loadIniFileSources(i.paths)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	for _, source := range sources {
+		for _, section := range source.cfg.Sections() {
+			condition, ok := ruleSectionCondition(section.Name())
+			if !ok || seen[condition] {
+				continue
+			}
+
+			rule, err := domain.NewProfileRule(section.Key("workspace").String(), condition)
+			if err != nil {
+				return nil, err
+			}
+
+			seen[condition] = true
+			rules = append(rules, rule)
+		}
+	}
+
+	return rules, nil
+}
+
+func (i *IniFileProfileRuleRepository) Save(rule *domain.ProfileRule) error {
+	if err := createIniFile(i.paths[0]); err != nil {
+		return err
+	}
+
+	sources, err := loadIniFileSources(i.paths)
+	if err != nil {
+		return err
+	}
+
+	name := ruleSectionName(rule.Condition())
+
+	// Update the rule where it is stored, new rules are stored in the first file
+	var source *iniFileSource = sources[0]
+	for _, c := range sources {
+		if _, err := c.cfg.GetSection(name); err == nil {
+			source = c
+			break
+		}
+	}
+
+	source.cfg.Section(name).Key("workspace").SetValue(rule.Workspace().String())
+
+	return source.cfg.SaveTo(source.path)
+}
+
+func (i *IniFileProfileRuleRepository) Delete(condition domain.ProfileRuleCondition) error {
+	sources, err := loadIniFileSources(i.paths)
+	if err != nil {
+		return err
+	}
+
+	name := ruleSectionName(condition)
+	for _, source := range sources {
+		if _, err := source.cfg.GetSection(name); err != nil {
+			continue
+		}
+
+		source.cfg.DeleteSection(name)
+		if err := source.cfg.SaveTo(source.path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func ruleSectionName(condition domain.ProfileRuleCondition) string {
+	return INI_SECTION_RULE + ` "` + condition.String() + `"`
+}
+
+// ruleSectionCondition returns the condition of a rule section name
+func ruleSectionCondition(name string) (string, bool) {
+	prefix := INI_SECTION_RULE + ` "`
+	if !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, `"`) || len(name) <= len(prefix) {
+		return "", false
+	}
+
+	return strings.TrimSuffix(strings.TrimPrefix(name, prefix), `"`), true
+}
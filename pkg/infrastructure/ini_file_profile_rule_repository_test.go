@@ -0,0 +1,88 @@
+package infrastructure_test
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/b4nd/git-profile/pkg/domain"
+	"github.com/b4nd/git-profile/pkg/infrastructure"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIniFileProfileRuleRepository(t *testing.T) {
+	t.Run("should return error when no paths are provided", func(t *testing.T) {
+		repo, err := infrastructure.NewIniFileProfileRuleRepository([]string{})
+		assert.Error(t, err)
+		assert.Nil(t, repo)
+	})
+
+	t.Run("should save, list and delete rules next to the profiles", func(t *testing.T) {
+		file, profiles, closeAndRemoveFile := generateTempFileAndProfiles(t, 3)
+		defer closeAndRemoveFile()
+
+		ruleRepository, err := infrastructure.NewIniFileProfileRuleRepository([]string{file.Name()})
+		assert.NoError(t, err)
+
+		profileRepository, err := infrastructure.NewIniFileProfileRepository([]string{file.Name()})
+		assert.NoError(t, err)
+
+		gitDirRule, err := domain.NewProfileRule(profiles[0].Workspace, "gitdir:~/work/")
+		assert.NoError(t, err)
+
+		remoteRule, err := domain.NewProfileRule(profiles[1].Workspace, "hasconfig:remote.*.url:git@github.com:acme/**")
+		assert.NoError(t, err)
+
+		assert.NoError(t, ruleRepository.Save(gitDirRule))
+		assert.NoError(t, ruleRepository.Save(remoteRule))
+
+		rules, err := ruleRepository.List()
+		assert.NoError(t, err)
+		assert.Equal(t, []*domain.ProfileRule{gitDirRule, remoteRule}, rules)
+
+		// The rules are not listed as profiles
+		gettedProfiles, err := profileRepository.List()
+		assert.NoError(t, err)
+		assert.Len(t, gettedProfiles, len(profiles))
+
+		// Saving the same condition moves it to another profile
+		movedRule, err := domain.NewProfileRule(profiles[2].Workspace, "gitdir:~/work/")
+		assert.NoError(t, err)
+		assert.NoError(t, ruleRepository.Save(movedRule))
+
+		rules, err = ruleRepository.List()
+		assert.NoError(t, err)
+		assert.Equal(t, []*domain.ProfileRule{movedRule, remoteRule}, rules)
+
+		assert.NoError(t, ruleRepository.Delete(remoteRule.Condition()))
+
+		rules, err = ruleRepository.List()
+		assert.NoError(t, err)
+		assert.Equal(t, []*domain.ProfileRule{movedRule}, rules)
+	})
+
+	t.Run("should return an empty list when the file does not exist", func(t *testing.T) {
+		ruleRepository, err := infrastructure.NewIniFileProfileRuleRepository([]string{path.Join(t.TempDir(), ".gitprofile")})
+		assert.NoError(t, err)
+
+		rules, err := ruleRepository.List()
+		assert.NoError(t, err)
+		assert.Len(t, rules, 0)
+	})
+
+	t.Run("should create the file when it does not exist", func(t *testing.T) {
+		file := path.Join(t.TempDir(), ".gitprofile")
+		ruleRepository, err := infrastructure.NewIniFileProfileRuleRepository([]string{file})
+		assert.NoError(t, err)
+
+		rule, err := domain.NewProfileRule("work", "gitdir:~/work/")
+		assert.NoError(t, err)
+
+		assert.NoError(t, ruleRepository.Save(rule))
+
+		content, err := os.ReadFile(file)
+		assert.NoError(t, err)
+		assert.Contains(t, string(content), `[rule "gitdir:~/work/"]`)
+	})
+}